@@ -6,15 +6,24 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"projectnexus/internal/api/routes"
 	"projectnexus/internal/config"
+	"projectnexus/internal/events"
 	"projectnexus/internal/repository"
 	"projectnexus/internal/repository/mongo"
+	"syscall"
 	"time"
 )
 
 func main() {
+	// Background work stops when the server is asked to shut down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Load configuration
 	configApp := config.Load()
 
@@ -38,20 +47,38 @@ func main() {
 	})
 
 	// Test Redis connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err := redisClient.Ping(ctx).Err(); err != nil {
+	if err := redisClient.Ping(pingCtx).Err(); err != nil {
 		log.Fatal("Failed to connect to Redis:", err)
 	}
 	log.Println("Successfully connected to Redis")
 
 	// Initialize token store
 	tokenStore := repository.NewRedisTokenStore(redisClient)
+	attempts := repository.NewRedisAttemptStore(redisClient)
+
+	// Domain events go through Redis when instances share them, and are
+	// handled in process otherwise
+	bus := events.NewBus()
+	if configApp.Events.Transport == "redis" {
+		consumer, err := os.Hostname()
+		if err != nil {
+			log.Fatal("Failed to get hostname for the event consumer:", err)
+		}
+		bus.UseTransport(events.NewRedisStreams(redisClient, configApp.Events.Stream, configApp.Events.Group, consumer))
+		log.Printf("Publishing events to Redis stream %s", configApp.Events.Stream)
+	}
 
 	// Create gin router
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	// Client addresses end up in the audit log, so X-Forwarded-For only
+	// counts when a known proxy sent it
+	if err := r.SetTrustedProxies(configApp.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
 
 	// Configure CORS
 	corsConfig := cors.DefaultConfig()
@@ -66,12 +93,20 @@ func main() {
 		"Accept",
 		"Authorization",
 		"X-Requested-With",
+		"X-Share-Password",
 	}
 	corsConfig.AllowCredentials = true
 	r.Use(cors.New(corsConfig))
 
 	// Setup routes with both DB and tokenStore
-	routes.SetupRouter(r, db, tokenStore)
+	wait := routes.SetupRouter(ctx, r, db, tokenStore, attempts, bus)
+
+	// Handle events from the transport once every subscriber is registered
+	go func() {
+		if err := bus.Run(ctx); err != nil {
+			log.Printf("Event consumer stopped: %v", err)
+		}
+	}()
 
 	// Add health check route
 	r.GET("/api/health", func(c *gin.Context) {
@@ -98,8 +133,27 @@ func main() {
 		})
 	})
 
-	log.Println("Starting server on :8085")
-	if err := r.Run("0.0.0.0:8085"); err != nil {
-		log.Fatal("Error starting server: ", err)
+	server := &http.Server{
+		Addr:    "0.0.0.0:8085",
+		Handler: r,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		log.Println("Starting server on :8085")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Error starting server: ", err)
+		}
+	}()
+
+	// Finish the requests in flight and let the background jobs wind down
+	<-ctx.Done()
+	log.Println("Shutting down")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
 	}
+	wait()
 }
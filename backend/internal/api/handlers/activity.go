@@ -0,0 +1,72 @@
+// Package handlers internal/api/handlers/activity.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/services"
+)
+
+type ActivityHandler struct {
+	activityService services.ActivityService
+}
+
+func NewActivityHandler(activityService services.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// ListActivity handles retrieving one page of the activity in the user's
+// projects, optionally filtered by ?type=, ?actor= and ?projectId=
+func (h *ActivityHandler) ListActivity(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.ActivityListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.activityService.ListActivity(c.Request.Context(), c.GetString("userID"), q)
+	if err != nil {
+		respondActivityError(c, err, "Failed to get activity")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListProjectActivity handles retrieving one page of a project's activity
+func (h *ActivityHandler) ListProjectActivity(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.ActivityListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.activityService.ListProjectActivity(c.Request.Context(), c.Param("id"), c.GetString("userID"), q)
+	if err != nil {
+		respondActivityError(c, err, "Failed to get project activity")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func respondActivityError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
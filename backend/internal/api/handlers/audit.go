@@ -0,0 +1,150 @@
+// Package handlers internal/api/handlers/audit.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/services"
+	"time"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEntries handles retrieving one page of the organization's audit log,
+// filtered by ?actor=, ?action=, ?resourceType=, ?resourceId=, ?projectId=
+// and a ?from= and ?to= time range
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.AuditListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.auditService.ListEntries(c.Request.Context(), q)
+	if err != nil {
+		respondAuditError(c, err, "Failed to get audit log")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListProjectEntries handles retrieving one page of a project's audit log
+func (h *AuditHandler) ListProjectEntries(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.AuditListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.auditService.ListProjectEntries(c.Request.Context(), c.Param("id"), c.GetString("userID"), q)
+	if err != nil {
+		respondAuditError(c, err, "Failed to get audit log")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ExportEntries handles downloading the organization's audit log as
+// ?format=csv (the default) or json, with the same filters as ListEntries
+func (h *AuditHandler) ExportEntries(c *gin.Context) {
+	q, format, ok := parseAuditExport(c)
+	if !ok {
+		return
+	}
+
+	w := &downloadWriter{c: c, format: format}
+	if err := h.auditService.ExportEntries(c.Request.Context(), q, format, w); err != nil {
+		w.fail(err)
+	}
+}
+
+// ExportProjectEntries handles downloading a project's audit log
+func (h *AuditHandler) ExportProjectEntries(c *gin.Context) {
+	q, format, ok := parseAuditExport(c)
+	if !ok {
+		return
+	}
+
+	w := &downloadWriter{c: c, format: format}
+	if err := h.auditService.ExportProjectEntries(c.Request.Context(), c.Param("id"), c.GetString("userID"), q, format, w); err != nil {
+		w.fail(err)
+	}
+}
+
+func parseAuditExport(c *gin.Context) (pagination.Query, models.AuditExportFormat, bool) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.AuditListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return q, "", false
+	}
+
+	format := models.AuditExportFormat(c.DefaultQuery("format", string(models.AuditExportCSV)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return q, "", false
+	}
+	return q, format, true
+}
+
+// downloadWriter streams an export as a file attachment. The headers go out
+// with the first write, so errors found before anything was written still
+// get a regular error response.
+type downloadWriter struct {
+	c       *gin.Context
+	format  models.AuditExportFormat
+	started bool
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		contentType := "text/csv; charset=utf-8"
+		if w.format == models.AuditExportJSON {
+			contentType = "application/json; charset=utf-8"
+		}
+		filename := fmt.Sprintf("audit-log-%s.%s", time.Now().UTC().Format("20060102-150405"), w.format)
+		w.c.Header("Content-Type", contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// fail reports an error, which mid-download can only be logged
+func (w *downloadWriter) fail(err error) {
+	if w.started {
+		log.Printf("Failed to export audit log: %v", err)
+		return
+	}
+	respondAuditError(w.c, err, "Failed to export audit log")
+}
+
+func respondAuditError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only organization admins and project owners can read the audit log"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
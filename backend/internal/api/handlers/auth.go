@@ -132,3 +132,21 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "successfully logged out"})
 }
+
+// IssueStreamTicket hands out a one-time ticket that opens an event stream,
+// as ?ticket=, for clients that cannot send the Authorization header
+func (h *AuthHandler) IssueStreamTicket(c *gin.Context) {
+	ticket, err := h.authService.IssueStreamTicket(c.Request.Context(), c.GetString("token"), c.GetString("orgID"))
+	if err != nil {
+		log.Printf("Stream ticket error: %v", err)
+		switch {
+		case errors.Is(err, errs.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue stream ticket"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/services"
 )
 
@@ -43,8 +44,12 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 		switch {
 		case errors.Is(err, errs.ErrProjectNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, errs.ErrFolderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to create documents in this project"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create document: %v", err)})
 		}
@@ -113,8 +118,12 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 		switch {
 		case errors.Is(err, errs.ErrDocumentNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		case errors.Is(err, errs.ErrFolderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this document"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   fmt.Sprintf("Failed to update document: %v", err),
@@ -139,6 +148,8 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to delete this document"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
 		}
@@ -152,27 +163,59 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	userID := c.GetString("userID")
 
-	docs, err := h.documentService.ListDocuments(c.Request.Context(), userID)
+	q, err := pagination.Parse(c.Request.URL.Query(), models.DocumentListSpec)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.documentService.ListDocuments(c.Request.Context(), userID, q)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list documents"})
 		return
 	}
 
-	c.JSON(http.StatusOK, docs)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetProjectDocuments handles listing all documents in a specific project
 func (h *DocumentHandler) GetProjectDocuments(c *gin.Context) {
-	projectID := c.Param("projectId")
+	projectID := c.Param("id")
 	userID := c.GetString("userID")
 
 	// Add debug logging
 	log.Printf("GetProjectDocuments request - ProjectID: %s, UserID: %s", projectID, userID)
 
-	docs, err := h.documentService.GetProjectDocuments(c.Request.Context(), projectID, userID)
+	// ?folderId= (empty for the project root) and repeatable ?tag= filters
+	var filter models.DocumentFilter
+	if folderID, ok := c.GetQuery("folderId"); ok {
+		filter.FolderID = &folderID
+	}
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		normalized, err := models.NormalizeTags(tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.Tags = normalized
+	}
+
+	q, err := pagination.Parse(c.Request.URL.Query(), models.ProjectDocumentListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.documentService.GetProjectDocuments(c.Request.Context(), projectID, filter, q, userID)
 	if err != nil {
 		log.Printf("Error getting project documents: %v", err)
 		switch {
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, errs.ErrProjectNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
@@ -186,11 +229,7 @@ func (h *DocumentHandler) GetProjectDocuments(c *gin.Context) {
 		return
 	}
 
-	if docs == nil {
-		docs = []*models.Document{} // Return empty array instead of null
-	}
-
-	c.JSON(http.StatusOK, docs)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetDocumentVersions handles retrieving version history of a document
@@ -213,3 +252,24 @@ func (h *DocumentHandler) GetDocumentVersions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, versions)
 }
+
+// GetProjectTags handles listing the document tags used in a project
+func (h *DocumentHandler) GetProjectTags(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	tags, err := h.documentService.GetProjectTags(c.Request.Context(), projectID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to access this project"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project tags"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
@@ -0,0 +1,153 @@
+// Package handlers internal/api/handlers/folder.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type FolderHandler struct {
+	folderService services.FolderService
+}
+
+func NewFolderHandler(folderService services.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+// ListFolders handles retrieving a project's folder tree
+func (h *FolderHandler) ListFolders(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	folders, err := h.folderService.ListFolders(c.Request.Context(), projectID, userID)
+	if err != nil {
+		respondFolderError(c, err, "Failed to get folders")
+		return
+	}
+
+	c.JSON(http.StatusOK, folders)
+}
+
+// CreateFolder handles creating a folder in a project
+func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.CreateFolderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.folderService.CreateFolder(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondFolderError(c, err, "Failed to create folder")
+		return
+	}
+
+	c.JSON(http.StatusCreated, folder)
+}
+
+// RenameFolder handles renaming a folder
+func (h *FolderHandler) RenameFolder(c *gin.Context) {
+	projectID := c.Param("id")
+	folderID := c.Param("folderId")
+	userID := c.GetString("userID")
+
+	var input models.RenameFolderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.folderService.RenameFolder(c.Request.Context(), projectID, folderID, input, userID)
+	if err != nil {
+		respondFolderError(c, err, "Failed to rename folder")
+		return
+	}
+
+	c.JSON(http.StatusOK, folder)
+}
+
+// MoveFolder handles moving a folder under a new parent and/or position
+func (h *FolderHandler) MoveFolder(c *gin.Context) {
+	projectID := c.Param("id")
+	folderID := c.Param("folderId")
+	userID := c.GetString("userID")
+
+	var input models.MoveFolderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.folderService.MoveFolder(c.Request.Context(), projectID, folderID, input, userID)
+	if err != nil {
+		respondFolderError(c, err, "Failed to move folder")
+		return
+	}
+
+	c.JSON(http.StatusOK, folder)
+}
+
+// ReorderFolders handles setting the order of the folders under one parent
+func (h *FolderHandler) ReorderFolders(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.ReorderFoldersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.folderService.ReorderFolders(c.Request.Context(), projectID, input, userID); err != nil {
+		respondFolderError(c, err, "Failed to reorder folders")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteFolder handles deleting an empty folder
+func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	projectID := c.Param("id")
+	folderID := c.Param("folderId")
+	userID := c.GetString("userID")
+
+	if err := h.folderService.DeleteFolder(c.Request.Context(), projectID, folderID, userID); err != nil {
+		respondFolderError(c, err, "Failed to delete folder")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondFolderError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrFolderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage folders in this project"})
+	case errors.Is(err, errs.ErrFolderNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": "Folder must be empty before it can be deleted"})
+	case errors.Is(err, errs.ErrInvalidMove):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Folder cannot be moved into itself or one of its sub-folders"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,168 @@
+// Package handlers internal/api/handlers/invitation.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService services.InvitationService
+}
+
+func NewInvitationHandler(invitationService services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// InviteToProject handles inviting someone to a project by email
+func (h *InvitationHandler) InviteToProject(c *gin.Context) {
+	var input models.CreateInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	invitation, err := h.invitationService.InviteToProject(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// InviteToTeam handles inviting someone to a team by email
+func (h *InvitationHandler) InviteToTeam(c *gin.Context) {
+	var input models.CreateInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	invitation, err := h.invitationService.InviteToTeam(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListProjectInvitations returns the pending invitations of a project
+func (h *InvitationHandler) ListProjectInvitations(c *gin.Context) {
+	invitations, err := h.invitationService.ListProjectInvitations(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	if invitations == nil {
+		invitations = make([]*models.Invitation, 0)
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// ListTeamInvitations returns the pending invitations of a team
+func (h *InvitationHandler) ListTeamInvitations(c *gin.Context) {
+	invitations, err := h.invitationService.ListTeamInvitations(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	if invitations == nil {
+		invitations = make([]*models.Invitation, 0)
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *InvitationHandler) RevokeProjectInvitation(c *gin.Context) {
+	err := h.invitationService.RevokeProjectInvitation(c.Request.Context(), c.Param("id"), c.Param("invitationId"), c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InvitationHandler) RevokeTeamInvitation(c *gin.Context) {
+	err := h.invitationService.RevokeTeamInvitation(c.Request.Context(), c.Param("id"), c.Param("invitationId"), c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation joins the signed-in user using an invitation token
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var input models.InvitationTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	invitation, err := h.invitationService.Accept(c.Request.Context(), input.Token, c.GetString("userID"))
+	if err != nil {
+		respondInvitationError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// DeclineInvitation is public: the token alone identifies the invitation
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	var input models.InvitationTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if err := h.invitationService.Decline(c.Request.Context(), input.Token); err != nil {
+		respondInvitationError(c, err, "Failed to decline invitation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondInvitationError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation link"})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	case errors.Is(err, errs.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage invitations"})
+	case errors.Is(err, errs.ErrInvitationEmailMismatch):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invitation was sent to a different email address"})
+	case errors.Is(err, errs.ErrInvitationExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "An invitation for this email is already pending"})
+	case errors.Is(err, errs.ErrInvitationClosed):
+		c.JSON(http.StatusGone, gin.H{"error": "This invitation has expired or was already used"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,153 @@
+// Package handlers internal/api/handlers/link.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type LinkHandler struct {
+	linkService services.LinkService
+}
+
+func NewLinkHandler(linkService services.LinkService) *LinkHandler {
+	return &LinkHandler{
+		linkService: linkService,
+	}
+}
+
+// CreateLink handles creating a typed link between two items
+func (h *LinkHandler) CreateLink(c *gin.Context) {
+	var input models.CreateLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("userID")
+	link, err := h.linkService.CreateLink(c.Request.Context(), input, userID)
+	if err != nil {
+		log.Printf("Error creating link: %v", err)
+		switch {
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Linked item not found"})
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to link these items"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+		case errors.Is(err, errs.ErrLinkExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "Link already exists"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// DeleteLink handles removing a manually created link
+func (h *LinkHandler) DeleteLink(c *gin.Context) {
+	linkID := c.Param("id")
+	userID := c.GetString("userID")
+
+	err := h.linkService.DeleteLink(c.Request.Context(), linkID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrLinkNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Extracted links are removed by editing the document content"})
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to delete this link"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete link"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDocumentLinks handles listing the outgoing links of a document
+func (h *LinkHandler) GetDocumentLinks(c *gin.Context) {
+	h.listLinks(c, models.LinkItemDocument, false)
+}
+
+// GetDocumentBacklinks handles listing the items linking to a document
+func (h *LinkHandler) GetDocumentBacklinks(c *gin.Context) {
+	h.listLinks(c, models.LinkItemDocument, true)
+}
+
+// GetMockupLinks handles listing the outgoing links of a mockup
+func (h *LinkHandler) GetMockupLinks(c *gin.Context) {
+	h.listLinks(c, models.LinkItemMockup, false)
+}
+
+// GetMockupBacklinks handles listing the items linking to a mockup
+func (h *LinkHandler) GetMockupBacklinks(c *gin.Context) {
+	h.listLinks(c, models.LinkItemMockup, true)
+}
+
+// GetProjectBacklinks handles listing the items linking to a project
+func (h *LinkHandler) GetProjectBacklinks(c *gin.Context) {
+	h.listLinks(c, models.LinkItemProject, true)
+}
+
+// GetBrokenLinks handles listing links in a project whose target was deleted
+func (h *LinkHandler) GetBrokenLinks(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	links, err := h.linkService.GetBrokenLinks(c.Request.Context(), projectID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to access this project"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get broken links"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+func (h *LinkHandler) listLinks(c *gin.Context, itemType models.LinkItemType, backlinks bool) {
+	itemID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var (
+		links []*models.Link
+		err   error
+	)
+	if backlinks {
+		links, err = h.linkService.GetBacklinks(c.Request.Context(), itemType, itemID, userID)
+	} else {
+		links, err = h.linkService.GetLinks(c.Request.Context(), itemType, itemID, userID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to access this item"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get links"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
@@ -0,0 +1,144 @@
+// Package handlers internal/api/handlers/milestone.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type MilestoneHandler struct {
+	milestoneService services.MilestoneService
+}
+
+func NewMilestoneHandler(milestoneService services.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{
+		milestoneService: milestoneService,
+	}
+}
+
+// ListMilestones handles retrieving a project's timeline, optionally filtered
+// by ?status= and ?owner=
+func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	filter := models.MilestoneFilter{
+		Status:  models.MilestoneStatus(c.Query("status")),
+		OwnerID: c.Query("owner"),
+	}
+
+	milestones, err := h.milestoneService.ListMilestones(c.Request.Context(), projectID, filter, userID)
+	if err != nil {
+		respondMilestoneError(c, err, "Failed to get milestones")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+// GetMilestone handles retrieving a single milestone
+func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
+	projectID := c.Param("id")
+	milestoneID := c.Param("milestoneId")
+	userID := c.GetString("userID")
+
+	milestone, err := h.milestoneService.GetMilestone(c.Request.Context(), projectID, milestoneID, userID)
+	if err != nil {
+		respondMilestoneError(c, err, "Failed to get milestone")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// CreateMilestone handles adding a milestone to a project
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.CreateMilestoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.CreateMilestone(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondMilestoneError(c, err, "Failed to create milestone")
+		return
+	}
+
+	c.JSON(http.StatusCreated, milestone)
+}
+
+// UpdateMilestone handles editing, completing and reopening a milestone
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+	projectID := c.Param("id")
+	milestoneID := c.Param("milestoneId")
+	userID := c.GetString("userID")
+
+	var input models.UpdateMilestoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.UpdateMilestone(c.Request.Context(), projectID, milestoneID, input, userID)
+	if err != nil {
+		respondMilestoneError(c, err, "Failed to update milestone")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// DeleteMilestone handles removing a milestone
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	projectID := c.Param("id")
+	milestoneID := c.Param("milestoneId")
+	userID := c.GetString("userID")
+
+	if err := h.milestoneService.DeleteMilestone(c.Request.Context(), projectID, milestoneID, userID); err != nil {
+		respondMilestoneError(c, err, "Failed to delete milestone")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListOverdueMilestones handles retrieving the overdue milestones across the
+// user's projects
+func (h *MilestoneHandler) ListOverdueMilestones(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	milestones, err := h.milestoneService.ListOverdue(c.Request.Context(), userID)
+	if err != nil {
+		respondMilestoneError(c, err, "Failed to get overdue milestones")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func respondMilestoneError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrMilestoneNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage milestones in this project"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
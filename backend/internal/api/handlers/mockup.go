@@ -2,11 +2,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/services"
 )
 
@@ -27,10 +30,9 @@ func (h *MockupHandler) CreateMockup(c *gin.Context) {
 
 	// Get user ID from context (set by auth middleware)
 	userID := c.GetString("userID")
-	mockup.CreatedBy = userID
 
-	if err := h.mockupService.CreateMockup(c, &mockup); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.mockupService.CreateMockup(c.Request.Context(), &mockup, userID); err != nil {
+		respondMockupError(c, err)
 		return
 	}
 
@@ -40,9 +42,9 @@ func (h *MockupHandler) CreateMockup(c *gin.Context) {
 func (h *MockupHandler) GetMockup(c *gin.Context) {
 	id := c.Param("id")
 
-	mockup, err := h.mockupService.GetMockupByID(c, id)
+	mockup, err := h.mockupService.GetMockupByID(c.Request.Context(), id, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Mockup not found"})
+		respondMockupError(c, err)
 		return
 	}
 
@@ -52,9 +54,9 @@ func (h *MockupHandler) GetMockup(c *gin.Context) {
 func (h *MockupHandler) GetProjectMockups(c *gin.Context) {
 	projectID := c.Param("projectId")
 
-	mockups, err := h.mockupService.GetProjectMockups(c, projectID)
+	mockups, err := h.mockupService.GetProjectMockups(c.Request.Context(), projectID, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondMockupError(c, err)
 		return
 	}
 
@@ -72,8 +74,8 @@ func (h *MockupHandler) UpdateMockup(c *gin.Context) {
 
 	mockup.ID = id
 
-	if err := h.mockupService.UpdateMockup(c, &mockup); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.mockupService.UpdateMockup(c.Request.Context(), &mockup, c.GetString("userID")); err != nil {
+		respondMockupError(c, err)
 		return
 	}
 
@@ -82,9 +84,10 @@ func (h *MockupHandler) UpdateMockup(c *gin.Context) {
 
 func (h *MockupHandler) DeleteMockup(c *gin.Context) {
 	id := c.Param("id")
+	userID := c.GetString("userID")
 
-	if err := h.mockupService.DeleteMockup(c, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.mockupService.DeleteMockup(c.Request.Context(), id, userID); err != nil {
+		respondMockupError(c, err)
 		return
 	}
 
@@ -92,11 +95,38 @@ func (h *MockupHandler) DeleteMockup(c *gin.Context) {
 }
 
 func (h *MockupHandler) ListMockups(c *gin.Context) {
-	mockups, err := h.mockupService.ListMockups(c)
+	q, err := pagination.Parse(c.Request.URL.Query(), models.MockupListSpec)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.mockupService.ListMockups(c.Request.Context(), c.GetString("userID"), q)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, mockups)
+	c.JSON(http.StatusOK, page)
+}
+
+// respondMockupError maps the mockup service's errors to statuses; changes
+// refused in archived projects are conflicts
+func respondMockupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errs.ErrMockupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mockup not found"})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to access this project"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,182 @@
+// Package handlers internal/api/handlers/notification.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/services"
+	"time"
+)
+
+// streamKeepAlive is how often an idle notification or change stream is
+// pinged so proxies do not close it
+const streamKeepAlive = 30 * time.Second
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListNotifications handles listing one page of the user's notifications
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.NotificationListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.notificationService.ListNotifications(c.Request.Context(), c.GetString("userID"), q)
+	if err != nil {
+		respondNotificationError(c, err, "Failed to get notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetUnreadCount handles counting the user's unread notifications
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.notificationService.UnreadCount(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondNotificationError(c, err, "Failed to count notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+// MarkRead handles marking one notification read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notification, err := h.notificationService.MarkRead(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondNotificationError(c, err, "Failed to mark notification read")
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllRead handles marking every notification of the user read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	count, err := h.notificationService.MarkAllRead(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondNotificationError(c, err, "Failed to mark notifications read")
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+// GetPreferences handles retrieving which notifications the user receives
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	preferences, err := h.notificationService.GetPreferences(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondNotificationError(c, err, "Failed to get notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences handles turning notification types on or off
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var input models.UpdateNotificationPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request.Context(), c.GetString("userID"), input)
+	if err != nil {
+		respondNotificationError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// Unsubscribe is public: the token of the link in an email identifies the
+// user. The token may be sent in the body or, as in one-click unsubscribe
+// requests from mail clients, in the query string.
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	input := models.UnsubscribeInput{Token: c.Query("token")}
+	if input.Token == "" {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+			return
+		}
+	}
+
+	if err := h.notificationService.Unsubscribe(c.Request.Context(), input.Token); err != nil {
+		respondNotificationError(c, err, "Failed to unsubscribe")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StreamNotifications keeps a server-sent events connection open and pushes
+// the user's new notifications and unread counts as they happen. The first
+// event is the current unread count.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetString("userID")
+
+	count, err := h.notificationService.UnreadCount(ctx, userID)
+	if err != nil {
+		respondNotificationError(c, err, "Failed to open notification stream")
+		return
+	}
+
+	messages := h.notificationService.Stream(ctx, userID)
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keeps nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent(services.EventUnreadCount, count)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return false
+			}
+			c.SSEvent(msg.Event, msg.Data)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+func respondNotificationError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrInvalidToken), errors.Is(err, errs.ErrTokenExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unsubscribe link"})
+	case errors.Is(err, errs.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+	case errors.Is(err, errs.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,155 @@
+// Package handlers internal/api/handlers/organization.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	orgService services.OrganizationService
+}
+
+func NewOrganizationHandler(orgService services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var input models.CreateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	org, err := h.orgService.CreateOrganization(c.Request.Context(), input, c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// ListOrganizations returns the organizations of the signed-in user, along
+// with the one the request acts in
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.orgService.ListOrganizations(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to fetch organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": orgs,
+		"currentId":     c.GetString("orgID"),
+	})
+}
+
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, err := h.orgService.GetOrganization(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to fetch organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var input models.UpdateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	org, err := h.orgService.UpdateOrganization(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// SwitchOrganization makes the organization the user's current one
+func (h *OrganizationHandler) SwitchOrganization(c *gin.Context) {
+	org, err := h.orgService.SwitchOrganization(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to switch organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var input models.AddOrgMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	member, err := h.orgService.AddMember(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to add organization member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	var input models.UpdateOrgMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	member, err := h.orgService.UpdateMember(c.Request.Context(), c.Param("id"), c.Param("userId"), input, c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update organization member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	err := h.orgService.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("userId"), c.GetString("userID"))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to remove organization member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondOrganizationError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrOrgNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+	case errors.Is(err, errs.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, errs.ErrNotInOrg):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not a member of this organization"})
+	case errors.Is(err, errs.ErrAlreadyInOrg):
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a member of this organization"})
+	case errors.Is(err, errs.ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": "An organization needs at least one admin"})
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only organization admins can do this"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
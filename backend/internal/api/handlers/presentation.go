@@ -0,0 +1,45 @@
+// Package handlers internal/api/handlers/presentation.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/services"
+)
+
+type PresentationHandler struct {
+	presentationService services.PresentationService
+}
+
+func NewPresentationHandler(presentationService services.PresentationService) *PresentationHandler {
+	return &PresentationHandler{
+		presentationService: presentationService,
+	}
+}
+
+// GetPresentation handles retrieving everything a project's presentation
+// shows in one response
+func (h *PresentationHandler) GetPresentation(c *gin.Context) {
+	presentation, err := h.presentationService.GetPresentation(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondPresentationError(c, err, "Failed to get project presentation")
+		return
+	}
+
+	c.JSON(http.StatusOK, presentation)
+}
+
+func respondPresentationError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
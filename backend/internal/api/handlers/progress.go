@@ -0,0 +1,73 @@
+// Package handlers internal/api/handlers/progress.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type ProgressHandler struct {
+	progressService services.ProgressService
+}
+
+func NewProgressHandler(progressService services.ProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+	}
+}
+
+// GetProgress handles retrieving how a project's progress is made up
+func (h *ProgressHandler) GetProgress(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	breakdown, err := h.progressService.GetProgress(c.Request.Context(), projectID, userID)
+	if err != nil {
+		respondProgressError(c, err, "Failed to get project progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// UpdateSettings handles switching a project between manual and computed
+// progress and tuning the computation
+func (h *ProgressHandler) UpdateSettings(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.UpdateProgressSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.progressService.UpdateSettings(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondProgressError(c, err, "Failed to update progress settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func respondProgressError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage this project's progress"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/services"
 )
 
@@ -32,7 +33,16 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	project, err := h.projectService.CreateProject(c.Request.Context(), input, userID)
 	if err != nil {
 		log.Printf("Error creating project: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project: " + err.Error()})
+		switch {
+		case errors.Is(err, errs.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Guests cannot create projects"})
+		case errors.Is(err, errs.ErrInvalidStatus):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project status"})
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project: " + err.Error()})
+		}
 		return
 	}
 
@@ -76,6 +86,10 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this project"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
 		}
@@ -108,13 +122,43 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 func (h *ProjectHandler) ListProjects(c *gin.Context) {
 	userID := c.GetString("userID")
 
-	projects, err := h.projectService.ListProjects(c.Request.Context(), userID)
+	q, err := pagination.Parse(c.Request.URL.Query(), models.ProjectListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.projectService.ListProjects(c.Request.Context(), userID, q)
 	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list projects"})
 		return
 	}
 
-	c.JSON(http.StatusOK, projects)
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	project, err := h.projectService.ArchiveProject(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondArchiveError(c, err, "Failed to archive project")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	project, err := h.projectService.UnarchiveProject(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondArchiveError(c, err, "Failed to unarchive project")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
 }
 
 func (h *ProjectHandler) AddTeamMember(c *gin.Context) {
@@ -145,6 +189,8 @@ func (h *ProjectHandler) AddTeamMember(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to modify team"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		case errors.Is(err, errs.ErrAlreadyInTeam):
 			c.JSON(http.StatusConflict, gin.H{"error": "User is already a team member"})
 		default:
@@ -176,10 +222,14 @@ func (h *ProjectHandler) RemoveTeamMember(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		case errors.Is(err, errs.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to modify team"})
+		case errors.Is(err, errs.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		case errors.Is(err, errs.ErrNotInTeam):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "User is not a team member"})
-		case errors.Is(err, errs.ErrCannotRemoveOwner):
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot remove project owner from team"})
+		case errors.Is(err, errs.ErrLastOwner):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot remove the last project owner"})
+		case errors.Is(err, errs.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "The project's owners were changed by someone else; reload and try again"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to remove team member",
@@ -191,3 +241,85 @@ func (h *ProjectHandler) RemoveTeamMember(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+func (h *ProjectHandler) RequestOwnershipTransfer(c *gin.Context) {
+	var input models.TransferOwnershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: userId is required"})
+		return
+	}
+
+	project, err := h.projectService.RequestOwnershipTransfer(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondOwnershipError(c, err, "Failed to request ownership transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) AcceptOwnershipTransfer(c *gin.Context) {
+	project, err := h.projectService.AcceptOwnershipTransfer(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondOwnershipError(c, err, "Failed to accept ownership transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) DeclineOwnershipTransfer(c *gin.Context) {
+	if err := h.projectService.DeclineOwnershipTransfer(c.Request.Context(), c.Param("id"), c.GetString("userID")); err != nil {
+		respondOwnershipError(c, err, "Failed to decline ownership transfer")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) CancelOwnershipTransfer(c *gin.Context) {
+	if err := h.projectService.CancelOwnershipTransfer(c.Request.Context(), c.Param("id"), c.GetString("userID")); err != nil {
+		respondOwnershipError(c, err, "Failed to cancel ownership transfer")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondOwnershipError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, errs.ErrNoTransfer):
+		c.JSON(http.StatusNotFound, gin.H{"error": "No ownership transfer is pending for you"})
+	case errors.Is(err, errs.ErrTransferPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "An ownership transfer is already pending"})
+	case errors.Is(err, errs.ErrLastOwner):
+		c.JSON(http.StatusConflict, gin.H{"error": "A project needs at least one owner"})
+	case errors.Is(err, errs.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "The project's owners were changed by someone else; reload and try again"})
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project owners can transfer ownership"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+func respondArchiveError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project owners can archive a project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
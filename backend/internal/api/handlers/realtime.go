@@ -0,0 +1,98 @@
+// Package handlers internal/api/handlers/realtime.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"strings"
+	"time"
+)
+
+type RealtimeHandler struct {
+	realtimeService services.RealtimeService
+}
+
+func NewRealtimeHandler(realtimeService services.RealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{
+		realtimeService: realtimeService,
+	}
+}
+
+// StreamChanges keeps a server-sent events connection open and pushes the
+// creations, updates and deletions in the user's projects as they happen.
+// ?projects= and ?documents= narrow it to comma-separated IDs. Every change
+// carries an event ID; clients reconnecting with it in Last-Event-ID, or in
+// ?lastEventId=, get the changes they missed.
+func (h *RealtimeHandler) StreamChanges(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	input := models.ChangeStreamInput{
+		ProjectIDs:  splitIDs(c.Query("projects")),
+		DocumentIDs: splitIDs(c.Query("documents")),
+		LastEventID: lastEventID,
+	}
+	messages, err := h.realtimeService.Stream(ctx, c.GetString("userID"), c.GetString("token"), input)
+	if err != nil {
+		respondRealtimeError(c, err, "Failed to open change stream")
+		return
+	}
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keeps nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{Id: msg.ID, Event: msg.Event, Data: msg.Data})
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+// splitIDs splits a comma-separated list of IDs, skipping empty ones
+func splitIDs(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func respondRealtimeError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to follow this project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,124 @@
+// Package handlers internal/api/handlers/share.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+// SharePasswordHeader carries the password of a protected share link
+const SharePasswordHeader = "X-Share-Password"
+
+type ShareHandler struct {
+	shareService services.ShareService
+}
+
+func NewShareHandler(shareService services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// CreateProjectLink handles sharing a project's presentation. The response
+// is the only one that includes the link's token and address.
+func (h *ShareHandler) CreateProjectLink(c *gin.Context) {
+	var input models.CreateShareLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.shareService.CreateProjectLink(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondShareError(c, err, "Failed to create share link")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// CreateDocumentLink handles sharing a single document
+func (h *ShareHandler) CreateDocumentLink(c *gin.Context) {
+	var input models.CreateShareLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.shareService.CreateDocumentLink(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondShareError(c, err, "Failed to create share link")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListLinks handles retrieving a project's share links with their view
+// counts
+func (h *ShareHandler) ListLinks(c *gin.Context) {
+	links, err := h.shareService.ListLinks(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondShareError(c, err, "Failed to get share links")
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// RevokeLink handles stopping a share link from working
+func (h *ShareHandler) RevokeLink(c *gin.Context) {
+	link, err := h.shareService.RevokeLink(c.Request.Context(), c.Param("id"), c.Param("shareId"), c.GetString("userID"))
+	if err != nil {
+		respondShareError(c, err, "Failed to revoke share link")
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// ViewShared handles showing a share link to someone who is not signed in.
+// Protected links take their password in the X-Share-Password header.
+func (h *ShareHandler) ViewShared(c *gin.Context) {
+	view, err := h.shareService.View(c.Request.Context(), c.Param("token"), c.GetHeader(SharePasswordHeader))
+	if err != nil {
+		respondShareError(c, err, "Failed to open share link")
+		return
+	}
+
+	// Shared content is read by people outside the organization; keep it
+	// out of shared caches
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, view)
+}
+
+func respondShareError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+	case errors.Is(err, errs.ErrShareLinkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+	case errors.Is(err, errs.ErrShareLinkInactive):
+		c.JSON(http.StatusGone, gin.H{"error": "This share link has expired or was revoked"})
+	case errors.Is(err, errs.ErrSharePasswordRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This share link needs a password", "passwordRequired": true})
+	case errors.Is(err, errs.ErrSharePasswordInvalid):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Wrong password", "passwordRequired": true})
+	case errors.Is(err, errs.ErrShareTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many password attempts, try again later"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to share this project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,64 @@
+// Package handlers internal/api/handlers/stats.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type StatsHandler struct {
+	statsService services.StatsService
+}
+
+func NewStatsHandler(statsService services.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetDashboardStats handles retrieving the statistics of the user's projects
+// over ?window=7d, 30d (the default), 90d or 365d
+func (h *StatsHandler) GetDashboardStats(c *gin.Context) {
+	window := models.StatsWindow(c.Query("window"))
+
+	stats, err := h.statsService.GetDashboardStats(c.Request.Context(), c.GetString("userID"), window)
+	if err != nil {
+		respondStatsError(c, err, "Failed to get dashboard statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetProjectMetrics handles retrieving the metrics of a project over the
+// same windows
+func (h *StatsHandler) GetProjectMetrics(c *gin.Context) {
+	window := models.StatsWindow(c.Query("window"))
+
+	stats, err := h.statsService.GetProjectMetrics(c.Request.Context(), c.Param("id"), c.GetString("userID"), window)
+	if err != nil {
+		respondStatsError(c, err, "Failed to get project metrics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func respondStatsError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this project"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,193 @@
+// Package handlers internal/api/handlers/task.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type TaskHandler struct {
+	taskService services.TaskService
+}
+
+func NewTaskHandler(taskService services.TaskService) *TaskHandler {
+	return &TaskHandler{
+		taskService: taskService,
+	}
+}
+
+// GetBoard handles retrieving a project's Kanban board with its tasks
+func (h *TaskHandler) GetBoard(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	board, err := h.taskService.GetBoard(c.Request.Context(), projectID, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to get board")
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// UpdateBoardColumns handles adding, renaming, reordering and removing columns
+func (h *TaskHandler) UpdateBoardColumns(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.UpdateBoardColumnsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := h.taskService.UpdateBoardColumns(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to update board columns")
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// ListTasks handles retrieving a project's tasks, optionally filtered by
+// ?column=, ?assignee=, ?milestone= and ?priority=
+func (h *TaskHandler) ListTasks(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	filter := models.TaskFilter{
+		ColumnID:    c.Query("column"),
+		AssigneeID:  c.Query("assignee"),
+		MilestoneID: c.Query("milestone"),
+		Priority:    models.TaskPriority(c.Query("priority")),
+	}
+
+	tasks, err := h.taskService.ListTasks(c.Request.Context(), projectID, filter, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to get tasks")
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+// GetTask handles retrieving a single task
+func (h *TaskHandler) GetTask(c *gin.Context) {
+	projectID := c.Param("id")
+	taskID := c.Param("taskId")
+	userID := c.GetString("userID")
+
+	task, err := h.taskService.GetTask(c.Request.Context(), projectID, taskID, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to get task")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// CreateTask handles adding a task to a project's board
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.CreateTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.CreateTask(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to create task")
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// UpdateTask handles editing a task's details
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	projectID := c.Param("id")
+	taskID := c.Param("taskId")
+	userID := c.GetString("userID")
+
+	var input models.UpdateTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.UpdateTask(c.Request.Context(), projectID, taskID, input, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to update task")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// MoveTask handles dragging a task within or between columns
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	projectID := c.Param("id")
+	taskID := c.Param("taskId")
+	userID := c.GetString("userID")
+
+	var input models.MoveTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.taskService.MoveTask(c.Request.Context(), projectID, taskID, input, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to move task")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// DeleteTask handles removing a task
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	projectID := c.Param("id")
+	taskID := c.Param("taskId")
+	userID := c.GetString("userID")
+
+	if err := h.taskService.DeleteTask(c.Request.Context(), projectID, taskID, userID); err != nil {
+		respondTaskError(c, err, "Failed to delete task")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondTaskError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, errs.ErrColumnNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Board column not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage tasks in this project"})
+	case errors.Is(err, errs.ErrColumnNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": "Move the tasks out of a column before removing it"})
+	case errors.Is(err, errs.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "The board was changed by someone else; reload and try again"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
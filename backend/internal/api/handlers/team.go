@@ -6,6 +6,7 @@ import (
 	"net/http"
 	internalerrors "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +31,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	userID := c.GetString("userID")
 	team, err := h.teamService.CreateTeam(c.Request.Context(), input, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team"})
+		respondTeamError(c, err, "Failed to create team")
 		return
 	}
 
@@ -38,19 +39,26 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 }
 
 func (h *TeamHandler) GetAllTeams(c *gin.Context) {
-	teams, err := h.teamService.GetAllTeams(c.Request.Context())
+	q, err := pagination.Parse(c.Request.URL.Query(), models.TeamListSpec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teams, err := h.teamService.GetAllTeams(c.Request.Context(), q)
+	if err != nil {
+		respondTeamError(c, err, "Failed to fetch teams")
 		return
 	}
 
 	c.JSON(http.StatusOK, teams)
 }
+
 func (h *TeamHandler) GetTeam(c *gin.Context) {
 	teamID := c.Param("id")
-	team, err := h.teamService.GetTeamByID(c.Request.Context(), teamID)
+	team, err := h.teamService.GetTeamByID(c.Request.Context(), teamID, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		respondTeamError(c, err, "Failed to fetch team")
 		return
 	}
 
@@ -65,9 +73,9 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.UpdateTeam(c.Request.Context(), teamID, input)
+	team, err := h.teamService.UpdateTeam(c.Request.Context(), teamID, input, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team"})
+		respondTeamError(c, err, "Failed to update team")
 		return
 	}
 
@@ -76,9 +84,148 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 
 func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	teamID := c.Param("id")
-	err := h.teamService.DeleteTeam(c.Request.Context(), teamID)
+	err := h.teamService.DeleteTeam(c.Request.Context(), teamID, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete team"})
+		respondTeamError(c, err, "Failed to delete team")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
+	teamID := c.Param("id")
+	members, err := h.teamService.GetTeamMembers(c.Request.Context(), teamID, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to fetch team members")
+		return
+	}
+
+	if members == nil {
+		members = make([]models.TeamMembership, 0)
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// GetMember handles retrieving one member of a standalone team
+func (h *TeamHandler) GetMember(c *gin.Context) {
+	member, err := h.teamService.GetMember(c.Request.Context(), c.Param("id"), c.Param("userId"), c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to fetch team member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// AddMember handles adding a user to a standalone team
+func (h *TeamHandler) AddMember(c *gin.Context) {
+	teamID := c.Param("id")
+
+	var input models.AddTeamMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	member, err := h.teamService.AddMember(c.Request.Context(), teamID, input, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to add team member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember handles changing a team member's role
+func (h *TeamHandler) UpdateMember(c *gin.Context) {
+	teamID := c.Param("id")
+	userID := c.Param("userId")
+
+	var input models.UpdateTeamMembershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	member, err := h.teamService.UpdateMember(c.Request.Context(), teamID, userID, input, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to update team member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles removing a user from a standalone team
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	teamID := c.Param("id")
+	userID := c.Param("userId")
+
+	err := h.teamService.RemoveMember(c.Request.Context(), teamID, userID, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to remove team member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetProjectTeams lists the teams assigned to a project
+func (h *TeamHandler) GetProjectTeams(c *gin.Context) {
+	assignments, err := h.teamService.GetProjectTeams(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to get project teams")
+		return
+	}
+
+	if assignments == nil {
+		assignments = make([]models.ProjectTeamAssignment, 0)
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// AssignTeam handles attaching a team to a project with a default role
+func (h *TeamHandler) AssignTeam(c *gin.Context) {
+	var input models.AssignTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	assignment, err := h.teamService.AssignTeam(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to assign team")
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// UpdateTeamAssignment handles changing the default role of an assigned team
+func (h *TeamHandler) UpdateTeamAssignment(c *gin.Context) {
+	var input models.UpdateTeamAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	assignment, err := h.teamService.UpdateTeamAssignment(c.Request.Context(), c.Param("id"), c.Param("teamId"), input, c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to update team assignment")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// UnassignTeam handles detaching a team from a project
+func (h *TeamHandler) UnassignTeam(c *gin.Context) {
+	err := h.teamService.UnassignTeam(c.Request.Context(), c.Param("id"), c.Param("teamId"), c.GetString("userID"))
+	if err != nil {
+		respondTeamError(c, err, "Failed to unassign team")
 		return
 	}
 
@@ -107,8 +254,12 @@ func (h *TeamHandler) AddTeamMember(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		case errors.Is(err, internalerrors.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to add team members"})
+		case errors.Is(err, internalerrors.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
 		case errors.Is(err, internalerrors.ErrAlreadyInTeam):
 			c.JSON(http.StatusConflict, gin.H{"error": "User is already a team member"})
+		case errors.Is(err, internalerrors.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "The project's owners were changed by someone else; reload and try again"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add team member"})
 		}
@@ -144,6 +295,14 @@ func (h *TeamHandler) UpdateTeamMember(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team member not found"})
 		case errors.Is(err, internalerrors.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update team members"})
+		case errors.Is(err, internalerrors.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+		case errors.Is(err, internalerrors.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, internalerrors.ErrLastOwner):
+			c.JSON(http.StatusConflict, gin.H{"error": "A project needs at least one owner"})
+		case errors.Is(err, internalerrors.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "The project's owners were changed by someone else; reload and try again"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team member"})
 		}
@@ -168,8 +327,12 @@ func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team member not found"})
 		case errors.Is(err, internalerrors.ErrUnauthorized):
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to remove team members"})
-		case errors.Is(err, internalerrors.ErrCannotRemoveOwner):
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot remove project owner from team"})
+		case errors.Is(err, internalerrors.ErrProjectArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+		case errors.Is(err, internalerrors.ErrLastOwner):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot remove the last project owner"})
+		case errors.Is(err, internalerrors.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "The project's owners were changed by someone else; reload and try again"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove team member"})
 		}
@@ -184,13 +347,17 @@ func (h *TeamHandler) GetTeamMember(c *gin.Context) {
 	projectID := c.Param("id")      // Get project ID from URL
 	memberID := c.Param("memberId") // Changed from id to memberId
 
-	member, err := h.teamService.GetTeamMember(c.Request.Context(), projectID, memberID)
+	member, err := h.teamService.GetTeamMember(c.Request.Context(), projectID, memberID, c.GetString("userID"))
 	if err != nil {
 		log.Printf("Error getting team member: %v", err)
 
 		switch {
 		case errors.Is(err, internalerrors.ErrNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team member not found"})
+		case errors.Is(err, internalerrors.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, internalerrors.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this project"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get team member"})
 		}
@@ -204,13 +371,15 @@ func (h *TeamHandler) GetTeamMember(c *gin.Context) {
 func (h *TeamHandler) GetProjectTeam(c *gin.Context) {
 	projectID := c.Param("id") // Changed from projectId to id
 
-	members, err := h.teamService.GetProjectTeam(c.Request.Context(), projectID)
+	members, err := h.teamService.GetProjectTeam(c.Request.Context(), projectID, c.GetString("userID"))
 	if err != nil {
 		log.Printf("Error getting project team: %v", err)
 
 		switch {
-		case errors.Is(err, internalerrors.ErrNotFound):
+		case errors.Is(err, internalerrors.ErrNotFound), errors.Is(err, internalerrors.ErrProjectNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, internalerrors.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this project"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project team"})
 		}
@@ -224,14 +393,34 @@ func (h *TeamHandler) GetProjectTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, members)
 }
 
-func (h *TeamHandler) GetTeamMembers(context *gin.Context) {
-	teamID := context.Param("id")
-	members, err := h.teamService.GetTeamMembers(context.Request.Context(), teamID)
-	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team members"})
-		return
+func respondTeamError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, internalerrors.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	case errors.Is(err, internalerrors.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, internalerrors.ErrTeamNotAssigned):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team is not assigned to this project"})
+	case errors.Is(err, internalerrors.ErrTeamAssigned):
+		c.JSON(http.StatusConflict, gin.H{"error": "Team is already assigned to this project"})
+	case errors.Is(err, internalerrors.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, internalerrors.ErrNotInTeam):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not a team member"})
+	case errors.Is(err, internalerrors.ErrInvalidTeamLead):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Team lead must be an existing user"})
+	case errors.Is(err, internalerrors.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, internalerrors.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage this team"})
+	case errors.Is(err, internalerrors.ErrAlreadyInTeam):
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a team member"})
+	case errors.Is(err, internalerrors.ErrCannotRemoveLead):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assign a new team lead before removing the current one"})
+	case errors.Is(err, internalerrors.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
 	}
-
-	context.JSON(http.StatusOK, members)
-
 }
@@ -0,0 +1,177 @@
+// Package handlers internal/api/handlers/template.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+)
+
+type TemplateHandler struct {
+	templateService services.TemplateService
+}
+
+func NewTemplateHandler(templateService services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// ListTemplates handles retrieving the templates of the organization
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondTemplateError(c, err, "Failed to get templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate handles retrieving a single template
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondTemplateError(c, err, "Failed to get template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate handles creating a template from starter documents
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var input models.TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), input, userID)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to create template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// SaveAsTemplate handles turning a project into a template
+func (h *TemplateHandler) SaveAsTemplate(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.SaveTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.SaveAsTemplate(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to save template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate handles replacing the content of a template
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var input models.TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), c.Param("id"), input, userID)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to update template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate handles removing a template
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), c.Param("id"), userID); err != nil {
+		respondTemplateError(c, err, "Failed to delete template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// UseTemplate handles creating a project from a template
+func (h *TemplateHandler) UseTemplate(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var input models.UseTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.templateService.UseTemplate(c.Request.Context(), c.Param("id"), input, userID)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to create project from template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// CloneProject handles copying a project; without a body it copies the
+// latest documents, mockups and members
+func (h *TemplateHandler) CloneProject(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var input models.CloneProjectInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.templateService.CloneProject(c.Request.Context(), projectID, input, userID)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to clone project")
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func respondTemplateError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrInvalidStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project status"})
+	case errors.Is(err, errs.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrUserNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template member not found"})
+	case errors.Is(err, errs.ErrTeamNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template team not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -44,6 +44,43 @@ func (m *MockAuthService) ValidateToken(token string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockAuthService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) IssueStreamTicket(ctx context.Context, token string, orgID string) (*models.StreamTicket, error) {
+	args := m.Called(ctx, token, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StreamTicket), args.Error(1)
+}
+
+func (m *MockAuthService) RedeemStreamTicket(ctx context.Context, ticket string) (*models.User, *models.StreamSession, error) {
+	args := m.Called(ctx, ticket)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.User), args.Get(1).(*models.StreamSession), args.Error(2)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,110 @@
+// Package handlers internal/api/handlers/trash.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/services"
+)
+
+type TrashHandler struct {
+	trashService services.TrashService
+}
+
+func NewTrashHandler(trashService services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListDeletedProjects handles retrieving the user's projects in the trash
+func (h *TrashHandler) ListDeletedProjects(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	projects, err := h.trashService.ListDeletedProjects(c.Request.Context(), userID)
+	if err != nil {
+		respondTrashError(c, err, "Failed to list deleted projects")
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+// ListProjectTrash handles retrieving the deleted documents and mockups of a project
+func (h *TrashHandler) ListProjectTrash(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	trash, err := h.trashService.ListProjectTrash(c.Request.Context(), projectID, userID)
+	if err != nil {
+		respondTrashError(c, err, "Failed to get project trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreProject handles restoring a project and everything deleted with it
+func (h *TrashHandler) RestoreProject(c *gin.Context) {
+	projectID := c.Param("id")
+	userID := c.GetString("userID")
+
+	project, err := h.trashService.RestoreProject(c.Request.Context(), projectID, userID)
+	if err != nil {
+		respondTrashError(c, err, "Failed to restore project")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// RestoreDocument handles restoring a deleted document
+func (h *TrashHandler) RestoreDocument(c *gin.Context) {
+	documentID := c.Param("id")
+	userID := c.GetString("userID")
+
+	doc, err := h.trashService.RestoreDocument(c.Request.Context(), documentID, userID)
+	if err != nil {
+		respondTrashError(c, err, "Failed to restore document")
+		return
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
+
+// RestoreMockup handles restoring a deleted mockup
+func (h *TrashHandler) RestoreMockup(c *gin.Context) {
+	mockupID := c.Param("id")
+	userID := c.GetString("userID")
+
+	mockup, err := h.trashService.RestoreMockup(c.Request.Context(), mockupID, userID)
+	if err != nil {
+		respondTrashError(c, err, "Failed to restore mockup")
+		return
+	}
+
+	c.JSON(http.StatusOK, mockup)
+}
+
+func respondTrashError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found in trash"})
+	case errors.Is(err, errs.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mockup not found in trash"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to restore this item"})
+	case errors.Is(err, errs.ErrProjectInTrash):
+		c.JSON(http.StatusConflict, gin.H{"error": "Restore the project before restoring its items"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
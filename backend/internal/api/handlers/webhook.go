@@ -0,0 +1,150 @@
+// Package handlers internal/api/handlers/webhook.go
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/services"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks handles retrieving a project's webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to get webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhook handles retrieving a single webhook
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// CreateWebhook handles subscribing a URL to project events. The response
+// is the only one that includes the signing secret.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input models.CreateWebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), c.Param("id"), input, c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// UpdateWebhook handles changing a webhook's URL, events or active state
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var input models.UpdateWebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), input, c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles removing a webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.GetString("userID")); err != nil {
+		respondWebhookError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles retrieving one page of a webhook's delivery log,
+// optionally filtered by ?status=
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	q, err := pagination.Parse(c.Request.URL.Query(), models.WebhookDeliveryListSpec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.GetString("userID"), q)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to get webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetDelivery handles retrieving a delivery with its attempts
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.Param("deliveryId"), c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to get webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver handles sending the payload of a delivery again
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.Param("deliveryId"), c.GetString("userID"))
+	if err != nil {
+		respondWebhookError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func respondWebhookError(c *gin.Context, err error, message string) {
+	log.Printf("%s: %v", message, err)
+	switch {
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errs.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, errs.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, errs.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook delivery not found"})
+	case errors.Is(err, errs.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project owners can manage webhooks"})
+	case errors.Is(err, errs.ErrProjectArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project is archived"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
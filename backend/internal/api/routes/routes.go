@@ -2,18 +2,29 @@
 package routes
 
 import (
+	"context"
+	"log"
 	"projectnexus/internal/api/handlers"
 	"projectnexus/internal/config"
+	"projectnexus/internal/events"
+	"projectnexus/internal/mail"
 	"projectnexus/internal/middleware"
+	"projectnexus/internal/realtime"
 	"projectnexus/internal/repository"
 	mongorepo "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/safehttp"
 	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.TokenStore) {
+// SetupRouter registers the routes and starts the background jobs, which stop
+// once ctx is cancelled. The returned function waits for them to finish.
+func SetupRouter(ctx context.Context, router *gin.Engine, db *mongo.Database, tokenStore repository.TokenStore, attempts repository.AttemptStore, bus *events.Bus) (wait func()) {
 	// Initialize repositories
 	userRepo := mongorepo.NewUserRepository(db)
 	projectRepo := mongorepo.NewProjectRepository(db)
@@ -21,21 +32,131 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 	teamRepo := mongorepo.NewTeamRepository(db)
 	teamMemberRepo := mongorepo.NewTeamMemberRepository(db)
 	mockupRepo := mongorepo.NewMockupRepository(db)
+	linkRepo := mongorepo.NewLinkRepository(db)
+	folderRepo := mongorepo.NewFolderRepository(db)
+	milestoneRepo := mongorepo.NewMilestoneRepository(db)
+	taskRepo := mongorepo.NewTaskRepository(db)
+	boardRepo := mongorepo.NewBoardRepository(db)
+	templateRepo := mongorepo.NewTemplateRepository(db)
+	invitationRepo := mongorepo.NewInvitationRepository(db)
+	orgRepo := mongorepo.NewOrganizationRepository(db)
+	notificationRepo := mongorepo.NewNotificationRepository(db)
+	webhookRepo := mongorepo.NewWebhookRepository(db)
+	webhookDeliveryRepo := mongorepo.NewWebhookDeliveryRepository(db)
+	auditRepo := mongorepo.NewAuditRepository(db)
+	activityRepo := mongorepo.NewActivityRepository(db)
+	statsRepo := mongorepo.NewStatsRepository(db)
+	shareRepo := mongorepo.NewShareLinkRepository(db)
+	txManager := mongorepo.NewTxManager(db.Client())
 
 	// Initialize services
 	config_ := config.Load()
-	authService := services.NewAuthService(userRepo, config_.JWTSecret, tokenStore)
-	projectService := services.NewProjectService(projectRepo, userRepo)
-	documentService := services.NewDocumentService(documentRepo, projectRepo)
-	teamService := services.NewTeamService(teamRepo, teamMemberRepo, projectRepo, userRepo)
-	mockupService := services.NewMockupService(mockupRepo, projectRepo)
+	var mailer mail.Mailer = mail.NewLogMailer()
+	if config_.SMTP.Host != "" {
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     config_.SMTP.Host,
+			Port:     config_.SMTP.Port,
+			Username: config_.SMTP.Username,
+			Password: config_.SMTP.Password,
+			From:     config_.MailFrom,
+		})
+	}
+	// Notification emails are sent in the background by a few workers
+	emails := mail.NewQueue(mailer, 1000, 4, 30*time.Second)
+	hub := realtime.NewHub()
+	feed := realtime.NewFeed()
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, hub, emails, bus, config_.JWTSecret, config_.AppURL)
+	digestService := services.NewDigestService(userRepo, projectRepo, documentRepo, notificationRepo, mailer, config_.JWTSecret, config_.AppURL, config_.DigestHour)
+	// Webhook URLs are chosen by users, so requests only go to public addresses
+	webhookService := services.NewWebhookService(webhookRepo, webhookDeliveryRepo, projectRepo, bus, safehttp.NewClient(10*time.Second))
+	orgService := services.NewOrganizationService(orgRepo, userRepo, bus, config_.DefaultOrgAdmin)
+	teamService := services.NewTeamService(teamRepo, teamMemberRepo, projectRepo, userRepo, orgRepo, notificationService, bus, txManager)
+	invitationService := services.NewInvitationService(invitationRepo, projectRepo, teamRepo, userRepo, teamService, mailer, bus, txManager,
+		config_.JWTSecret, time.Duration(config_.InvitationTTLHours)*time.Hour, config_.AppURL)
+	// New users get a workspace and join whatever they were invited to before registering
+	authService := services.NewAuthService(userRepo, config_.JWTSecret, tokenStore, orgService, invitationService)
+	linkService := services.NewLinkService(linkRepo, documentRepo, mockupRepo, projectRepo, bus)
+	projectService := services.NewProjectService(projectRepo, userRepo, documentRepo, mockupRepo, teamMemberRepo, orgRepo, linkService, notificationService, bus, txManager)
+	progressService := services.NewProgressService(projectRepo, documentRepo, mockupRepo, bus)
+	documentService := services.NewDocumentService(documentRepo, projectRepo, folderRepo, linkService, progressService, notificationService, bus, txManager)
+	folderService := services.NewFolderService(folderRepo, documentRepo, projectRepo, bus, txManager)
+	mockupService := services.NewMockupService(mockupRepo, projectRepo, linkService, progressService, bus)
+	milestoneService := services.NewMilestoneService(milestoneRepo, taskRepo, projectRepo, bus, txManager)
+	taskService := services.NewTaskService(taskRepo, boardRepo, projectRepo, milestoneRepo, documentRepo, bus, txManager)
+	templateService := services.NewTemplateService(templateRepo, projectRepo, documentRepo, mockupRepo, teamMemberRepo, linkRepo, userRepo, orgRepo, projectService, teamService, linkService, progressService, notificationService, bus, txManager)
+	auditService := services.NewAuditService(auditRepo, projectRepo, userRepo)
+	activityService := services.NewActivityService(activityRepo, projectRepo, userRepo, teamRepo)
+	statsService := services.NewStatsService(statsRepo, projectRepo, userRepo)
+	presentationService := services.NewPresentationService(projectRepo, teamMemberRepo, userRepo, documentRepo, mockupRepo, milestoneRepo)
+	realtimeService := services.NewRealtimeService(feed, authService, projectRepo, documentRepo, orgRepo)
+	shareService := services.NewShareService(shareRepo, projectRepo, teamMemberRepo, documentRepo, mockupRepo, attempts, bus, config_.AppURL)
+	trashService := services.NewTrashService(projectRepo, documentRepo, mockupRepo, teamMemberRepo, folderRepo, milestoneRepo, taskRepo, boardRepo, webhookRepo, webhookDeliveryRepo, linkService, progressService, bus, txManager, config_.TrashRetentionDays)
+
+	// Migrations and background jobs work across organizations, which
+	// repositories only allow when asked to
+	jobs := tenant.Unscoped(ctx)
+	var running sync.WaitGroup
+	background := func(job func(ctx context.Context)) {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			job(jobs)
+		}()
+	}
+
+	// Teams kept among project members move to their own collection first,
+	// so the organization migration below adopts them too
+	if err := teamService.MigrateLegacyTeams(jobs); err != nil {
+		log.Printf("Warning: Failed to migrate legacy teams: %v", err)
+	}
+	// Data from before organizations existed moves into a default one
+	if err := orgService.MigrateLegacyData(jobs); err != nil {
+		log.Printf("Warning: Failed to migrate data into organizations: %v", err)
+	}
+	// Documents saved before authors, editors and approvals were kept apart
+	if err := documentService.MigrateHistory(jobs); err != nil {
+		log.Printf("Warning: Failed to fill in document history: %v", err)
+	}
+
+	background(emails.Run)
+	// Permanently remove trashed items once their retention period is over
+	background(func(ctx context.Context) { trashService.RunPurger(ctx, time.Hour) })
+	// Email the daily and weekly summaries when they are due
+	background(func(ctx context.Context) { digestService.RunDigests(ctx, time.Hour) })
+	// Queue webhook deliveries for the events they subscribe to
+	webhookService.Subscribe(bus)
+	// Record every change in the audit log
+	auditService.Subscribe(bus)
+	// Build the activity feeds from the same changes
+	activityService.Subscribe(bus)
+	// Push changes to the clients following them
+	realtimeService.Subscribe(bus)
+	// Send queued webhooks and retry failed ones when their time comes
+	background(func(ctx context.Context) { webhookService.RunDispatcher(ctx, 15*time.Second) })
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	projectHandler := handlers.NewProjectHandler(projectService)
+	progressHandler := handlers.NewProgressHandler(progressService)
 	documentHandler := handlers.NewDocumentHandler(documentService)
 	teamHandler := handlers.NewTeamHandler(teamService)
 	mockupHandler := handlers.NewMockupHandler(mockupService)
+	linkHandler := handlers.NewLinkHandler(linkService)
+	folderHandler := handlers.NewFolderHandler(folderService)
+	milestoneHandler := handlers.NewMilestoneHandler(milestoneService)
+	taskHandler := handlers.NewTaskHandler(taskService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	organizationHandler := handlers.NewOrganizationHandler(orgService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	activityHandler := handlers.NewActivityHandler(activityService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	presentationHandler := handlers.NewPresentationHandler(presentationService)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeService)
+	shareHandler := handlers.NewShareHandler(shareService)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -45,8 +166,10 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 		})
 	})
 
-	// API v1 routes
+	// API v1 routes; the client address and agent of every request go to
+	// the audit log with the changes it makes
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.RequestMeta())
 	{
 		// Auth routes (public)
 		auth := v1.Group("/auth")
@@ -57,9 +180,32 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 			auth.POST("/logout", authHandler.Logout)
 		}
 
+		// Declining an invitation works without an account
+		v1.POST("/invitations/decline", invitationHandler.DeclineInvitation)
+		// Unsubscribe links in emails work without signing in
+		v1.POST("/notifications/unsubscribe", notificationHandler.Unsubscribe)
+
+		// Share links are viewed without an account
+		public := v1.Group("/public")
+		{
+			public.GET("/shares/:token", shareHandler.ViewShared)
+		}
+
+		// Event streams, which browsers open with EventSource and so
+		// authenticate with a ticket instead of the Authorization header
+		streams := v1.Group("")
+		streams.Use(middleware.StreamAuthMiddleware(authService), middleware.TenantMiddleware(orgService))
+		{
+			// Changes to the projects and documents the user can access, as
+			// server-sent events
+			streams.GET("/changes/stream", realtimeHandler.StreamChanges)
+			// The user's new notifications and unread counts
+			streams.GET("/notifications/stream", notificationHandler.StreamNotifications)
+		}
+
 		// Protected routes
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(authService))
+		protected.Use(middleware.AuthMiddleware(authService), middleware.TenantMiddleware(orgService))
 		{
 			// User routes
 			user := protected.Group("/users")
@@ -67,14 +213,125 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 				user.GET("/me", authHandler.GetMe)
 			}
 
+			protected.POST("/invitations/accept", invitationHandler.AcceptInvitation)
+
+			// Notifications of the current user in the current organization
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.ListNotifications)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.POST("/read-all", notificationHandler.MarkAllRead)
+				notifications.POST("/:id/read", notificationHandler.MarkRead)
+				notifications.GET("/preferences", notificationHandler.GetPreferences)
+				notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+			}
+
+			// Organizations; requests act in the current one, or the one
+			// named by the X-Organization-ID header
+			organizations := protected.Group("/organizations")
+			{
+				organizations.GET("", organizationHandler.ListOrganizations)
+				organizations.POST("", organizationHandler.CreateOrganization)
+				organizations.GET("/:id", organizationHandler.GetOrganization)
+				organizations.PUT("/:id", organizationHandler.UpdateOrganization)
+				organizations.POST("/:id/switch", organizationHandler.SwitchOrganization)
+				organizations.POST("/:id/members", organizationHandler.AddMember)
+				organizations.PUT("/:id/members/:userId", organizationHandler.UpdateMember)
+				organizations.DELETE("/:id/members/:userId", organizationHandler.RemoveMember)
+			}
+
+			// Activity in the user's projects and their statistics, for the dashboard
+			protected.GET("/activity", activityHandler.ListActivity)
+			protected.GET("/stats/dashboard", statsHandler.GetDashboardStats)
+
+			// One-time tickets to open the event streams below with
+			protected.POST("/streams/ticket", authHandler.IssueStreamTicket)
+
+			// Audit log of the current organization, for its admins
+			protected.GET("/audit", auditHandler.ListEntries)
+			protected.GET("/audit/export", auditHandler.ExportEntries)
+
 			// Project routes
 			projects := protected.Group("/projects")
 			{
 				projects.POST("", projectHandler.CreateProject)
 				projects.GET("", projectHandler.ListProjects)
+				projects.GET("/trash", trashHandler.ListDeletedProjects)
 				projects.GET("/:id", projectHandler.GetProject)
 				projects.PUT("/:id", projectHandler.UpdateProject)
 				projects.DELETE("/:id", projectHandler.DeleteProject)
+				projects.POST("/:id/restore", trashHandler.RestoreProject)
+				projects.POST("/:id/archive", projectHandler.ArchiveProject)
+				projects.POST("/:id/unarchive", projectHandler.UnarchiveProject)
+				projects.GET("/:id/trash", trashHandler.ListProjectTrash)
+				projects.GET("/:id/backlinks", linkHandler.GetProjectBacklinks)
+				projects.GET("/:id/links/broken", linkHandler.GetBrokenLinks)
+				projects.GET("/:id/tags", documentHandler.GetProjectTags)
+				projects.GET("/:id/progress", progressHandler.GetProgress)
+				projects.PUT("/:id/progress/settings", progressHandler.UpdateSettings)
+				projects.POST("/:id/clone", templateHandler.CloneProject)
+				projects.POST("/:id/template", templateHandler.SaveAsTemplate)
+
+				// Folder routes
+				folders := projects.Group("/:id/folders")
+				{
+					folders.GET("", folderHandler.ListFolders)
+					folders.POST("", folderHandler.CreateFolder)
+					folders.POST("/reorder", folderHandler.ReorderFolders)
+					folders.PUT("/:folderId", folderHandler.RenameFolder)
+					folders.POST("/:folderId/move", folderHandler.MoveFolder)
+					folders.DELETE("/:folderId", folderHandler.DeleteFolder)
+				}
+
+				// Milestone routes
+				milestones := projects.Group("/:id/milestones")
+				{
+					milestones.GET("", milestoneHandler.ListMilestones)
+					milestones.POST("", milestoneHandler.CreateMilestone)
+					milestones.GET("/:milestoneId", milestoneHandler.GetMilestone)
+					milestones.PUT("/:milestoneId", milestoneHandler.UpdateMilestone)
+					milestones.DELETE("/:milestoneId", milestoneHandler.DeleteMilestone)
+				}
+
+				projects.GET("/:id/activity", activityHandler.ListProjectActivity)
+				projects.GET("/:id/metrics", statsHandler.GetProjectMetrics)
+				projects.GET("/:id/presentation", presentationHandler.GetPresentation)
+
+				// Public read-only links to the presentation
+				shares := projects.Group("/:id/shares")
+				{
+					shares.GET("", shareHandler.ListLinks)
+					shares.POST("", shareHandler.CreateProjectLink)
+					shares.DELETE("/:shareId", shareHandler.RevokeLink)
+				}
+				projects.GET("/:id/audit", auditHandler.ListProjectEntries)
+				projects.GET("/:id/audit/export", auditHandler.ExportProjectEntries)
+
+				// Outbound webhooks and their delivery logs
+				webhooks := projects.Group("/:id/webhooks")
+				{
+					webhooks.GET("", webhookHandler.ListWebhooks)
+					webhooks.POST("", webhookHandler.CreateWebhook)
+					webhooks.GET("/:webhookId", webhookHandler.GetWebhook)
+					webhooks.PUT("/:webhookId", webhookHandler.UpdateWebhook)
+					webhooks.DELETE("/:webhookId", webhookHandler.DeleteWebhook)
+					webhooks.GET("/:webhookId/deliveries", webhookHandler.ListDeliveries)
+					webhooks.GET("/:webhookId/deliveries/:deliveryId", webhookHandler.GetDelivery)
+					webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+				}
+
+				// Kanban board and task routes
+				projects.GET("/:id/board", taskHandler.GetBoard)
+				projects.PUT("/:id/board/columns", taskHandler.UpdateBoardColumns)
+				tasks := projects.Group("/:id/tasks")
+				{
+					tasks.GET("", taskHandler.ListTasks)
+					tasks.POST("", taskHandler.CreateTask)
+					tasks.GET("/:taskId", taskHandler.GetTask)
+					tasks.PUT("/:taskId", taskHandler.UpdateTask)
+					tasks.POST("/:taskId/move", taskHandler.MoveTask)
+					tasks.DELETE("/:taskId", taskHandler.DeleteTask)
+				}
 
 				// Team routes - Using :id consistently
 				team := projects.Group("/:id/team")
@@ -85,6 +342,32 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 					team.PUT("/:memberId", teamHandler.UpdateTeamMember)
 					team.DELETE("/:memberId", teamHandler.RemoveTeamMember)
 				}
+
+				// Email invitations to the project
+				projectInvitations := projects.Group("/:id/invitations")
+				{
+					projectInvitations.GET("", invitationHandler.ListProjectInvitations)
+					projectInvitations.POST("", invitationHandler.InviteToProject)
+					projectInvitations.DELETE("/:invitationId", invitationHandler.RevokeProjectInvitation)
+				}
+
+				// Teams assigned to the project
+				projectTeams := projects.Group("/:id/teams")
+				{
+					projectTeams.GET("", teamHandler.GetProjectTeams)
+					projectTeams.POST("", teamHandler.AssignTeam)
+					projectTeams.PUT("/:teamId", teamHandler.UpdateTeamAssignment)
+					projectTeams.DELETE("/:teamId", teamHandler.UnassignTeam)
+				}
+
+				// Handing the project to another owner
+				transfer := projects.Group("/:id/ownership-transfer")
+				{
+					transfer.POST("", projectHandler.RequestOwnershipTransfer)
+					transfer.DELETE("", projectHandler.CancelOwnershipTransfer)
+					transfer.POST("/accept", projectHandler.AcceptOwnershipTransfer)
+					transfer.POST("/decline", projectHandler.DeclineOwnershipTransfer)
+				}
 			}
 			// Teams routes
 			teams := protected.Group("/teams")
@@ -99,10 +382,18 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 				members := teams.Group("/:id/members")
 				{
 					members.GET("", teamHandler.GetTeamMembers)
-					members.POST("", teamHandler.AddTeamMember)
-					members.GET("/:memberId", teamHandler.GetTeamMember)
-					members.PUT("/:memberId", teamHandler.UpdateTeamMember)
-					members.DELETE("/:memberId", teamHandler.RemoveTeamMember)
+					members.POST("", teamHandler.AddMember)
+					members.GET("/:userId", teamHandler.GetMember)
+					members.PUT("/:userId", teamHandler.UpdateMember)
+					members.DELETE("/:userId", teamHandler.RemoveMember)
+				}
+
+				// Email invitations to the team
+				teamInvitations := teams.Group("/:id/invitations")
+				{
+					teamInvitations.GET("", invitationHandler.ListTeamInvitations)
+					teamInvitations.POST("", invitationHandler.InviteToTeam)
+					teamInvitations.DELETE("/:invitationId", invitationHandler.RevokeTeamInvitation)
 				}
 				// Document routes
 				documents := protected.Group("/documents")
@@ -112,7 +403,11 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 					documents.GET("/:id", documentHandler.GetDocument)
 					documents.PUT("/:id", documentHandler.UpdateDocument)
 					documents.DELETE("/:id", documentHandler.DeleteDocument)
+					documents.POST("/:id/restore", trashHandler.RestoreDocument)
 					documents.GET("/:id/versions", documentHandler.GetDocumentVersions)
+					documents.GET("/:id/links", linkHandler.GetDocumentLinks)
+					documents.GET("/:id/backlinks", linkHandler.GetDocumentBacklinks)
+					documents.POST("/:id/shares", shareHandler.CreateDocumentLink)
 					documents.GET("/project/:id", documentHandler.GetProjectDocuments)
 				}
 			}
@@ -123,7 +418,32 @@ func SetupRouter(router *gin.Engine, db *mongo.Database, tokenStore repository.T
 				mockups.GET("/:id", mockupHandler.GetMockup)
 				mockups.PUT("/:id", mockupHandler.UpdateMockup)
 				mockups.DELETE("/:id", mockupHandler.DeleteMockup)
+				mockups.POST("/:id/restore", trashHandler.RestoreMockup)
+				mockups.GET("/:id/links", linkHandler.GetMockupLinks)
+				mockups.GET("/:id/backlinks", linkHandler.GetMockupBacklinks)
+			}
+			// Project templates of the organization
+			templates := protected.Group("/templates")
+			{
+				templates.GET("", templateHandler.ListTemplates)
+				templates.POST("", templateHandler.CreateTemplate)
+				templates.GET("/:id", templateHandler.GetTemplate)
+				templates.PUT("/:id", templateHandler.UpdateTemplate)
+				templates.DELETE("/:id", templateHandler.DeleteTemplate)
+				templates.POST("/:id/projects", templateHandler.UseTemplate)
+			}
+
+			// Overdue milestones across the user's projects
+			protected.GET("/milestones/overdue", milestoneHandler.ListOverdueMilestones)
+
+			// Link routes
+			links := protected.Group("/links")
+			{
+				links.POST("", linkHandler.CreateLink)
+				links.DELETE("/:id", linkHandler.DeleteLink)
 			}
 		}
 	}
+
+	return running.Wait
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -11,11 +12,47 @@ type Config struct {
 	DatabaseName   string
 	JWTSecret      string
 	AllowedOrigins []string
+	// TrustedProxies are the addresses or CIDR ranges of the proxies whose
+	// X-Forwarded-For header names the client; requests from anywhere else
+	// are attributed to the address they came from
+	TrustedProxies []string
 	Environment    string
 	Redis          struct {
 		URL      string
 		Password string
 	}
+
+	// TrashRetentionDays is how long deleted items stay restorable
+	TrashRetentionDays int
+
+	// AppURL is the frontend address used in links sent by email
+	AppURL string
+	// InvitationTTLHours is how long an email invitation can be accepted
+	InvitationTTLHours int
+	// DefaultOrgAdmin is the email of the user who administers the default
+	// organization holding the data from before organizations existed
+	DefaultOrgAdmin string
+
+	// SMTP is the mail server; without a host, emails are only logged
+	SMTP struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+	}
+	// MailFrom is the sender of every email
+	MailFrom string
+	// DigestHour is the hour of the day, in UTC, digest emails are sent at
+	DigestHour int
+
+	// Events configures how domain events reach their subscribers. With the
+	// "redis" transport they go through a Redis stream shared by every
+	// instance in the group; otherwise they are handled in process.
+	Events struct {
+		Transport string
+		Stream    string
+		Group     string
+	}
 }
 
 func Load() *Config {
@@ -27,6 +64,25 @@ func Load() *Config {
 		Environment:  getEnv("GIN_MODE", "debug"),
 	}
 
+	config.TrashRetentionDays = getEnvInt("TRASH_RETENTION_DAYS", 30)
+	config.AppURL = strings.TrimSuffix(getEnv("APP_URL", "http://localhost:3050"), "/")
+	config.InvitationTTLHours = getEnvInt("INVITATION_TTL_HOURS", 7*24)
+	config.DefaultOrgAdmin = getEnv("DEFAULT_ORG_ADMIN", "")
+
+	config.SMTP.Host = getEnv("SMTP_HOST", "")
+	config.SMTP.Port = getEnvInt("SMTP_PORT", 1025)
+	config.SMTP.Username = getEnv("SMTP_USERNAME", "")
+	config.SMTP.Password = getEnv("SMTP_PASSWORD", "")
+	config.MailFrom = getEnv("MAIL_FROM", "ProjectNexus <noreply@projectnexus.local>")
+	config.DigestHour = getEnvInt("DIGEST_HOUR", 8)
+	if config.DigestHour > 23 {
+		config.DigestHour = 8
+	}
+
+	config.Events.Transport = getEnv("EVENT_TRANSPORT", "")
+	config.Events.Stream = getEnv("EVENT_STREAM", "projectnexus:events")
+	config.Events.Group = getEnv("EVENT_GROUP", "projectnexus")
+
 	// Load your configuration from environment variables or file
 	config.Redis.URL = getEnv("REDIS_URL", "localhost:6479")
 	config.Redis.Password = getEnv("REDIS_PASSWORD", "")
@@ -35,6 +91,13 @@ func Load() *Config {
 	originsStr := getEnv("ALLOWED_ORIGINS", "http://localhost:3050")
 	config.AllowedOrigins = strings.Split(originsStr, ",")
 
+	// No proxy is trusted unless configured
+	if proxies := getEnv("TRUSTED_PROXIES", ""); proxies != "" {
+		for _, proxy := range strings.Split(proxies, ",") {
+			config.TrustedProxies = append(config.TrustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+
 	return config
 }
 
@@ -45,3 +108,11 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -22,6 +22,10 @@ var (
 var (
 	ErrProjectNotFound = errors.New("project not found")
 	ErrInvalidStatus   = errors.New("invalid project status")
+	ErrLastOwner       = errors.New("a project needs at least one owner")
+	ErrNoTransfer      = errors.New("no ownership transfer is pending")
+	ErrTransferPending = errors.New("an ownership transfer is already pending")
+	ErrProjectArchived = errors.New("project is archived")
 )
 
 // Document errors
@@ -33,8 +37,93 @@ var (
 
 // Team errors
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrAlreadyInTeam     = errors.New("user already in team")
-	ErrNotInTeam         = errors.New("user not in team")
-	ErrCannotRemoveOwner = errors.New("cannot remove project owner from team")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrAlreadyInTeam    = errors.New("user already in team")
+	ErrNotInTeam        = errors.New("user not in team")
+	ErrTeamNotFound     = errors.New("team not found")
+	ErrInvalidTeamLead  = errors.New("team lead must be an existing user")
+	ErrCannotRemoveLead = errors.New("cannot remove the team lead from the team")
+	ErrTeamNotAssigned  = errors.New("team is not assigned to the project")
+	ErrTeamAssigned     = errors.New("team is already assigned to the project")
+)
+
+// Mockup errors
+var (
+	ErrMockupNotFound = errors.New("mockup not found")
+)
+
+// Link errors
+var (
+	ErrLinkNotFound = errors.New("link not found")
+	ErrLinkExists   = errors.New("link already exists")
+)
+
+// Folder errors
+var (
+	ErrFolderNotFound = errors.New("folder not found")
+	ErrFolderNotEmpty = errors.New("folder is not empty")
+	ErrInvalidMove    = errors.New("folder cannot be moved into itself or a descendant")
+)
+
+// Milestone errors
+var (
+	ErrMilestoneNotFound = errors.New("milestone not found")
+)
+
+// Template errors
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+// Task errors
+var (
+	ErrTaskNotFound    = errors.New("task not found")
+	ErrColumnNotFound  = errors.New("board column not found")
+	ErrColumnNotEmpty  = errors.New("board column still has tasks")
+	ErrVersionConflict = errors.New("modified by someone else since it was read")
+)
+
+// Trash errors
+var (
+	ErrProjectInTrash = errors.New("project is in the trash")
+)
+
+// Invitation errors
+var (
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationClosed        = errors.New("invitation is no longer open")
+	ErrInvitationExists        = errors.New("an invitation for this email is already pending")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+)
+
+// Organization errors
+var (
+	ErrOrgNotFound  = errors.New("organization not found")
+	ErrAlreadyInOrg = errors.New("user already in organization")
+	ErrNotInOrg     = errors.New("user not in organization")
+	ErrLastAdmin    = errors.New("an organization needs at least one admin")
+)
+
+// Notification errors
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// Webhook errors
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// Share link errors
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrShareLinkInactive = errors.New("share link has expired or was revoked")
+	// ErrSharePasswordRequired and ErrSharePasswordInvalid ask the viewer of
+	// a protected link for its password
+	ErrSharePasswordRequired = errors.New("share link password required")
+	ErrSharePasswordInvalid  = errors.New("wrong share link password")
+	// ErrShareTooManyAttempts stops password guessing on share links for a
+	// while
+	ErrShareTooManyAttempts = errors.New("too many share link password attempts")
 )
@@ -0,0 +1,416 @@
+// Package events internal/events/bus.go
+//
+// The event bus lets parts of the backend react to changes without the
+// services that make them knowing about it. Services publish typed events
+// after their writes succeed; subscribers register for the event types they
+// care about.
+//
+// Without a transport, subscribers run in the publishing process before
+// Publish returns, and the ones that fail are retried in the background.
+// With a transport such as Redis Streams, events are sent through it and
+// every subscriber runs once across all instances sharing the consumer
+// group, as instances and workers pull events off the stream. An event is
+// redelivered until all of its subscribers succeed, so subscribers
+// must cope with seeing an event more than once. Local subscribers, which
+// keep state in memory, instead run on every instance.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"projectnexus/internal/requestmeta"
+	"projectnexus/internal/tenant"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Event is a change that happened in the backend
+type Event interface {
+	// EventName identifies the kind of event, e.g. "document.updated"
+	EventName() string
+	// Subject is the resource the event is about
+	Subject() Subject
+}
+
+// Change is implemented by events that carry the state of their subject
+// before and after the change. Before is nil for creations and after is nil
+// for deletions.
+type Change interface {
+	Change() (before, after interface{})
+}
+
+// Envelope carries an event along with where, when and by whom it happened
+type Envelope struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Subject    Subject         `json:"subject"`
+	OrgID      string          `json:"orgId,omitempty"`
+	ActorID    string          `json:"actorId,omitempty"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+	// IP and UserAgent identify the client of the request that caused the
+	// event; events of background jobs have neither
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// event is the typed event when it was published in this process
+	event Event
+}
+
+// Handler reacts to an event. Returning an error has the event redelivered
+// when a transport is in use.
+type Handler func(ctx context.Context, env Envelope) error
+
+// Transport carries events between processes
+type Transport interface {
+	Publish(ctx context.Context, env Envelope) error
+	// Consume hands received events to handle until the context is done. An
+	// event is acknowledged once handle returns nil.
+	Consume(ctx context.Context, handle Handler) error
+}
+
+// Broadcaster is implemented by transports that can also hand every event to
+// every instance. Without it, local subscribers run wherever the event is
+// consumed.
+type Broadcaster interface {
+	// Broadcast hands the events published from now on to handle until the
+	// context is done, whatever other instances do. Events are not
+	// redelivered when handle fails.
+	Broadcast(ctx context.Context, handle Handler) error
+}
+
+// allEvents subscribes a handler to every event
+const allEvents = "*"
+
+const (
+	// retryAttempts is how often a handler is given an event handled in
+	// process before it is given up on
+	retryAttempts = 6
+	// retryDelay is the wait before the first retry; it doubles after each
+	retryDelay = time.Second
+)
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]subscription
+	// local subscribers see every event on every instance
+	local     []subscription
+	transport Transport
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]subscription)}
+}
+
+// UseTransport sends events through a transport instead of handling them in
+// the publishing process. It must be called before events are published.
+func (b *Bus) UseTransport(transport Transport) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.transport = transport
+}
+
+// Subscribe registers a handler for events of the given name. The subscriber
+// name shows up in logs.
+func (b *Bus) Subscribe(subscriber string, eventName string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventName] = append(b.handlers[eventName], subscription{name: subscriber, handler: handler})
+}
+
+// SubscribeAll registers a handler for every event
+func (b *Bus) SubscribeAll(subscriber string, handler Handler) {
+	b.Subscribe(subscriber, allEvents, handler)
+}
+
+// SubscribeLocal registers a handler for every event that runs on every
+// instance rather than once across them, for subscribers that keep what they
+// are told in memory, such as the feed of open change streams. Its failures
+// are logged and never redelivered.
+func (b *Bus) SubscribeLocal(subscriber string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.local = append(b.local, subscription{name: subscriber, handler: handler})
+}
+
+// known maps event names to their types, so envelopes that came through a
+// transport can be decoded without knowing their type up front
+var known = make(map[string]reflect.Type)
+
+// register makes event types known by name
+func register(events ...Event) {
+	for _, event := range events {
+		known[event.EventName()] = reflect.TypeOf(event)
+	}
+}
+
+// Event returns the typed event of an envelope, whatever its type
+func (env Envelope) Event() (Event, error) {
+	if env.event != nil {
+		return env.event, nil
+	}
+	t, ok := known[env.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", env.Name)
+	}
+	event := reflect.New(t)
+	if err := json.Unmarshal(env.Payload, event.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", env.Name, err)
+	}
+	return event.Elem().Interface().(Event), nil
+}
+
+// On registers a handler for one type of event, decoded into its Go type
+func On[E Event](b *Bus, subscriber string, handler func(ctx context.Context, env Envelope, event E) error) {
+	var zero E
+	b.Subscribe(subscriber, zero.EventName(), func(ctx context.Context, env Envelope) error {
+		event, err := Decode[E](env)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, env, event)
+	})
+}
+
+// Decode returns the typed event of an envelope
+func Decode[E Event](env Envelope) (E, error) {
+	if event, ok := env.event.(E); ok {
+		return event, nil
+	}
+	var event E
+	if err := json.Unmarshal(env.Payload, &event); err != nil {
+		return event, fmt.Errorf("failed to decode %s event: %w", env.Name, err)
+	}
+	return event, nil
+}
+
+// Publish announces an event made by the actor in the organization of the
+// context. It never fails the change that caused the event: problems are
+// logged. A nil bus drops events, which suits tests that do not listen.
+func (b *Bus) Publish(ctx context.Context, actorID string, event Event) {
+	if b == nil {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event.EventName(), err)
+		return
+	}
+	meta := requestmeta.From(ctx)
+	env := Envelope{
+		ID:         newEventID(),
+		Name:       event.EventName(),
+		Subject:    event.Subject(),
+		OrgID:      tenant.OrgID(ctx),
+		ActorID:    actorID,
+		OccurredAt: time.Now(),
+		Payload:    payload,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+		event:      event,
+	}
+	if held, ok := ctx.Value(heldKey{}).(*Held); ok {
+		held.add(b, env)
+		return
+	}
+	b.send(ctx, env)
+}
+
+// send hands an envelope to the transport, or to the subscribers when there
+// is none or it fails
+func (b *Bus) send(ctx context.Context, env Envelope) {
+	b.mu.RLock()
+	transport := b.transport
+	b.mu.RUnlock()
+	if transport != nil {
+		err := transport.Publish(ctx, env)
+		if err == nil {
+			return
+		}
+		// Handling the event here beats losing it
+		log.Printf("Failed to publish %s event %s, handling it in process: %v", env.Name, env.ID, err)
+	}
+
+	b.dispatchRetrying(ctx, env)
+	b.dispatchLocal(ctx, env)
+}
+
+type heldKey struct{}
+
+type heldEvent struct {
+	bus *Bus
+	env Envelope
+}
+
+// Held collects the events published with a context from Hold
+type Held struct {
+	mu     sync.Mutex
+	events []heldEvent
+}
+
+// Hold returns a context under which published events are kept back until
+// Release is called. Wrap a transaction with it so subscribers never hear of
+// changes that are rolled back; events held by a failed transaction are
+// simply dropped.
+func Hold(ctx context.Context) (context.Context, *Held) {
+	held := &Held{}
+	return context.WithValue(ctx, heldKey{}, held), held
+}
+
+func (h *Held) add(b *Bus, env Envelope) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, heldEvent{bus: b, env: env})
+}
+
+// Release publishes the held events in the order they were published. Events
+// published outside any organization, as when an invitation token decides
+// where a user goes, take the organization of ctx.
+func (h *Held) Release(ctx context.Context) {
+	h.mu.Lock()
+	held := h.events
+	h.events = nil
+	h.mu.Unlock()
+
+	for _, e := range held {
+		if e.env.OrgID == "" {
+			e.env.OrgID = tenant.OrgID(ctx)
+		}
+		e.bus.send(ctx, e.env)
+	}
+}
+
+// Run consumes events from the transport until the context is done. Events
+// are handled in the organization they happened in. Without a transport it
+// returns at once, as events are handled when they are published.
+func (b *Bus) Run(ctx context.Context) error {
+	b.mu.RLock()
+	transport := b.transport
+	local := len(b.local) > 0
+	b.mu.RUnlock()
+	if transport == nil {
+		return nil
+	}
+
+	broadcaster, broadcasts := transport.(Broadcaster)
+	if local && broadcasts {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := broadcaster.Broadcast(ctx, func(ctx context.Context, env Envelope) error {
+				b.dispatchLocal(inOrg(ctx, env), env)
+				return nil
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Event broadcast stopped: %v", err)
+			}
+		}()
+		defer wg.Wait()
+	}
+
+	return transport.Consume(ctx, func(ctx context.Context, env Envelope) error {
+		ctx = inOrg(ctx, env)
+		if !broadcasts {
+			b.dispatchLocal(ctx, env)
+		}
+		return b.Dispatch(ctx, env)
+	})
+}
+
+// inOrg scopes the handling of an event to the organization it happened in
+func inOrg(ctx context.Context, env Envelope) context.Context {
+	if env.OrgID != "" {
+		return tenant.WithOrg(ctx, env.OrgID, "")
+	}
+	return ctx
+}
+
+// subscriptions returns the handlers subscribed to an envelope
+func (b *Bus) subscriptions(env Envelope) []subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append(append([]subscription{}, b.handlers[env.Name]...), b.handlers[allEvents]...)
+}
+
+// Dispatch runs the handlers subscribed to an envelope and reports the ones
+// that failed. A failing or panicking handler does not stop the others.
+func (b *Bus) Dispatch(ctx context.Context, env Envelope) error {
+	var errs []error
+	for _, sub := range b.subscriptions(env) {
+		if err := safeHandle(ctx, sub, env); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dispatchRetrying runs the handlers subscribed to an envelope handled in
+// process. Handlers that fail are retried in the background, the way a
+// transport redelivers the event, so subscribers such as the audit log do
+// not lose it to a passing failure.
+func (b *Bus) dispatchRetrying(ctx context.Context, env Envelope) {
+	for _, sub := range b.subscriptions(env) {
+		if err := safeHandle(ctx, sub, env); err != nil {
+			log.Printf("Failed to handle %s event %s in %s, retrying: %v", env.Name, env.ID, sub.name, err)
+			go retry(context.WithoutCancel(ctx), sub, env)
+		}
+	}
+}
+
+// retry gives an event to a handler again, waiting longer after each
+// failure, until it succeeds or retryAttempts is reached
+func retry(ctx context.Context, sub subscription, env Envelope) {
+	delay := retryDelay
+	for attempt := 2; attempt <= retryAttempts; attempt++ {
+		time.Sleep(delay)
+		err := safeHandle(ctx, sub, env)
+		if err == nil {
+			return
+		}
+		if attempt == retryAttempts {
+			log.Printf("Gave up on %s event %s in %s after %d attempts: %v", env.Name, env.ID, sub.name, attempt, err)
+			return
+		}
+		delay *= 2
+	}
+}
+
+// dispatchLocal runs the local subscribers, logging the ones that failed
+func (b *Bus) dispatchLocal(ctx context.Context, env Envelope) {
+	b.mu.RLock()
+	subs := append([]subscription{}, b.local...)
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if err := safeHandle(ctx, sub, env); err != nil {
+			log.Printf("Failed to handle %s event %s in %s: %v", env.Name, env.ID, sub.name, err)
+		}
+	}
+}
+
+func safeHandle(ctx context.Context, sub subscription, env Envelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler(ctx, env)
+}
+
+func newEventID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,263 @@
+// Package events internal/events/org.go
+package events
+
+import "projectnexus/internal/models"
+
+// Events about the organization rather than a single project: its members,
+// teams, invitations and templates, and the webhooks of projects
+
+func init() {
+	register(
+		OrganizationCreated{}, OrganizationUpdated{}, OrgMemberAdded{}, OrgMemberUpdated{}, OrgMemberRemoved{},
+		TeamCreated{}, TeamUpdated{}, TeamDeleted{}, TeamMemberAdded{}, TeamMemberUpdated{}, TeamMemberRemoved{},
+		InvitationCreated{}, InvitationRevoked{}, InvitationAccepted{}, InvitationDeclined{},
+		WebhookCreated{}, WebhookUpdated{}, WebhookDeleted{},
+		TemplateCreated{}, TemplateUpdated{}, TemplateDeleted{},
+	)
+}
+
+func orgSubject(orgID string) Subject {
+	return Subject{Type: ResourceOrganization, ID: orgID}
+}
+
+// OrganizationCreated is published when an organization is created. It is
+// published in the new organization.
+type OrganizationCreated struct {
+	Organization models.Organization `json:"organization"`
+}
+
+func (OrganizationCreated) EventName() string  { return "organization.created" }
+func (e OrganizationCreated) Subject() Subject { return orgSubject(e.Organization.ID) }
+func (e OrganizationCreated) Change() (before, after interface{}) {
+	return nil, e.Organization
+}
+
+// OrganizationUpdated is published when an organization is renamed
+type OrganizationUpdated struct {
+	Organization models.Organization `json:"organization"`
+	Previous     models.Organization `json:"previous"`
+}
+
+func (OrganizationUpdated) EventName() string  { return "organization.updated" }
+func (e OrganizationUpdated) Subject() Subject { return orgSubject(e.Organization.ID) }
+func (e OrganizationUpdated) Change() (before, after interface{}) {
+	return e.Previous, e.Organization
+}
+
+// OrgMemberAdded is published when a user joins an organization by being
+// added to it
+type OrgMemberAdded struct {
+	OrgID  string         `json:"orgId"`
+	UserID string         `json:"userId"`
+	Role   models.OrgRole `json:"role"`
+}
+
+func (OrgMemberAdded) EventName() string  { return "organization.member_added" }
+func (e OrgMemberAdded) Subject() Subject { return orgSubject(e.OrgID) }
+func (e OrgMemberAdded) Change() (before, after interface{}) {
+	return nil, membership{e.UserID, e.Role}
+}
+
+// OrgMemberUpdated is published when a member's role in an organization
+// changes
+type OrgMemberUpdated struct {
+	OrgID        string         `json:"orgId"`
+	UserID       string         `json:"userId"`
+	Role         models.OrgRole `json:"role"`
+	PreviousRole models.OrgRole `json:"previousRole"`
+}
+
+func (OrgMemberUpdated) EventName() string  { return "organization.member_updated" }
+func (e OrgMemberUpdated) Subject() Subject { return orgSubject(e.OrgID) }
+func (e OrgMemberUpdated) Change() (before, after interface{}) {
+	return membership{e.UserID, e.PreviousRole}, membership{e.UserID, e.Role}
+}
+
+// OrgMemberRemoved is published when a user is removed from an organization
+type OrgMemberRemoved struct {
+	OrgID  string `json:"orgId"`
+	UserID string `json:"userId"`
+}
+
+func (OrgMemberRemoved) EventName() string  { return "organization.member_removed" }
+func (e OrgMemberRemoved) Subject() Subject { return orgSubject(e.OrgID) }
+func (e OrgMemberRemoved) Change() (before, after interface{}) {
+	return membership{UserID: e.UserID}, nil
+}
+
+func teamSubject(teamID string) Subject {
+	return Subject{Type: ResourceTeam, ID: teamID}
+}
+
+// TeamCreated is published when a standalone team is created
+type TeamCreated struct {
+	Team models.Team `json:"team"`
+}
+
+func (TeamCreated) EventName() string                     { return "team.created" }
+func (e TeamCreated) Subject() Subject                    { return teamSubject(e.Team.ID) }
+func (e TeamCreated) Change() (before, after interface{}) { return nil, e.Team }
+
+// TeamUpdated is published when a team's details or lead change
+type TeamUpdated struct {
+	Team     models.Team `json:"team"`
+	Previous models.Team `json:"previous"`
+}
+
+func (TeamUpdated) EventName() string                     { return "team.updated" }
+func (e TeamUpdated) Subject() Subject                    { return teamSubject(e.Team.ID) }
+func (e TeamUpdated) Change() (before, after interface{}) { return e.Previous, e.Team }
+
+// TeamDeleted is published when a team is deleted
+type TeamDeleted struct {
+	Team models.Team `json:"team"`
+}
+
+func (TeamDeleted) EventName() string                     { return "team.deleted" }
+func (e TeamDeleted) Subject() Subject                    { return teamSubject(e.Team.ID) }
+func (e TeamDeleted) Change() (before, after interface{}) { return e.Team, nil }
+
+// TeamMemberAdded is published when a user joins a team
+type TeamMemberAdded struct {
+	TeamID string          `json:"teamId"`
+	UserID string          `json:"userId"`
+	Role   models.TeamRole `json:"role"`
+}
+
+func (TeamMemberAdded) EventName() string  { return "team.member_added" }
+func (e TeamMemberAdded) Subject() Subject { return teamSubject(e.TeamID) }
+func (e TeamMemberAdded) Change() (before, after interface{}) {
+	return nil, membership{e.UserID, e.Role}
+}
+
+// TeamMemberUpdated is published when a member's role in a team changes
+type TeamMemberUpdated struct {
+	TeamID       string          `json:"teamId"`
+	UserID       string          `json:"userId"`
+	Role         models.TeamRole `json:"role"`
+	PreviousRole models.TeamRole `json:"previousRole"`
+}
+
+func (TeamMemberUpdated) EventName() string  { return "team.member_updated" }
+func (e TeamMemberUpdated) Subject() Subject { return teamSubject(e.TeamID) }
+func (e TeamMemberUpdated) Change() (before, after interface{}) {
+	return membership{e.UserID, e.PreviousRole}, membership{e.UserID, e.Role}
+}
+
+// TeamMemberRemoved is published when a user leaves a team
+type TeamMemberRemoved struct {
+	TeamID string `json:"teamId"`
+	UserID string `json:"userId"`
+}
+
+func (TeamMemberRemoved) EventName() string  { return "team.member_removed" }
+func (e TeamMemberRemoved) Subject() Subject { return teamSubject(e.TeamID) }
+func (e TeamMemberRemoved) Change() (before, after interface{}) {
+	return membership{UserID: e.UserID}, nil
+}
+
+func invitationSubject(invitation models.Invitation) Subject {
+	return Subject{Type: ResourceInvitation, ID: invitation.ID, ProjectID: invitation.ProjectID}
+}
+
+// InvitationCreated is published when someone is invited by email
+type InvitationCreated struct {
+	Invitation models.Invitation `json:"invitation"`
+}
+
+func (InvitationCreated) EventName() string  { return "invitation.created" }
+func (e InvitationCreated) Subject() Subject { return invitationSubject(e.Invitation) }
+func (e InvitationCreated) Change() (before, after interface{}) {
+	return nil, e.Invitation
+}
+
+// InvitationRevoked is published when a pending invitation is withdrawn
+type InvitationRevoked struct {
+	Invitation models.Invitation `json:"invitation"`
+}
+
+func (InvitationRevoked) EventName() string  { return "invitation.revoked" }
+func (e InvitationRevoked) Subject() Subject { return invitationSubject(e.Invitation) }
+
+// InvitationAccepted is published when an invitation is accepted
+type InvitationAccepted struct {
+	Invitation models.Invitation `json:"invitation"`
+}
+
+func (InvitationAccepted) EventName() string  { return "invitation.accepted" }
+func (e InvitationAccepted) Subject() Subject { return invitationSubject(e.Invitation) }
+
+// InvitationDeclined is published when an invitation is declined
+type InvitationDeclined struct {
+	Invitation models.Invitation `json:"invitation"`
+}
+
+func (InvitationDeclined) EventName() string  { return "invitation.declined" }
+func (e InvitationDeclined) Subject() Subject { return invitationSubject(e.Invitation) }
+
+func webhookSubject(webhook models.Webhook) Subject {
+	return Subject{Type: ResourceWebhook, ID: webhook.ID, ProjectID: webhook.ProjectID}
+}
+
+// WebhookCreated is published when a webhook is added to a project
+type WebhookCreated struct {
+	Webhook models.Webhook `json:"webhook"`
+}
+
+func (WebhookCreated) EventName() string                     { return "webhook.created" }
+func (e WebhookCreated) Subject() Subject                    { return webhookSubject(e.Webhook) }
+func (e WebhookCreated) Change() (before, after interface{}) { return nil, e.Webhook }
+
+// WebhookUpdated is published when a webhook's URL, events or state change
+type WebhookUpdated struct {
+	Webhook  models.Webhook `json:"webhook"`
+	Previous models.Webhook `json:"previous"`
+}
+
+func (WebhookUpdated) EventName() string                     { return "webhook.updated" }
+func (e WebhookUpdated) Subject() Subject                    { return webhookSubject(e.Webhook) }
+func (e WebhookUpdated) Change() (before, after interface{}) { return e.Previous, e.Webhook }
+
+// WebhookDeleted is published when a webhook is removed
+type WebhookDeleted struct {
+	Webhook models.Webhook `json:"webhook"`
+}
+
+func (WebhookDeleted) EventName() string                     { return "webhook.deleted" }
+func (e WebhookDeleted) Subject() Subject                    { return webhookSubject(e.Webhook) }
+func (e WebhookDeleted) Change() (before, after interface{}) { return e.Webhook, nil }
+
+func templateSubject(templateID string) Subject {
+	return Subject{Type: ResourceTemplate, ID: templateID}
+}
+
+// TemplateCreated is published when a template is created, from scratch or
+// from a project
+type TemplateCreated struct {
+	Template models.ProjectTemplate `json:"template"`
+}
+
+func (TemplateCreated) EventName() string                     { return "template.created" }
+func (e TemplateCreated) Subject() Subject                    { return templateSubject(e.Template.ID) }
+func (e TemplateCreated) Change() (before, after interface{}) { return nil, e.Template }
+
+// TemplateUpdated is published when a template is edited
+type TemplateUpdated struct {
+	Template models.ProjectTemplate `json:"template"`
+	Previous models.ProjectTemplate `json:"previous"`
+}
+
+func (TemplateUpdated) EventName() string  { return "template.updated" }
+func (e TemplateUpdated) Subject() Subject { return templateSubject(e.Template.ID) }
+func (e TemplateUpdated) Change() (before, after interface{}) {
+	return e.Previous, e.Template
+}
+
+// TemplateDeleted is published when a template is deleted
+type TemplateDeleted struct {
+	Template models.ProjectTemplate `json:"template"`
+}
+
+func (TemplateDeleted) EventName() string                     { return "template.deleted" }
+func (e TemplateDeleted) Subject() Subject                    { return templateSubject(e.Template.ID) }
+func (e TemplateDeleted) Change() (before, after interface{}) { return e.Template, nil }
@@ -0,0 +1,235 @@
+// Package events internal/events/redis.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// redisStreamMaxLen caps the stream; Redis trims the oldest events
+	redisStreamMaxLen = 100000
+	// redisReadBlock is how long a read waits for new events
+	redisReadBlock = 5 * time.Second
+	// redisReadCount is how many events are read at once
+	redisReadCount = 20
+	// redisClaimAfter is how long an event may stay unacknowledged before
+	// another consumer takes it over, so events held by a crashed instance or
+	// whose handlers failed are retried
+	redisClaimAfter = time.Minute
+	// redisMaxDeliveries is how often an event is handed to the handlers
+	// before it is moved to the dead-letter stream
+	redisMaxDeliveries = 10
+	// redisDeadLetterSuffix names the dead-letter stream after the stream
+	redisDeadLetterSuffix = ":dead"
+)
+
+// RedisStreams carries events through a Redis stream. Instances that share a
+// group split the events between them; each event is acknowledged once its
+// handlers succeed. Events whose handlers keep failing end up in a
+// dead-letter stream, named after the stream with a ":dead" suffix, for
+// someone to look at. Broadcast reads the stream outside the group, so every
+// instance sees every event.
+type RedisStreams struct {
+	client     *redis.Client
+	stream     string
+	deadLetter string
+	group      string
+	consumer   string
+}
+
+// NewRedisStreams returns a transport over the stream. The consumer name
+// must be unique to the instance, e.g. its hostname.
+func NewRedisStreams(client *redis.Client, stream, group, consumer string) *RedisStreams {
+	return &RedisStreams{
+		client:     client,
+		stream:     stream,
+		deadLetter: stream + redisDeadLetterSuffix,
+		group:      group,
+		consumer:   consumer,
+	}
+}
+
+func (t *RedisStreams) Publish(ctx context.Context, env Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return t.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: t.stream,
+		MaxLen: redisStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"envelope": data},
+	}).Err()
+}
+
+func (t *RedisStreams) Consume(ctx context.Context, handle Handler) error {
+	err := t.client.XGroupCreateMkStream(ctx, t.stream, t.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	for ctx.Err() == nil {
+		if err := t.moveDeadLetters(ctx); err != nil {
+			t.pause(ctx, "dead-letter", err)
+			continue
+		}
+
+		// Retry the events left unacknowledged for too long first
+		claimed, _, err := t.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   t.stream,
+			Group:    t.group,
+			Consumer: t.consumer,
+			MinIdle:  redisClaimAfter,
+			Start:    "0-0",
+			Count:    redisReadCount,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			t.pause(ctx, "claim", err)
+			continue
+		}
+		t.handleAll(ctx, claimed, handle)
+
+		streams, err := t.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    t.group,
+			Consumer: t.consumer,
+			Streams:  []string{t.stream, ">"},
+			Count:    redisReadCount,
+			Block:    redisReadBlock,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				t.pause(ctx, "read", err)
+			}
+			continue
+		}
+		for _, stream := range streams {
+			t.handleAll(ctx, stream.Messages, handle)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// Broadcast reads the events added to the stream from now on, without a
+// consumer group, so no other instance takes them
+func (t *RedisStreams) Broadcast(ctx context.Context, handle Handler) error {
+	last := "$"
+	for ctx.Err() == nil {
+		streams, err := t.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{t.stream, last},
+			Count:   redisReadCount,
+			Block:   redisReadBlock,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				t.pause(ctx, "broadcast", err)
+			}
+			continue
+		}
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				last = msg.ID
+				var env Envelope
+				data, _ := msg.Values["envelope"].(string)
+				if err := json.Unmarshal([]byte(data), &env); err != nil {
+					log.Printf("Skipping undecodable event %s: %v", msg.ID, err)
+					continue
+				}
+				if err := handle(ctx, env); err != nil {
+					log.Printf("Failed to handle broadcast %s event %s: %v", env.Name, env.ID, err)
+				}
+			}
+		}
+	}
+
+	return ctx.Err()
+}
+
+// handleAll hands messages to the handler and acknowledges the ones it
+// handled. Messages that cannot be decoded are acknowledged and dropped, as
+// retrying them cannot help.
+func (t *RedisStreams) handleAll(ctx context.Context, messages []redis.XMessage, handle Handler) {
+	for _, msg := range messages {
+		var env Envelope
+		data, _ := msg.Values["envelope"].(string)
+		if err := json.Unmarshal([]byte(data), &env); err != nil {
+			log.Printf("Dropping undecodable event %s: %v", msg.ID, err)
+		} else if err := handle(ctx, env); err != nil {
+			log.Printf("Failed to handle %s event %s, will retry: %v", env.Name, env.ID, err)
+			continue
+		}
+
+		if err := t.client.XAck(ctx, t.stream, t.group, msg.ID).Err(); err != nil {
+			log.Printf("Failed to acknowledge event %s: %v", msg.ID, err)
+		}
+	}
+}
+
+// moveDeadLetters takes the events that were handed out redisMaxDeliveries
+// times without being acknowledged off the group's pending list and adds them
+// to the dead-letter stream, so a poison event is not retried forever
+func (t *RedisStreams) moveDeadLetters(ctx context.Context) error {
+	pending, err := t.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: t.stream,
+		Group:  t.group,
+		Idle:   redisClaimAfter,
+		Start:  "-",
+		End:    "+",
+		Count:  redisReadCount,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		if p.RetryCount < redisMaxDeliveries {
+			continue
+		}
+
+		values := map[string]interface{}{"id": p.ID, "deliveries": p.RetryCount}
+		messages, err := t.client.XRangeN(ctx, t.stream, p.ID, p.ID, 1).Result()
+		if err != nil {
+			return err
+		}
+		// Trimmed events are only taken off the list
+		if len(messages) > 0 {
+			values["envelope"] = messages[0].Values["envelope"]
+			err := t.client.XAdd(ctx, &redis.XAddArgs{
+				Stream: t.deadLetter,
+				MaxLen: redisStreamMaxLen,
+				Approx: true,
+				Values: values,
+			}).Err()
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := t.client.XAck(ctx, t.stream, t.group, p.ID).Err(); err != nil {
+			return err
+		}
+		log.Printf("Moved event %s to %s after %d failed deliveries", p.ID, t.deadLetter, p.RetryCount)
+	}
+	return nil
+}
+
+// pause waits a moment after a Redis error so an outage is not hammered
+func (t *RedisStreams) pause(ctx context.Context, action string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	log.Printf("Failed to %s events from %s: %v", action, t.stream, err)
+	select {
+	case <-ctx.Done():
+	case <-time.After(redisReadBlock):
+	}
+}
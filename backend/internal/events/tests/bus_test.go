@@ -0,0 +1,271 @@
+// internal/events/bus_test.go
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/requestmeta"
+	"projectnexus/internal/tenant"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTransport keeps published envelopes and hands them back on Consume
+type fakeTransport struct {
+	published []events.Envelope
+	failing   bool
+	handleErr error
+}
+
+func (t *fakeTransport) Publish(ctx context.Context, env events.Envelope) error {
+	if t.failing {
+		return errors.New("transport down")
+	}
+	t.published = append(t.published, env)
+	return nil
+}
+
+func (t *fakeTransport) Consume(ctx context.Context, handle events.Handler) error {
+	for _, env := range t.published {
+		t.handleErr = errors.Join(t.handleErr, handle(ctx, env))
+	}
+	return nil
+}
+
+func TestBus_PublishInProcess(t *testing.T) {
+	bus := events.NewBus()
+	ctx := tenant.WithOrg(context.Background(), "org-1", "")
+
+	var got []events.DocumentDeleted
+	var orgs []string
+	events.On(bus, "test", func(ctx context.Context, env events.Envelope, event events.DocumentDeleted) error {
+		got = append(got, event)
+		orgs = append(orgs, env.OrgID)
+		return nil
+	})
+	var all []string
+	bus.SubscribeAll("audit", func(ctx context.Context, env events.Envelope) error {
+		all = append(all, env.Name)
+		return nil
+	})
+
+	bus.Publish(ctx, "user-1", events.DocumentDeleted{Document: models.Document{ID: "doc-1", ProjectID: "project-1"}})
+	bus.Publish(ctx, "user-1", events.ProjectDeleted{Project: models.Project{ID: "project-1"}})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "doc-1", got[0].Document.ID)
+	assert.Equal(t, []string{"org-1"}, orgs)
+	assert.Equal(t, []string{"document.deleted", "project.deleted"}, all)
+}
+
+func TestBus_FailingHandlersDoNotStopOthers(t *testing.T) {
+	bus := events.NewBus()
+	bus.Subscribe("broken", "project.deleted", func(ctx context.Context, env events.Envelope) error {
+		panic("boom")
+	})
+	bus.Subscribe("failing", "project.deleted", func(ctx context.Context, env events.Envelope) error {
+		return errors.New("nope")
+	})
+	called := false
+	bus.Subscribe("working", "project.deleted", func(ctx context.Context, env events.Envelope) error {
+		called = true
+		return nil
+	})
+
+	err := bus.Dispatch(context.Background(), events.Envelope{Name: "project.deleted", Payload: []byte(`{}`)})
+	assert.True(t, called)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken: panic: boom")
+	assert.Contains(t, err.Error(), "failing: nope")
+}
+
+func TestBus_InProcessFailuresAreRetried(t *testing.T) {
+	bus := events.NewBus()
+	var attempts atomic.Int32
+	bus.SubscribeAll("audit", func(ctx context.Context, env events.Envelope) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("database away")
+		}
+		return nil
+	})
+
+	bus.Publish(context.Background(), "user-1", events.ProjectDeleted{Project: models.Project{ID: "project-1"}})
+	assert.Eventually(t, func() bool { return attempts.Load() == 2 }, 3*time.Second, 50*time.Millisecond)
+	// Once the handler succeeds it is left alone
+	time.Sleep(2500 * time.Millisecond)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestBus_Transport(t *testing.T) {
+	transport := &fakeTransport{}
+	bus := events.NewBus()
+	bus.UseTransport(transport)
+
+	var got []events.DocumentStatusChanged
+	var orgs []string
+	events.On(bus, "test", func(ctx context.Context, env events.Envelope, event events.DocumentStatusChanged) error {
+		got = append(got, event)
+		orgs = append(orgs, tenant.OrgID(ctx))
+		return nil
+	})
+
+	ctx := tenant.WithOrg(context.Background(), "org-1", "")
+	bus.Publish(ctx, "user-1", events.DocumentStatusChanged{
+		Document:       models.Document{ID: "doc-1", Status: models.DocumentStatusApproved},
+		PreviousStatus: models.DocumentStatusInReview,
+	})
+
+	// Nothing is handled until the event comes back from the transport
+	assert.Empty(t, got)
+	require.Len(t, transport.published, 1)
+	assert.Equal(t, "user-1", transport.published[0].ActorID)
+
+	require.NoError(t, bus.Run(context.Background()))
+	require.NoError(t, transport.handleErr)
+	require.Len(t, got, 1)
+	assert.Equal(t, models.DocumentStatusApproved, got[0].Document.Status)
+	assert.Equal(t, models.DocumentStatusInReview, got[0].PreviousStatus)
+	assert.Equal(t, []string{"org-1"}, orgs)
+}
+
+func TestBus_TransportFailureFallsBackToInProcess(t *testing.T) {
+	bus := events.NewBus()
+	bus.UseTransport(&fakeTransport{failing: true})
+
+	called := false
+	events.On(bus, "test", func(ctx context.Context, env events.Envelope, event events.ProjectDeleted) error {
+		called = true
+		return nil
+	})
+
+	bus.Publish(context.Background(), "", events.ProjectDeleted{Project: models.Project{ID: "project-1"}})
+	assert.True(t, called)
+}
+
+func TestHold(t *testing.T) {
+	bus := events.NewBus()
+	var got []string
+	events.On(bus, "test", func(ctx context.Context, env events.Envelope, event events.ProjectDeleted) error {
+		got = append(got, event.Project.ID)
+		return nil
+	})
+
+	ctx, held := events.Hold(context.Background())
+	bus.Publish(ctx, "", events.ProjectDeleted{Project: models.Project{ID: "project-1"}})
+	bus.Publish(ctx, "", events.ProjectDeleted{Project: models.Project{ID: "project-2"}})
+	assert.Empty(t, got)
+
+	held.Release(context.Background())
+	assert.Equal(t, []string{"project-1", "project-2"}, got)
+
+	// Released events are not sent twice
+	held.Release(context.Background())
+	assert.Len(t, got, 2)
+}
+
+func TestHold_ReleaseFillsInOrganization(t *testing.T) {
+	bus := events.NewBus()
+	var orgs []string
+	bus.SubscribeAll("test", func(ctx context.Context, env events.Envelope) error {
+		orgs = append(orgs, env.OrgID)
+		return nil
+	})
+
+	ctx, held := events.Hold(tenant.Unscoped(context.Background()))
+	bus.Publish(ctx, "", events.ProjectDeleted{Project: models.Project{ID: "project-1"}})
+	bus.Publish(tenant.WithOrg(ctx, "org-2", ""), "", events.ProjectDeleted{Project: models.Project{ID: "project-2"}})
+
+	held.Release(tenant.WithOrg(context.Background(), "org-1", ""))
+	assert.Equal(t, []string{"org-1", "org-2"}, orgs)
+}
+
+func TestEnvelope_Event(t *testing.T) {
+	transport := &fakeTransport{}
+	bus := events.NewBus()
+	bus.UseTransport(transport)
+
+	ctx := requestmeta.With(context.Background(), requestmeta.Meta{IP: "10.0.0.1", UserAgent: "curl/8.0"})
+	bus.Publish(ctx, "user-1", events.TaskUpdated{
+		Task:     models.Task{ID: "task-1", ProjectID: "project-1", Title: "After"},
+		Previous: models.Task{ID: "task-1", ProjectID: "project-1", Title: "Before"},
+	})
+	require.Len(t, transport.published, 1)
+	env := transport.published[0]
+	assert.Equal(t, events.Subject{Type: events.ResourceTask, ID: "task-1", ProjectID: "project-1"}, env.Subject)
+	assert.Equal(t, "10.0.0.1", env.IP)
+	assert.Equal(t, "curl/8.0", env.UserAgent)
+
+	// Envelopes from a transport carry only the payload
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+	var received events.Envelope
+	require.NoError(t, json.Unmarshal(data, &received))
+
+	event, err := received.Event()
+	require.NoError(t, err)
+	change, ok := event.(events.Change)
+	require.True(t, ok)
+	before, after := change.Change()
+	assert.Equal(t, "Before", before.(models.Task).Title)
+	assert.Equal(t, "After", after.(models.Task).Title)
+
+	_, err = events.Envelope{Name: "unknown.event", Payload: []byte(`{}`)}.Event()
+	assert.Error(t, err)
+}
+
+// fakeBroadcaster also hands every published envelope to Broadcast
+type fakeBroadcaster struct {
+	fakeTransport
+}
+
+func (t *fakeBroadcaster) Broadcast(ctx context.Context, handle events.Handler) error {
+	for _, env := range t.published {
+		_ = handle(ctx, env)
+	}
+	return nil
+}
+
+func TestBus_LocalSubscribers(t *testing.T) {
+	var local []string
+	subscribe := func(bus *events.Bus) {
+		bus.SubscribeLocal("feed", func(ctx context.Context, env events.Envelope) error {
+			local = append(local, tenant.OrgID(ctx))
+			return errors.New("not retried")
+		})
+	}
+	ctx := tenant.WithOrg(context.Background(), "org-1", "")
+	event := events.ProjectDeleted{Project: models.Project{ID: "project-1"}}
+
+	// In process they run like any other subscriber
+	bus := events.NewBus()
+	subscribe(bus)
+	bus.Publish(ctx, "", event)
+	assert.Equal(t, []string{"org-1"}, local)
+
+	// A transport that cannot broadcast runs them where the event is consumed
+	local = nil
+	transport := &fakeTransport{}
+	bus = events.NewBus()
+	bus.UseTransport(transport)
+	subscribe(bus)
+	bus.Publish(ctx, "", event)
+	require.NoError(t, bus.Run(context.Background()))
+	assert.Equal(t, []string{"org-1"}, local)
+	assert.NoError(t, transport.handleErr)
+
+	// Otherwise they run from the broadcast, and consuming leaves them out
+	local = nil
+	broadcaster := &fakeBroadcaster{}
+	bus = events.NewBus()
+	bus.UseTransport(broadcaster)
+	subscribe(bus)
+	bus.Publish(ctx, "", event)
+	require.NoError(t, bus.Run(context.Background()))
+	assert.Equal(t, []string{"org-1"}, local)
+}
@@ -0,0 +1,513 @@
+// Package events internal/events/types.go
+package events
+
+import "projectnexus/internal/models"
+
+// Events carry copies of what changed, so subscribers in another process
+// need not load it again. Updates carry the previous state as well.
+
+// Resource types events are about
+const (
+	ResourceProject      = "project"
+	ResourceDocument     = "document"
+	ResourceMockup       = "mockup"
+	ResourceFolder       = "folder"
+	ResourceLink         = "link"
+	ResourceMilestone    = "milestone"
+	ResourceTask         = "task"
+	ResourceBoard        = "board"
+	ResourceTeam         = "team"
+	ResourceInvitation   = "invitation"
+	ResourceOrganization = "organization"
+	ResourceWebhook      = "webhook"
+	ResourceTemplate     = "template"
+	ResourceShareLink    = "share_link"
+)
+
+// Subject is the resource an event is about. Membership changes are about
+// the project, team or organization whose members changed.
+type Subject struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	// ProjectID is the project the resource belongs to, if any
+	ProjectID string `json:"projectId,omitempty"`
+}
+
+func projectSubject(projectID string) Subject {
+	return Subject{Type: ResourceProject, ID: projectID, ProjectID: projectID}
+}
+
+func init() {
+	register(
+		ProjectCreated{}, ProjectUpdated{}, ProjectStatusChanged{}, ProjectDeleted{}, ProjectRestored{},
+		ProjectArchived{}, ProjectUnarchived{},
+		OwnershipTransferRequested{}, OwnershipTransferAccepted{}, OwnershipTransferDeclined{}, OwnershipTransferCancelled{},
+		MemberAdded{}, MemberUpdated{}, MemberRemoved{}, TeamAssigned{}, TeamAssignmentUpdated{}, TeamUnassigned{},
+		DocumentCreated{}, DocumentUpdated{}, DocumentStatusChanged{}, DocumentDeleted{}, DocumentRestored{},
+		MockupCreated{}, MockupUpdated{}, MockupDeleted{}, MockupRestored{},
+		FolderCreated{}, FolderUpdated{}, FoldersReordered{}, FolderDeleted{},
+		LinkCreated{}, LinkDeleted{},
+		MilestoneCreated{}, MilestoneUpdated{}, MilestoneDeleted{},
+		TaskCreated{}, TaskUpdated{}, TaskDeleted{}, BoardUpdated{},
+		ShareLinkCreated{}, ShareLinkRevoked{},
+	)
+}
+
+// ProjectCreated is published when a project is created, from scratch or
+// from a template
+type ProjectCreated struct {
+	Project models.Project `json:"project"`
+}
+
+func (ProjectCreated) EventName() string                     { return "project.created" }
+func (e ProjectCreated) Subject() Subject                    { return projectSubject(e.Project.ID) }
+func (e ProjectCreated) Change() (before, after interface{}) { return nil, e.Project }
+
+// ProjectUpdated is published when a project's details or settings change
+type ProjectUpdated struct {
+	Project  models.Project `json:"project"`
+	Previous models.Project `json:"previous"`
+}
+
+func (ProjectUpdated) EventName() string                     { return "project.updated" }
+func (e ProjectUpdated) Subject() Subject                    { return projectSubject(e.Project.ID) }
+func (e ProjectUpdated) Change() (before, after interface{}) { return e.Previous, e.Project }
+
+// ProjectStatusChanged is published along with ProjectUpdated when the
+// update changed the project's status
+type ProjectStatusChanged struct {
+	Project        models.Project       `json:"project"`
+	PreviousStatus models.ProjectStatus `json:"previousStatus"`
+}
+
+func (ProjectStatusChanged) EventName() string  { return "project.status_changed" }
+func (e ProjectStatusChanged) Subject() Subject { return projectSubject(e.Project.ID) }
+func (e ProjectStatusChanged) Change() (before, after interface{}) {
+	return statusOf{e.PreviousStatus}, statusOf{e.Project.Status}
+}
+
+// ProjectDeleted is published when a project is moved to the trash
+type ProjectDeleted struct {
+	Project models.Project `json:"project"`
+}
+
+func (ProjectDeleted) EventName() string                     { return "project.deleted" }
+func (e ProjectDeleted) Subject() Subject                    { return projectSubject(e.Project.ID) }
+func (e ProjectDeleted) Change() (before, after interface{}) { return e.Project, nil }
+
+// ProjectRestored is published when a project is taken out of the trash
+type ProjectRestored struct {
+	Project models.Project `json:"project"`
+}
+
+func (ProjectRestored) EventName() string  { return "project.restored" }
+func (e ProjectRestored) Subject() Subject { return projectSubject(e.Project.ID) }
+
+// ProjectArchived is published when a project is made read-only
+type ProjectArchived struct {
+	Project models.Project `json:"project"`
+}
+
+func (ProjectArchived) EventName() string  { return "project.archived" }
+func (e ProjectArchived) Subject() Subject { return projectSubject(e.Project.ID) }
+
+// ProjectUnarchived is published when an archived project is reopened
+type ProjectUnarchived struct {
+	Project models.Project `json:"project"`
+}
+
+func (ProjectUnarchived) EventName() string  { return "project.unarchived" }
+func (e ProjectUnarchived) Subject() Subject { return projectSubject(e.Project.ID) }
+
+// OwnershipTransferRequested is published when an owner offers ownership of
+// a project to another user
+type OwnershipTransferRequested struct {
+	ProjectID string                   `json:"projectId"`
+	Transfer  models.OwnershipTransfer `json:"transfer"`
+}
+
+func (OwnershipTransferRequested) EventName() string  { return "project.transfer_requested" }
+func (e OwnershipTransferRequested) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e OwnershipTransferRequested) Change() (before, after interface{}) {
+	return nil, e.Transfer
+}
+
+// OwnershipTransferAccepted is published when a user takes up ownership
+// offered to them
+type OwnershipTransferAccepted struct {
+	Project  models.Project           `json:"project"`
+	Transfer models.OwnershipTransfer `json:"transfer"`
+}
+
+func (OwnershipTransferAccepted) EventName() string  { return "project.transfer_accepted" }
+func (e OwnershipTransferAccepted) Subject() Subject { return projectSubject(e.Project.ID) }
+func (e OwnershipTransferAccepted) Change() (before, after interface{}) {
+	return e.Transfer, nil
+}
+
+// OwnershipTransferDeclined is published when a user turns down ownership
+// offered to them
+type OwnershipTransferDeclined struct {
+	ProjectID string                   `json:"projectId"`
+	Transfer  models.OwnershipTransfer `json:"transfer"`
+}
+
+func (OwnershipTransferDeclined) EventName() string  { return "project.transfer_declined" }
+func (e OwnershipTransferDeclined) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e OwnershipTransferDeclined) Change() (before, after interface{}) {
+	return e.Transfer, nil
+}
+
+// OwnershipTransferCancelled is published when an owner withdraws an offer
+// of ownership
+type OwnershipTransferCancelled struct {
+	ProjectID string                   `json:"projectId"`
+	Transfer  models.OwnershipTransfer `json:"transfer"`
+}
+
+func (OwnershipTransferCancelled) EventName() string  { return "project.transfer_cancelled" }
+func (e OwnershipTransferCancelled) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e OwnershipTransferCancelled) Change() (before, after interface{}) {
+	return e.Transfer, nil
+}
+
+// statusOf shows a status change as a change of the status field alone
+type statusOf struct {
+	Status interface{} `json:"status"`
+}
+
+// membership shows a member as the user and their role
+type membership struct {
+	UserID string      `json:"userId"`
+	Role   interface{} `json:"role,omitempty"`
+}
+
+// MemberAdded is published when a user is added to a project directly
+type MemberAdded struct {
+	ProjectID string          `json:"projectId"`
+	UserID    string          `json:"userId"`
+	Role      models.TeamRole `json:"role,omitempty"`
+}
+
+func (MemberAdded) EventName() string  { return "member.added" }
+func (e MemberAdded) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e MemberAdded) Change() (before, after interface{}) {
+	return nil, membership{e.UserID, e.Role}
+}
+
+// MemberUpdated is published when a project member's role or status is
+// changed by hand
+type MemberUpdated struct {
+	Member   models.TeamMember `json:"member"`
+	Previous models.TeamMember `json:"previous"`
+}
+
+func (MemberUpdated) EventName() string                     { return "member.updated" }
+func (e MemberUpdated) Subject() Subject                    { return projectSubject(e.Member.ProjectID) }
+func (e MemberUpdated) Change() (before, after interface{}) { return e.Previous, e.Member }
+
+// MemberRemoved is published when a user is removed from a project
+type MemberRemoved struct {
+	ProjectID string `json:"projectId"`
+	UserID    string `json:"userId"`
+}
+
+func (MemberRemoved) EventName() string  { return "member.removed" }
+func (e MemberRemoved) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e MemberRemoved) Change() (before, after interface{}) {
+	return membership{UserID: e.UserID}, nil
+}
+
+// TeamAssigned is published when a team is assigned to a project
+type TeamAssigned struct {
+	ProjectID  string                       `json:"projectId"`
+	Assignment models.ProjectTeamAssignment `json:"assignment"`
+}
+
+func (TeamAssigned) EventName() string                     { return "project.team_assigned" }
+func (e TeamAssigned) Subject() Subject                    { return projectSubject(e.ProjectID) }
+func (e TeamAssigned) Change() (before, after interface{}) { return nil, e.Assignment }
+
+// TeamAssignmentUpdated is published when the role of an assigned team
+// changes
+type TeamAssignmentUpdated struct {
+	ProjectID  string                       `json:"projectId"`
+	Assignment models.ProjectTeamAssignment `json:"assignment"`
+	Previous   models.ProjectTeamAssignment `json:"previous"`
+}
+
+func (TeamAssignmentUpdated) EventName() string  { return "project.team_assignment_updated" }
+func (e TeamAssignmentUpdated) Subject() Subject { return projectSubject(e.ProjectID) }
+func (e TeamAssignmentUpdated) Change() (before, after interface{}) {
+	return e.Previous, e.Assignment
+}
+
+// TeamUnassigned is published when a team is taken off a project
+type TeamUnassigned struct {
+	ProjectID  string                       `json:"projectId"`
+	Assignment models.ProjectTeamAssignment `json:"assignment"`
+}
+
+func (TeamUnassigned) EventName() string                     { return "project.team_unassigned" }
+func (e TeamUnassigned) Subject() Subject                    { return projectSubject(e.ProjectID) }
+func (e TeamUnassigned) Change() (before, after interface{}) { return e.Assignment, nil }
+
+func documentSubject(doc models.Document) Subject {
+	return Subject{Type: ResourceDocument, ID: doc.ID, ProjectID: doc.ProjectID}
+}
+
+// DocumentCreated is published when a document is created
+type DocumentCreated struct {
+	Document models.Document `json:"document"`
+}
+
+func (DocumentCreated) EventName() string                     { return "document.created" }
+func (e DocumentCreated) Subject() Subject                    { return documentSubject(e.Document) }
+func (e DocumentCreated) Change() (before, after interface{}) { return nil, e.Document }
+
+// DocumentUpdated is published when a document is edited, moved or tagged
+type DocumentUpdated struct {
+	Document models.Document `json:"document"`
+	Previous models.Document `json:"previous"`
+}
+
+func (DocumentUpdated) EventName() string                     { return "document.updated" }
+func (e DocumentUpdated) Subject() Subject                    { return documentSubject(e.Document) }
+func (e DocumentUpdated) Change() (before, after interface{}) { return e.Previous, e.Document }
+
+// DocumentStatusChanged is published along with DocumentUpdated when the
+// update changed the document's status
+type DocumentStatusChanged struct {
+	Document       models.Document       `json:"document"`
+	PreviousStatus models.DocumentStatus `json:"previousStatus"`
+}
+
+func (DocumentStatusChanged) EventName() string  { return "document.status_changed" }
+func (e DocumentStatusChanged) Subject() Subject { return documentSubject(e.Document) }
+func (e DocumentStatusChanged) Change() (before, after interface{}) {
+	return statusOf{e.PreviousStatus}, statusOf{e.Document.Status}
+}
+
+// DocumentDeleted is published when a document is moved to the trash
+type DocumentDeleted struct {
+	Document models.Document `json:"document"`
+}
+
+func (DocumentDeleted) EventName() string                     { return "document.deleted" }
+func (e DocumentDeleted) Subject() Subject                    { return documentSubject(e.Document) }
+func (e DocumentDeleted) Change() (before, after interface{}) { return e.Document, nil }
+
+// DocumentRestored is published when a document is taken out of the trash
+type DocumentRestored struct {
+	Document models.Document `json:"document"`
+}
+
+func (DocumentRestored) EventName() string  { return "document.restored" }
+func (e DocumentRestored) Subject() Subject { return documentSubject(e.Document) }
+
+func mockupSubject(mockup models.Mockup) Subject {
+	return Subject{Type: ResourceMockup, ID: mockup.ID, ProjectID: mockup.ProjectID}
+}
+
+// MockupCreated is published when a mockup is created
+type MockupCreated struct {
+	Mockup models.Mockup `json:"mockup"`
+}
+
+func (MockupCreated) EventName() string                     { return "mockup.created" }
+func (e MockupCreated) Subject() Subject                    { return mockupSubject(e.Mockup) }
+func (e MockupCreated) Change() (before, after interface{}) { return nil, e.Mockup }
+
+// MockupUpdated is published when a mockup is edited
+type MockupUpdated struct {
+	Mockup   models.Mockup `json:"mockup"`
+	Previous models.Mockup `json:"previous"`
+}
+
+func (MockupUpdated) EventName() string                     { return "mockup.updated" }
+func (e MockupUpdated) Subject() Subject                    { return mockupSubject(e.Mockup) }
+func (e MockupUpdated) Change() (before, after interface{}) { return e.Previous, e.Mockup }
+
+// MockupDeleted is published when a mockup is moved to the trash
+type MockupDeleted struct {
+	Mockup models.Mockup `json:"mockup"`
+}
+
+func (MockupDeleted) EventName() string                     { return "mockup.deleted" }
+func (e MockupDeleted) Subject() Subject                    { return mockupSubject(e.Mockup) }
+func (e MockupDeleted) Change() (before, after interface{}) { return e.Mockup, nil }
+
+// MockupRestored is published when a mockup is taken out of the trash
+type MockupRestored struct {
+	Mockup models.Mockup `json:"mockup"`
+}
+
+func (MockupRestored) EventName() string  { return "mockup.restored" }
+func (e MockupRestored) Subject() Subject { return mockupSubject(e.Mockup) }
+
+func folderSubject(folder models.Folder) Subject {
+	return Subject{Type: ResourceFolder, ID: folder.ID, ProjectID: folder.ProjectID}
+}
+
+// FolderCreated is published when a folder is created
+type FolderCreated struct {
+	Folder models.Folder `json:"folder"`
+}
+
+func (FolderCreated) EventName() string                     { return "folder.created" }
+func (e FolderCreated) Subject() Subject                    { return folderSubject(e.Folder) }
+func (e FolderCreated) Change() (before, after interface{}) { return nil, e.Folder }
+
+// FolderUpdated is published when a folder is renamed or moved
+type FolderUpdated struct {
+	Folder   models.Folder `json:"folder"`
+	Previous models.Folder `json:"previous"`
+}
+
+func (FolderUpdated) EventName() string                     { return "folder.updated" }
+func (e FolderUpdated) Subject() Subject                    { return folderSubject(e.Folder) }
+func (e FolderUpdated) Change() (before, after interface{}) { return e.Previous, e.Folder }
+
+// FoldersReordered is published when the folders under a parent are put in a
+// new order
+type FoldersReordered struct {
+	ProjectID string   `json:"projectId"`
+	ParentID  string   `json:"parentId,omitempty"`
+	FolderIDs []string `json:"folderIds"`
+}
+
+func (FoldersReordered) EventName() string  { return "folder.reordered" }
+func (e FoldersReordered) Subject() Subject { return projectSubject(e.ProjectID) }
+
+// FolderDeleted is published when a folder is deleted
+type FolderDeleted struct {
+	Folder models.Folder `json:"folder"`
+}
+
+func (FolderDeleted) EventName() string                     { return "folder.deleted" }
+func (e FolderDeleted) Subject() Subject                    { return folderSubject(e.Folder) }
+func (e FolderDeleted) Change() (before, after interface{}) { return e.Folder, nil }
+
+func linkSubject(link models.Link) Subject {
+	return Subject{Type: ResourceLink, ID: link.ID, ProjectID: link.ProjectID}
+}
+
+// LinkCreated is published when a link is added by hand
+type LinkCreated struct {
+	Link models.Link `json:"link"`
+}
+
+func (LinkCreated) EventName() string                     { return "link.created" }
+func (e LinkCreated) Subject() Subject                    { return linkSubject(e.Link) }
+func (e LinkCreated) Change() (before, after interface{}) { return nil, e.Link }
+
+// LinkDeleted is published when a link is removed by hand
+type LinkDeleted struct {
+	Link models.Link `json:"link"`
+}
+
+func (LinkDeleted) EventName() string                     { return "link.deleted" }
+func (e LinkDeleted) Subject() Subject                    { return linkSubject(e.Link) }
+func (e LinkDeleted) Change() (before, after interface{}) { return e.Link, nil }
+
+func milestoneSubject(milestone models.Milestone) Subject {
+	return Subject{Type: ResourceMilestone, ID: milestone.ID, ProjectID: milestone.ProjectID}
+}
+
+// MilestoneCreated is published when a milestone is created
+type MilestoneCreated struct {
+	Milestone models.Milestone `json:"milestone"`
+}
+
+func (MilestoneCreated) EventName() string                     { return "milestone.created" }
+func (e MilestoneCreated) Subject() Subject                    { return milestoneSubject(e.Milestone) }
+func (e MilestoneCreated) Change() (before, after interface{}) { return nil, e.Milestone }
+
+// MilestoneUpdated is published when a milestone or its checklist changes
+type MilestoneUpdated struct {
+	Milestone models.Milestone `json:"milestone"`
+	Previous  models.Milestone `json:"previous"`
+}
+
+func (MilestoneUpdated) EventName() string  { return "milestone.updated" }
+func (e MilestoneUpdated) Subject() Subject { return milestoneSubject(e.Milestone) }
+func (e MilestoneUpdated) Change() (before, after interface{}) {
+	return e.Previous, e.Milestone
+}
+
+// MilestoneDeleted is published when a milestone is deleted
+type MilestoneDeleted struct {
+	Milestone models.Milestone `json:"milestone"`
+}
+
+func (MilestoneDeleted) EventName() string                     { return "milestone.deleted" }
+func (e MilestoneDeleted) Subject() Subject                    { return milestoneSubject(e.Milestone) }
+func (e MilestoneDeleted) Change() (before, after interface{}) { return e.Milestone, nil }
+
+func taskSubject(task models.Task) Subject {
+	return Subject{Type: ResourceTask, ID: task.ID, ProjectID: task.ProjectID}
+}
+
+// TaskCreated is published when a task is created
+type TaskCreated struct {
+	Task models.Task `json:"task"`
+}
+
+func (TaskCreated) EventName() string                     { return "task.created" }
+func (e TaskCreated) Subject() Subject                    { return taskSubject(e.Task) }
+func (e TaskCreated) Change() (before, after interface{}) { return nil, e.Task }
+
+// TaskUpdated is published when a task is edited or moved on the board
+type TaskUpdated struct {
+	Task     models.Task `json:"task"`
+	Previous models.Task `json:"previous"`
+}
+
+func (TaskUpdated) EventName() string                     { return "task.updated" }
+func (e TaskUpdated) Subject() Subject                    { return taskSubject(e.Task) }
+func (e TaskUpdated) Change() (before, after interface{}) { return e.Previous, e.Task }
+
+// TaskDeleted is published when a task is deleted
+type TaskDeleted struct {
+	Task models.Task `json:"task"`
+}
+
+func (TaskDeleted) EventName() string                     { return "task.deleted" }
+func (e TaskDeleted) Subject() Subject                    { return taskSubject(e.Task) }
+func (e TaskDeleted) Change() (before, after interface{}) { return e.Task, nil }
+
+// BoardUpdated is published when the columns of a project's board change
+type BoardUpdated struct {
+	Board    models.Board `json:"board"`
+	Previous models.Board `json:"previous"`
+}
+
+func (BoardUpdated) EventName() string { return "board.updated" }
+func (e BoardUpdated) Subject() Subject {
+	return Subject{Type: ResourceBoard, ID: e.Board.ID, ProjectID: e.Board.ProjectID}
+}
+func (e BoardUpdated) Change() (before, after interface{}) { return e.Previous, e.Board }
+
+func shareLinkSubject(link models.ShareLink) Subject {
+	return Subject{Type: ResourceShareLink, ID: link.ID, ProjectID: link.ProjectID}
+}
+
+// ShareLinkCreated is published when a public share link is made for a
+// project presentation or a document
+type ShareLinkCreated struct {
+	ShareLink models.ShareLink `json:"shareLink"`
+}
+
+func (ShareLinkCreated) EventName() string                     { return "share_link.created" }
+func (e ShareLinkCreated) Subject() Subject                    { return shareLinkSubject(e.ShareLink) }
+func (e ShareLinkCreated) Change() (before, after interface{}) { return nil, e.ShareLink }
+
+// ShareLinkRevoked is published when a share link is revoked
+type ShareLinkRevoked struct {
+	ShareLink models.ShareLink `json:"shareLink"`
+	Previous  models.ShareLink `json:"previous"`
+}
+
+func (ShareLinkRevoked) EventName() string                     { return "share_link.revoked" }
+func (e ShareLinkRevoked) Subject() Subject                    { return shareLinkSubject(e.ShareLink) }
+func (e ShareLinkRevoked) Change() (before, after interface{}) { return e.Previous, e.ShareLink }
@@ -0,0 +1,61 @@
+// Package events internal/events/user.go
+package events
+
+import "projectnexus/internal/models"
+
+// Events about a user's own account. They are published in the organization
+// the user acts in, so its admins see them in the audit log.
+
+func init() {
+	register(UserRegistered{}, NotificationPreferencesUpdated{}, OrganizationSwitched{})
+}
+
+// ResourceUser is the type of events about a user's account
+const ResourceUser = "user"
+
+func userSubject(userID string) Subject {
+	return Subject{Type: ResourceUser, ID: userID}
+}
+
+// UserRegistered is published when someone signs up. It is published in the
+// workspace they get.
+type UserRegistered struct {
+	User models.User `json:"user"`
+}
+
+func (UserRegistered) EventName() string                     { return "user.registered" }
+func (e UserRegistered) Subject() Subject                    { return userSubject(e.User.ID) }
+func (e UserRegistered) Change() (before, after interface{}) { return nil, e.User }
+
+// NotificationPreferencesUpdated is published when a user changes which
+// notifications they get, including by following an unsubscribe link
+type NotificationPreferencesUpdated struct {
+	UserID      string                         `json:"userId"`
+	Preferences models.NotificationPreferences `json:"preferences"`
+	Previous    models.NotificationPreferences `json:"previous"`
+}
+
+func (NotificationPreferencesUpdated) EventName() string  { return "user.notification_preferences_updated" }
+func (e NotificationPreferencesUpdated) Subject() Subject { return userSubject(e.UserID) }
+func (e NotificationPreferencesUpdated) Change() (before, after interface{}) {
+	return e.Previous, e.Preferences
+}
+
+// OrganizationSwitched is published when a user switches to another
+// organization. It is published in the organization they switched to.
+type OrganizationSwitched struct {
+	UserID     string `json:"userId"`
+	OrgID      string `json:"orgId"`
+	PreviousID string `json:"previousOrgId,omitempty"`
+}
+
+func (OrganizationSwitched) EventName() string  { return "user.organization_switched" }
+func (e OrganizationSwitched) Subject() Subject { return userSubject(e.UserID) }
+func (e OrganizationSwitched) Change() (before, after interface{}) {
+	return currentOrg{e.PreviousID}, currentOrg{e.OrgID}
+}
+
+// currentOrg is how the audit log shows a switch of organization
+type currentOrg struct {
+	CurrentOrgID string `json:"currentOrgId"`
+}
@@ -0,0 +1,37 @@
+// Package mail internal/mail/mail.go
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// Message is an email with a plain text body and, optionally, an HTML
+// alternative
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	HTML    string
+	// Headers are added to the standard ones, e.g. List-Unsubscribe
+	Headers map[string]string
+}
+
+// Mailer delivers email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer writes messages to the log instead of sending them; it is used
+// when no mail server is configured
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	// The body carries invitation and reset links, so it stays out of the log
+	log.Printf("Mail to %s: %s", msg.To, msg.Subject)
+	return nil
+}
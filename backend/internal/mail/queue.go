@@ -0,0 +1,81 @@
+// Package mail internal/mail/queue.go
+package mail
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Queue sends email in the background with a fixed number of workers, so a
+// slow mail server holds up neither the changes that cause email nor an
+// unbounded number of goroutines
+type Queue struct {
+	mailer   Mailer
+	messages chan Message
+	workers  int
+	// timeout bounds sending one message
+	timeout time.Duration
+}
+
+func NewQueue(mailer Mailer, size int, workers int, timeout time.Duration) *Queue {
+	return &Queue{
+		mailer:   mailer,
+		messages: make(chan Message, size),
+		workers:  workers,
+		timeout:  timeout,
+	}
+}
+
+// Enqueue queues a message for sending and reports false when the queue is
+// full
+func (q *Queue) Enqueue(msg Message) bool {
+	select {
+	case q.messages <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run sends the queued messages until the context is done, then sends the
+// ones still queued before it returns
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case msg := <-q.messages:
+			q.send(msg)
+		case <-ctx.Done():
+			for {
+				select {
+				case msg := <-q.messages:
+					q.send(msg)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *Queue) send(msg Message) {
+	// Queued messages outlive the request that queued them
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+	if err := q.mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send %q to %s: %v", msg.Subject, msg.To, err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package mail internal/mail/template.go
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Each email has a plain text and an HTML template, templates/<name>.txt and
+// templates/<name>.html
+//
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/*.html"))
+)
+
+// Render fills the text and HTML bodies of a message from the named templates
+func Render(msg *Message, name string, data interface{}) error {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return fmt.Errorf("failed to render %s email: %w", name, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return fmt.Errorf("failed to render %s email: %w", name, err)
+	}
+
+	msg.Body = text.String()
+	msg.HTML = html.String()
+	return nil
+}
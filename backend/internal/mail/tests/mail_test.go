@@ -0,0 +1,214 @@
+// internal/mail/mail_test.go
+package tests
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"projectnexus/internal/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts one message the way a local stand-in like MailHog
+// would and hands back the envelope and data it received
+type fakeSMTPServer struct {
+	listener net.Listener
+	received chan received
+}
+
+type received struct {
+	from, to string
+	data     string
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener, received: make(chan received, 1)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		var msg received
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				msg.to = strings.Trim(line[len("RCPT TO:"):], "<>")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(l, "."))
+				}
+				msg.data = data.String()
+				reply("250 OK")
+				server.received <- msg
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) config() mail.SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return mail.SMTPConfig{
+		Host: "127.0.0.1",
+		Port: addr.Port,
+		From: "ProjectNexus <noreply@projectnexus.test>",
+	}
+}
+
+func TestSMTPMailer_SendsTextAndHTML(t *testing.T) {
+	server := startFakeSMTPServer(t)
+	mailer := mail.NewSMTPMailer(server.config())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := mailer.Send(ctx, mail.Message{
+		To:      "Jane Doe <jane@example.com>",
+		Subject: "Réunion approved",
+		Body:    "Plain body",
+		HTML:    "<p>HTML body</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://app.test/unsubscribe>"},
+	})
+	require.NoError(t, err)
+
+	var got received
+	select {
+	case got = <-server.received:
+	case <-ctx.Done():
+		t.Fatal("server received no message")
+	}
+	assert.Equal(t, "noreply@projectnexus.test", got.from)
+	assert.Equal(t, "jane@example.com", got.to)
+
+	msg, err := netmail.ReadMessage(strings.NewReader(got.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Réunion approved", subject)
+	assert.Equal(t, "<https://app.test/unsubscribe>", msg.Header.Get("List-Unsubscribe"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[contentType] = string(body)
+	}
+	assert.Equal(t, "Plain body", bodies["text/plain"])
+	assert.Equal(t, "<p>HTML body</p>", bodies["text/html"])
+}
+
+func TestSMTPMailer_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := mail.NewSMTPMailer(mail.SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@projectnexus.test"})
+	err = mailer.Send(context.Background(), mail.Message{To: "jane@example.com", Subject: "Hi", Body: "Hi"})
+	assert.ErrorContains(t, err, "failed to connect")
+
+	err = mailer.Send(context.Background(), mail.Message{To: "not an address", Subject: "Hi", Body: "Hi"})
+	assert.ErrorContains(t, err, "invalid recipient")
+}
+
+func TestRender(t *testing.T) {
+	var msg mail.Message
+	err := mail.Render(&msg, "notification", map[string]string{
+		"Name":            "Jane",
+		"Message":         "Alice approved <LLD>",
+		"Link":            "https://app.test/documents/1",
+		"UnsubscribeLink": "https://app.test/unsubscribe?token=t",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, msg.Body, "Alice approved <LLD>.")
+	assert.Contains(t, msg.Body, "https://app.test/unsubscribe?token=t")
+	// The HTML version escapes content
+	assert.Contains(t, msg.HTML, "Alice approved &lt;LLD&gt;.")
+	assert.Contains(t, msg.HTML, `href="https://app.test/documents/1"`)
+
+	assert.Error(t, mail.Render(&msg, "missing", nil))
+}
+
+// recordingMailer collects the messages it is asked to send
+type recordingMailer struct {
+	sent chan mail.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mail.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestQueue_SendsQueuedMessagesBeforeStopping(t *testing.T) {
+	mailer := &recordingMailer{sent: make(chan mail.Message, 3)}
+	queue := mail.NewQueue(mailer, 2, 1, time.Second)
+
+	assert.True(t, queue.Enqueue(mail.Message{To: "a@example.com"}))
+	assert.True(t, queue.Enqueue(mail.Message{To: "b@example.com"}))
+	// A full queue turns messages away instead of blocking the caller
+	assert.False(t, queue.Enqueue(mail.Message{To: "c@example.com"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queue.Run(ctx)
+
+	require.Len(t, mailer.sent, 2)
+	assert.Equal(t, "a@example.com", (<-mailer.sent).To)
+	assert.Equal(t, "b@example.com", (<-mailer.sent).To)
+}
@@ -32,6 +32,7 @@ func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
 		// Set both user object and userID in context
 		c.Set("user", user)
 		c.Set("userID", user.ID) // Make sure to set userID specifically
+		c.Set("token", parts[1])
 
 		// Add debug logging
 		log.Printf("User authenticated: ID=%s, Email=%s", user.ID, user.Email)
@@ -39,3 +40,35 @@ func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StreamTicketParam carries a ticket from POST /streams/ticket on requests
+// that open an event stream
+const StreamTicketParam = "ticket"
+
+// StreamAuthMiddleware authenticates event streams. Browsers open them with
+// EventSource, which cannot send an Authorization header, so a one-time
+// ticket in the query stands in for it; the header still works for other
+// clients. The ticket also picks the organization the stream runs in.
+func StreamAuthMiddleware(authService services.AuthService) gin.HandlerFunc {
+	withHeader := AuthMiddleware(authService)
+	return func(c *gin.Context) {
+		ticket := c.Query(StreamTicketParam)
+		if ticket == "" {
+			withHeader(c)
+			return
+		}
+
+		user, session, err := authService.RedeemStreamTicket(c.Request.Context(), ticket)
+		if err != nil {
+			log.Printf("Stream ticket redemption failed: %v", err)
+			c.AbortWithStatusJSON(401, gin.H{"error": "invalid stream ticket"})
+			return
+		}
+
+		c.Set("user", user)
+		c.Set("userID", user.ID)
+		c.Set("token", session.Token)
+		c.Set(requestedOrgKey, session.OrgID)
+		c.Next()
+	}
+}
@@ -0,0 +1,20 @@
+// Package middleware internal/middleware/requestmeta.go
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"projectnexus/internal/requestmeta"
+)
+
+// RequestMeta records the client address and user agent of the request on
+// its context, where the audit log picks them up
+func RequestMeta() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(requestmeta.With(c.Request.Context(), requestmeta.Meta{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}))
+
+		c.Next()
+	}
+}
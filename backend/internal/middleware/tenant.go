@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+)
+
+// OrganizationHeader lets a request pick the organization it acts in instead
+// of the user's current one
+const OrganizationHeader = "X-Organization-ID"
+
+// requestedOrgKey holds the organization picked by something other than the
+// header, such as a stream ticket
+const requestedOrgKey = "requestedOrgID"
+
+// TenantMiddleware scopes the request to an organization of the signed-in
+// user. It must run after AuthMiddleware.
+func TenantMiddleware(orgService services.OrganizationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user")
+		user, ok := value.(*models.User)
+		if !ok {
+			c.AbortWithStatusJSON(401, gin.H{"error": "authentication required"})
+			return
+		}
+
+		requested := c.GetHeader(OrganizationHeader)
+		if requested == "" {
+			requested = c.GetString(requestedOrgKey)
+		}
+		org, role, err := orgService.Resolve(c.Request.Context(), user, requested)
+		if err != nil {
+			if errors.Is(err, errs.ErrNotInOrg) {
+				c.AbortWithStatusJSON(403, gin.H{"error": "not a member of this organization"})
+				return
+			}
+			log.Printf("Failed to resolve organization for user %s: %v", user.ID, err)
+			c.AbortWithStatusJSON(500, gin.H{"error": "failed to resolve organization"})
+			return
+		}
+
+		c.Set("orgID", org.ID)
+		c.Set("orgRole", role)
+		c.Request = c.Request.WithContext(tenant.WithOrg(c.Request.Context(), org.ID, role))
+
+		c.Next()
+	}
+}
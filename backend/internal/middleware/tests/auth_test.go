@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"projectnexus/internal/errors"
 	"projectnexus/internal/middleware"
 	"projectnexus/internal/models"
 	"testing"
@@ -42,6 +43,43 @@ func (m *MockAuthService) ValidateToken(token string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockAuthService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) IssueStreamTicket(ctx context.Context, token string, orgID string) (*models.StreamTicket, error) {
+	args := m.Called(ctx, token, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StreamTicket), args.Error(1)
+}
+
+func (m *MockAuthService) RedeemStreamTicket(ctx context.Context, ticket string) (*models.User, *models.StreamSession, error) {
+	args := m.Called(ctx, ticket)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.User), args.Get(1).(*models.StreamSession), args.Error(2)
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -70,3 +108,45 @@ func TestAuthMiddleware(t *testing.T) {
 		mockAuth.AssertExpectations(t)
 	})
 }
+
+func TestStreamAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(mockAuth *MockAuthService, url string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		var seen *gin.Context
+		_, r := gin.CreateTestContext(w)
+		r.Use(middleware.StreamAuthMiddleware(mockAuth))
+		r.GET("/stream", func(c *gin.Context) {
+			seen = c
+			c.Status(http.StatusOK)
+		})
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		r.ServeHTTP(w, req)
+		return w, seen
+	}
+
+	t.Run("ticket stands in for the header", func(t *testing.T) {
+		mockAuth := new(MockAuthService)
+		user := &models.User{ID: "123"}
+		mockAuth.On("RedeemStreamTicket", mock.Anything, "ticket-1").Return(user, &models.StreamSession{Token: "session", OrgID: "org-1"}, nil)
+
+		w, c := serve(mockAuth, "/stream?ticket=ticket-1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "123", c.GetString("userID"))
+		assert.Equal(t, "session", c.GetString("token"))
+	})
+
+	t.Run("used ticket", func(t *testing.T) {
+		mockAuth := new(MockAuthService)
+		mockAuth.On("RedeemStreamTicket", mock.Anything, "ticket-1").Return(nil, nil, errors.ErrInvalidToken)
+
+		w, _ := serve(mockAuth, "/stream?ticket=ticket-1")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no ticket or header", func(t *testing.T) {
+		w, _ := serve(new(MockAuthService), "/stream")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,165 @@
+// Package models internal/models/activity.go
+package models
+
+import (
+	"fmt"
+	"projectnexus/internal/pagination"
+	"time"
+)
+
+// ActivityBurstWindow is how long after someone's last change another one of
+// the same kind to the same thing joins its entry rather than starting a new
+// one
+const ActivityBurstWindow = 15 * time.Minute
+
+// ActivityType groups activity by what it is about
+type ActivityType string
+
+const (
+	ActivityTypeProject  ActivityType = "project"
+	ActivityTypeDocument ActivityType = "document"
+	ActivityTypeMockup   ActivityType = "mockup"
+	ActivityTypeTeam     ActivityType = "team"
+)
+
+func (t ActivityType) IsValid() bool {
+	switch t {
+	case ActivityTypeProject, ActivityTypeDocument, ActivityTypeMockup, ActivityTypeTeam:
+		return true
+	default:
+		return false
+	}
+}
+
+// ActivityVerb is what someone did
+type ActivityVerb string
+
+const (
+	ActivityProjectCreated    ActivityVerb = "project.created"
+	ActivityProjectUpdated    ActivityVerb = "project.updated"
+	ActivityProjectStatus     ActivityVerb = "project.status_changed"
+	ActivityProjectArchived   ActivityVerb = "project.archived"
+	ActivityProjectUnarchived ActivityVerb = "project.unarchived"
+	ActivityProjectDeleted    ActivityVerb = "project.deleted"
+	ActivityProjectRestored   ActivityVerb = "project.restored"
+
+	ActivityDocumentCreated  ActivityVerb = "document.created"
+	ActivityDocumentEdited   ActivityVerb = "document.edited"
+	ActivityDocumentStatus   ActivityVerb = "document.status_changed"
+	ActivityDocumentApproved ActivityVerb = "document.approved"
+	ActivityDocumentDeleted  ActivityVerb = "document.deleted"
+	ActivityDocumentRestored ActivityVerb = "document.restored"
+
+	ActivityMockupCreated  ActivityVerb = "mockup.created"
+	ActivityMockupEdited   ActivityVerb = "mockup.edited"
+	ActivityMockupDeleted  ActivityVerb = "mockup.deleted"
+	ActivityMockupRestored ActivityVerb = "mockup.restored"
+
+	ActivityMemberAdded    ActivityVerb = "member.added"
+	ActivityMemberUpdated  ActivityVerb = "member.updated"
+	ActivityMemberRemoved  ActivityVerb = "member.removed"
+	ActivityTeamAssigned   ActivityVerb = "team.assigned"
+	ActivityTeamUnassigned ActivityVerb = "team.unassigned"
+)
+
+// Activity is an entry in the activity feeds: something someone did in a
+// project. Repeats of the same change in quick succession share one entry,
+// counted in Count.
+type Activity struct {
+	ID          string       `bson:"_id,omitempty" json:"id"`
+	OrgID       string       `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID   string       `bson:"project_id" json:"projectId"`
+	ProjectName string       `bson:"project_name" json:"projectName"`
+	ActorID     string       `bson:"actor_id" json:"actorId"`
+	ActorName   string       `bson:"actor_name" json:"actorName"`
+	Type        ActivityType `bson:"type" json:"type"`
+	Verb        ActivityVerb `bson:"verb" json:"verb"`
+	// ResourceID and Subject are the document, mockup, member or team acted
+	// on, or the project itself
+	ResourceID string `bson:"resource_id" json:"resourceId"`
+	Subject    string `bson:"subject" json:"subject"`
+	// Detail is the new status or role, for changes of either
+	Detail string `bson:"detail,omitempty" json:"detail,omitempty"`
+	Count  int    `bson:"count" json:"count"`
+	// Message is the entry as shown to people, e.g. "Alice approved LLD v4"
+	Message string `bson:"-" json:"message"`
+	// EventIDs are the latest events counted in the entry, so that an event
+	// delivered twice is counted once
+	EventIDs  []string  `bson:"event_ids" json:"-"`
+	StartedAt time.Time `bson:"started_at" json:"startedAt"`
+	// OccurredAt is when the latest change of the entry was made
+	OccurredAt time.Time `bson:"occurred_at" json:"occurredAt"`
+}
+
+// Describe writes the message shown for the entry
+func (a *Activity) Describe() string {
+	actor := a.ActorName
+	if actor == "" {
+		actor = "Someone"
+	}
+
+	var message string
+	switch a.Verb {
+	case ActivityProjectCreated:
+		message = fmt.Sprintf("%s created project %s", actor, a.Subject)
+	case ActivityProjectUpdated:
+		message = fmt.Sprintf("%s updated project %s", actor, a.Subject)
+	case ActivityProjectStatus:
+		message = fmt.Sprintf("%s moved project %s to %s", actor, a.Subject, a.Detail)
+	case ActivityProjectArchived:
+		message = fmt.Sprintf("%s archived project %s", actor, a.Subject)
+	case ActivityProjectUnarchived:
+		message = fmt.Sprintf("%s unarchived project %s", actor, a.Subject)
+	case ActivityProjectDeleted:
+		message = fmt.Sprintf("%s deleted project %s", actor, a.Subject)
+	case ActivityProjectRestored:
+		message = fmt.Sprintf("%s restored project %s", actor, a.Subject)
+	case ActivityDocumentCreated:
+		message = fmt.Sprintf("%s created %s", actor, a.Subject)
+	case ActivityDocumentEdited:
+		message = fmt.Sprintf("%s edited %s", actor, a.Subject)
+	case ActivityDocumentStatus:
+		message = fmt.Sprintf("%s changed the status of %s to %s", actor, a.Subject, a.Detail)
+	case ActivityDocumentApproved:
+		message = fmt.Sprintf("%s approved %s", actor, a.Subject)
+	case ActivityDocumentDeleted:
+		message = fmt.Sprintf("%s deleted %s", actor, a.Subject)
+	case ActivityDocumentRestored:
+		message = fmt.Sprintf("%s restored %s", actor, a.Subject)
+	case ActivityMockupCreated:
+		message = fmt.Sprintf("%s created mockup %s", actor, a.Subject)
+	case ActivityMockupEdited:
+		message = fmt.Sprintf("%s edited mockup %s", actor, a.Subject)
+	case ActivityMockupDeleted:
+		message = fmt.Sprintf("%s deleted mockup %s", actor, a.Subject)
+	case ActivityMockupRestored:
+		message = fmt.Sprintf("%s restored mockup %s", actor, a.Subject)
+	case ActivityMemberAdded:
+		message = fmt.Sprintf("%s added %s to the team", actor, a.Subject)
+	case ActivityMemberUpdated:
+		message = fmt.Sprintf("%s made %s %s", actor, a.Subject, a.Detail)
+	case ActivityMemberRemoved:
+		message = fmt.Sprintf("%s removed %s from the team", actor, a.Subject)
+	case ActivityTeamAssigned:
+		message = fmt.Sprintf("%s assigned team %s", actor, a.Subject)
+	case ActivityTeamUnassigned:
+		message = fmt.Sprintf("%s unassigned team %s", actor, a.Subject)
+	default:
+		message = fmt.Sprintf("%s changed %s", actor, a.Subject)
+	}
+
+	if a.Count > 1 {
+		message = fmt.Sprintf("%s (%d times)", message, a.Count)
+	}
+	return message
+}
+
+// ActivityListSpec describes the sorting and filtering of the activity
+// feeds; the latest activity comes first
+var ActivityListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "occurredAt", Field: "occurred_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-occurredAt",
+	Filters:     []string{pagination.FilterType, pagination.FilterActor, pagination.FilterProject},
+}
@@ -0,0 +1,181 @@
+// Package models internal/models/audit.go
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"projectnexus/internal/pagination"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// AuditEntry records one change: who made it, to what, from where, and how
+// the resource differed before and after. Entries are never changed or
+// removed.
+type AuditEntry struct {
+	ID    string `bson:"_id,omitempty" json:"id"`
+	OrgID string `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	// EventID is the event the entry was recorded from; an event recorded
+	// twice still has a single entry
+	EventID string `bson:"event_id" json:"eventId"`
+	// Action is the name of the event, e.g. "member.removed"
+	Action  string `bson:"action" json:"action"`
+	ActorID string `bson:"actor_id,omitempty" json:"actorId,omitempty"`
+	// ActorEmail is the actor's email when the change was made, kept in case
+	// the account changes or goes away
+	ActorEmail   string        `bson:"actor_email,omitempty" json:"actorEmail,omitempty"`
+	ResourceType string        `bson:"resource_type" json:"resourceType"`
+	ResourceID   string        `bson:"resource_id" json:"resourceId"`
+	ProjectID    string        `bson:"project_id,omitempty" json:"projectId,omitempty"`
+	Changes      []AuditChange `bson:"changes,omitempty" json:"changes"`
+	IP           string        `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent    string        `bson:"user_agent,omitempty" json:"userAgent,omitempty"`
+	OccurredAt   time.Time     `bson:"occurred_at" json:"occurredAt"`
+}
+
+// AuditChange is a field whose value changed. The values are kept as the JSON
+// the API shows for the field.
+type AuditChange struct {
+	Field  string     `bson:"field" json:"field"`
+	Before AuditValue `bson:"before,omitempty" json:"before"`
+	After  AuditValue `bson:"after,omitempty" json:"after"`
+}
+
+// AuditValue is a JSON value stored as text; empty means the field had no
+// value
+type AuditValue string
+
+func (v AuditValue) MarshalJSON() ([]byte, error) {
+	if v == "" {
+		return []byte("null"), nil
+	}
+	return []byte(v), nil
+}
+
+func (v *AuditValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	*v = AuditValue(data)
+	return nil
+}
+
+const (
+	// auditTextLimit is how many characters of long text a change keeps
+	auditTextLimit = 500
+)
+
+// auditIgnoredFields are maintained by the system and would show up in every
+// change
+var auditIgnoredFields = map[string]bool{
+	"id":        true,
+	"orgId":     true,
+	"createdAt": true,
+	"updatedAt": true,
+	"version":   true,
+}
+
+// AuditDiff lists the fields that differ between two states of a resource,
+// by their JSON names. Either state may be nil, for creations and deletions;
+// empty fields of the missing side's counterpart are left out.
+func AuditDiff(before, after interface{}) []AuditChange {
+	beforeFields := auditFields(before)
+	afterFields := auditFields(after)
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []AuditChange{}
+	for _, name := range names {
+		if auditIgnoredFields[name] {
+			continue
+		}
+		b, a := beforeFields[name], afterFields[name]
+		if bytes.Equal(b, a) || (isEmptyJSON(b) && isEmptyJSON(a)) {
+			continue
+		}
+		changes = append(changes, AuditChange{
+			Field:  name,
+			Before: auditValue(b),
+			After:  auditValue(a),
+		})
+	}
+	return changes
+}
+
+// auditFields returns the top-level JSON fields of a value
+func auditFields(v interface{}) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch string(raw) {
+	case "", "null", `""`, "[]", "{}", "false", "0":
+		return true
+	default:
+		return false
+	}
+}
+
+// auditValue keeps a field's value, shortening long text such as document
+// content
+func auditValue(raw json.RawMessage) AuditValue {
+	if isEmptyJSON(raw) && string(raw) != "false" && string(raw) != "0" {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil && utf8.RuneCountInString(text) > auditTextLimit {
+		short, _ := json.Marshal(string([]rune(text)[:auditTextLimit]) + "…")
+		return AuditValue(short)
+	}
+	return AuditValue(raw)
+}
+
+// AuditExportFormat is the file format of an audit log export
+type AuditExportFormat string
+
+const (
+	AuditExportCSV  AuditExportFormat = "csv"
+	AuditExportJSON AuditExportFormat = "json"
+)
+
+func (f AuditExportFormat) IsValid() bool {
+	return f == AuditExportCSV || f == AuditExportJSON
+}
+
+// AuditListSpec describes the sorting and filtering of the audit log; the
+// latest changes come first
+var AuditListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "occurredAt", Field: "occurred_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-occurredAt",
+	Filters: []string{
+		pagination.FilterActor,
+		pagination.FilterAction,
+		pagination.FilterResourceType,
+		pagination.FilterResourceID,
+		pagination.FilterProject,
+		pagination.FilterFrom,
+		pagination.FilterTo,
+	},
+}
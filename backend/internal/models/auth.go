@@ -10,6 +10,9 @@ type RegisterInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 	Name     string `json:"name" binding:"required"`
+	// InvitationToken, from an invitation link, joins the new account to
+	// the project or team it was invited to
+	InvitationToken string `json:"invitationToken,omitempty"`
 }
 
 type LoginInput struct {
@@ -22,6 +25,21 @@ type AuthResponse struct {
 	User  *User  `json:"user"`
 }
 
+// StreamTicket opens an event stream in place of the session token, for
+// clients such as the browser's EventSource that cannot send headers. It
+// works once and only briefly.
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// StreamSession is what a stream ticket stands for: the session it was
+// issued to and the organization it was issued in
+type StreamSession struct {
+	Token string `json:"token"`
+	OrgID string `json:"orgId,omitempty"`
+}
+
 // Claims represents our custom JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
@@ -3,6 +3,7 @@ package models
 
 import (
 	"fmt"
+	"projectnexus/internal/pagination"
 	"strings"
 	"time"
 )
@@ -27,24 +28,41 @@ const (
 
 type Document struct {
 	ID        string         `bson:"_id,omitempty" json:"id"`
+	OrgID     string         `bson:"org_id,omitempty" json:"orgId,omitempty"`
 	ProjectID string         `bson:"project_id" json:"projectId"`
 	Title     string         `bson:"title" json:"title"`
 	Type      DocumentType   `bson:"type" json:"type"`
 	Content   string         `bson:"content" json:"content"`
 	Version   int            `bson:"version" json:"version"`
 	Status    DocumentStatus `bson:"status" json:"status"` // Add status field
+	FolderID  string         `bson:"folder_id" json:"folderId"`
+	Tags      []string       `bson:"tags" json:"tags"`
 	CreatedBy string         `bson:"created_by" json:"createdBy"`
 	CreatedAt time.Time      `bson:"created_at" json:"createdAt"`
 	UpdatedAt time.Time      `bson:"updated_at" json:"updatedAt"`
+	DeletedAt *time.Time     `bson:"deleted_at,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy string         `bson:"deleted_by,omitempty" json:"deletedBy,omitempty"`
+	// UpdatedBy saved the latest version, while CreatedBy stays the author
+	UpdatedBy string `bson:"updated_by,omitempty" json:"updatedBy,omitempty"`
+	// ApprovedAt is when the document was last approved, for the approval
+	// lead times in the statistics
+	ApprovedAt *time.Time `bson:"approved_at,omitempty" json:"approvedAt,omitempty"`
+	// CascadeDeleted marks documents trashed together with their project,
+	// so restoring the project brings back only those
+	CascadeDeleted bool `bson:"cascade_deleted,omitempty" json:"-"`
 }
 
 type DocumentVersion struct {
 	ID         string    `bson:"_id,omitempty" json:"id"`
+	OrgID      string    `bson:"org_id,omitempty" json:"orgId,omitempty"`
 	DocumentID string    `bson:"document_id" json:"documentId"`
 	Version    int       `bson:"version" json:"version"`
 	Content    string    `bson:"content" json:"content"`
 	CreatedBy  string    `bson:"created_by" json:"createdBy"`
 	CreatedAt  time.Time `bson:"created_at" json:"createdAt"`
+	// Status is the status the document was saved with; versions written
+	// before it was recorded have none
+	Status DocumentStatus `bson:"status,omitempty" json:"status,omitempty"`
 }
 
 type CreateDocumentInput struct {
@@ -53,6 +71,8 @@ type CreateDocumentInput struct {
 	Type      DocumentType   `json:"type" binding:"required"`
 	Content   string         `json:"content" binding:"required"`
 	Status    DocumentStatus `json:"status" binding:"required"`
+	FolderID  string         `json:"folderId"`
+	Tags      []string       `json:"tags"`
 }
 
 type UpdateDocumentInput struct {
@@ -60,6 +80,16 @@ type UpdateDocumentInput struct {
 	Type    *DocumentType   `json:"type,omitempty"`
 	Content *string         `json:"content,omitempty"`
 	Status  *DocumentStatus `json:"status,omitempty"`
+	// FolderID moves the document; an empty string moves it out of any folder
+	FolderID *string   `json:"folderId,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+}
+
+// OnlyOrganization reports whether the update only touches folder or tags,
+// which does not produce a new document version
+func (i *UpdateDocumentInput) OnlyOrganization() bool {
+	return i.Title == nil && i.Type == nil && i.Content == nil && i.Status == nil &&
+		(i.FolderID != nil || i.Tags != nil)
 }
 
 func (i *UpdateDocumentInput) Validate() error {
@@ -87,6 +117,18 @@ func (t DocumentType) IsValid() bool {
 	}
 }
 
+func (s DocumentStatus) IsValid() bool {
+	switch s {
+	case DocumentStatusDraft,
+		DocumentStatusInReview,
+		DocumentStatusApproved,
+		DocumentStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
 func (d *Document) Validate() error {
 	if d.ProjectID == "" {
 		return fmt.Errorf("project ID is required")
@@ -121,3 +163,50 @@ func (i *CreateDocumentInput) Validate() error {
 	}
 	return nil
 }
+
+var documentSorts = []pagination.SortField{
+	{Key: "title", Field: "title", Kind: pagination.SortString},
+	{Key: "type", Field: "type", Kind: pagination.SortString},
+	{Key: "status", Field: "status", Kind: pagination.SortString},
+	{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+	{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+}
+
+var documentFilters = []string{
+	pagination.FilterStatus,
+	pagination.FilterType,
+	pagination.FilterCreatedBy,
+	pagination.FilterUpdatedSince,
+}
+
+// ApprovingVersion returns the version that approved a document still
+// approved: the first of the approved versions at the end of its history.
+// versions are ordered latest first, as the repository returns them. It
+// returns nil when the latest version is not approved or its status was not
+// recorded.
+func ApprovingVersion(versions []*DocumentVersion) *DocumentVersion {
+	var approving *DocumentVersion
+	for _, version := range versions {
+		if version.Status != DocumentStatusApproved {
+			break
+		}
+		approving = version
+	}
+	return approving
+}
+
+// DocumentListSpec describes the sorting and filtering of the documents
+// across all of a user's projects; the most recently updated come first
+var DocumentListSpec = pagination.Spec{
+	Sorts:       documentSorts,
+	DefaultSort: "-updatedAt",
+	Filters:     documentFilters,
+}
+
+// ProjectDocumentListSpec describes the sorting and filtering of the
+// documents of one project, listed by title like a folder view
+var ProjectDocumentListSpec = pagination.Spec{
+	Sorts:       documentSorts,
+	DefaultSort: "title",
+	Filters:     documentFilters,
+}
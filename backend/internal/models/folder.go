@@ -0,0 +1,144 @@
+// Package models internal/models/folder.go
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxTagLength caps the length of a single document tag
+const MaxTagLength = 50
+
+type Folder struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	OrgID     string    `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID string    `bson:"project_id" json:"projectId"`
+	ParentID  string    `bson:"parent_id" json:"parentId"` // Empty for top-level folders
+	Name      string    `bson:"name" json:"name"`
+	Position  int       `bson:"position" json:"position"`
+	CreatedBy string    `bson:"created_by" json:"createdBy"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// FolderNode is a folder with its nested sub-folders, used for tree responses
+type FolderNode struct {
+	*Folder
+	Children []*FolderNode `json:"children"`
+}
+
+// BuildFolderTree nests a flat list of project folders by parent, ordering
+// siblings by position. Folders whose parent is missing are treated as roots.
+func BuildFolderTree(folders []*Folder) []*FolderNode {
+	nodes := make(map[string]*FolderNode, len(folders))
+	for _, f := range folders {
+		nodes[f.ID] = &FolderNode{Folder: f, Children: []*FolderNode{}}
+	}
+
+	roots := make([]*FolderNode, 0)
+	for _, f := range folders {
+		node := nodes[f.ID]
+		if parent, ok := nodes[f.ParentID]; ok && f.ParentID != f.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sortNodes func(list []*FolderNode)
+	sortNodes = func(list []*FolderNode) {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Position != list[j].Position {
+				return list[i].Position < list[j].Position
+			}
+			return list[i].Name < list[j].Name
+		})
+		for _, n := range list {
+			sortNodes(n.Children)
+		}
+	}
+	sortNodes(roots)
+
+	return roots
+}
+
+type CreateFolderInput struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID string `json:"parentId"`
+}
+
+func (i *CreateFolderInput) Validate() error {
+	return validateFolderName(i.Name)
+}
+
+type RenameFolderInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (i *RenameFolderInput) Validate() error {
+	return validateFolderName(i.Name)
+}
+
+type MoveFolderInput struct {
+	ParentID string `json:"parentId"`           // Empty moves the folder to the top level
+	Position *int   `json:"position,omitempty"` // Defaults to the end of the new parent
+}
+
+func (i *MoveFolderInput) Validate() error {
+	if i.Position != nil && *i.Position < 0 {
+		return fmt.Errorf("position cannot be negative")
+	}
+	return nil
+}
+
+type ReorderFoldersInput struct {
+	ParentID  string   `json:"parentId"`
+	FolderIDs []string `json:"folderIds" binding:"required"`
+}
+
+func validateFolderName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("folder name is required")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("folder name cannot contain '/'")
+	}
+	return nil
+}
+
+// DocumentFilter narrows project document listings
+type DocumentFilter struct {
+	// FolderID restricts results to one folder; a pointer to "" selects
+	// documents outside any folder and nil disables the filter.
+	FolderID *string
+	Tags     []string
+	// Status restricts results to documents currently in that status; ""
+	// disables the filter
+	Status DocumentStatus
+	// ContentLength cuts the content FindByProject loads to at most that
+	// many characters, for listings that only show its start; 0 loads it
+	// whole
+	ContentLength int
+}
+
+// NormalizeTags trims, lowercases and de-duplicates tags, dropping empty ones
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q exceeds %d characters", tag, MaxTagLength)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result, nil
+}
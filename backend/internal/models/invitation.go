@@ -0,0 +1,71 @@
+// Package models internal/models/invitation.go
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusDeclined InvitationStatus = "declined"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	// InvitationStatusExpired closes a pending invitation past its expiry
+	// when the same address is invited again
+	InvitationStatusExpired InvitationStatus = "expired"
+)
+
+// Invitation asks someone, by email, to join a project or a team. Exactly
+// one of ProjectID and TeamID is set.
+type Invitation struct {
+	ID          string           `bson:"_id,omitempty" json:"id"`
+	OrgID       string           `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	Email       string           `bson:"email" json:"email"`
+	Role        TeamRole         `bson:"role" json:"role"`
+	ProjectID   string           `bson:"project_id,omitempty" json:"projectId,omitempty"`
+	TeamID      string           `bson:"team_id,omitempty" json:"teamId,omitempty"`
+	InvitedBy   string           `bson:"invited_by" json:"invitedBy"`
+	Status      InvitationStatus `bson:"status" json:"status"`
+	ExpiresAt   time.Time        `bson:"expires_at" json:"expiresAt"`
+	RespondedBy string           `bson:"responded_by,omitempty" json:"respondedBy,omitempty"`
+	RespondedAt *time.Time       `bson:"responded_at,omitempty" json:"respondedAt,omitempty"`
+	CreatedAt   time.Time        `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time        `bson:"updated_at" json:"updatedAt"`
+}
+
+// IsOpen reports whether the invitation can still be accepted or declined
+func (i *Invitation) IsOpen(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
+
+type CreateInvitationInput struct {
+	Email string   `json:"email" binding:"required"`
+	Role  TeamRole `json:"role" binding:"required"`
+}
+
+// Validate checks the input and normalizes the email address
+func (i *CreateInvitationInput) Validate() error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(i.Email))
+	if err != nil {
+		return errors.New("invalid email address")
+	}
+	i.Email = NormalizeEmail(addr.Address)
+	if !i.Role.IsValid() {
+		return errors.New("invalid team role")
+	}
+	return nil
+}
+
+type InvitationTokenInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// NormalizeEmail is the form email addresses are compared in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
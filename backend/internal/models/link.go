@@ -0,0 +1,148 @@
+// Package models internal/models/link.go
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type LinkType string
+
+const (
+	LinkTypeReferences LinkType = "references"
+	LinkTypeImplements LinkType = "implements"
+	LinkTypeSupersedes LinkType = "supersedes"
+	LinkTypeDependsOn  LinkType = "depends-on"
+)
+
+func (t LinkType) IsValid() bool {
+	switch t {
+	case LinkTypeReferences,
+		LinkTypeImplements,
+		LinkTypeSupersedes,
+		LinkTypeDependsOn:
+		return true
+	default:
+		return false
+	}
+}
+
+// LinkItemType identifies the kind of item on either end of a link
+type LinkItemType string
+
+const (
+	LinkItemDocument LinkItemType = "document"
+	LinkItemMockup   LinkItemType = "mockup"
+	LinkItemProject  LinkItemType = "project"
+)
+
+func (t LinkItemType) IsValid() bool {
+	switch t {
+	case LinkItemDocument, LinkItemMockup, LinkItemProject:
+		return true
+	default:
+		return false
+	}
+}
+
+type Link struct {
+	ID         string       `bson:"_id,omitempty" json:"id"`
+	OrgID      string       `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID  string       `bson:"project_id" json:"projectId"`
+	SourceType LinkItemType `bson:"source_type" json:"sourceType"`
+	SourceID   string       `bson:"source_id" json:"sourceId"`
+	TargetType LinkItemType `bson:"target_type" json:"targetType"`
+	TargetID   string       `bson:"target_id" json:"targetId"`
+	Type       LinkType     `bson:"type" json:"type"`
+	// Extracted is set for links found in Markdown content; they are
+	// replaced every time the source document is saved.
+	Extracted bool       `bson:"extracted" json:"extracted"`
+	Broken    bool       `bson:"broken" json:"broken"`
+	BrokenAt  *time.Time `bson:"broken_at,omitempty" json:"brokenAt,omitempty"`
+	CreatedBy string     `bson:"created_by" json:"createdBy"`
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+}
+
+type CreateLinkInput struct {
+	SourceType LinkItemType `json:"sourceType" binding:"required"`
+	SourceID   string       `json:"sourceId" binding:"required"`
+	TargetType LinkItemType `json:"targetType" binding:"required"`
+	TargetID   string       `json:"targetId" binding:"required"`
+	Type       LinkType     `json:"type" binding:"required"`
+}
+
+func (i *CreateLinkInput) Validate() error {
+	if !i.SourceType.IsValid() || i.SourceType == LinkItemProject {
+		return fmt.Errorf("invalid source type: %s", i.SourceType)
+	}
+	if !i.TargetType.IsValid() {
+		return fmt.Errorf("invalid target type: %s", i.TargetType)
+	}
+	if strings.TrimSpace(i.SourceID) == "" || strings.TrimSpace(i.TargetID) == "" {
+		return fmt.Errorf("source and target IDs are required")
+	}
+	if i.SourceType == i.TargetType && i.SourceID == i.TargetID {
+		return fmt.Errorf("an item cannot link to itself")
+	}
+	if !i.Type.IsValid() {
+		return fmt.Errorf("invalid link type: %s", i.Type)
+	}
+	return nil
+}
+
+// LinkRef is an internal link target found in document content
+type LinkRef struct {
+	Type LinkItemType
+	ID   string
+}
+
+// internalLinkPattern matches Markdown link destinations pointing at
+// documents, mockups or projects, with or without host and API prefix,
+// e.g. [LLD](/documents/65a...), [Login](https://host/mockups/65a...)
+var internalLinkPattern = regexp.MustCompile(`\]\(\s*<?(?:[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s)]+)?(?:/api/v1)?(?:/projects/[0-9a-fA-F]{24})?/(documents|mockups|projects)/([0-9a-fA-F]{24})\b[^)]*\)`)
+
+// ExtractLinkRefs returns the distinct internal items referenced by
+// Markdown links in content, in order of first appearance.
+func ExtractLinkRefs(content string) []LinkRef {
+	matches := internalLinkPattern.FindAllStringSubmatch(content, -1)
+	seen := make(map[LinkRef]bool, len(matches))
+	refs := make([]LinkRef, 0, len(matches))
+	for _, m := range matches {
+		ref := LinkRef{ID: strings.ToLower(m[2])}
+		switch m[1] {
+		case "documents":
+			ref.Type = LinkItemDocument
+		case "mockups":
+			ref.Type = LinkItemMockup
+		default:
+			ref.Type = LinkItemProject
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+var objectIDPattern = regexp.MustCompile(`\b[0-9a-fA-F]{24}\b`)
+
+// RemapLinks rewrites the internal links in content, replacing each item ID
+// found in ids with the ID it maps to. Links to other items are kept as they
+// are. Used when copying items, so links between copies point at copies.
+func RemapLinks(content string, ids map[string]string) string {
+	if len(ids) == 0 {
+		return content
+	}
+	return internalLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		return objectIDPattern.ReplaceAllStringFunc(link, func(id string) string {
+			if mapped, ok := ids[strings.ToLower(id)]; ok {
+				return mapped
+			}
+			return id
+		})
+	})
+}
@@ -0,0 +1,180 @@
+// Package models internal/models/milestone.go
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MilestoneStatus is where a milestone stands on the project timeline. It is
+// derived from the due date and completion, never stored.
+type MilestoneStatus string
+
+const (
+	MilestoneStatusCompleted MilestoneStatus = "completed"
+	// MilestoneStatusOverdue is an open milestone whose due date has passed
+	MilestoneStatusOverdue MilestoneStatus = "overdue"
+	// MilestoneStatusCurrent is the next open milestone that is not overdue
+	MilestoneStatusCurrent  MilestoneStatus = "current"
+	MilestoneStatusUpcoming MilestoneStatus = "upcoming"
+)
+
+func (s MilestoneStatus) IsValid() bool {
+	switch s {
+	case MilestoneStatusCompleted, MilestoneStatusOverdue, MilestoneStatusCurrent, MilestoneStatusUpcoming:
+		return true
+	default:
+		return false
+	}
+}
+
+type Milestone struct {
+	ID          string          `bson:"_id,omitempty" json:"id"`
+	OrgID       string          `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID   string          `bson:"project_id" json:"projectId"`
+	Title       string          `bson:"title" json:"title"`
+	Description string          `bson:"description" json:"description"`
+	DueDate     time.Time       `bson:"due_date" json:"dueDate"`
+	OwnerID     string          `bson:"owner_id,omitempty" json:"ownerId,omitempty"`
+	Completed   bool            `bson:"completed" json:"completed"`
+	CompletedAt *time.Time      `bson:"completed_at,omitempty" json:"completedAt,omitempty"`
+	CompletedBy string          `bson:"completed_by,omitempty" json:"completedBy,omitempty"`
+	Checklist   []ChecklistItem `bson:"checklist" json:"checklist"`
+	CreatedBy   string          `bson:"created_by" json:"createdBy"`
+	CreatedAt   time.Time       `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time       `bson:"updated_at" json:"updatedAt"`
+
+	// Status and Overdue are filled in by AnnotateMilestones
+	Status  MilestoneStatus `bson:"-" json:"status"`
+	Overdue bool            `bson:"-" json:"overdue"`
+}
+
+// ChecklistItem is a step towards completing a milestone
+type ChecklistItem struct {
+	ID    string `bson:"id" json:"id"`
+	Title string `bson:"title" json:"title"`
+	Done  bool   `bson:"done" json:"done"`
+}
+
+// IsOverdue reports whether the milestone is still open after its due date
+func (m *Milestone) IsOverdue(now time.Time) bool {
+	return !m.Completed && m.DueDate.Before(now)
+}
+
+// SetCompleted marks the milestone complete or reopens it
+func (m *Milestone) SetCompleted(completed bool, userID string, now time.Time) {
+	if completed == m.Completed {
+		return
+	}
+	m.Completed = completed
+	if completed {
+		m.CompletedAt = &now
+		m.CompletedBy = userID
+	} else {
+		m.CompletedAt = nil
+		m.CompletedBy = ""
+	}
+}
+
+// SortMilestones orders milestones by due date, then title
+func SortMilestones(milestones []*Milestone) {
+	sort.SliceStable(milestones, func(i, j int) bool {
+		if !milestones[i].DueDate.Equal(milestones[j].DueDate) {
+			return milestones[i].DueDate.Before(milestones[j].DueDate)
+		}
+		return milestones[i].Title < milestones[j].Title
+	})
+}
+
+// AnnotateMilestones sorts a project's milestones and sets their status as of
+// now. The earliest open milestone that is not overdue is the current one.
+func AnnotateMilestones(milestones []*Milestone, now time.Time) {
+	SortMilestones(milestones)
+
+	current := false
+	for _, m := range milestones {
+		m.Overdue = m.IsOverdue(now)
+		switch {
+		case m.Completed:
+			m.Status = MilestoneStatusCompleted
+		case m.Overdue:
+			m.Status = MilestoneStatusOverdue
+		case !current:
+			m.Status = MilestoneStatusCurrent
+			current = true
+		default:
+			m.Status = MilestoneStatusUpcoming
+		}
+	}
+}
+
+type ChecklistItemInput struct {
+	ID    string `json:"id"` // Empty for new items
+	Title string `json:"title" binding:"required"`
+	Done  bool   `json:"done"`
+}
+
+type CreateMilestoneInput struct {
+	Title       string               `json:"title" binding:"required"`
+	Description string               `json:"description"`
+	DueDate     time.Time            `json:"dueDate" binding:"required"`
+	OwnerID     string               `json:"ownerId"`
+	Checklist   []ChecklistItemInput `json:"checklist"`
+}
+
+func (i *CreateMilestoneInput) Validate() error {
+	i.Title = strings.TrimSpace(i.Title)
+	if i.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if i.DueDate.IsZero() {
+		return fmt.Errorf("due date is required")
+	}
+	return validateChecklist(i.Checklist)
+}
+
+// UpdateMilestoneInput changes the fields it sets. A checklist replaces the
+// current one; items keep their state by passing their ID.
+type UpdateMilestoneInput struct {
+	Title       *string               `json:"title,omitempty"`
+	Description *string               `json:"description,omitempty"`
+	DueDate     *time.Time            `json:"dueDate,omitempty"`
+	OwnerID     *string               `json:"ownerId,omitempty"` // "" clears the owner
+	Completed   *bool                 `json:"completed,omitempty"`
+	Checklist   *[]ChecklistItemInput `json:"checklist,omitempty"`
+}
+
+func (i *UpdateMilestoneInput) Validate() error {
+	if i.Title != nil {
+		*i.Title = strings.TrimSpace(*i.Title)
+		if *i.Title == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+	}
+	if i.DueDate != nil && i.DueDate.IsZero() {
+		return fmt.Errorf("due date cannot be empty")
+	}
+	if i.Checklist != nil {
+		return validateChecklist(*i.Checklist)
+	}
+	return nil
+}
+
+func validateChecklist(items []ChecklistItemInput) error {
+	for n := range items {
+		items[n].Title = strings.TrimSpace(items[n].Title)
+		if items[n].Title == "" {
+			return fmt.Errorf("checklist item %d needs a title", n+1)
+		}
+	}
+	return nil
+}
+
+// MilestoneFilter narrows milestone listings
+type MilestoneFilter struct {
+	// Status keeps only milestones with this derived status; empty keeps all
+	Status  MilestoneStatus
+	OwnerID string
+}
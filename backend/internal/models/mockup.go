@@ -2,18 +2,41 @@
 package models
 
 import (
+	"projectnexus/internal/pagination"
 	"time"
 )
 
 type Mockup struct {
-	ID        string    `bson:"_id,omitempty" json:"id"`
-	ProjectID string    `bson:"project_id" json:"projectId"`
-	Name      string    `bson:"name" json:"name"`
-	Type      string    `bson:"type" json:"type"`
-	Tool      string    `bson:"tool" json:"tool"`
-	Thumbnail string    `bson:"thumbnail" json:"thumbnail"`
-	Status    string    `bson:"status" json:"status"`
-	CreatedBy string    `bson:"created_by" json:"createdBy"`
-	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+	ID             string     `bson:"_id,omitempty" json:"id"`
+	OrgID          string     `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID      string     `bson:"project_id" json:"projectId"`
+	Name           string     `bson:"name" json:"name"`
+	Type           string     `bson:"type" json:"type"`
+	Tool           string     `bson:"tool" json:"tool"`
+	Thumbnail      string     `bson:"thumbnail" json:"thumbnail"`
+	Status         string     `bson:"status" json:"status"`
+	CreatedBy      string     `bson:"created_by" json:"createdBy"`
+	CreatedAt      time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `bson:"updated_at" json:"updatedAt"`
+	DeletedAt      *time.Time `bson:"deleted_at,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy      string     `bson:"deleted_by,omitempty" json:"deletedBy,omitempty"`
+	CascadeDeleted bool       `bson:"cascade_deleted,omitempty" json:"-"`
+}
+
+// MockupListSpec describes the sorting and filtering of mockup listings
+var MockupListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "name", Field: "name", Kind: pagination.SortString},
+		{Key: "type", Field: "type", Kind: pagination.SortString},
+		{Key: "status", Field: "status", Kind: pagination.SortString},
+		{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+		{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-updatedAt",
+	Filters: []string{
+		pagination.FilterStatus,
+		pagination.FilterType,
+		pagination.FilterCreatedBy,
+		pagination.FilterUpdatedSince,
+	},
 }
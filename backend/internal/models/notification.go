@@ -0,0 +1,294 @@
+// Package models internal/models/notification.go
+package models
+
+import (
+	"fmt"
+	"projectnexus/internal/pagination"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NotificationType is the kind of event a notification reports
+type NotificationType string
+
+const (
+	// NotificationProjectAdded tells a user they were added to a project
+	NotificationProjectAdded NotificationType = "project_added"
+	// NotificationDocumentStatus tells a document's author and the project
+	// owners that its status changed
+	NotificationDocumentStatus NotificationType = "document_status"
+	// NotificationDocumentApproved tells the whole project a document was
+	// approved
+	NotificationDocumentApproved NotificationType = "document_approved"
+	// NotificationMention tells a user they were mentioned in a document
+	NotificationMention NotificationType = "mention"
+)
+
+// NotificationTypes lists every notification type
+var NotificationTypes = []NotificationType{
+	NotificationProjectAdded,
+	NotificationDocumentStatus,
+	NotificationDocumentApproved,
+	NotificationMention,
+}
+
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHighPriority reports whether notifications of the type are emailed right
+// away unless the user turned that off. The others only appear in digests.
+func (t NotificationType) IsHighPriority() bool {
+	switch t {
+	case NotificationProjectAdded, NotificationDocumentApproved, NotificationMention:
+		return true
+	default:
+		return false
+	}
+}
+
+// DigestFrequency is how often a user gets a summary email of the changes in
+// their projects
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// DefaultDigest is the frequency of users who never chose one
+const DefaultDigest = DigestWeekly
+
+func (f DigestFrequency) IsValid() bool {
+	switch f {
+	case DigestOff, DigestDaily, DigestWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Period is the time a digest covers
+func (f DigestFrequency) Period() time.Duration {
+	if f == DigestDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// Notification is an event recorded for one user
+type Notification struct {
+	ID     string           `bson:"_id,omitempty" json:"id"`
+	OrgID  string           `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	UserID string           `bson:"user_id" json:"userId"`
+	Type   NotificationType `bson:"type" json:"type"`
+	// ActorID is the user whose change caused the notification
+	ActorID    string `bson:"actor_id" json:"actorId"`
+	ProjectID  string `bson:"project_id,omitempty" json:"projectId,omitempty"`
+	DocumentID string `bson:"document_id,omitempty" json:"documentId,omitempty"`
+	// Subject is the name of the project or document the event is about
+	Subject string `bson:"subject" json:"subject"`
+	// Status is the new document status, for status changes
+	Status    DocumentStatus `bson:"status,omitempty" json:"status,omitempty"`
+	Message   string         `bson:"message" json:"message"`
+	Read      bool           `bson:"read" json:"read"`
+	ReadAt    *time.Time     `bson:"read_at,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time      `bson:"created_at" json:"createdAt"`
+}
+
+// Describe writes the message shown for the notification
+func (n *Notification) Describe(actorName string) string {
+	if actorName == "" {
+		actorName = "Someone"
+	}
+	switch n.Type {
+	case NotificationProjectAdded:
+		return fmt.Sprintf("%s added you to %s", actorName, n.Subject)
+	case NotificationDocumentStatus:
+		return fmt.Sprintf("%s changed the status of %s to %s", actorName, n.Subject, n.Status)
+	case NotificationDocumentApproved:
+		return fmt.Sprintf("%s approved %s", actorName, n.Subject)
+	case NotificationMention:
+		return fmt.Sprintf("%s mentioned you in %s", actorName, n.Subject)
+	default:
+		return n.Subject
+	}
+}
+
+// UnreadCount is the number of notifications a user has not read
+type UnreadCount struct {
+	Count int64 `json:"count"`
+}
+
+// NotificationListSpec describes the sorting and filtering of a user's
+// notifications; the newest come first
+var NotificationListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-createdAt",
+	Filters:     []string{pagination.FilterType, pagination.FilterUnread},
+}
+
+// NotificationPreferences says which notifications a user gets in the app
+// and by email
+type NotificationPreferences struct {
+	// Types holds the user's in-app choices; types left out are on
+	Types map[NotificationType]bool `bson:"types,omitempty" json:"types"`
+	// Email holds the user's choices of immediate emails; types left out are
+	// emailed when they are high priority
+	Email map[NotificationType]bool `bson:"email,omitempty" json:"email"`
+	// Digest is empty until the user picks a frequency
+	Digest DigestFrequency `bson:"digest,omitempty" json:"digest"`
+}
+
+// Wants reports whether the user wants notifications of a type in the app
+func (p NotificationPreferences) Wants(t NotificationType) bool {
+	enabled, ok := p.Types[t]
+	return !ok || enabled
+}
+
+// WantsEmail reports whether the user wants an email as soon as a
+// notification of the type is recorded. Types turned off in the app are
+// never emailed.
+func (p NotificationPreferences) WantsEmail(t NotificationType) bool {
+	if !p.Wants(t) {
+		return false
+	}
+	enabled, ok := p.Email[t]
+	if !ok {
+		return t.IsHighPriority()
+	}
+	return enabled
+}
+
+// DigestFrequency returns the user's digest frequency or the default one
+func (p NotificationPreferences) DigestFrequency() DigestFrequency {
+	if p.Digest == "" {
+		return DefaultDigest
+	}
+	return p.Digest
+}
+
+// Resolved returns the preferences with every type spelled out
+func (p NotificationPreferences) Resolved() NotificationPreferences {
+	types := make(map[NotificationType]bool, len(NotificationTypes))
+	email := make(map[NotificationType]bool, len(NotificationTypes))
+	for _, t := range NotificationTypes {
+		types[t] = p.Wants(t)
+		email[t] = p.WantsEmail(t)
+	}
+	return NotificationPreferences{Types: types, Email: email, Digest: p.DigestFrequency()}
+}
+
+// WithoutEmail returns the preferences with every email turned off, as
+// chosen through an unsubscribe link
+func (p NotificationPreferences) WithoutEmail() NotificationPreferences {
+	optOut := EmailOptOut()
+	return optOut.Apply(p)
+}
+
+// EmailOptOut is the change an unsubscribe link makes: every email and the
+// digest are turned off
+func EmailOptOut() UpdateNotificationPreferencesInput {
+	email := make(map[NotificationType]bool, len(NotificationTypes))
+	for _, t := range NotificationTypes {
+		email[t] = false
+	}
+	return UpdateNotificationPreferencesInput{Email: email, Digest: DigestOff}
+}
+
+// UpdateNotificationPreferencesInput changes the listed choices and leaves
+// the others as they are
+type UpdateNotificationPreferencesInput struct {
+	Types  map[NotificationType]bool `json:"types"`
+	Email  map[NotificationType]bool `json:"email"`
+	Digest DigestFrequency           `json:"digest"`
+}
+
+func (i *UpdateNotificationPreferencesInput) Validate() error {
+	for _, choices := range []map[NotificationType]bool{i.Types, i.Email} {
+		for t := range choices {
+			if !t.IsValid() {
+				return fmt.Errorf("invalid notification type: %s", t)
+			}
+		}
+	}
+	if i.Digest != "" && !i.Digest.IsValid() {
+		return fmt.Errorf("invalid digest frequency: %s", i.Digest)
+	}
+	return nil
+}
+
+// Apply merges the input into existing preferences
+func (i *UpdateNotificationPreferencesInput) Apply(p NotificationPreferences) NotificationPreferences {
+	updated := NotificationPreferences{
+		Types:  mergeChoices(p.Types, i.Types),
+		Email:  mergeChoices(p.Email, i.Email),
+		Digest: p.Digest,
+	}
+	if i.Digest != "" {
+		updated.Digest = i.Digest
+	}
+	return updated
+}
+
+func mergeChoices(current, changes map[NotificationType]bool) map[NotificationType]bool {
+	merged := make(map[NotificationType]bool, len(current)+len(changes))
+	for t, enabled := range current {
+		merged[t] = enabled
+	}
+	for t, enabled := range changes {
+		merged[t] = enabled
+	}
+	return merged
+}
+
+// UnsubscribeInput carries the token of an unsubscribe link
+type UnsubscribeInput struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// mentionPattern matches mentions written as Markdown links to a user,
+// @[Jane Doe](user:<id>)
+var mentionPattern = regexp.MustCompile(`@\[[^\]]*\]\(\s*user:([0-9a-fA-F]{24})\s*\)`)
+
+// ExtractMentions returns the users mentioned in content, each once, in the
+// order they first appear
+func ExtractMentions(content string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	seen := make(map[string]bool, len(matches))
+	users := make([]string, 0, len(matches))
+	for _, m := range matches {
+		id := strings.ToLower(m[1])
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		users = append(users, id)
+	}
+	return users
+}
+
+// NewMentions returns the users mentioned in content who were not mentioned
+// in the previous content
+func NewMentions(previous, content string) []string {
+	before := make(map[string]bool)
+	for _, id := range ExtractMentions(previous) {
+		before[id] = true
+	}
+	added := make([]string, 0)
+	for _, id := range ExtractMentions(content) {
+		if !before[id] {
+			added = append(added, id)
+		}
+	}
+	return added
+}
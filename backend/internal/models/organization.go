@@ -0,0 +1,127 @@
+// Package models internal/models/organization.go
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// OrgRole is a user's role in an organization
+type OrgRole string
+
+const (
+	// OrgRoleAdmin manages the organization and its members
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleMember creates and works on projects and teams
+	OrgRoleMember OrgRole = "member"
+	// OrgRoleGuest only sees the projects and teams they were added to
+	OrgRoleGuest OrgRole = "guest"
+)
+
+func (r OrgRole) IsValid() bool {
+	switch r {
+	case OrgRoleAdmin, OrgRoleMember, OrgRoleGuest:
+		return true
+	default:
+		return false
+	}
+}
+
+// Organization is a workspace that owns projects and teams. Everything in
+// one organization is invisible from the others.
+type Organization struct {
+	ID        string          `bson:"_id,omitempty" json:"id"`
+	Name      string          `bson:"name" json:"name"`
+	CreatedBy string          `bson:"created_by" json:"createdBy"`
+	Members   []OrgMembership `bson:"members" json:"members"`
+	// Default marks the organization that took over the data created before
+	// organizations existed; users without one join it
+	Default   bool      `bson:"default,omitempty" json:"default,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+type OrgMembership struct {
+	UserID   string    `bson:"user_id" json:"userId"`
+	Role     OrgRole   `bson:"role" json:"role"`
+	JoinedAt time.Time `bson:"joined_at" json:"joinedAt"`
+}
+
+// Member returns the membership of a user, or nil if they do not belong to
+// the organization
+func (o *Organization) Member(userID string) *OrgMembership {
+	for i := range o.Members {
+		if o.Members[i].UserID == userID {
+			return &o.Members[i]
+		}
+	}
+	return nil
+}
+
+// IsAdmin reports whether a user administers the organization
+func (o *Organization) IsAdmin(userID string) bool {
+	member := o.Member(userID)
+	return member != nil && member.Role == OrgRoleAdmin
+}
+
+// AdminCount returns the number of admins
+func (o *Organization) AdminCount() int {
+	count := 0
+	for _, member := range o.Members {
+		if member.Role == OrgRoleAdmin {
+			count++
+		}
+	}
+	return count
+}
+
+type CreateOrganizationInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (i *CreateOrganizationInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type UpdateOrganizationInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (i *UpdateOrganizationInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type AddOrgMemberInput struct {
+	UserID string  `json:"userId" binding:"required"`
+	Role   OrgRole `json:"role" binding:"required"`
+}
+
+func (i *AddOrgMemberInput) Validate() error {
+	if i.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if !i.Role.IsValid() {
+		return errors.New("invalid organization role")
+	}
+	return nil
+}
+
+type UpdateOrgMemberInput struct {
+	Role OrgRole `json:"role" binding:"required"`
+}
+
+func (i *UpdateOrgMemberInput) Validate() error {
+	if !i.Role.IsValid() {
+		return errors.New("invalid organization role")
+	}
+	return nil
+}
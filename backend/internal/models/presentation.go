@@ -0,0 +1,122 @@
+// Package models internal/models/presentation.go
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DocumentPreviewLength is how many characters of a document a presentation
+// previews
+const DocumentPreviewLength = 280
+
+// TimelineEventKind tells the project's own dates apart from its milestones
+type TimelineEventKind string
+
+const (
+	TimelineEventStart     TimelineEventKind = "start"
+	TimelineEventMilestone TimelineEventKind = "milestone"
+	TimelineEventEnd       TimelineEventKind = "end"
+)
+
+// PresentationMember is a project member as the presentation shows them.
+// Members have no pictures, so their initials stand in for one.
+type PresentationMember struct {
+	UserID   string   `json:"userId"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Role     TeamRole `json:"role"`
+	Initials string   `json:"initials"`
+}
+
+// PresentationDocument is an approved document with the start of its
+// content
+type PresentationDocument struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	Type       DocumentType `json:"type"`
+	Version    int          `json:"version"`
+	Preview    string       `json:"preview"`
+	Tags       []string     `json:"tags"`
+	AuthorID   string       `json:"authorId"`
+	AuthorName string       `json:"authorName"`
+	ApprovedAt *time.Time   `json:"approvedAt,omitempty"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+}
+
+type PresentationMockup struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Tool      string    `json:"tool"`
+	Thumbnail string    `json:"thumbnail"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TimelineEvent is a dated step of the project: its start and end dates and
+// its milestones, in date order
+type TimelineEvent struct {
+	ID           string            `json:"id"`
+	Kind         TimelineEventKind `json:"kind"`
+	Title        string            `json:"title"`
+	Date         time.Time         `json:"date"`
+	Status       MilestoneStatus   `json:"status"`
+	AssigneeID   string            `json:"assigneeId,omitempty"`
+	AssigneeName string            `json:"assigneeName,omitempty"`
+}
+
+// Presentation is everything the presentation page of a project shows
+type Presentation struct {
+	Project   *Project               `json:"project"`
+	Team      []PresentationMember   `json:"team"`
+	Documents []PresentationDocument `json:"documents"`
+	Mockups   []PresentationMockup   `json:"mockups"`
+	Timeline  []TimelineEvent        `json:"timeline"`
+}
+
+// DocumentPreview returns the start of a document's content as plain text on
+// one line, cut at a word boundary after at most n characters
+func DocumentPreview(content string, n int) string {
+	lines := strings.Split(content, "\n")
+	words := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		// Drop the markers of headings, lists and quotes
+		line = strings.TrimLeft(line, "#>-*+ ")
+		words = append(words, strings.Fields(line)...)
+	}
+	text := strings.Join(words, " ")
+	text = strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	cut := n
+	for cut > 0 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		cut = n
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
+
+// Initials returns the initials of a name's first and last words, or of the
+// email when there is no name
+func Initials(name, email string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		words = strings.Fields(strings.SplitN(email, "@", 2)[0])
+	}
+	if len(words) > 2 {
+		words = []string{words[0], words[len(words)-1]}
+	}
+	initials := ""
+	for _, word := range words {
+		initials += strings.ToUpper(string([]rune(word)[0]))
+	}
+	return initials
+}
@@ -0,0 +1,227 @@
+// Package models internal/models/progress.go
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type ProgressMode string
+
+const (
+	// ProgressModeManual keeps whatever progress members enter
+	ProgressModeManual ProgressMode = "manual"
+	// ProgressModeAuto derives progress from the project's documents and mockups
+	ProgressModeAuto ProgressMode = "auto"
+)
+
+func (m ProgressMode) IsValid() bool {
+	return m == ProgressModeManual || m == ProgressModeAuto
+}
+
+// DefaultMockupShare is the part of automatic progress that comes from mockups
+const DefaultMockupShare = 0.25
+
+// DefaultTypeWeights weigh design documents above the rest
+var DefaultTypeWeights = map[DocumentType]float64{
+	DocumentTypeHLD:   3,
+	DocumentTypeLLD:   2,
+	DocumentTypeSpec:  2,
+	DocumentTypeOther: 1,
+}
+
+// ProgressSettings configures how a project's progress is computed
+type ProgressSettings struct {
+	Mode ProgressMode `bson:"mode" json:"mode"`
+	// TypeWeights overrides DefaultTypeWeights for the types it lists
+	TypeWeights map[DocumentType]float64 `bson:"type_weights,omitempty" json:"typeWeights,omitempty"`
+	// RequiredTypes are document types the project must have; each one that
+	// is missing counts as an unapproved document
+	RequiredTypes []DocumentType `bson:"required_types,omitempty" json:"requiredTypes,omitempty"`
+	MockupShare   *float64       `bson:"mockup_share,omitempty" json:"mockupShare,omitempty"`
+	// Override pins progress to a value entered by hand, in automatic mode too
+	Override *int `bson:"override,omitempty" json:"override,omitempty"`
+}
+
+// Auto reports whether progress is computed
+func (s *ProgressSettings) Auto() bool {
+	return s != nil && s.Mode == ProgressModeAuto
+}
+
+// TypeWeight returns the weight of a document type
+func (s *ProgressSettings) TypeWeight(t DocumentType) float64 {
+	if s != nil {
+		if w, ok := s.TypeWeights[t]; ok {
+			return w
+		}
+	}
+	if w, ok := DefaultTypeWeights[t]; ok {
+		return w
+	}
+	return 1
+}
+
+func (s *ProgressSettings) mockupShare() float64 {
+	if s != nil && s.MockupShare != nil {
+		return *s.MockupShare
+	}
+	return DefaultMockupShare
+}
+
+// ProgressBreakdown explains how a project's progress was computed
+type ProgressBreakdown struct {
+	Progress   int              `bson:"progress" json:"progress"`
+	Computed   int              `bson:"computed" json:"computed"`
+	Overridden bool             `bson:"overridden" json:"overridden"`
+	Documents  DocumentProgress `bson:"documents" json:"documents"`
+	Mockups    MockupProgress   `bson:"mockups" json:"mockups"`
+	// MockupShare is the part of the computed value that came from mockups;
+	// it is 0 without mockups and 1 without documents
+	MockupShare float64   `bson:"mockup_share" json:"mockupShare"`
+	ComputedAt  time.Time `bson:"computed_at" json:"computedAt"`
+}
+
+type DocumentProgress struct {
+	Total          int                    `bson:"total" json:"total"`
+	Approved       int                    `bson:"approved" json:"approved"`
+	Weight         float64                `bson:"weight" json:"weight"`
+	ApprovedWeight float64                `bson:"approved_weight" json:"approvedWeight"`
+	Percent        float64                `bson:"percent" json:"percent"`
+	ByType         []DocumentTypeProgress `bson:"by_type" json:"byType"`
+	MissingTypes   []DocumentType         `bson:"missing_types" json:"missingTypes"`
+}
+
+type DocumentTypeProgress struct {
+	Type     DocumentType `bson:"type" json:"type"`
+	Weight   float64      `bson:"weight" json:"weight"`
+	Total    int          `bson:"total" json:"total"`
+	Approved int          `bson:"approved" json:"approved"`
+}
+
+type MockupProgress struct {
+	Total    int     `bson:"total" json:"total"`
+	Approved int     `bson:"approved" json:"approved"`
+	Percent  float64 `bson:"percent" json:"percent"`
+}
+
+// MockupStatusApproved is the status of signed-off mockups
+const MockupStatusApproved = "Approved"
+
+// ComputeProgress derives progress from the approved share of a project's
+// documents, weighted by type, and of its mockups
+func ComputeProgress(settings *ProgressSettings, documents []*Document, mockups []*Mockup, now time.Time) *ProgressBreakdown {
+	breakdown := &ProgressBreakdown{ComputedAt: now}
+
+	docs := &breakdown.Documents
+	byType := make(map[DocumentType]*DocumentTypeProgress)
+	typeOrder := []DocumentType{DocumentTypeHLD, DocumentTypeLLD, DocumentTypeSpec, DocumentTypeOther}
+	for _, t := range typeOrder {
+		byType[t] = &DocumentTypeProgress{Type: t, Weight: settings.TypeWeight(t)}
+	}
+	for _, d := range documents {
+		tp, ok := byType[d.Type]
+		if !ok {
+			tp = &DocumentTypeProgress{Type: d.Type, Weight: settings.TypeWeight(d.Type)}
+			byType[d.Type] = tp
+			typeOrder = append(typeOrder, d.Type)
+		}
+		tp.Total++
+		docs.Total++
+		docs.Weight += tp.Weight
+		if d.Status == DocumentStatusApproved {
+			tp.Approved++
+			docs.Approved++
+			docs.ApprovedWeight += tp.Weight
+		}
+	}
+
+	// A missing required document weighs as much as an unapproved one
+	docs.MissingTypes = []DocumentType{}
+	if settings != nil {
+		for _, t := range settings.RequiredTypes {
+			if tp, ok := byType[t]; !ok || tp.Total == 0 {
+				docs.MissingTypes = append(docs.MissingTypes, t)
+				docs.Weight += settings.TypeWeight(t)
+			}
+		}
+	}
+
+	docs.ByType = make([]DocumentTypeProgress, 0, len(typeOrder))
+	for _, t := range typeOrder {
+		if byType[t].Total > 0 {
+			docs.ByType = append(docs.ByType, *byType[t])
+		}
+	}
+	if docs.Weight > 0 {
+		docs.Percent = 100 * docs.ApprovedWeight / docs.Weight
+	}
+
+	for _, m := range mockups {
+		breakdown.Mockups.Total++
+		if strings.EqualFold(m.Status, MockupStatusApproved) {
+			breakdown.Mockups.Approved++
+		}
+	}
+	if breakdown.Mockups.Total > 0 {
+		breakdown.Mockups.Percent = 100 * float64(breakdown.Mockups.Approved) / float64(breakdown.Mockups.Total)
+	}
+
+	// Whichever signal a project lacks gives its share to the other
+	switch {
+	case breakdown.Mockups.Total == 0:
+		breakdown.MockupShare = 0
+	case docs.Weight == 0:
+		breakdown.MockupShare = 1
+	default:
+		breakdown.MockupShare = settings.mockupShare()
+	}
+
+	computed := (1-breakdown.MockupShare)*docs.Percent + breakdown.MockupShare*breakdown.Mockups.Percent
+	breakdown.Computed = int(math.Round(computed))
+	breakdown.Progress = breakdown.Computed
+	if settings != nil && settings.Override != nil {
+		breakdown.Progress = *settings.Override
+		breakdown.Overridden = true
+	}
+
+	return breakdown
+}
+
+// UpdateProgressSettingsInput changes the fields it sets
+type UpdateProgressSettingsInput struct {
+	Mode          *ProgressMode             `json:"mode,omitempty"`
+	TypeWeights   *map[DocumentType]float64 `json:"typeWeights,omitempty"`
+	RequiredTypes *[]DocumentType           `json:"requiredTypes,omitempty"`
+	MockupShare   *float64                  `json:"mockupShare,omitempty"`
+	// ClearOverride goes back to the computed value
+	ClearOverride bool `json:"clearOverride"`
+}
+
+func (i *UpdateProgressSettingsInput) Validate() error {
+	if i.Mode != nil && !i.Mode.IsValid() {
+		return fmt.Errorf("invalid progress mode: %s", *i.Mode)
+	}
+	if i.TypeWeights != nil {
+		for t, w := range *i.TypeWeights {
+			if !t.IsValid() {
+				return fmt.Errorf("invalid document type: %s", t)
+			}
+			if w < 0 {
+				return fmt.Errorf("weight of %s cannot be negative", t)
+			}
+		}
+	}
+	if i.RequiredTypes != nil {
+		for _, t := range *i.RequiredTypes {
+			if !t.IsValid() {
+				return fmt.Errorf("invalid document type: %s", t)
+			}
+		}
+	}
+	if i.MockupShare != nil && (*i.MockupShare < 0 || *i.MockupShare > 1) {
+		return fmt.Errorf("mockup share must be between 0 and 1")
+	}
+	return nil
+}
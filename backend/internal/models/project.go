@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/pagination"
 	"strings"
 	"time"
 )
@@ -30,14 +32,129 @@ func (s ProjectStatus) IsValid() bool {
 
 type Project struct {
 	ID          string        `bson:"_id,omitempty" json:"id"`
+	OrgID       string        `bson:"org_id,omitempty" json:"orgId,omitempty"`
 	Name        string        `bson:"name" json:"name"`
 	Description string        `bson:"description" json:"description"`
 	Status      ProjectStatus `bson:"status" json:"status"`
 	Progress    int           `bson:"progress" json:"progress"`
 	Team        []string      `bson:"team" json:"team"` // User IDs
+	StartDate   *time.Time    `bson:"start_date,omitempty" json:"startDate,omitempty"`
+	EndDate     *time.Time    `bson:"end_date,omitempty" json:"endDate,omitempty"`
 	CreatedBy   string        `bson:"created_by" json:"createdBy"`
 	CreatedAt   time.Time     `bson:"created_at" json:"createdAt"`
 	UpdatedAt   time.Time     `bson:"updated_at" json:"updatedAt"`
+	DeletedAt   *time.Time    `bson:"deleted_at,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy   string        `bson:"deleted_by,omitempty" json:"deletedBy,omitempty"`
+
+	// Teams are the standalone teams whose members are project members
+	Teams []ProjectTeamAssignment `bson:"teams,omitempty" json:"teams,omitempty"`
+
+	// Owners may manage the project. Projects created before ownership could
+	// be shared have none stored, and their creator is the owner.
+	Owners          []string           `bson:"owners,omitempty" json:"owners"`
+	PendingTransfer *OwnershipTransfer `bson:"pending_transfer,omitempty" json:"pendingTransfer,omitempty"`
+
+	// ProgressSettings switches Progress to being computed; the breakdown of
+	// the last computation is kept alongside it
+	ProgressSettings  *ProgressSettings  `bson:"progress_settings,omitempty" json:"progressSettings,omitempty"`
+	ProgressBreakdown *ProgressBreakdown `bson:"progress_breakdown,omitempty" json:"progressBreakdown,omitempty"`
+
+	// ArchivedAt marks a project put away by its owners. Archived projects
+	// are read-only and left out of listings unless asked for.
+	ArchivedAt *time.Time `bson:"archived_at,omitempty" json:"archivedAt,omitempty"`
+	ArchivedBy string     `bson:"archived_by,omitempty" json:"archivedBy,omitempty"`
+}
+
+// OwnershipTransfer hands a project to another user once they accept it
+type OwnershipTransfer struct {
+	From string `bson:"from" json:"from"`
+	To   string `bson:"to" json:"to"`
+	// KeepOwnership leaves the current owner in place, adding a co-owner
+	KeepOwnership bool      `bson:"keep_ownership" json:"keepOwnership"`
+	RequestedAt   time.Time `bson:"requested_at" json:"requestedAt"`
+}
+
+type TransferOwnershipInput struct {
+	UserID        string `json:"userId" binding:"required"`
+	KeepOwnership bool   `json:"keepOwnership"`
+}
+
+// OwnerIDs returns the project's owners
+func (p *Project) OwnerIDs() []string {
+	if len(p.Owners) == 0 && p.CreatedBy != "" {
+		return []string{p.CreatedBy}
+	}
+	return p.Owners
+}
+
+func (p *Project) IsOwner(userID string) bool {
+	for _, owner := range p.OwnerIDs() {
+		if owner == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAccess reports whether a user is an owner or on the project team
+func (p *Project) HasAccess(userID string) bool {
+	if p.IsOwner(userID) {
+		return true
+	}
+	for _, member := range p.Team {
+		if member == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddOwner makes a user an owner
+func (p *Project) AddOwner(userID string) {
+	if !p.IsOwner(userID) {
+		p.Owners = append(p.OwnerIDs(), userID)
+	}
+}
+
+// RemoveOwner takes ownership away from a user. It refuses to remove the
+// last owner and reports whether the owners changed.
+func (p *Project) RemoveOwner(userID string) (bool, error) {
+	if !p.IsOwner(userID) {
+		return false, nil
+	}
+	owners := make([]string, 0, len(p.OwnerIDs()))
+	for _, owner := range p.OwnerIDs() {
+		if owner != userID {
+			owners = append(owners, owner)
+		}
+	}
+	if len(owners) == 0 {
+		return false, errs.ErrLastOwner
+	}
+	p.Owners = owners
+	return true, nil
+}
+
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+// CheckWritable refuses changes to an archived project and its content
+func (p *Project) CheckWritable() error {
+	if p.IsArchived() {
+		return errs.ErrProjectArchived
+	}
+	return nil
+}
+
+// TeamAssignment returns the assignment of a team, or nil if it is not assigned
+func (p *Project) TeamAssignment(teamID string) *ProjectTeamAssignment {
+	for i := range p.Teams {
+		if p.Teams[i].TeamID == teamID {
+			return &p.Teams[i]
+		}
+	}
+	return nil
 }
 
 func (p *Project) Validate() error {
@@ -47,6 +164,14 @@ func (p *Project) Validate() error {
 	if p.Progress < 0 || p.Progress > 100 {
 		return fmt.Errorf("progress must be between 0 and 100")
 	}
+	return validateProjectDates(p.StartDate, p.EndDate)
+}
+
+// validateProjectDates checks that a project does not end before it starts
+func validateProjectDates(start, end *time.Time) error {
+	if start != nil && end != nil && end.Before(*start) {
+		return fmt.Errorf("end date cannot be before start date")
+	}
 	return nil
 }
 
@@ -54,6 +179,8 @@ type CreateProjectInput struct {
 	Name        string        `json:"name" binding:"required"`
 	Description string        `json:"description" binding:"required"`
 	Status      ProjectStatus `json:"status" binding:"required"`
+	StartDate   *time.Time    `json:"startDate,omitempty"`
+	EndDate     *time.Time    `json:"endDate,omitempty"`
 }
 
 func (i *CreateProjectInput) Validate() error {
@@ -66,7 +193,7 @@ func (i *CreateProjectInput) Validate() error {
 	if !i.Status.IsValid() {
 		return fmt.Errorf("invalid project status: %s", i.Status)
 	}
-	return nil
+	return validateProjectDates(i.StartDate, i.EndDate)
 }
 
 type UpdateProjectInput struct {
@@ -74,14 +201,52 @@ type UpdateProjectInput struct {
 	Description *string        `json:"description,omitempty"`
 	Status      *ProjectStatus `json:"status,omitempty"`
 	Progress    *int           `json:"progress,omitempty"`
+	StartDate   *time.Time     `json:"startDate,omitempty"`
+	EndDate     *time.Time     `json:"endDate,omitempty"`
+	// ClearStartDate and ClearEndDate remove a date, since a missing one
+	// leaves it unchanged
+	ClearStartDate bool `json:"clearStartDate"`
+	ClearEndDate   bool `json:"clearEndDate"`
 }
 
 func (i *UpdateProjectInput) Validate() error {
 	if i.Status != nil && !i.Status.IsValid() {
 		return fmt.Errorf("invalid project status: %s", *i.Status)
 	}
+	if i.StartDate != nil && i.ClearStartDate {
+		return fmt.Errorf("start date cannot be both set and cleared")
+	}
+	if i.EndDate != nil && i.ClearEndDate {
+		return fmt.Errorf("end date cannot be both set and cleared")
+	}
 	if i.Progress != nil && (*i.Progress < 0 || *i.Progress > 100) {
 		return fmt.Errorf("progress must be between 0 and 100")
 	}
 	return nil
 }
+
+// ProjectTrash lists the soft-deleted items of a project awaiting purge
+type ProjectTrash struct {
+	Documents     []*Document `json:"documents"`
+	Mockups       []*Mockup   `json:"mockups"`
+	RetentionDays int         `json:"retentionDays"` // Items are purged this long after deletion
+}
+
+// ProjectListSpec describes the sorting and filtering of project listings
+var ProjectListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "name", Field: "name", Kind: pagination.SortString},
+		{Key: "status", Field: "status", Kind: pagination.SortString},
+		{Key: "progress", Field: "progress", Kind: pagination.SortNumber},
+		{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+		{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-updatedAt",
+	Filters: []string{
+		pagination.FilterStatus,
+		pagination.FilterCreatedBy,
+		pagination.FilterUpdatedSince,
+		pagination.FilterArchived,
+		pagination.FilterSearch,
+	},
+}
@@ -0,0 +1,175 @@
+// Package models internal/models/share.go
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+// ShareLinkMaxItems is how many documents and mockups a presentation link
+// can show
+const ShareLinkMaxItems = 100
+
+// ShareLinkMinPassword is the shortest password a share link takes
+const ShareLinkMinPassword = 6
+
+type ShareLinkKind string
+
+const (
+	// ShareLinkPresentation shows a project's presentation with the
+	// documents and mockups picked for it
+	ShareLinkPresentation ShareLinkKind = "presentation"
+	// ShareLinkDocument shows a single document
+	ShareLinkDocument ShareLinkKind = "document"
+)
+
+// ShareLink lets people without an account view part of a project,
+// read-only. Only a hash of its token is kept; the token itself is shown
+// once, when the link is created.
+type ShareLink struct {
+	ID        string        `bson:"_id,omitempty" json:"id"`
+	OrgID     string        `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID string        `bson:"project_id" json:"projectId"`
+	Kind      ShareLinkKind `bson:"kind" json:"kind"`
+	// DocumentIDs and MockupIDs are all the link shows besides the project
+	// overview; a document link has exactly one document
+	DocumentIDs []string `bson:"document_ids" json:"documentIds"`
+	MockupIDs   []string `bson:"mockup_ids" json:"mockupIds"`
+	TokenHash   string   `bson:"token_hash" json:"-"`
+	// PasswordHash is set for links that ask for a password
+	PasswordHash      string     `bson:"password_hash,omitempty" json:"-"`
+	PasswordProtected bool       `bson:"password_protected" json:"passwordProtected"`
+	ExpiresAt         *time.Time `bson:"expires_at,omitempty" json:"expiresAt,omitempty"`
+	Views             int64      `bson:"views" json:"views"`
+	LastViewedAt      *time.Time `bson:"last_viewed_at,omitempty" json:"lastViewedAt,omitempty"`
+	RevokedAt         *time.Time `bson:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+	RevokedBy         string     `bson:"revoked_by,omitempty" json:"revokedBy,omitempty"`
+	CreatedBy         string     `bson:"created_by" json:"createdBy"`
+	CreatedAt         time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time  `bson:"updated_at" json:"updatedAt"`
+}
+
+// HashShareToken returns the hash a share link's token is looked up by
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive reports whether the link can still be viewed
+func (l *ShareLink) IsActive(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// SetPassword makes the link ask for a password
+func (l *ShareLink) SetPassword(password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	l.PasswordHash = string(hash)
+	l.PasswordProtected = true
+	return nil
+}
+
+// CheckPassword reports whether a password opens the link; links without
+// one take any
+func (l *ShareLink) CheckPassword(password string) bool {
+	if !l.PasswordProtected {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(l.PasswordHash), []byte(password)) == nil
+}
+
+// CreatedShareLink is a new share link along with its token and the address
+// to give out
+type CreatedShareLink struct {
+	*ShareLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+type CreateShareLinkInput struct {
+	// DocumentIDs and MockupIDs pick what a presentation link shows; they
+	// are ignored for document links
+	DocumentIDs []string   `json:"documentIds"`
+	MockupIDs   []string   `json:"mockupIds"`
+	Password    string     `json:"password,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Validate checks the input and drops repeated IDs
+func (i *CreateShareLinkInput) Validate(now time.Time) error {
+	if i.Password != "" && len(i.Password) < ShareLinkMinPassword {
+		return errors.New("password must be at least 6 characters")
+	}
+	if i.ExpiresAt != nil && !i.ExpiresAt.After(now) {
+		return errors.New("expiry must be in the future")
+	}
+	i.DocumentIDs = uniqueIDs(i.DocumentIDs)
+	i.MockupIDs = uniqueIDs(i.MockupIDs)
+	if len(i.DocumentIDs)+len(i.MockupIDs) > ShareLinkMaxItems {
+		return errors.New("a share link can show at most 100 documents and mockups")
+	}
+	return nil
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := []string{}
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// SharedProject is the overview of a project shown through a share link
+type SharedProject struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Status      ProjectStatus `json:"status"`
+	Progress    int           `json:"progress"`
+	StartDate   *time.Time    `json:"startDate,omitempty"`
+	EndDate     *time.Time    `json:"endDate,omitempty"`
+}
+
+// SharedDocument is a document shown through a share link
+type SharedDocument struct {
+	ID        string         `json:"id"`
+	Title     string         `json:"title"`
+	Type      DocumentType   `json:"type"`
+	Status    DocumentStatus `json:"status"`
+	Version   int            `json:"version"`
+	Content   string         `json:"content"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// SharedMockup is a mockup shown through a share link
+type SharedMockup struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Tool      string    `json:"tool"`
+	Thumbnail string    `json:"thumbnail"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SharedView is everything a share link shows, leaving out who made what
+// and anything else internal
+type SharedView struct {
+	Kind      ShareLinkKind    `json:"kind"`
+	Project   SharedProject    `json:"project"`
+	Documents []SharedDocument `json:"documents"`
+	Mockups   []SharedMockup   `json:"mockups"`
+	ExpiresAt *time.Time       `json:"expiresAt,omitempty"`
+}
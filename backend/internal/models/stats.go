@@ -0,0 +1,184 @@
+// Package models internal/models/stats.go
+package models
+
+import (
+	"time"
+)
+
+// StatsWindow is how far back the statistics look
+type StatsWindow string
+
+const (
+	StatsWindowWeek    StatsWindow = "7d"
+	StatsWindowMonth   StatsWindow = "30d"
+	StatsWindowQuarter StatsWindow = "90d"
+	StatsWindowYear    StatsWindow = "365d"
+
+	DefaultStatsWindow = StatsWindowMonth
+)
+
+func (w StatsWindow) IsValid() bool {
+	switch w {
+	case StatsWindowWeek, StatsWindowMonth, StatsWindowQuarter, StatsWindowYear:
+		return true
+	default:
+		return false
+	}
+}
+
+// Duration returns the length of the window
+func (w StatsWindow) Duration() time.Duration {
+	switch w {
+	case StatsWindowWeek:
+		return 7 * 24 * time.Hour
+	case StatsWindowQuarter:
+		return 90 * 24 * time.Hour
+	case StatsWindowYear:
+		return 365 * 24 * time.Hour
+	default:
+		return 30 * 24 * time.Hour
+	}
+}
+
+// Bucket returns the period the window's trends are counted in: days for
+// up to a month, weeks beyond that
+func (w StatsWindow) Bucket() StatsBucket {
+	if w == StatsWindowQuarter || w == StatsWindowYear {
+		return StatsBucketWeek
+	}
+	return StatsBucketDay
+}
+
+// StatsBucket is the period trend data is counted in. Buckets are in UTC and
+// weeks start on Monday, the same as Mongo's $dateTrunc is told to use.
+type StatsBucket string
+
+const (
+	StatsBucketDay  StatsBucket = "day"
+	StatsBucketWeek StatsBucket = "week"
+)
+
+// Start returns the start of the bucket a time falls in
+func (b StatsBucket) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if b != StatsBucketWeek {
+		return day
+	}
+	// Go counts weekdays from Sunday
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// next returns the start of the bucket after the one starting at start
+func (b StatsBucket) next(start time.Time) time.Time {
+	if b == StatsBucketWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// KeyCount is how many things share a value, such as a status
+type KeyCount struct {
+	Key   string `bson:"_id" json:"key"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// TrendPoint is the count of one bucket of trend data, shaped as the charts
+// take it
+type TrendPoint struct {
+	Date  time.Time `bson:"_id" json:"date"`
+	Value int64     `bson:"value" json:"value"`
+}
+
+// FillTrend returns the points of every bucket from from to to, in order,
+// with zeros for the buckets nothing happened in
+func FillTrend(points []TrendPoint, from, to time.Time, bucket StatsBucket) []TrendPoint {
+	values := make(map[time.Time]int64, len(points))
+	for _, p := range points {
+		values[bucket.Start(p.Date)] += p.Value
+	}
+
+	filled := []TrendPoint{}
+	for start := bucket.Start(from); !start.After(to); start = bucket.next(start) {
+		filled = append(filled, TrendPoint{Date: start, Value: values[start]})
+	}
+	return filled
+}
+
+// ApprovalLeadTime is how long the documents approved in a window took from
+// being created to being approved
+type ApprovalLeadTime struct {
+	Approvals    int64   `bson:"approvals" json:"approvals"`
+	AverageHours float64 `bson:"average_hours" json:"averageHours"`
+	MinHours     float64 `bson:"min_hours" json:"minHours"`
+	MaxHours     float64 `bson:"max_hours" json:"maxHours"`
+}
+
+// DocumentStats are the document counts of a set of projects
+type DocumentStats struct {
+	ByStatus []KeyCount
+	ByType   []KeyCount
+	// Created and Approved are counted per bucket of the window
+	Created  []TrendPoint
+	Approved []TrendPoint
+	LeadTime ApprovalLeadTime
+	// Creators counts the documents created in the window per user
+	Creators []KeyCount
+}
+
+// EditStats are the document edits of a set of projects, each new version
+// after the first being one edit
+type EditStats struct {
+	// Edits are counted per bucket of the window, PerWeek per week
+	Edits   []TrendPoint
+	PerWeek []TrendPoint
+	// Editors counts the edits made in the window per user
+	Editors []KeyCount
+}
+
+// MemberContribution is what one person did in the window
+type MemberContribution struct {
+	UserID           string `json:"userId"`
+	Name             string `json:"name"`
+	DocumentsCreated int64  `json:"documentsCreated"`
+	Edits            int64  `json:"edits"`
+}
+
+// StatsSummary holds the headline numbers of the statistics
+type StatsSummary struct {
+	Documents        int64 `json:"documents"`
+	PendingReviews   int64 `json:"pendingReviews"`
+	TeamMembers      int   `json:"teamMembers"`
+	DocumentsCreated int64 `json:"documentsCreated"`
+	Edits            int64 `json:"edits"`
+	Approvals        int64 `json:"approvals"`
+}
+
+// StatsTrends are the time series of the window
+type StatsTrends struct {
+	DocumentsCreated []TrendPoint `json:"documentsCreated"`
+	Edits            []TrendPoint `json:"edits"`
+	Approvals        []TrendPoint `json:"approvals"`
+}
+
+// Stats are the dashboard statistics of everything a user can access, or the
+// metrics of one project
+type Stats struct {
+	ProjectID string      `json:"projectId,omitempty"`
+	Window    StatsWindow `json:"window"`
+	Bucket    StatsBucket `json:"bucket"`
+	From      time.Time   `json:"from"`
+	To        time.Time   `json:"to"`
+
+	Summary StatsSummary `json:"summary"`
+	// ProjectsByStatus is left out of a single project's metrics
+	ProjectsByStatus  []KeyCount       `json:"projectsByStatus,omitempty"`
+	DocumentsByStatus []KeyCount       `json:"documentsByStatus"`
+	DocumentsByType   []KeyCount       `json:"documentsByType"`
+	ApprovalLeadTime  ApprovalLeadTime `json:"approvalLeadTime"`
+	EditsPerWeek      []TrendPoint     `json:"editsPerWeek"`
+	// Contributions are the most active members first
+	Contributions []MemberContribution `json:"contributions"`
+	Trends        StatsTrends          `json:"trends"`
+}
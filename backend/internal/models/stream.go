@@ -0,0 +1,11 @@
+// Package models internal/models/stream.go
+package models
+
+// ChangeStreamInput picks what a change stream follows. Without projects or
+// documents it follows every project the user can access.
+type ChangeStreamInput struct {
+	ProjectIDs  []string
+	DocumentIDs []string
+	// LastEventID is the resume token of the last change the client saw
+	LastEventID string
+}
@@ -0,0 +1,233 @@
+// Package models internal/models/task.go
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TaskPriority string
+
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh, TaskPriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Task is a unit of work on a project's board. Tasks in a column are ordered
+// by Rank; Version grows with every edit so that concurrent edits can be
+// detected. Reranking a column to make room is not an edit.
+type Task struct {
+	ID          string       `bson:"_id,omitempty" json:"id"`
+	OrgID       string       `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID   string       `bson:"project_id" json:"projectId"`
+	Title       string       `bson:"title" json:"title"`
+	Description string       `bson:"description" json:"description"`
+	AssigneeID  string       `bson:"assignee_id,omitempty" json:"assigneeId,omitempty"`
+	ColumnID    string       `bson:"column_id" json:"columnId"`
+	Rank        string       `bson:"rank" json:"rank"`
+	Priority    TaskPriority `bson:"priority" json:"priority"`
+	Estimate    *float64     `bson:"estimate,omitempty" json:"estimate,omitempty"` // Story points
+	DueDate     *time.Time   `bson:"due_date,omitempty" json:"dueDate,omitempty"`
+	MilestoneID string       `bson:"milestone_id,omitempty" json:"milestoneId,omitempty"`
+	DocumentIDs []string     `bson:"document_ids" json:"documentIds"`
+	Version     int64        `bson:"version" json:"version"`
+	CreatedBy   string       `bson:"created_by" json:"createdBy"`
+	CreatedAt   time.Time    `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time    `bson:"updated_at" json:"updatedAt"`
+}
+
+// Board holds the columns of a project's task board. Every project gets one
+// with the default columns the first time it is opened.
+type Board struct {
+	ID        string        `bson:"_id,omitempty" json:"id"`
+	OrgID     string        `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID string        `bson:"project_id" json:"projectId"`
+	Columns   []BoardColumn `bson:"columns" json:"columns"`
+	Version   int64         `bson:"version" json:"version"`
+	CreatedAt time.Time     `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updatedAt"`
+}
+
+type BoardColumn struct {
+	ID   string `bson:"id" json:"id"`
+	Name string `bson:"name" json:"name"`
+}
+
+// DefaultBoardColumns are the columns of a new board
+func DefaultBoardColumns() []BoardColumn {
+	return []BoardColumn{
+		{ID: "todo", Name: "To Do"},
+		{ID: "in_progress", Name: "In Progress"},
+		{ID: "review", Name: "Review"},
+		{ID: "done", Name: "Done"},
+	}
+}
+
+// Column returns the column with the given ID, or nil if the board has none
+func (b *Board) Column(id string) *BoardColumn {
+	for i := range b.Columns {
+		if b.Columns[i].ID == id {
+			return &b.Columns[i]
+		}
+	}
+	return nil
+}
+
+// BoardView is a board with the tasks of each column in order
+type BoardView struct {
+	*Board
+	Columns []BoardColumnView `json:"columns"`
+}
+
+type BoardColumnView struct {
+	BoardColumn
+	Tasks []*Task `json:"tasks"`
+}
+
+// BuildBoardView groups tasks into the columns of a board. Tasks must already
+// be ordered by rank; tasks in columns the board no longer has are left out.
+func BuildBoardView(board *Board, tasks []*Task) *BoardView {
+	view := &BoardView{Board: board, Columns: make([]BoardColumnView, len(board.Columns))}
+	index := make(map[string]int, len(board.Columns))
+	for i, column := range board.Columns {
+		view.Columns[i] = BoardColumnView{BoardColumn: column, Tasks: []*Task{}}
+		index[column.ID] = i
+	}
+	for _, task := range tasks {
+		if i, ok := index[task.ColumnID]; ok {
+			view.Columns[i].Tasks = append(view.Columns[i].Tasks, task)
+		}
+	}
+	return view
+}
+
+type CreateTaskInput struct {
+	Title       string       `json:"title" binding:"required"`
+	Description string       `json:"description"`
+	AssigneeID  string       `json:"assigneeId"`
+	ColumnID    string       `json:"columnId"` // Defaults to the first column
+	Priority    TaskPriority `json:"priority"` // Defaults to medium
+	Estimate    *float64     `json:"estimate,omitempty"`
+	DueDate     *time.Time   `json:"dueDate,omitempty"`
+	MilestoneID string       `json:"milestoneId"`
+	DocumentIDs []string     `json:"documentIds"`
+}
+
+func (i *CreateTaskInput) Validate() error {
+	i.Title = strings.TrimSpace(i.Title)
+	if i.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if i.Priority == "" {
+		i.Priority = TaskPriorityMedium
+	}
+	if !i.Priority.IsValid() {
+		return fmt.Errorf("invalid task priority: %s", i.Priority)
+	}
+	return validateEstimate(i.Estimate)
+}
+
+// UpdateTaskInput changes the fields it sets. Empty strings clear the
+// assignee and milestone. A version, when given, must match the task's.
+type UpdateTaskInput struct {
+	Title       *string       `json:"title,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	AssigneeID  *string       `json:"assigneeId,omitempty"`
+	Priority    *TaskPriority `json:"priority,omitempty"`
+	Estimate    *float64      `json:"estimate,omitempty"`
+	DueDate     *time.Time    `json:"dueDate,omitempty"`
+	MilestoneID *string       `json:"milestoneId,omitempty"`
+	DocumentIDs *[]string     `json:"documentIds,omitempty"`
+	Version     *int64        `json:"version,omitempty"`
+}
+
+func (i *UpdateTaskInput) Validate() error {
+	if i.Title != nil {
+		*i.Title = strings.TrimSpace(*i.Title)
+		if *i.Title == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+	}
+	if i.Priority != nil && !i.Priority.IsValid() {
+		return fmt.Errorf("invalid task priority: %s", *i.Priority)
+	}
+	return validateEstimate(i.Estimate)
+}
+
+// MoveTaskInput places a task in a column between two neighbours. AfterID is
+// the task that should end up directly above it and BeforeID the one directly
+// below; leaving both empty puts the task at the bottom of the column.
+type MoveTaskInput struct {
+	ColumnID string `json:"columnId" binding:"required"`
+	AfterID  string `json:"afterId"`
+	BeforeID string `json:"beforeId"`
+	// Version is the version of the task the client dragged; a move based
+	// on an older one is refused
+	Version int64 `json:"version" binding:"required"`
+}
+
+func (i *MoveTaskInput) Validate() error {
+	if i.ColumnID == "" {
+		return fmt.Errorf("column is required")
+	}
+	if i.Version <= 0 {
+		return fmt.Errorf("version is required")
+	}
+	if i.AfterID != "" && i.AfterID == i.BeforeID {
+		return fmt.Errorf("a task cannot be both above and below the moved task")
+	}
+	return nil
+}
+
+// UpdateBoardColumnsInput replaces the columns of a board. Existing columns
+// are kept by passing their ID; columns left out are removed and must be
+// empty.
+type UpdateBoardColumnsInput struct {
+	Columns []BoardColumnInput `json:"columns" binding:"required"`
+	Version int64              `json:"version"`
+}
+
+type BoardColumnInput struct {
+	ID   string `json:"id"` // Empty for new columns
+	Name string `json:"name" binding:"required"`
+}
+
+func (i *UpdateBoardColumnsInput) Validate() error {
+	if len(i.Columns) == 0 {
+		return fmt.Errorf("a board needs at least one column")
+	}
+	for n := range i.Columns {
+		i.Columns[n].Name = strings.TrimSpace(i.Columns[n].Name)
+		if i.Columns[n].Name == "" {
+			return fmt.Errorf("column %d needs a name", n+1)
+		}
+	}
+	return nil
+}
+
+// TaskFilter narrows task listings; empty fields match every task
+type TaskFilter struct {
+	ColumnID    string
+	AssigneeID  string
+	MilestoneID string
+	Priority    TaskPriority
+}
+
+func validateEstimate(estimate *float64) error {
+	if estimate != nil && *estimate < 0 {
+		return fmt.Errorf("estimate cannot be negative")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 
 import (
 	"errors"
+	"projectnexus/internal/pagination"
 	"time"
 )
 
@@ -21,8 +22,17 @@ const (
 	TeamMemberStatusInactive TeamMemberStatus = "inactive"
 )
 
+// TeamMemberSource tells how a user became a project member
+type TeamMemberSource string
+
+const (
+	TeamMemberSourceDirect TeamMemberSource = "direct"
+	TeamMemberSourceTeam   TeamMemberSource = "team"
+)
+
 type TeamMember struct {
 	ID          string           `bson:"_id,omitempty" json:"id"`
+	OrgID       string           `bson:"org_id,omitempty" json:"orgId,omitempty"`
 	UserID      string           `bson:"user_id" json:"userId"`
 	Name        string           `bson:"name" json:"name"`
 	Description string           `bson:"description" json:"description"`
@@ -32,21 +42,61 @@ type TeamMember struct {
 	CreatedAt   time.Time        `bson:"created_at" json:"createdAt"`
 	UpdatedAt   time.Time        `bson:"updated_at" json:"updatedAt"`
 	ProjectID   string           `bson:"project_id" json:"projectId"`
+	DeletedAt   *time.Time       `bson:"deleted_at,omitempty" json:"-"`
+
+	// Source is empty on members added before teams could be assigned,
+	// which counts as direct
+	Source TeamMemberSource `bson:"source,omitempty" json:"source"`
+	// TeamIDs lists the assigned teams the user is a member through
+	TeamIDs []string `bson:"team_ids,omitempty" json:"teamIds,omitempty"`
+	// Override marks a team-derived member whose role or status was set by
+	// hand; syncing with the teams leaves those untouched
+	Override bool `bson:"override,omitempty" json:"override"`
+}
+
+// IsDirect reports whether the member was added to the project individually
+func (m *TeamMember) IsDirect() bool {
+	return m.Source != TeamMemberSourceTeam
 }
 
+// ProjectTeamAssignment attaches a standalone team to a project; its members
+// become project members with the default role
+type ProjectTeamAssignment struct {
+	TeamID     string    `bson:"team_id" json:"teamId"`
+	Role       TeamRole  `bson:"role" json:"role"`
+	AssignedBy string    `bson:"assigned_by" json:"assignedBy"`
+	AssignedAt time.Time `bson:"assigned_at" json:"assignedAt"`
+}
+
+// Team is a standalone group of users, stored in its own collection.
+// Members reference users by ID; the lead is always one of them.
 type Team struct {
-	ID          string       `bson:"_id,omitempty" json:"id"`
-	Name        string       `bson:"name" json:"name"`
-	Description string       `bson:"description" json:"description"`
-	Lead        string       `bson:"lead" json:"lead"`
-	CreatedAt   time.Time    `bson:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time    `bson:"updated_at" json:"updatedAt"`
-	Members     []TeamMember `bson:"members" json:"members"`
+	ID          string           `bson:"_id,omitempty" json:"id"`
+	OrgID       string           `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	Name        string           `bson:"name" json:"name"`
+	Description string           `bson:"description" json:"description"`
+	Lead        string           `bson:"lead" json:"lead"`
+	CreatedBy   string           `bson:"created_by" json:"createdBy"`
+	CreatedAt   time.Time        `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time        `bson:"updated_at" json:"updatedAt"`
+	Members     []TeamMembership `bson:"members" json:"members"`
+}
+
+// TeamMembership is a user's place in a team
+type TeamMembership struct {
+	UserID   string    `bson:"user_id" json:"userId"`
+	Role     TeamRole  `bson:"role" json:"role"`
+	JoinedAt time.Time `bson:"joined_at" json:"joinedAt"`
+	// Name and Email come from the user when a team is read
+	Name  string `bson:"-" json:"name"`
+	Email string `bson:"-" json:"email"`
 }
 
 type CreateTeamInput struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description" binding:"required"`
+	// Lead defaults to the creator
+	Lead string `json:"lead,omitempty"`
 }
 
 type UpdateTeamInput struct {
@@ -60,9 +110,25 @@ type AddTeamMemberInput struct {
 	Role   TeamRole `json:"role" binding:"required"`
 }
 
+type UpdateTeamMembershipInput struct {
+	Role TeamRole `json:"role" binding:"required"`
+}
+
 type UpdateTeamMemberInput struct {
 	Role   *TeamRole         `json:"role,omitempty"`
 	Status *TeamMemberStatus `json:"status,omitempty"`
+	// Inherit drops the override of a team-derived member so its role and
+	// status follow the assigned teams again
+	Inherit bool `json:"inherit,omitempty"`
+}
+
+type AssignTeamInput struct {
+	TeamID string   `json:"teamId" binding:"required"`
+	Role   TeamRole `json:"role" binding:"required"`
+}
+
+type UpdateTeamAssignmentInput struct {
+	Role TeamRole `json:"role" binding:"required"`
 }
 
 func (r TeamRole) IsValid() bool {
@@ -74,6 +140,20 @@ func (r TeamRole) IsValid() bool {
 	}
 }
 
+// Rank orders roles by the access they grant
+func (r TeamRole) Rank() int {
+	switch r {
+	case TeamRoleOwner:
+		return 3
+	case TeamRoleMember:
+		return 2
+	case TeamRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func (s TeamMemberStatus) IsValid() bool {
 	switch s {
 	case TeamMemberStatusActive, TeamMemberStatusInactive:
@@ -93,6 +173,36 @@ func (i *AddTeamMemberInput) Validate() error {
 	return nil
 }
 
+func (i *UpdateTeamMembershipInput) Validate() error {
+	if !i.Role.IsValid() {
+		return errors.New("invalid team role")
+	}
+	return nil
+}
+
+func (i *AssignTeamInput) Validate() error {
+	if i.TeamID == "" {
+		return errors.New("team ID is required")
+	}
+	return validateAssignmentRole(i.Role)
+}
+
+func (i *UpdateTeamAssignmentInput) Validate() error {
+	return validateAssignmentRole(i.Role)
+}
+
+// validateAssignmentRole keeps project ownership to directly added members,
+// so it cannot change hands as people join and leave a team
+func validateAssignmentRole(role TeamRole) error {
+	if !role.IsValid() {
+		return errors.New("invalid team role")
+	}
+	if role == TeamRoleOwner {
+		return errors.New("teams cannot be assigned the owner role")
+	}
+	return nil
+}
+
 func (i *UpdateTeamMemberInput) Validate() error {
 	if i.Role != nil && !i.Role.IsValid() {
 		return errors.New("invalid team role")
@@ -102,3 +212,61 @@ func (i *UpdateTeamMemberInput) Validate() error {
 	}
 	return nil
 }
+
+// Member returns the membership of a user, or nil if they are not in the team
+func (t *Team) Member(userID string) *TeamMembership {
+	for i := range t.Members {
+		if t.Members[i].UserID == userID {
+			return &t.Members[i]
+		}
+	}
+	return nil
+}
+
+// CanManage reports whether a user may edit the team and its members
+func (t *Team) CanManage(userID string) bool {
+	if userID == t.Lead || userID == t.CreatedBy {
+		return true
+	}
+	member := t.Member(userID)
+	return member != nil && member.Role == TeamRoleOwner
+}
+
+// DeriveProjectMembers works out which users an assigned set of teams makes
+// project members, with the highest default role among their teams
+func DeriveProjectMembers(assignments []ProjectTeamAssignment, teams map[string]*Team) map[string]*TeamMember {
+	derived := make(map[string]*TeamMember)
+	for _, assignment := range assignments {
+		team, ok := teams[assignment.TeamID]
+		if !ok {
+			continue
+		}
+		for _, membership := range team.Members {
+			member, ok := derived[membership.UserID]
+			if !ok {
+				member = &TeamMember{
+					UserID: membership.UserID,
+					Role:   assignment.Role,
+					Status: TeamMemberStatusActive,
+					Source: TeamMemberSourceTeam,
+				}
+				derived[membership.UserID] = member
+			} else if assignment.Role.Rank() > member.Role.Rank() {
+				member.Role = assignment.Role
+			}
+			member.TeamIDs = append(member.TeamIDs, assignment.TeamID)
+		}
+	}
+	return derived
+}
+
+// TeamListSpec describes the sorting and filtering of team listings
+var TeamListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "name", Field: "name", Kind: pagination.SortString},
+		{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+		{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "name",
+	Filters:     []string{pagination.FilterUpdatedSince},
+}
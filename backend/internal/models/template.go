@@ -0,0 +1,254 @@
+// Package models internal/models/template.go
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProjectTemplate is a starting point for new projects: the documents they
+// begin with and the people and teams they are shared with. Templates belong
+// to an organization and every member can use them.
+type ProjectTemplate struct {
+	ID          string `bson:"_id,omitempty" json:"id"`
+	OrgID       string `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+	// Status is the status of projects created from the template
+	Status    ProjectStatus      `bson:"status" json:"status"`
+	Documents []TemplateDocument `bson:"documents" json:"documents"`
+	Members   []TemplateMember   `bson:"members" json:"members"`
+	Teams     []TemplateTeam     `bson:"teams" json:"teams"`
+	// ProjectKey stands in for the project ID in document links, for
+	// templates saved from a project
+	ProjectKey string    `bson:"project_key,omitempty" json:"projectKey,omitempty"`
+	CreatedBy  string    `bson:"created_by" json:"createdBy"`
+	CreatedAt  time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// TemplateDocument is a starter document. Its key stands in for a document
+// ID, so content can link to the other documents of the template with
+// /documents/<key>; the links point at the new documents once created.
+type TemplateDocument struct {
+	Key     string         `bson:"key" json:"key"`
+	Title   string         `bson:"title" json:"title"`
+	Type    DocumentType   `bson:"type" json:"type"`
+	Content string         `bson:"content" json:"content"`
+	Status  DocumentStatus `bson:"status" json:"status"`
+	Tags    []string       `bson:"tags" json:"tags"`
+}
+
+// TemplateMember is a user who joins projects created from the template
+type TemplateMember struct {
+	UserID string   `bson:"user_id" json:"userId"`
+	Role   TeamRole `bson:"role" json:"role"`
+}
+
+// TemplateTeam is a team assigned to projects created from the template
+type TemplateTeam struct {
+	TeamID string   `bson:"team_id" json:"teamId"`
+	Role   TeamRole `bson:"role" json:"role"`
+}
+
+var templateKeyPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// TemplateInput creates a template or replaces all of its content
+type TemplateInput struct {
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	Status      ProjectStatus      `json:"status"` // Defaults to Planning
+	Documents   []TemplateDocument `json:"documents"`
+	Members     []TemplateMember   `json:"members"`
+	Teams       []TemplateTeam     `json:"teams"`
+}
+
+// Validate checks the input and fills in defaults. Documents without a key
+// are left for the service to give one.
+func (i *TemplateInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if i.Status == "" {
+		i.Status = ProjectStatusPlanning
+	}
+	if !i.Status.IsValid() {
+		return fmt.Errorf("invalid project status: %s", i.Status)
+	}
+
+	keys := make(map[string]bool, len(i.Documents))
+	for n := range i.Documents {
+		doc := &i.Documents[n]
+		doc.Title = strings.TrimSpace(doc.Title)
+		if doc.Title == "" {
+			return fmt.Errorf("document %d needs a title", n+1)
+		}
+		if !doc.Type.IsValid() {
+			return fmt.Errorf("invalid document type: %s", doc.Type)
+		}
+		if doc.Status == "" {
+			doc.Status = DocumentStatusDraft
+		}
+		if !doc.Status.IsValid() {
+			return fmt.Errorf("invalid document status: %s", doc.Status)
+		}
+		if doc.Key == "" {
+			continue
+		}
+		doc.Key = strings.ToLower(doc.Key)
+		if !templateKeyPattern.MatchString(doc.Key) {
+			return fmt.Errorf("document key %s must be 24 hexadecimal characters", doc.Key)
+		}
+		if keys[doc.Key] {
+			return fmt.Errorf("duplicate document key: %s", doc.Key)
+		}
+		keys[doc.Key] = true
+	}
+
+	users := make(map[string]bool, len(i.Members))
+	for _, m := range i.Members {
+		if m.UserID == "" {
+			return fmt.Errorf("members need a user")
+		}
+		if !m.Role.IsValid() {
+			return fmt.Errorf("invalid team role: %s", m.Role)
+		}
+		if users[m.UserID] {
+			return fmt.Errorf("duplicate member: %s", m.UserID)
+		}
+		users[m.UserID] = true
+	}
+
+	teams := make(map[string]bool, len(i.Teams))
+	for _, t := range i.Teams {
+		if t.TeamID == "" {
+			return fmt.Errorf("teams need an ID")
+		}
+		if err := validateAssignmentRole(t.Role); err != nil {
+			return err
+		}
+		if teams[t.TeamID] {
+			return fmt.Errorf("duplicate team: %s", t.TeamID)
+		}
+		teams[t.TeamID] = true
+	}
+	return nil
+}
+
+// DocumentSelection picks which documents a copy of a project takes
+type DocumentSelection string
+
+const (
+	// DocumentSelectionLatest copies every document as it is now
+	DocumentSelectionLatest DocumentSelection = "latest"
+	// DocumentSelectionApproved copies the last approved version of every
+	// document that has been approved, even if it changed since
+	DocumentSelectionApproved DocumentSelection = "approved"
+	// DocumentSelectionNone copies no documents
+	DocumentSelectionNone DocumentSelection = "none"
+)
+
+func (s DocumentSelection) IsValid() bool {
+	switch s {
+	case DocumentSelectionLatest, DocumentSelectionApproved, DocumentSelectionNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Includes reports whether a document, as it is now, is part of the
+// selection
+func (s DocumentSelection) Includes(doc *Document) bool {
+	switch s {
+	case DocumentSelectionLatest:
+		return true
+	case DocumentSelectionApproved:
+		return doc.Status == DocumentStatusApproved
+	default:
+		return false
+	}
+}
+
+// SaveTemplateInput turns a project into a template
+type SaveTemplateInput struct {
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Documents   DocumentSelection `json:"documents"` // Defaults to latest
+	// IncludeMembers keeps the project's direct members and assigned teams
+	IncludeMembers bool `json:"includeMembers"`
+}
+
+func (i *SaveTemplateInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if i.Documents == "" {
+		i.Documents = DocumentSelectionLatest
+	}
+	if !i.Documents.IsValid() {
+		return fmt.Errorf("invalid document selection: %s", i.Documents)
+	}
+	return nil
+}
+
+// UseTemplateInput creates a project from a template
+type UseTemplateInput struct {
+	Name        string        `json:"name" binding:"required"`
+	Description string        `json:"description"` // Defaults to the template's
+	Status      ProjectStatus `json:"status"`      // Defaults to the template's
+	StartDate   *time.Time    `json:"startDate,omitempty"`
+	EndDate     *time.Time    `json:"endDate,omitempty"`
+}
+
+// ProjectInput combines the input with the template's defaults
+func (i *UseTemplateInput) ProjectInput(template *ProjectTemplate) CreateProjectInput {
+	input := CreateProjectInput{
+		Name:        strings.TrimSpace(i.Name),
+		Description: i.Description,
+		Status:      i.Status,
+		StartDate:   i.StartDate,
+		EndDate:     i.EndDate,
+	}
+	if strings.TrimSpace(input.Description) == "" {
+		input.Description = template.Description
+	}
+	if strings.TrimSpace(input.Description) == "" {
+		input.Description = template.Name
+	}
+	if input.Status == "" {
+		input.Status = template.Status
+	}
+	return input
+}
+
+// CloneProjectInput copies a project. Pointers default to true.
+type CloneProjectInput struct {
+	Name           string            `json:"name"`      // Defaults to "Copy of <name>"
+	Documents      DocumentSelection `json:"documents"` // Defaults to latest
+	IncludeMockups *bool             `json:"includeMockups,omitempty"`
+	IncludeMembers *bool             `json:"includeMembers,omitempty"`
+}
+
+func (i *CloneProjectInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Documents == "" {
+		i.Documents = DocumentSelectionLatest
+	}
+	if !i.Documents.IsValid() {
+		return fmt.Errorf("invalid document selection: %s", i.Documents)
+	}
+	return nil
+}
+
+func (i *CloneProjectInput) Mockups() bool {
+	return i.IncludeMockups == nil || *i.IncludeMockups
+}
+
+func (i *CloneProjectInput) Members() bool {
+	return i.IncludeMembers == nil || *i.IncludeMembers
+}
@@ -0,0 +1,49 @@
+// internal/models/activity_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestActivity_Describe(t *testing.T) {
+	tests := []struct {
+		name     string
+		activity models.Activity
+		want     string
+	}{
+		{
+			name:     "approval",
+			activity: models.Activity{ActorName: "Alice", Verb: models.ActivityDocumentApproved, Subject: "LLD v4", Count: 1},
+			want:     "Alice approved LLD v4",
+		},
+		{
+			name:     "burst of edits",
+			activity: models.Activity{ActorName: "Alice", Verb: models.ActivityDocumentEdited, Subject: "LLD v4", Count: 10},
+			want:     "Alice edited LLD v4 (10 times)",
+		},
+		{
+			name:     "status change",
+			activity: models.Activity{ActorName: "Bob", Verb: models.ActivityProjectStatus, Subject: "Checkout", Detail: "In Progress", Count: 1},
+			want:     "Bob moved project Checkout to In Progress",
+		},
+		{
+			name:     "unknown actor",
+			activity: models.Activity{Verb: models.ActivityMemberAdded, Subject: "Carol", Count: 1},
+			want:     "Someone added Carol to the team",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.activity.Describe())
+		})
+	}
+}
+
+func TestActivityType_IsValid(t *testing.T) {
+	assert.True(t, models.ActivityTypeDocument.IsValid())
+	assert.True(t, models.ActivityTypeTeam.IsValid())
+	assert.False(t, models.ActivityType("task").IsValid())
+}
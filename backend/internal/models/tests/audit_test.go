@@ -0,0 +1,57 @@
+// internal/models/audit_test.go
+package tests
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := models.Task{ID: "task-1", Title: "Draft", Priority: models.TaskPriorityLow, Version: 1, UpdatedAt: time.Now()}
+	after := before
+	after.Title = "Final"
+	after.AssigneeID = "user-1"
+	after.Version = 2
+	after.UpdatedAt = before.UpdatedAt.Add(time.Minute)
+
+	changes := models.AuditDiff(before, after)
+	require.Len(t, changes, 2)
+	assert.Equal(t, models.AuditChange{Field: "assigneeId", Before: "", After: `"user-1"`}, changes[0])
+	assert.Equal(t, models.AuditChange{Field: "title", Before: `"Draft"`, After: `"Final"`}, changes[1])
+
+	data, err := json.Marshal(changes[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"field":"assigneeId","before":null,"after":"user-1"}`, string(data))
+}
+
+func TestAuditDiff_CreationsAndDeletions(t *testing.T) {
+	task := models.Task{ID: "task-1", Title: "Draft"}
+
+	created := models.AuditDiff(nil, task)
+	require.Len(t, created, 1)
+	assert.Equal(t, "title", created[0].Field)
+	assert.Equal(t, models.AuditValue(`"Draft"`), created[0].After)
+
+	deleted := models.AuditDiff(task, nil)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, models.AuditValue(`"Draft"`), deleted[0].Before)
+	assert.Equal(t, models.AuditValue(""), deleted[0].After)
+
+	assert.Empty(t, models.AuditDiff(task, task))
+}
+
+func TestAuditDiff_ShortensLongText(t *testing.T) {
+	before := models.Document{Content: "short"}
+	after := models.Document{Content: strings.Repeat("é", 600)}
+
+	changes := models.AuditDiff(before, after)
+	require.Len(t, changes, 1)
+	var text string
+	require.NoError(t, json.Unmarshal([]byte(changes[0].After), &text))
+	assert.Equal(t, strings.Repeat("é", 500)+"…", text)
+}
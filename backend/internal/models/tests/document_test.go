@@ -228,3 +228,23 @@ func stringPtr(s string) *string {
 func docTypePtr(t models.DocumentType) *models.DocumentType {
 	return &t
 }
+
+func TestApprovingVersion(t *testing.T) {
+	approved, draft := models.DocumentStatusApproved, models.DocumentStatusDraft
+	versions := func(statuses ...models.DocumentStatus) []*models.DocumentVersion {
+		result := make([]*models.DocumentVersion, len(statuses))
+		for i, status := range statuses {
+			result[i] = &models.DocumentVersion{Version: len(statuses) - i, Status: status}
+		}
+		return result
+	}
+
+	// Latest first: versions 4 and 3 approved it again after the draft
+	got := models.ApprovingVersion(versions(approved, approved, draft, approved))
+	if assert.NotNil(t, got) {
+		assert.Equal(t, 3, got.Version)
+	}
+	assert.Nil(t, models.ApprovingVersion(versions(draft, approved)))
+	assert.Nil(t, models.ApprovingVersion(versions("", approved)))
+	assert.Nil(t, models.ApprovingVersion(nil))
+}
@@ -0,0 +1,85 @@
+// internal/models/folder_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestBuildFolderTree(t *testing.T) {
+	folders := []*models.Folder{
+		{ID: "design", Name: "Design", Position: 1},
+		{ID: "specs", Name: "Specs", Position: 0},
+		{ID: "lld", ParentID: "design", Name: "LLD", Position: 1},
+		{ID: "hld", ParentID: "design", Name: "HLD", Position: 0},
+		{ID: "orphan", ParentID: "missing", Name: "Orphan", Position: 2},
+	}
+
+	tree := models.BuildFolderTree(folders)
+
+	require.Len(t, tree, 3)
+	assert.Equal(t, "specs", tree[0].ID)
+	assert.Equal(t, "design", tree[1].ID)
+	assert.Equal(t, "orphan", tree[2].ID)
+
+	require.Len(t, tree[1].Children, 2)
+	assert.Equal(t, "hld", tree[1].Children[0].ID)
+	assert.Equal(t, "lld", tree[1].Children[1].ID)
+	assert.Empty(t, tree[0].Children)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "trims lowercases and sorts",
+			tags: []string{" Backend", "api ", "Auth"},
+			want: []string{"api", "auth", "backend"},
+		},
+		{
+			name: "drops empty and duplicate tags",
+			tags: []string{"api", "", "API", "  "},
+			want: []string{"api"},
+		},
+		{
+			name: "nil input",
+			tags: nil,
+			want: []string{},
+		},
+		{
+			name:    "tag too long",
+			tags:    []string{strings.Repeat("x", models.MaxTagLength+1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.NormalizeTags(tt.tags)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateDocumentInput_OnlyOrganization(t *testing.T) {
+	folderID := "folder1"
+	title := "New title"
+	tags := []string{"api"}
+
+	assert.True(t, (&models.UpdateDocumentInput{FolderID: &folderID}).OnlyOrganization())
+	assert.True(t, (&models.UpdateDocumentInput{Tags: &tags}).OnlyOrganization())
+	assert.False(t, (&models.UpdateDocumentInput{FolderID: &folderID, Title: &title}).OnlyOrganization())
+	assert.False(t, (&models.UpdateDocumentInput{}).OnlyOrganization())
+}
@@ -0,0 +1,57 @@
+// internal/models/invitation_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCreateInvitationInput_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     models.CreateInvitationInput
+		wantErr   bool
+		wantEmail string
+	}{
+		{
+			name:      "normalizes email",
+			input:     models.CreateInvitationInput{Email: "  Jane.Doe@Example.COM ", Role: models.TeamRoleMember},
+			wantEmail: "jane.doe@example.com",
+		},
+		{
+			name:    "invalid email",
+			input:   models.CreateInvitationInput{Email: "not-an-email", Role: models.TeamRoleMember},
+			wantErr: true,
+		},
+		{
+			name:    "invalid role",
+			input:   models.CreateInvitationInput{Email: "jane@example.com", Role: "admin"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEmail, tt.input.Email)
+		})
+	}
+}
+
+func TestInvitation_IsOpen(t *testing.T) {
+	now := time.Now()
+	pending := &models.Invitation{Status: models.InvitationStatusPending, ExpiresAt: now.Add(time.Hour)}
+	expired := &models.Invitation{Status: models.InvitationStatusPending, ExpiresAt: now.Add(-time.Hour)}
+	accepted := &models.Invitation{Status: models.InvitationStatusAccepted, ExpiresAt: now.Add(time.Hour)}
+
+	assert.True(t, pending.IsOpen(now))
+	assert.False(t, expired.IsOpen(now))
+	assert.False(t, accepted.IsOpen(now))
+}
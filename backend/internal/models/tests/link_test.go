@@ -0,0 +1,147 @@
+// internal/models/link_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestExtractLinkRefs(t *testing.T) {
+	docID := "65a1b2c3d4e5f6a7b8c9d0e1"
+	mockupID := "65a1b2c3d4e5f6a7b8c9d0e2"
+	projectID := "65a1b2c3d4e5f6a7b8c9d0e3"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []models.LinkRef
+	}{
+		{
+			name:    "relative document link",
+			content: "See the [LLD](/documents/" + docID + ") for details.",
+			want:    []models.LinkRef{{Type: models.LinkItemDocument, ID: docID}},
+		},
+		{
+			name:    "absolute mockup link with api prefix",
+			content: "[Login](https://project.chillotters.xyz/api/v1/mockups/" + mockupID + ")",
+			want:    []models.LinkRef{{Type: models.LinkItemMockup, ID: mockupID}},
+		},
+		{
+			name:    "project scoped document link",
+			content: "[Spec](/projects/" + projectID + "/documents/" + docID + "#section)",
+			want:    []models.LinkRef{{Type: models.LinkItemDocument, ID: docID}},
+		},
+		{
+			name:    "project link",
+			content: "Part of [Nexus](/projects/" + projectID + ")",
+			want:    []models.LinkRef{{Type: models.LinkItemProject, ID: projectID}},
+		},
+		{
+			name: "duplicates collapsed in order",
+			content: "[a](/mockups/" + mockupID + ") [b](/documents/" + docID + ") [c](/mockups/" +
+				mockupID + ")",
+			want: []models.LinkRef{
+				{Type: models.LinkItemMockup, ID: mockupID},
+				{Type: models.LinkItemDocument, ID: docID},
+			},
+		},
+		{
+			name:    "external and bare links ignored",
+			content: "[Docs](https://example.com/guide) and /documents/" + docID,
+			want:    []models.LinkRef{},
+		},
+		{
+			name:    "malformed id ignored",
+			content: "[x](/documents/" + docID + "ff)",
+			want:    []models.LinkRef{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ExtractLinkRefs(tt.content))
+		})
+	}
+}
+
+func TestCreateLinkInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   models.CreateLinkInput
+		wantErr bool
+	}{
+		{
+			name: "valid document to mockup",
+			input: models.CreateLinkInput{
+				SourceType: models.LinkItemDocument,
+				SourceID:   "doc1",
+				TargetType: models.LinkItemMockup,
+				TargetID:   "mockup1",
+				Type:       models.LinkTypeImplements,
+			},
+			wantErr: false,
+		},
+		{
+			name: "project as source",
+			input: models.CreateLinkInput{
+				SourceType: models.LinkItemProject,
+				SourceID:   "proj1",
+				TargetType: models.LinkItemDocument,
+				TargetID:   "doc1",
+				Type:       models.LinkTypeReferences,
+			},
+			wantErr: true,
+		},
+		{
+			name: "self link",
+			input: models.CreateLinkInput{
+				SourceType: models.LinkItemDocument,
+				SourceID:   "doc1",
+				TargetType: models.LinkItemDocument,
+				TargetID:   "doc1",
+				Type:       models.LinkTypeSupersedes,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid link type",
+			input: models.CreateLinkInput{
+				SourceType: models.LinkItemDocument,
+				SourceID:   "doc1",
+				TargetType: models.LinkItemDocument,
+				TargetID:   "doc2",
+				Type:       models.LinkType("blocks"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemapLinks(t *testing.T) {
+	oldDoc := "65a1b2c3d4e5f6a7b8c9d0e1"
+	newDoc := "75a1b2c3d4e5f6a7b8c9d0e1"
+	oldProject := "65a1b2c3d4e5f6a7b8c9d0e3"
+	newProject := "75a1b2c3d4e5f6a7b8c9d0e3"
+	other := "65a1b2c3d4e5f6a7b8c9d0ff"
+	ids := map[string]string{oldDoc: newDoc, oldProject: newProject}
+
+	content := "See [LLD](/projects/" + oldProject + "/documents/" + oldDoc + "#api), " +
+		"[Other](/documents/" + other + ") and commit " + oldDoc + "."
+	want := "See [LLD](/projects/" + newProject + "/documents/" + newDoc + "#api), " +
+		"[Other](/documents/" + other + ") and commit " + oldDoc + "."
+
+	assert.Equal(t, want, models.RemapLinks(content, ids))
+	assert.Equal(t, content, models.RemapLinks(content, nil))
+}
@@ -0,0 +1,75 @@
+// internal/models/milestone_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAnnotateMilestones(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	milestones := []*models.Milestone{
+		{ID: "later", DueDate: now.Add(30 * day)},
+		{ID: "done", DueDate: now.Add(-20 * day), Completed: true},
+		{ID: "next", DueDate: now.Add(5 * day)},
+		{ID: "late", DueDate: now.Add(-2 * day)},
+	}
+
+	models.AnnotateMilestones(milestones, now)
+
+	order := make([]string, 0, len(milestones))
+	statuses := make(map[string]models.MilestoneStatus)
+	for _, m := range milestones {
+		order = append(order, m.ID)
+		statuses[m.ID] = m.Status
+	}
+	assert.Equal(t, []string{"done", "late", "next", "later"}, order)
+	assert.Equal(t, models.MilestoneStatusCompleted, statuses["done"])
+	assert.Equal(t, models.MilestoneStatusOverdue, statuses["late"])
+	assert.Equal(t, models.MilestoneStatusCurrent, statuses["next"])
+	assert.Equal(t, models.MilestoneStatusUpcoming, statuses["later"])
+	assert.True(t, milestones[1].Overdue)
+	assert.False(t, milestones[0].Overdue)
+}
+
+func TestMilestone_SetCompleted(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	m := &models.Milestone{DueDate: now.Add(-time.Hour)}
+	assert.True(t, m.IsOverdue(now))
+
+	m.SetCompleted(true, "user1", now)
+	assert.True(t, m.Completed)
+	assert.Equal(t, "user1", m.CompletedBy)
+	assert.Equal(t, now, *m.CompletedAt)
+	assert.False(t, m.IsOverdue(now))
+
+	// Completing again keeps the original completion
+	m.SetCompleted(true, "user2", now.Add(time.Hour))
+	assert.Equal(t, "user1", m.CompletedBy)
+
+	m.SetCompleted(false, "user2", now)
+	assert.Nil(t, m.CompletedAt)
+	assert.Empty(t, m.CompletedBy)
+}
+
+func TestMilestoneInputs_Validate(t *testing.T) {
+	due := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	create := models.CreateMilestoneInput{Title: "  Beta  ", DueDate: due}
+	assert.NoError(t, create.Validate())
+	assert.Equal(t, "Beta", create.Title)
+	assert.Error(t, (&models.CreateMilestoneInput{Title: "Beta"}).Validate())
+	assert.Error(t, (&models.CreateMilestoneInput{
+		Title:     "Beta",
+		DueDate:   due,
+		Checklist: []models.ChecklistItemInput{{Title: " "}},
+	}).Validate())
+
+	empty := ""
+	assert.Error(t, (&models.UpdateMilestoneInput{Title: &empty}).Validate())
+	assert.NoError(t, (&models.UpdateMilestoneInput{}).Validate())
+}
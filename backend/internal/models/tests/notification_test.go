@@ -0,0 +1,106 @@
+// internal/models/notification_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+const (
+	aliceID = "64b7f0c2e4b0a1a2b3c4d5e6"
+	bobID   = "64b7f0c2e4b0a1a2b3c4d5e7"
+)
+
+func TestExtractMentions(t *testing.T) {
+	content := "Thanks @[Alice](user:" + aliceID + ") and @[Bob]( user:" + bobID + " ). " +
+		"Again @[Alice again](user:" + aliceID + "), not @bob or [Bob](/documents/" + bobID + ")."
+
+	assert.Equal(t, []string{aliceID, bobID}, models.ExtractMentions(content))
+	assert.Empty(t, models.ExtractMentions("no mentions here"))
+}
+
+func TestNewMentions(t *testing.T) {
+	previous := "@[Alice](user:" + aliceID + ")"
+	content := previous + " @[Bob](user:" + bobID + ")"
+
+	assert.Equal(t, []string{bobID}, models.NewMentions(previous, content))
+	assert.Empty(t, models.NewMentions(content, previous))
+	assert.Equal(t, []string{aliceID, bobID}, models.NewMentions("", content))
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	var prefs models.NotificationPreferences
+	for _, nt := range models.NotificationTypes {
+		assert.True(t, prefs.Wants(nt))
+	}
+
+	input := models.UpdateNotificationPreferencesInput{Types: map[models.NotificationType]bool{
+		models.NotificationMention: false,
+	}}
+	assert.NoError(t, input.Validate())
+	prefs = input.Apply(prefs)
+	assert.False(t, prefs.Wants(models.NotificationMention))
+	assert.True(t, prefs.Wants(models.NotificationProjectAdded))
+
+	// Later updates keep earlier choices
+	input = models.UpdateNotificationPreferencesInput{Types: map[models.NotificationType]bool{
+		models.NotificationProjectAdded: false,
+	}}
+	prefs = input.Apply(prefs)
+	assert.False(t, prefs.Wants(models.NotificationMention))
+	assert.False(t, prefs.Wants(models.NotificationProjectAdded))
+
+	resolved := prefs.Resolved()
+	assert.Len(t, resolved.Types, len(models.NotificationTypes))
+	assert.True(t, resolved.Types[models.NotificationDocumentApproved])
+
+	invalid := models.UpdateNotificationPreferencesInput{Types: map[models.NotificationType]bool{"digest": true}}
+	assert.Error(t, invalid.Validate())
+}
+
+func TestNotificationPreferences_Email(t *testing.T) {
+	var prefs models.NotificationPreferences
+	// Only high priority types are emailed by default
+	assert.True(t, prefs.WantsEmail(models.NotificationMention))
+	assert.True(t, prefs.WantsEmail(models.NotificationDocumentApproved))
+	assert.False(t, prefs.WantsEmail(models.NotificationDocumentStatus))
+	assert.Equal(t, models.DigestWeekly, prefs.DigestFrequency())
+
+	// Types turned off in the app are not emailed either
+	off := models.NotificationPreferences{
+		Types: map[models.NotificationType]bool{models.NotificationMention: false},
+		Email: map[models.NotificationType]bool{models.NotificationMention: true},
+	}
+	assert.False(t, off.WantsEmail(models.NotificationMention))
+
+	input := models.UpdateNotificationPreferencesInput{
+		Email:  map[models.NotificationType]bool{models.NotificationDocumentStatus: true, models.NotificationMention: false},
+		Digest: models.DigestDaily,
+	}
+	assert.NoError(t, input.Validate())
+	prefs = input.Apply(prefs)
+	assert.True(t, prefs.WantsEmail(models.NotificationDocumentStatus))
+	assert.False(t, prefs.WantsEmail(models.NotificationMention))
+	assert.Equal(t, models.DigestDaily, prefs.DigestFrequency())
+
+	// Unsubscribing turns off every email but leaves in-app choices alone
+	prefs.Types = map[models.NotificationType]bool{models.NotificationProjectAdded: false}
+	prefs = prefs.WithoutEmail()
+	for _, nt := range models.NotificationTypes {
+		assert.False(t, prefs.WantsEmail(nt))
+	}
+	assert.Equal(t, models.DigestOff, prefs.DigestFrequency())
+	assert.False(t, prefs.Wants(models.NotificationProjectAdded))
+
+	invalid := models.UpdateNotificationPreferencesInput{Digest: "hourly"}
+	assert.Error(t, invalid.Validate())
+}
+
+func TestNotification_Describe(t *testing.T) {
+	n := models.Notification{Type: models.NotificationDocumentStatus, Subject: "LLD", Status: models.DocumentStatusInReview}
+	assert.Equal(t, "Alice changed the status of LLD to "+string(models.DocumentStatusInReview), n.Describe("Alice"))
+
+	n = models.Notification{Type: models.NotificationMention, Subject: "LLD"}
+	assert.Equal(t, "Someone mentioned you in LLD", n.Describe(""))
+}
@@ -0,0 +1,37 @@
+// internal/models/organization_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestOrganization_Members(t *testing.T) {
+	org := &models.Organization{
+		Members: []models.OrgMembership{
+			{UserID: "admin", Role: models.OrgRoleAdmin},
+			{UserID: "member", Role: models.OrgRoleMember},
+			{UserID: "guest", Role: models.OrgRoleGuest},
+		},
+	}
+
+	assert.True(t, org.IsAdmin("admin"))
+	assert.False(t, org.IsAdmin("member"))
+	assert.False(t, org.IsAdmin("stranger"))
+	assert.Nil(t, org.Member("stranger"))
+	assert.Equal(t, models.OrgRoleGuest, org.Member("guest").Role)
+	assert.Equal(t, 1, org.AdminCount())
+}
+
+func TestOrganizationInputs_Validate(t *testing.T) {
+	create := models.CreateOrganizationInput{Name: "  Acme  "}
+	assert.NoError(t, create.Validate())
+	assert.Equal(t, "Acme", create.Name)
+	assert.Error(t, (&models.CreateOrganizationInput{Name: "   "}).Validate())
+
+	assert.NoError(t, (&models.AddOrgMemberInput{UserID: "u1", Role: models.OrgRoleGuest}).Validate())
+	assert.Error(t, (&models.AddOrgMemberInput{UserID: "u1", Role: models.OrgRole("owner")}).Validate())
+	assert.Error(t, (&models.AddOrgMemberInput{Role: models.OrgRoleMember}).Validate())
+	assert.Error(t, (&models.UpdateOrgMemberInput{Role: ""}).Validate())
+}
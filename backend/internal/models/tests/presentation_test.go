@@ -0,0 +1,29 @@
+// internal/models/presentation_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestDocumentPreview(t *testing.T) {
+	content := "# Overview\n\nThe **payment** service\n- handles `refunds`\n> and disputes"
+	assert.Equal(t, "Overview The payment service handles refunds and disputes", models.DocumentPreview(content, 280))
+
+	preview := models.DocumentPreview("alpha beta gamma delta", 13)
+	assert.Equal(t, "alpha beta…", preview)
+
+	// Words longer than the preview are cut where they reach it
+	assert.Equal(t, "abcde…", models.DocumentPreview(strings.Repeat("abcdef", 3), 5))
+
+	assert.Equal(t, "", models.DocumentPreview("", 10))
+}
+
+func TestInitials(t *testing.T) {
+	assert.Equal(t, "AL", models.Initials("ada lovelace", "ada@example.com"))
+	assert.Equal(t, "GH", models.Initials("Grace Brewster Hopper", ""))
+	assert.Equal(t, "Ö", models.Initials("Östen", ""))
+	assert.Equal(t, "A", models.Initials("", "ada@example.com"))
+}
@@ -0,0 +1,86 @@
+// internal/models/progress_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestComputeProgress(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	documents := []*models.Document{
+		{Type: models.DocumentTypeHLD, Status: models.DocumentStatusApproved},
+		{Type: models.DocumentTypeLLD, Status: models.DocumentStatusDraft},
+		{Type: models.DocumentTypeOther, Status: models.DocumentStatusApproved},
+	}
+	mockups := []*models.Mockup{
+		{Status: "Approved"},
+		{Status: "Draft"},
+	}
+
+	t.Run("documents only", func(t *testing.T) {
+		b := models.ComputeProgress(nil, documents, nil, now)
+		// Approved weight 3+1 out of 3+2+1
+		assert.Equal(t, 67, b.Computed)
+		assert.Equal(t, 67, b.Progress)
+		assert.Equal(t, 0.0, b.MockupShare)
+		assert.Len(t, b.Documents.ByType, 3)
+		assert.Equal(t, 2, b.Documents.Approved)
+	})
+
+	t.Run("documents and mockups", func(t *testing.T) {
+		b := models.ComputeProgress(nil, documents, mockups, now)
+		// 0.75 * 66.7 + 0.25 * 50
+		assert.Equal(t, 63, b.Computed)
+		assert.Equal(t, 1, b.Mockups.Approved)
+	})
+
+	t.Run("mockups only", func(t *testing.T) {
+		b := models.ComputeProgress(nil, nil, mockups, now)
+		assert.Equal(t, 50, b.Computed)
+		assert.Equal(t, 1.0, b.MockupShare)
+	})
+
+	t.Run("missing required types", func(t *testing.T) {
+		settings := &models.ProgressSettings{
+			Mode:          models.ProgressModeAuto,
+			RequiredTypes: []models.DocumentType{models.DocumentTypeHLD, models.DocumentTypeSpec},
+		}
+		b := models.ComputeProgress(settings, documents, nil, now)
+		assert.Equal(t, []models.DocumentType{models.DocumentTypeSpec}, b.Documents.MissingTypes)
+		// The missing spec adds weight 2: 4 out of 8
+		assert.Equal(t, 50, b.Computed)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		override := 90
+		settings := &models.ProgressSettings{Mode: models.ProgressModeAuto, Override: &override}
+		b := models.ComputeProgress(settings, documents, mockups, now)
+		assert.Equal(t, 63, b.Computed)
+		assert.Equal(t, 90, b.Progress)
+		assert.True(t, b.Overridden)
+	})
+
+	t.Run("empty project", func(t *testing.T) {
+		b := models.ComputeProgress(nil, nil, nil, now)
+		assert.Equal(t, 0, b.Progress)
+		assert.NotNil(t, b.Documents.MissingTypes)
+	})
+}
+
+func TestUpdateProgressSettingsInput_Validate(t *testing.T) {
+	auto := models.ProgressModeAuto
+	share := 0.5
+	assert.NoError(t, (&models.UpdateProgressSettingsInput{Mode: &auto, MockupShare: &share}).Validate())
+
+	invalid := models.ProgressMode("magic")
+	assert.Error(t, (&models.UpdateProgressSettingsInput{Mode: &invalid}).Validate())
+
+	tooMuch := 1.5
+	assert.Error(t, (&models.UpdateProgressSettingsInput{MockupShare: &tooMuch}).Validate())
+
+	weights := map[models.DocumentType]float64{"Poem": 1}
+	assert.Error(t, (&models.UpdateProgressSettingsInput{TypeWeights: &weights}).Validate())
+}
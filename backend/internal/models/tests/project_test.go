@@ -3,8 +3,10 @@ package tests
 
 import (
 	"github.com/stretchr/testify/assert"
+	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
 	"testing"
+	"time"
 )
 
 func statusPtr(s models.ProjectStatus) *models.ProjectStatus {
@@ -14,6 +16,10 @@ func statusPtr(s models.ProjectStatus) *models.ProjectStatus {
 func intPtr(i int) *int {
 	return &i
 }
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
 func TestProjectStatus_IsValid(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -169,6 +175,17 @@ func TestCreateProjectInput_Validation(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "end before start",
+			input: models.CreateProjectInput{
+				Name:        "Test Project",
+				Description: "Test Description",
+				Status:      models.ProjectStatusPlanning,
+				StartDate:   timePtr(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+				EndDate:     timePtr(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			valid: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -221,6 +238,22 @@ func TestUpdateProjectInput_Validation(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "valid input - clear dates",
+			input: models.UpdateProjectInput{
+				ClearStartDate: true,
+				ClearEndDate:   true,
+			},
+			valid: true,
+		},
+		{
+			name: "date both set and cleared",
+			input: models.UpdateProjectInput{
+				EndDate:      timePtr(time.Now()),
+				ClearEndDate: true,
+			},
+			valid: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -234,3 +267,41 @@ func TestUpdateProjectInput_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestProject_Owners(t *testing.T) {
+	// Projects saved before owners were stored fall back to their creator
+	legacy := &models.Project{CreatedBy: "creator", Team: []string{"creator", "member"}}
+	assert.Equal(t, []string{"creator"}, legacy.OwnerIDs())
+	assert.True(t, legacy.IsOwner("creator"))
+	assert.False(t, legacy.IsOwner("member"))
+	assert.True(t, legacy.HasAccess("member"))
+	assert.False(t, legacy.HasAccess("stranger"))
+
+	legacy.AddOwner("member")
+	assert.Equal(t, []string{"creator", "member"}, legacy.Owners)
+
+	// The creator is no longer special once owners are stored
+	removed, err := legacy.RemoveOwner("creator")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	assert.False(t, legacy.IsOwner("creator"))
+	assert.True(t, legacy.HasAccess("creator"))
+
+	removed, err = legacy.RemoveOwner("stranger")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	_, err = legacy.RemoveOwner("member")
+	assert.ErrorIs(t, err, errs.ErrLastOwner)
+	assert.True(t, legacy.IsOwner("member"))
+}
+
+func TestProject_CheckWritable(t *testing.T) {
+	project := &models.Project{}
+	assert.False(t, project.IsArchived())
+	assert.NoError(t, project.CheckWritable())
+
+	project.ArchivedAt = timePtr(time.Now())
+	assert.True(t, project.IsArchived())
+	assert.ErrorIs(t, project.CheckWritable(), errs.ErrProjectArchived)
+}
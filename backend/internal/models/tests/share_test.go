@@ -0,0 +1,85 @@
+// internal/models/share_test.go
+package tests
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestHashShareToken(t *testing.T) {
+	hash := models.HashShareToken("token")
+	assert.Equal(t, hash, models.HashShareToken("token"))
+	assert.NotEqual(t, hash, models.HashShareToken("other"))
+	assert.Len(t, hash, 64)
+}
+
+func TestShareLink_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, (&models.ShareLink{}).IsActive(now))
+	assert.True(t, (&models.ShareLink{ExpiresAt: &future}).IsActive(now))
+	assert.False(t, (&models.ShareLink{ExpiresAt: &past}).IsActive(now))
+	assert.False(t, (&models.ShareLink{RevokedAt: &past}).IsActive(now))
+}
+
+func TestShareLink_Password(t *testing.T) {
+	link := &models.ShareLink{}
+	assert.True(t, link.CheckPassword(""))
+
+	require.NoError(t, link.SetPassword("secret1"))
+	assert.True(t, link.PasswordProtected)
+	assert.NotEqual(t, "secret1", link.PasswordHash)
+	assert.True(t, link.CheckPassword("secret1"))
+	assert.False(t, link.CheckPassword("secret2"))
+	assert.False(t, link.CheckPassword(""))
+}
+
+func TestCreateShareLinkInput_Validate(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		input   models.CreateShareLinkInput
+		wantErr bool
+	}{
+		{name: "empty", input: models.CreateShareLinkInput{}},
+		{name: "password and expiry", input: models.CreateShareLinkInput{Password: "secret1", ExpiresAt: &future}},
+		{name: "short password", input: models.CreateShareLinkInput{Password: "abc"}, wantErr: true},
+		{name: "past expiry", input: models.CreateShareLinkInput{ExpiresAt: &past}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate(now)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateShareLinkInput_ValidateItems(t *testing.T) {
+	input := models.CreateShareLinkInput{
+		DocumentIDs: []string{"a", "b", "a", ""},
+		MockupIDs:   []string{"m", "m"},
+	}
+	require.NoError(t, input.Validate(time.Now()))
+	assert.Equal(t, []string{"a", "b"}, input.DocumentIDs)
+	assert.Equal(t, []string{"m"}, input.MockupIDs)
+
+	input = models.CreateShareLinkInput{}
+	for i := 0; i <= models.ShareLinkMaxItems; i++ {
+		input.DocumentIDs = append(input.DocumentIDs, fmt.Sprintf("doc-%d", i))
+	}
+	assert.Error(t, input.Validate(time.Now()))
+}
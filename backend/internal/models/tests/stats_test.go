@@ -0,0 +1,60 @@
+// internal/models/stats_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestStatsWindow(t *testing.T) {
+	assert.True(t, models.StatsWindowWeek.IsValid())
+	assert.True(t, models.StatsWindowYear.IsValid())
+	assert.False(t, models.StatsWindow("14d").IsValid())
+
+	assert.Equal(t, 7*24*time.Hour, models.StatsWindowWeek.Duration())
+	assert.Equal(t, models.StatsBucketDay, models.StatsWindowMonth.Bucket())
+	assert.Equal(t, models.StatsBucketWeek, models.StatsWindowQuarter.Bucket())
+}
+
+func TestStatsBucket_Start(t *testing.T) {
+	// A Sunday afternoon
+	at := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), models.StatsBucketDay.Start(at))
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), models.StatsBucketWeek.Start(at))
+	// Mondays start their own week
+	monday := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), models.StatsBucketWeek.Start(monday))
+}
+
+func TestFillTrend(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	points := []models.TrendPoint{
+		{Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Value: 3},
+		{Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Value: 1},
+	}
+
+	filled := models.FillTrend(points, from, to, models.StatsBucketDay)
+
+	values := make([]int64, 0, len(filled))
+	for _, p := range filled {
+		values = append(values, p.Value)
+	}
+	assert.Equal(t, []int64{0, 3, 0, 1}, values)
+	assert.Equal(t, from, filled[0].Date)
+}
+
+func TestFillTrend_Empty(t *testing.T) {
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	filled := models.FillTrend(nil, from, to, models.StatsBucketWeek)
+
+	assert.Len(t, filled, 3)
+	for _, p := range filled {
+		assert.Zero(t, p.Value)
+	}
+}
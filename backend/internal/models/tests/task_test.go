@@ -0,0 +1,51 @@
+// internal/models/task_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestBuildBoardView(t *testing.T) {
+	board := &models.Board{Columns: models.DefaultBoardColumns()}
+	tasks := []*models.Task{
+		{ID: "a", ColumnID: "todo", Rank: "V"},
+		{ID: "b", ColumnID: "done", Rank: "W"},
+		{ID: "c", ColumnID: "todo", Rank: "X"},
+		{ID: "d", ColumnID: "removed", Rank: "Y"},
+	}
+
+	view := models.BuildBoardView(board, tasks)
+
+	assert.Len(t, view.Columns, 4)
+	assert.Equal(t, "todo", view.Columns[0].ID)
+	assert.Len(t, view.Columns[0].Tasks, 2)
+	assert.Equal(t, "c", view.Columns[0].Tasks[1].ID)
+	assert.Empty(t, view.Columns[1].Tasks)
+	assert.NotNil(t, view.Columns[1].Tasks)
+	assert.Len(t, view.Columns[3].Tasks, 1)
+	assert.NotNil(t, board.Column("review"))
+	assert.Nil(t, board.Column("removed"))
+}
+
+func TestTaskInputs_Validate(t *testing.T) {
+	create := models.CreateTaskInput{Title: " Write spec "}
+	assert.NoError(t, create.Validate())
+	assert.Equal(t, "Write spec", create.Title)
+	assert.Equal(t, models.TaskPriorityMedium, create.Priority)
+
+	negative := -1.0
+	assert.Error(t, (&models.CreateTaskInput{Title: "Spec", Estimate: &negative}).Validate())
+	assert.Error(t, (&models.CreateTaskInput{Title: "Spec", Priority: "someday"}).Validate())
+
+	invalid := models.TaskPriority("someday")
+	assert.Error(t, (&models.UpdateTaskInput{Priority: &invalid}).Validate())
+
+	assert.Error(t, (&models.MoveTaskInput{ColumnID: "todo", AfterID: "a", BeforeID: "a", Version: 1}).Validate())
+	assert.Error(t, (&models.MoveTaskInput{ColumnID: "todo", AfterID: "a", BeforeID: "b"}).Validate())
+	assert.NoError(t, (&models.MoveTaskInput{ColumnID: "todo", AfterID: "a", BeforeID: "b", Version: 1}).Validate())
+
+	assert.Error(t, (&models.UpdateBoardColumnsInput{}).Validate())
+	assert.Error(t, (&models.UpdateBoardColumnsInput{Columns: []models.BoardColumnInput{{Name: " "}}}).Validate())
+}
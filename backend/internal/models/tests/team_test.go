@@ -0,0 +1,87 @@
+// internal/models/team_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestTeam_CanManage(t *testing.T) {
+	team := &models.Team{
+		Lead:      "lead",
+		CreatedBy: "creator",
+		Members: []models.TeamMembership{
+			{UserID: "lead", Role: models.TeamRoleOwner},
+			{UserID: "owner", Role: models.TeamRoleOwner},
+			{UserID: "member", Role: models.TeamRoleMember},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		want   bool
+	}{
+		{name: "lead", userID: "lead", want: true},
+		{name: "creator no longer a member", userID: "creator", want: true},
+		{name: "owner", userID: "owner", want: true},
+		{name: "plain member", userID: "member", want: false},
+		{name: "outsider", userID: "someone", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, team.CanManage(tt.userID))
+		})
+	}
+}
+
+func TestTeam_Member(t *testing.T) {
+	team := &models.Team{Members: []models.TeamMembership{{UserID: "a", Role: models.TeamRoleMember}}}
+
+	member := team.Member("a")
+	if assert.NotNil(t, member) {
+		member.Role = models.TeamRoleViewer
+		assert.Equal(t, models.TeamRoleViewer, team.Members[0].Role)
+	}
+	assert.Nil(t, team.Member("b"))
+}
+
+func TestUpdateTeamMembershipInput_Validate(t *testing.T) {
+	assert.NoError(t, (&models.UpdateTeamMembershipInput{Role: models.TeamRoleViewer}).Validate())
+	assert.Error(t, (&models.UpdateTeamMembershipInput{Role: "admin"}).Validate())
+}
+
+func TestDeriveProjectMembers(t *testing.T) {
+	teams := map[string]*models.Team{
+		"design": {ID: "design", Members: []models.TeamMembership{{UserID: "ann"}, {UserID: "bob"}}},
+		"eng":    {ID: "eng", Members: []models.TeamMembership{{UserID: "bob"}, {UserID: "cat"}}},
+	}
+	assignments := []models.ProjectTeamAssignment{
+		{TeamID: "design", Role: models.TeamRoleViewer},
+		{TeamID: "eng", Role: models.TeamRoleMember},
+		{TeamID: "deleted", Role: models.TeamRoleOwner},
+	}
+
+	derived := models.DeriveProjectMembers(assignments, teams)
+
+	assert.Len(t, derived, 3)
+	assert.Equal(t, models.TeamRoleViewer, derived["ann"].Role)
+	assert.Equal(t, models.TeamRoleMember, derived["bob"].Role, "highest role among the user's teams wins")
+	assert.Equal(t, []string{"design", "eng"}, derived["bob"].TeamIDs)
+	assert.Equal(t, models.TeamMemberSourceTeam, derived["cat"].Source)
+	assert.False(t, derived["cat"].IsDirect())
+}
+
+func TestTeamMember_IsDirect(t *testing.T) {
+	assert.True(t, (&models.TeamMember{}).IsDirect(), "members from before team assignments are direct")
+	assert.True(t, (&models.TeamMember{Source: models.TeamMemberSourceDirect}).IsDirect())
+	assert.False(t, (&models.TeamMember{Source: models.TeamMemberSourceTeam}).IsDirect())
+}
+
+func TestAssignTeamInput_Validate(t *testing.T) {
+	assert.NoError(t, (&models.AssignTeamInput{TeamID: "t1", Role: models.TeamRoleMember}).Validate())
+	assert.Error(t, (&models.AssignTeamInput{TeamID: "t1", Role: models.TeamRoleOwner}).Validate())
+	assert.Error(t, (&models.UpdateTeamAssignmentInput{Role: models.TeamRoleOwner}).Validate())
+}
@@ -0,0 +1,151 @@
+// internal/models/template_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"testing"
+)
+
+func TestTemplateInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   models.TemplateInput
+		wantErr bool
+	}{
+		{
+			name: "valid template",
+			input: models.TemplateInput{
+				Name: "Service",
+				Documents: []models.TemplateDocument{
+					{Title: "HLD", Type: models.DocumentTypeHLD},
+					{Key: "65A1B2C3D4E5F6A7B8C9D0E1", Title: "Spec", Type: models.DocumentTypeSpec, Status: models.DocumentStatusApproved},
+				},
+				Members: []models.TemplateMember{{UserID: "user1", Role: models.TeamRoleOwner}},
+				Teams:   []models.TemplateTeam{{TeamID: "team1", Role: models.TeamRoleMember}},
+			},
+		},
+		{
+			name:    "missing name",
+			input:   models.TemplateInput{Name: "  "},
+			wantErr: true,
+		},
+		{
+			name:    "invalid project status",
+			input:   models.TemplateInput{Name: "Service", Status: "Unknown"},
+			wantErr: true,
+		},
+		{
+			name: "invalid document type",
+			input: models.TemplateInput{Name: "Service", Documents: []models.TemplateDocument{
+				{Title: "HLD", Type: "Memo"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid document status",
+			input: models.TemplateInput{Name: "Service", Documents: []models.TemplateDocument{
+				{Title: "HLD", Type: models.DocumentTypeHLD, Status: "Done"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "malformed key",
+			input: models.TemplateInput{Name: "Service", Documents: []models.TemplateDocument{
+				{Key: "hld", Title: "HLD", Type: models.DocumentTypeHLD},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate key",
+			input: models.TemplateInput{Name: "Service", Documents: []models.TemplateDocument{
+				{Key: "65a1b2c3d4e5f6a7b8c9d0e1", Title: "HLD", Type: models.DocumentTypeHLD},
+				{Key: "65a1b2c3d4e5f6a7b8c9d0e1", Title: "LLD", Type: models.DocumentTypeLLD},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate member",
+			input: models.TemplateInput{Name: "Service", Members: []models.TemplateMember{
+				{UserID: "user1", Role: models.TeamRoleMember},
+				{UserID: "user1", Role: models.TeamRoleViewer},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "team as owner",
+			input: models.TemplateInput{Name: "Service", Teams: []models.TemplateTeam{
+				{TeamID: "team1", Role: models.TeamRoleOwner},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTemplateInput_ValidateDefaults(t *testing.T) {
+	input := models.TemplateInput{
+		Name: " Service ",
+		Documents: []models.TemplateDocument{
+			{Key: "65A1B2C3D4E5F6A7B8C9D0E1", Title: "HLD", Type: models.DocumentTypeHLD},
+		},
+	}
+
+	assert.NoError(t, input.Validate())
+	assert.Equal(t, "Service", input.Name)
+	assert.Equal(t, models.ProjectStatusPlanning, input.Status)
+	assert.Equal(t, models.DocumentStatusDraft, input.Documents[0].Status)
+	assert.Equal(t, "65a1b2c3d4e5f6a7b8c9d0e1", input.Documents[0].Key)
+}
+
+func TestUseTemplateInput_ProjectInput(t *testing.T) {
+	template := &models.ProjectTemplate{Name: "Service", Description: "A new service", Status: models.ProjectStatusInProgress}
+
+	input := models.UseTemplateInput{Name: "Billing"}
+	project := input.ProjectInput(template)
+	assert.Equal(t, "Billing", project.Name)
+	assert.Equal(t, "A new service", project.Description)
+	assert.Equal(t, models.ProjectStatusInProgress, project.Status)
+
+	input = models.UseTemplateInput{Name: "Billing", Description: "Invoices", Status: models.ProjectStatusReview}
+	project = input.ProjectInput(template)
+	assert.Equal(t, "Invoices", project.Description)
+	assert.Equal(t, models.ProjectStatusReview, project.Status)
+}
+
+func TestCloneProjectInput_Defaults(t *testing.T) {
+	input := models.CloneProjectInput{}
+	assert.NoError(t, input.Validate())
+	assert.Equal(t, models.DocumentSelectionLatest, input.Documents)
+	assert.True(t, input.Mockups())
+	assert.True(t, input.Members())
+
+	no := false
+	input = models.CloneProjectInput{Documents: models.DocumentSelectionApproved, IncludeMockups: &no, IncludeMembers: &no}
+	assert.NoError(t, input.Validate())
+	assert.False(t, input.Mockups())
+	assert.False(t, input.Members())
+
+	input = models.CloneProjectInput{Documents: "oldest"}
+	assert.Error(t, input.Validate())
+}
+
+func TestDocumentSelection_Includes(t *testing.T) {
+	draft := &models.Document{Status: models.DocumentStatusDraft}
+	approved := &models.Document{Status: models.DocumentStatusApproved}
+
+	assert.True(t, models.DocumentSelectionLatest.Includes(draft))
+	assert.False(t, models.DocumentSelectionApproved.Includes(draft))
+	assert.True(t, models.DocumentSelectionApproved.Includes(approved))
+	assert.False(t, models.DocumentSelectionNone.Includes(approved))
+}
@@ -0,0 +1,82 @@
+// internal/models/webhook_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	"testing"
+	"time"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, models.WebhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, models.WebhookRetryDelay(2))
+	assert.Equal(t, 8*time.Minute, models.WebhookRetryDelay(4))
+	assert.Equal(t, 6*time.Hour, models.WebhookRetryDelay(20))
+}
+
+func TestWebhookDelivery_Record(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	delivery := &models.WebhookDelivery{Status: models.WebhookDeliveryPending, NextAttemptAt: &start}
+
+	delivery.Record(models.WebhookAttempt{At: start, ResponseCode: 500})
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	require.NotNil(t, delivery.NextAttemptAt)
+	assert.Equal(t, start.Add(time.Minute), *delivery.NextAttemptAt)
+
+	// Network errors are retried like error responses
+	delivery.Record(models.WebhookAttempt{At: start.Add(time.Minute), Error: "connection refused"})
+	assert.Equal(t, start.Add(3*time.Minute), *delivery.NextAttemptAt)
+
+	delivery.Record(models.WebhookAttempt{At: start.Add(3 * time.Minute), ResponseCode: 204})
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Len(t, delivery.Attempts, 3)
+
+	failing := &models.WebhookDelivery{Status: models.WebhookDeliveryPending}
+	for i := 0; i < models.WebhookMaxAttempts; i++ {
+		failing.Record(models.WebhookAttempt{At: start, ResponseCode: 410})
+	}
+	assert.Equal(t, models.WebhookDeliveryFailed, failing.Status)
+	assert.Nil(t, failing.NextAttemptAt)
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	webhook := &models.Webhook{Active: true, Events: []models.WebhookEvent{models.WebhookDocumentApproved}}
+	assert.True(t, webhook.Subscribes(models.WebhookDocumentApproved))
+	assert.False(t, webhook.Subscribes(models.WebhookProjectStatusChanged))
+
+	webhook.Active = false
+	assert.False(t, webhook.Subscribes(models.WebhookDocumentApproved))
+}
+
+func TestCreateWebhookInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   models.CreateWebhookInput
+		wantErr bool
+	}{
+		{name: "valid", input: models.CreateWebhookInput{URL: " https://ci.example.com/hooks ", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}},
+		{name: "relative url", input: models.CreateWebhookInput{URL: "/hooks", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "other scheme", input: models.CreateWebhookInput{URL: "ftp://example.com", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "loopback", input: models.CreateWebhookInput{URL: "http://127.0.0.1:6379", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "localhost", input: models.CreateWebhookInput{URL: "http://localhost/hooks", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "metadata service", input: models.CreateWebhookInput{URL: "http://169.254.169.254/latest", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "private network", input: models.CreateWebhookInput{URL: "http://[fd00::1]/hooks", Events: []models.WebhookEvent{models.WebhookDocumentApproved}}, wantErr: true},
+		{name: "no events", input: models.CreateWebhookInput{URL: "https://example.com"}, wantErr: true},
+		{name: "unknown event", input: models.CreateWebhookInput{URL: "https://example.com", Events: []models.WebhookEvent{"document.deleted"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "https://ci.example.com/hooks", tt.input.URL)
+		})
+	}
+}
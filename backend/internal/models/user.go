@@ -12,6 +12,12 @@ type User struct {
 	Name         string    `bson:"name" json:"name"`
 	CreatedAt    time.Time `bson:"created_at" json:"createdAt"`
 	UpdatedAt    time.Time `bson:"updated_at" json:"updatedAt"`
+	// CurrentOrgID is the organization the user last switched to
+	CurrentOrgID string `bson:"current_org_id,omitempty" json:"currentOrgId,omitempty"`
+	// NotificationPreferences are read through the notifications API
+	NotificationPreferences NotificationPreferences `bson:"notification_preferences" json:"-"`
+	// DigestSentAt is when the user was last sent a digest email
+	DigestSentAt *time.Time `bson:"digest_sent_at,omitempty" json:"-"`
 }
 
 func (u *User) SetPassword(password string) error {
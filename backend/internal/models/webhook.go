@@ -0,0 +1,288 @@
+// Package models internal/models/webhook.go
+package models
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/safehttp"
+	"strings"
+	"time"
+)
+
+// WebhookEvent is a kind of change a webhook can subscribe to
+type WebhookEvent string
+
+const (
+	// WebhookDocumentStatusChanged is sent whenever a document's status changes
+	WebhookDocumentStatusChanged WebhookEvent = "document.status_changed"
+	// WebhookDocumentApproved is sent when a document is approved, along with
+	// document.status_changed
+	WebhookDocumentApproved WebhookEvent = "document.approved"
+	// WebhookProjectStatusChanged is sent whenever a project's status changes
+	WebhookProjectStatusChanged WebhookEvent = "project.status_changed"
+)
+
+// WebhookEvents lists every webhook event
+var WebhookEvents = []WebhookEvent{
+	WebhookDocumentStatusChanged,
+	WebhookDocumentApproved,
+	WebhookProjectStatusChanged,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	for _, known := range WebhookEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook sends the events of a project it subscribes to to a URL
+type Webhook struct {
+	ID        string         `bson:"_id,omitempty" json:"id"`
+	OrgID     string         `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	ProjectID string         `bson:"project_id" json:"projectId"`
+	URL       string         `bson:"url" json:"url"`
+	Events    []WebhookEvent `bson:"events" json:"events"`
+	// Secret signs payloads; it is shown once, when the webhook is created
+	Secret    string    `bson:"secret" json:"-"`
+	Active    bool      `bson:"active" json:"active"`
+	CreatedBy string    `bson:"created_by" json:"createdBy"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// Subscribes reports whether the webhook wants the event
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	if !w.Active {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedWebhook is a new webhook along with its signing secret
+type CreatedWebhook struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+type CreateWebhookInput struct {
+	URL    string         `json:"url" binding:"required"`
+	Events []WebhookEvent `json:"events" binding:"required"`
+	// Active defaults to true
+	Active *bool `json:"active,omitempty"`
+}
+
+func (i *CreateWebhookInput) Validate() error {
+	i.URL = strings.TrimSpace(i.URL)
+	if err := validateWebhookURL(i.URL); err != nil {
+		return err
+	}
+	return validateWebhookEvents(i.Events)
+}
+
+// UpdateWebhookInput changes the fields it sets; events replace the current
+// ones
+type UpdateWebhookInput struct {
+	URL    *string         `json:"url,omitempty"`
+	Events *[]WebhookEvent `json:"events,omitempty"`
+	Active *bool           `json:"active,omitempty"`
+}
+
+func (i *UpdateWebhookInput) Validate() error {
+	if i.URL != nil {
+		*i.URL = strings.TrimSpace(*i.URL)
+		if err := validateWebhookURL(*i.URL); err != nil {
+			return err
+		}
+	}
+	if i.Events != nil {
+		return validateWebhookEvents(*i.Events)
+	}
+	return nil
+}
+
+// validateWebhookURL refuses URLs that name an internal host outright.
+// Names resolving to internal addresses are refused when connecting.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url must point to a public host")
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !safehttp.IsPublic(ip) {
+		return fmt.Errorf("url must point to a public host")
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []WebhookEvent) error {
+	if len(events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, e := range events {
+		if !e.IsValid() {
+			return fmt.Errorf("invalid webhook event: %s", e)
+		}
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body of every webhook request
+type WebhookPayload struct {
+	// ID identifies the event; redeliveries keep it so receivers can tell
+	// them apart from new events
+	ID         string       `json:"id"`
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	OrgID      string       `json:"orgId,omitempty"`
+	ProjectID  string       `json:"projectId"`
+	// ActorID is the user whose change caused the event
+	ActorID string      `json:"actorId,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// DocumentStatusChange is the data of document events
+type DocumentStatusChange struct {
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Status         DocumentStatus `json:"status"`
+	PreviousStatus DocumentStatus `json:"previousStatus"`
+}
+
+// ProjectStatusChange is the data of project events
+type ProjectStatusChange struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Status         ProjectStatus `json:"status"`
+	PreviousStatus ProjectStatus `json:"previousStatus"`
+}
+
+// WebhookDeliveryStatus is where a delivery stands
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its first attempt or a retry
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed gave up after the last retry
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// WebhookMaxAttempts is how often a delivery is tried before giving up
+	WebhookMaxAttempts = 10
+	// webhookFirstRetry is the wait after the first failure; it doubles after
+	// each further one
+	webhookFirstRetry = time.Minute
+	webhookMaxRetry   = 6 * time.Hour
+)
+
+// WebhookRetryDelay is the wait before retrying after the given number of
+// failed attempts
+func WebhookRetryDelay(failures int) time.Duration {
+	delay := webhookFirstRetry
+	for i := 1; i < failures && delay < webhookMaxRetry; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetry {
+		delay = webhookMaxRetry
+	}
+	return delay
+}
+
+// WebhookDelivery is one event queued for one webhook, with the log of its
+// attempts
+type WebhookDelivery struct {
+	ID        string       `bson:"_id,omitempty" json:"id"`
+	OrgID     string       `bson:"org_id,omitempty" json:"orgId,omitempty"`
+	WebhookID string       `bson:"webhook_id" json:"webhookId"`
+	ProjectID string       `bson:"project_id" json:"projectId"`
+	EventID   string       `bson:"event_id" json:"eventId"`
+	Event     WebhookEvent `bson:"event" json:"event"`
+	// Payload is the exact body sent, so redeliveries send the same bytes
+	Payload  string                `bson:"payload" json:"payload"`
+	Status   WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts []WebhookAttempt      `bson:"attempts" json:"attempts"`
+	// NextAttemptAt is set while the delivery is pending
+	NextAttemptAt *time.Time `bson:"next_attempt_at" json:"nextAttemptAt,omitempty"`
+	// LockedUntil keeps other workers off a delivery being sent
+	LockedUntil *time.Time `bson:"locked_until,omitempty" json:"-"`
+	// RedeliveryOf is the delivery this one repeats. It is stored empty on
+	// first deliveries, which are unique per webhook and event ID.
+	RedeliveryOf string    `bson:"redelivery_of" json:"redeliveryOf,omitempty"`
+	CreatedAt    time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// WebhookAttempt records one request of a delivery
+type WebhookAttempt struct {
+	At time.Time `bson:"at" json:"at"`
+	// ResponseCode is 0 when no response arrived. The response body is never
+	// kept, so webhooks cannot be used to read from other servers.
+	ResponseCode int    `bson:"response_code,omitempty" json:"responseCode,omitempty"`
+	Error        string `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs   int64  `bson:"duration_ms" json:"durationMs"`
+}
+
+// Succeeded reports whether the receiver accepted the request
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.ResponseCode >= 200 && a.ResponseCode < 300
+}
+
+// Record logs an attempt and schedules the retry it calls for, if any
+func (d *WebhookDelivery) Record(attempt WebhookAttempt) {
+	d.Attempts = append(d.Attempts, attempt)
+	d.LockedUntil = nil
+	switch {
+	case attempt.Succeeded():
+		d.Status = WebhookDeliverySucceeded
+		d.NextAttemptAt = nil
+	case len(d.Attempts) >= WebhookMaxAttempts:
+		d.Status = WebhookDeliveryFailed
+		d.NextAttemptAt = nil
+	default:
+		next := attempt.At.Add(WebhookRetryDelay(len(d.Attempts)))
+		d.Status = WebhookDeliveryPending
+		d.NextAttemptAt = &next
+	}
+}
+
+// Abandon logs an attempt that could not be made, such as one for an
+// inactive webhook, and gives up on the delivery without retrying
+func (d *WebhookDelivery) Abandon(attempt WebhookAttempt) {
+	d.Attempts = append(d.Attempts, attempt)
+	d.LockedUntil = nil
+	d.Status = WebhookDeliveryFailed
+	d.NextAttemptAt = nil
+}
+
+// WebhookDeliveryListSpec describes the sorting and filtering of a webhook's
+// deliveries; the newest come first
+var WebhookDeliveryListSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "createdAt", Field: "created_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-createdAt",
+	Filters:     []string{pagination.FilterStatus},
+}
@@ -0,0 +1,309 @@
+// Package pagination internal/pagination/pagination.go
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	errs "projectnexus/internal/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Filter parameter names accepted on list endpoints
+const (
+	FilterStatus       = "status"
+	FilterType         = "type"
+	FilterCreatedBy    = "createdBy"
+	FilterUpdatedSince = "updatedSince"
+	FilterArchived     = "archived"
+	FilterSearch       = "q"
+	FilterUnread       = "unread"
+	FilterActor        = "actor"
+	FilterAction       = "action"
+	FilterResourceType = "resourceType"
+	FilterResourceID   = "resourceId"
+	FilterProject      = "projectId"
+	FilterFrom         = "from"
+	FilterTo           = "to"
+)
+
+// Values of the archived filter
+const (
+	ArchivedExclude = "false"
+	ArchivedOnly    = "true"
+	ArchivedInclude = "all"
+)
+
+type SortKind int
+
+const (
+	SortString SortKind = iota
+	SortTime
+	SortNumber
+)
+
+// SortField maps a sort key accepted by the API to the stored field
+type SortField struct {
+	Key   string
+	Field string
+	Kind  SortKind
+}
+
+// Spec describes what a list endpoint supports
+type Spec struct {
+	Sorts []SortField
+	// DefaultSort is a sort key, prefixed with "-" for descending order
+	DefaultSort string
+	Filters     []string
+}
+
+type Filter struct {
+	Status       string
+	Type         string
+	CreatedBy    string
+	UpdatedSince *time.Time
+	// Archived is one of the Archived values; left empty, archived items are
+	// shown only to searches
+	Archived string
+	// Search matches items by name or description
+	Search string
+	// Unread keeps only items the user has not read yet
+	Unread bool
+
+	// Actor, Action, ResourceType, ResourceID and ProjectID narrow down
+	// audit log entries
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	ProjectID    string
+	// From and To bound when an item happened; From is inclusive and To is
+	// exclusive
+	From *time.Time
+	To   *time.Time
+}
+
+// Query is a parsed page request
+type Query struct {
+	Limit  int
+	Sort   SortField
+	Desc   bool
+	Filter Filter
+
+	// AfterValue and AfterID position the page after the last item of the
+	// previous one; AfterValue has the Go type matching Sort.Kind, or is nil
+	// when that item has no value for the sort field
+	AfterValue interface{}
+	AfterID    string
+}
+
+// Page is the envelope returned by every list endpoint
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// cursor is the decoded form of the opaque cursor handed to clients. It
+// records the sort it was issued for so it cannot be replayed under another.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Null  bool   `json:"n,omitempty"`
+	ID    string `json:"id"`
+}
+
+// value returns the sort value a cursor resumes after
+func (c cursor) value(field SortField) (interface{}, error) {
+	if c.Null {
+		return nil, nil
+	}
+	return field.parseValue(c.Value)
+}
+
+// Parse reads limit, sort, cursor and the filters allowed by spec from the
+// query string
+func Parse(values url.Values, spec Spec) (Query, error) {
+	q := Query{Limit: DefaultLimit}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return q, fmt.Errorf("%w: limit must be a positive number", errs.ErrInvalidInput)
+		}
+		q.Limit = min(limit, MaxLimit)
+	}
+
+	sortKey := values.Get("sort")
+	if sortKey == "" {
+		sortKey = spec.DefaultSort
+	}
+	q.Desc = strings.HasPrefix(sortKey, "-")
+	field, ok := spec.sortField(strings.TrimPrefix(sortKey, "-"))
+	if !ok {
+		return q, fmt.Errorf("%w: unsupported sort %q", errs.ErrInvalidInput, sortKey)
+	}
+	q.Sort = field
+
+	if raw := values.Get("cursor"); raw != "" {
+		c, err := decodeCursor(raw)
+		if err != nil || c.Sort != sortKey || c.ID == "" {
+			return q, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+		}
+		value, err := c.value(field)
+		if err != nil {
+			return q, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+		}
+		q.AfterValue = value
+		q.AfterID = c.ID
+	}
+
+	for _, name := range spec.Filters {
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		switch name {
+		case FilterStatus:
+			q.Filter.Status = raw
+		case FilterType:
+			q.Filter.Type = raw
+		case FilterCreatedBy:
+			q.Filter.CreatedBy = raw
+		case FilterUpdatedSince:
+			since, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return q, fmt.Errorf("%w: updatedSince must be an RFC 3339 timestamp", errs.ErrInvalidInput)
+			}
+			q.Filter.UpdatedSince = &since
+		case FilterArchived:
+			switch raw {
+			case ArchivedExclude, ArchivedOnly, ArchivedInclude:
+				q.Filter.Archived = raw
+			default:
+				return q, fmt.Errorf("%w: archived must be true, false or all", errs.ErrInvalidInput)
+			}
+		case FilterSearch:
+			q.Filter.Search = strings.TrimSpace(raw)
+		case FilterUnread:
+			unread, err := strconv.ParseBool(raw)
+			if err != nil {
+				return q, fmt.Errorf("%w: unread must be true or false", errs.ErrInvalidInput)
+			}
+			q.Filter.Unread = unread
+		case FilterActor:
+			q.Filter.Actor = raw
+		case FilterAction:
+			q.Filter.Action = raw
+		case FilterResourceType:
+			q.Filter.ResourceType = raw
+		case FilterResourceID:
+			q.Filter.ResourceID = raw
+		case FilterProject:
+			q.Filter.ProjectID = raw
+		case FilterFrom, FilterTo:
+			at, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return q, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", errs.ErrInvalidInput, name)
+			}
+			if name == FilterFrom {
+				q.Filter.From = &at
+			} else {
+				q.Filter.To = &at
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// NextCursor builds the cursor pointing after an item with the given sort
+// value and ID
+func (q Query) NextCursor(value interface{}, id string) string {
+	sortKey := q.Sort.Key
+	if q.Desc {
+		sortKey = "-" + sortKey
+	}
+	if value == nil {
+		return encodeCursor(cursor{Sort: sortKey, Null: true, ID: id})
+	}
+	return encodeCursor(cursor{
+		Sort:  sortKey,
+		Value: formatValue(value),
+		ID:    id,
+	})
+}
+
+// Resume returns the query for the page following the one that ended with
+// the cursor, for callers that walk through every page
+func (q Query) Resume(raw string) (Query, error) {
+	c, err := decodeCursor(raw)
+	if err != nil {
+		return q, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+	}
+	value, err := c.value(q.Sort)
+	if err != nil {
+		return q, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+	}
+	q.AfterValue = value
+	q.AfterID = c.ID
+	return q, nil
+}
+
+func (s Spec) sortField(key string) (SortField, bool) {
+	for _, field := range s.Sorts {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return SortField{}, false
+}
+
+func (f SortField) parseValue(raw string) (interface{}, error) {
+	switch f.Kind {
+	case SortTime:
+		return time.Parse(time.RFC3339Nano, raw)
+	case SortNumber:
+		return strconv.ParseFloat(raw, 64)
+	default:
+		return raw, nil
+	}
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
@@ -0,0 +1,195 @@
+// internal/pagination/pagination_test.go
+package tests
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/pagination"
+	"testing"
+	"time"
+)
+
+var testSpec = pagination.Spec{
+	Sorts: []pagination.SortField{
+		{Key: "name", Field: "name", Kind: pagination.SortString},
+		{Key: "progress", Field: "progress", Kind: pagination.SortNumber},
+		{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+	},
+	DefaultSort: "-updatedAt",
+	Filters:     []string{pagination.FilterStatus, pagination.FilterUpdatedSince, pagination.FilterArchived, pagination.FilterSearch, pagination.FilterUnread, pagination.FilterActor, pagination.FilterFrom, pagination.FilterTo},
+}
+
+func TestParse_Defaults(t *testing.T) {
+	q, err := pagination.Parse(url.Values{}, testSpec)
+	require.NoError(t, err)
+
+	assert.Equal(t, pagination.DefaultLimit, q.Limit)
+	assert.Equal(t, "updated_at", q.Sort.Field)
+	assert.True(t, q.Desc)
+	assert.Empty(t, q.AfterID)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  url.Values
+		wantErr bool
+		check   func(t *testing.T, q pagination.Query)
+	}{
+		{
+			name:   "limit is capped",
+			values: url.Values{"limit": {"500"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Equal(t, pagination.MaxLimit, q.Limit)
+			},
+		},
+		{
+			name:    "invalid limit",
+			values:  url.Values{"limit": {"0"}},
+			wantErr: true,
+		},
+		{
+			name:   "ascending sort",
+			values: url.Values{"sort": {"name"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Equal(t, "name", q.Sort.Field)
+				assert.False(t, q.Desc)
+			},
+		},
+		{
+			name:    "unknown sort",
+			values:  url.Values{"sort": {"secret"}},
+			wantErr: true,
+		},
+		{
+			name:   "allowed filters",
+			values: url.Values{"status": {"Active"}, "updatedSince": {"2024-01-02T03:04:05Z"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Equal(t, "Active", q.Filter.Status)
+				require.NotNil(t, q.Filter.UpdatedSince)
+				assert.True(t, q.Filter.UpdatedSince.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+			},
+		},
+		{
+			name:   "filters outside the spec are ignored",
+			values: url.Values{"type": {"prd"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Empty(t, q.Filter.Type)
+			},
+		},
+		{
+			name:    "invalid updatedSince",
+			values:  url.Values{"updatedSince": {"yesterday"}},
+			wantErr: true,
+		},
+		{
+			name:   "archived and search filters",
+			values: url.Values{"archived": {"all"}, "q": {"  roadmap "}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Equal(t, pagination.ArchivedInclude, q.Filter.Archived)
+				assert.Equal(t, "roadmap", q.Filter.Search)
+			},
+		},
+		{
+			name:   "unread filter",
+			values: url.Values{"unread": {"true"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.True(t, q.Filter.Unread)
+			},
+		},
+		{
+			name:    "invalid unread",
+			values:  url.Values{"unread": {"soon"}},
+			wantErr: true,
+		},
+		{
+			name:   "audit filters",
+			values: url.Values{"actor": {"user-1"}, "from": {"2024-01-01T00:00:00Z"}, "to": {"2024-02-01T00:00:00Z"}},
+			check: func(t *testing.T, q pagination.Query) {
+				assert.Equal(t, "user-1", q.Filter.Actor)
+				require.NotNil(t, q.Filter.From)
+				require.NotNil(t, q.Filter.To)
+				assert.True(t, q.Filter.To.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)))
+			},
+		},
+		{
+			name:    "invalid from",
+			values:  url.Values{"from": {"last week"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid archived",
+			values:  url.Values{"archived": {"maybe"}},
+			wantErr: true,
+		},
+		{
+			name:    "garbage cursor",
+			values:  url.Values{"cursor": {"not-a-cursor"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := pagination.Parse(tt.values, testSpec)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, errs.ErrInvalidInput))
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, q)
+		})
+	}
+}
+
+func TestNextCursor_RoundTrip(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+
+	first, err := pagination.Parse(url.Values{}, testSpec)
+	require.NoError(t, err)
+	next := first.NextCursor(updatedAt, "65f1c0ffee0000000000beef")
+
+	q, err := pagination.Parse(url.Values{"cursor": {next}}, testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, "65f1c0ffee0000000000beef", q.AfterID)
+	assert.Equal(t, updatedAt, q.AfterValue)
+
+	byProgress, err := pagination.Parse(url.Values{"sort": {"progress"}}, testSpec)
+	require.NoError(t, err)
+	next = byProgress.NextCursor(float64(40), "65f1c0ffee0000000000beef")
+
+	q, err = pagination.Parse(url.Values{"sort": {"progress"}, "cursor": {next}}, testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, float64(40), q.AfterValue)
+}
+
+func TestNextCursor_NullValue(t *testing.T) {
+	first, err := pagination.Parse(url.Values{"sort": {"name"}}, testSpec)
+	require.NoError(t, err)
+
+	// An item without a value is told apart from one with an empty value
+	q, err := pagination.Parse(url.Values{"sort": {"name"}, "cursor": {first.NextCursor(nil, "65f1c0ffee0000000000beef")}}, testSpec)
+	require.NoError(t, err)
+	assert.Nil(t, q.AfterValue)
+	assert.Equal(t, "65f1c0ffee0000000000beef", q.AfterID)
+
+	q, err = first.Resume(first.NextCursor("", "65f1c0ffee0000000000beef"))
+	require.NoError(t, err)
+	assert.Equal(t, "", q.AfterValue)
+
+	q, err = first.Resume(first.NextCursor(nil, "65f1c0ffee0000000000beef"))
+	require.NoError(t, err)
+	assert.Nil(t, q.AfterValue)
+}
+
+func TestParse_CursorFromOtherSort(t *testing.T) {
+	first, err := pagination.Parse(url.Values{"sort": {"name"}}, testSpec)
+	require.NoError(t, err)
+	next := first.NextCursor("Alpha", "65f1c0ffee0000000000beef")
+
+	_, err = pagination.Parse(url.Values{"sort": {"-name"}, "cursor": {next}}, testSpec)
+	assert.True(t, errors.Is(err, errs.ErrInvalidInput))
+}
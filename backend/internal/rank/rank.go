@@ -0,0 +1,117 @@
+// Package rank internal/rank/rank.go
+//
+// Ranks are strings that order items by plain string comparison. A new rank
+// can always be made between two others, so moving an item only rewrites
+// that item; its neighbours keep their ranks.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	// ErrInvalid is returned for strings that are not ranks
+	ErrInvalid = errors.New("invalid rank")
+	// ErrOrder is returned when the lower bound is not below the upper one
+	ErrOrder = errors.New("ranks are out of order")
+)
+
+// Between returns a rank that sorts after prev and before next. An empty prev
+// means the start of the list and an empty next the end.
+func Between(prev, next string) (string, error) {
+	if !valid(prev) || !valid(next) {
+		return "", ErrInvalid
+	}
+	if next != "" && prev >= next {
+		return "", ErrOrder
+	}
+	return midpoint(prev, next), nil
+}
+
+// Spread returns n ranks in ascending order, evenly spaced so that later
+// inserts stay short
+func Spread(n int) []string {
+	width, space := 1, len(digits)
+	for space <= n {
+		width++
+		space *= len(digits)
+	}
+	step := space / (n + 1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * step
+		key := make([]byte, width)
+		for pos := width - 1; pos >= 0; pos-- {
+			key[pos] = digits[value%len(digits)]
+			value /= len(digits)
+		}
+		// Trailing zeros add nothing to the order and would leave no room
+		// below the rank
+		ranks[i] = strings.TrimRight(string(key), "0")
+	}
+	return ranks
+}
+
+// valid reports whether s is empty or a rank. Ranks never end in the lowest
+// digit, which keeps room below every one of them.
+func valid(s string) bool {
+	if strings.HasSuffix(s, "0") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(digits, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// midpoint returns a string between a and b, which must satisfy a < b; an
+// empty b stands for the end of the list
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, reading a as padded with zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	low := strings.IndexByte(digits, digitAt(a, 0))
+	high := len(digits)
+	if b != "" {
+		high = strings.IndexByte(digits, b[0])
+	}
+	if high-low > 1 {
+		return string(digits[(low+high)/2])
+	}
+
+	// The first digits are adjacent; a longer b has room below it at its
+	// first digit, otherwise continue after a's first digit
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[low]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
@@ -0,0 +1,81 @@
+// internal/rank/rank_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/rank"
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+	}{
+		{name: "empty list", prev: "", next: ""},
+		{name: "start of list", prev: "", next: "V"},
+		{name: "end of list", prev: "V", next: ""},
+		{name: "wide gap", prev: "A", next: "Z"},
+		{name: "adjacent digits", prev: "A", next: "B"},
+		{name: "longer next", prev: "A", next: "B5"},
+		{name: "shared prefix", prev: "AV", next: "AW"},
+		{name: "prefix of next", prev: "A", next: "A1"},
+		{name: "lowest rank", prev: "", next: "1"},
+		{name: "highest digit", prev: "z", next: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rank.Between(tt.prev, tt.next)
+			require.NoError(t, err)
+			assert.Greater(t, got, tt.prev)
+			if tt.next != "" {
+				assert.Less(t, got, tt.next)
+			}
+			assert.NotEqual(t, byte('0'), got[len(got)-1])
+		})
+	}
+}
+
+func TestBetween_Errors(t *testing.T) {
+	_, err := rank.Between("B", "A")
+	assert.ErrorIs(t, err, rank.ErrOrder)
+	_, err = rank.Between("A", "A")
+	assert.ErrorIs(t, err, rank.ErrOrder)
+	_, err = rank.Between("A0", "")
+	assert.ErrorIs(t, err, rank.ErrInvalid)
+	_, err = rank.Between("", "a-b")
+	assert.ErrorIs(t, err, rank.ErrInvalid)
+}
+
+func TestBetween_RepeatedInserts(t *testing.T) {
+	// Inserting at the same spot over and over must keep finding room
+	low, high := "", ""
+	for i := 0; i < 200; i++ {
+		mid, err := rank.Between(low, high)
+		require.NoError(t, err)
+		if i%2 == 0 {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 61, 62, 500} {
+		ranks := rank.Spread(n)
+		assert.Len(t, ranks, n)
+		assert.True(t, sort.StringsAreSorted(ranks))
+		for i := 1; i < len(ranks); i++ {
+			assert.NotEqual(t, ranks[i-1], ranks[i])
+		}
+		for _, r := range ranks {
+			_, err := rank.Between(r, "")
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package realtime internal/realtime/feed.go
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+// feedSize is how many recent changes a feed keeps for clients that
+// reconnect
+const feedSize = 1024
+
+// feedBufferSize is how many changes a feed subscription can fall behind
+// before it is closed
+const feedBufferSize = 64
+
+// Change is a change to a resource, sent to the clients following it. Its ID
+// is the resume token clients reconnect with.
+type Change struct {
+	ID           string      `json:"id"`
+	Event        string      `json:"event"`
+	OrgID        string      `json:"-"`
+	ResourceType string      `json:"resourceType"`
+	ResourceID   string      `json:"resourceId"`
+	ProjectID    string      `json:"projectId,omitempty"`
+	ActorID      string      `json:"actorId,omitempty"`
+	OccurredAt   time.Time   `json:"occurredAt"`
+	Data         interface{} `json:"data,omitempty"`
+}
+
+// FeedSubscription receives the changes published to a feed. C is closed
+// when the subscription ends, including when it fell too far behind; the
+// client can then resume from the last change it saw.
+type FeedSubscription struct {
+	C <-chan Change
+	c chan Change
+}
+
+// Feed fans changes out to every subscriber and keeps the latest ones, so
+// that clients that lost their connection can catch up. Like the Hub it
+// lives in one process.
+type Feed struct {
+	mu     sync.Mutex
+	recent []Change
+	// next is where the next change goes in recent once it is full
+	next int
+	subs map[*FeedSubscription]struct{}
+}
+
+func NewFeed() *Feed {
+	return &Feed{
+		recent: make([]Change, 0, feedSize),
+		subs:   make(map[*FeedSubscription]struct{}),
+	}
+}
+
+// Publish records a change and sends it to every subscriber without waiting.
+// Subscribers too far behind to take it are closed.
+func (f *Feed) Publish(change Change) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.recent) < feedSize {
+		f.recent = append(f.recent, change)
+	} else {
+		f.recent[f.next] = change
+		f.next = (f.next + 1) % feedSize
+	}
+
+	for sub := range f.subs {
+		select {
+		case sub.c <- change:
+		default:
+			delete(f.subs, sub)
+			close(sub.c)
+		}
+	}
+}
+
+// Subscribe opens a subscription. With a resume token it also returns the
+// changes published after that change; ok is false when the token is not
+// among the changes kept, and the client has to reload what it shows.
+// Callers must Unsubscribe when the connection ends.
+func (f *Feed) Subscribe(after string) (sub *FeedSubscription, missed []Change, ok bool) {
+	c := make(chan Change, feedBufferSize)
+	sub = &FeedSubscription{C: c, c: c}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subs[sub] = struct{}{}
+
+	if after == "" {
+		return sub, nil, true
+	}
+	ordered := append(append([]Change{}, f.recent[f.next:]...), f.recent[:f.next]...)
+	for i, change := range ordered {
+		if change.ID == after {
+			return sub, ordered[i+1:], true
+		}
+	}
+	return sub, nil, false
+}
+
+// Unsubscribe closes a subscription; calling it again does nothing
+func (f *Feed) Unsubscribe(sub *FeedSubscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[sub]; !ok {
+		return
+	}
+	delete(f.subs, sub)
+	close(sub.c)
+}
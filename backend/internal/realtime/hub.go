@@ -0,0 +1,98 @@
+// Package realtime internal/realtime/hub.go
+package realtime
+
+import (
+	"log"
+	"sync"
+)
+
+// bufferSize is how many messages a connection can fall behind before new
+// ones are dropped for it
+const bufferSize = 16
+
+// Message is an event sent to a connected client
+type Message struct {
+	// ID lets a client resume a stream after the message; messages that
+	// cannot be resumed from have none
+	ID    string
+	Event string
+	// OrgID is the organization the message belongs to; clients working in
+	// another organization do not receive it. Empty means every organization.
+	OrgID string
+	Data  interface{}
+}
+
+// Subscription receives the messages published to a user
+type Subscription struct {
+	C      <-chan Message
+	c      chan Message
+	userID string
+}
+
+// Hub fans messages out to the open connections of each user. It lives in
+// one process; clients connected to another instance do not see its
+// messages.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subs: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe opens a subscription for a user. Callers must Unsubscribe when
+// the connection ends.
+func (h *Hub) Subscribe(userID string) *Subscription {
+	c := make(chan Message, bufferSize)
+	sub := &Subscription{C: c, c: c, userID: userID}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe closes a subscription; calling it again does nothing
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.c)
+}
+
+// Publish sends a message to every connection of a user without waiting;
+// connections that are too far behind miss it
+func (h *Hub) Publish(userID string, msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subs[userID] {
+		select {
+		case sub.c <- msg:
+		default:
+			log.Printf("Dropping %s message for user %s: connection is too slow", msg.Event, userID)
+		}
+	}
+}
+
+// Connections returns how many connections a user has open
+func (h *Hub) Connections(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs[userID])
+}
@@ -0,0 +1,80 @@
+// internal/realtime/feed_test.go
+package tests
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/realtime"
+	"testing"
+)
+
+func TestFeed_PublishReachesEverySubscriber(t *testing.T) {
+	feed := realtime.NewFeed()
+	first, _, _ := feed.Subscribe("")
+	second, _, _ := feed.Subscribe("")
+
+	feed.Publish(realtime.Change{ID: "1", Event: "document.updated"})
+
+	for _, sub := range []*realtime.FeedSubscription{first, second} {
+		select {
+		case change := <-sub.C:
+			assert.Equal(t, "1", change.ID)
+		default:
+			t.Fatal("change was not delivered")
+		}
+	}
+}
+
+func TestFeed_SubscribeResumesAfterToken(t *testing.T) {
+	feed := realtime.NewFeed()
+	for i := 1; i <= 3; i++ {
+		feed.Publish(realtime.Change{ID: fmt.Sprint(i)})
+	}
+
+	_, missed, ok := feed.Subscribe("1")
+	require.True(t, ok)
+	require.Len(t, missed, 2)
+	assert.Equal(t, "2", missed[0].ID)
+	assert.Equal(t, "3", missed[1].ID)
+
+	_, missed, ok = feed.Subscribe("3")
+	assert.True(t, ok)
+	assert.Empty(t, missed)
+}
+
+func TestFeed_SubscribeWithForgottenToken(t *testing.T) {
+	feed := realtime.NewFeed()
+	// Enough changes to push the first ones out
+	for i := 0; i < 2000; i++ {
+		feed.Publish(realtime.Change{ID: fmt.Sprint(i)})
+	}
+
+	_, missed, ok := feed.Subscribe("5")
+	assert.False(t, ok)
+	assert.Empty(t, missed)
+
+	_, missed, ok = feed.Subscribe("1990")
+	require.True(t, ok)
+	require.Len(t, missed, 9)
+	assert.Equal(t, "1991", missed[0].ID)
+	assert.Equal(t, "1999", missed[8].ID)
+}
+
+func TestFeed_SlowSubscribersAreClosed(t *testing.T) {
+	feed := realtime.NewFeed()
+	sub, _, _ := feed.Subscribe("")
+
+	for i := 0; i < 100; i++ {
+		feed.Publish(realtime.Change{ID: fmt.Sprint(i)})
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Less(t, received, 100)
+
+	// Unsubscribing a closed subscription is a no-op
+	feed.Unsubscribe(sub)
+}
@@ -0,0 +1,59 @@
+// internal/realtime/hub_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/realtime"
+	"testing"
+)
+
+func TestHub_PublishReachesEveryConnectionOfTheUser(t *testing.T) {
+	hub := realtime.NewHub()
+	first := hub.Subscribe("alice")
+	second := hub.Subscribe("alice")
+	other := hub.Subscribe("bob")
+	assert.Equal(t, 2, hub.Connections("alice"))
+
+	hub.Publish("alice", realtime.Message{Event: "notification", Data: "hello"})
+
+	for _, sub := range []*realtime.Subscription{first, second} {
+		select {
+		case msg := <-sub.C:
+			assert.Equal(t, "notification", msg.Event)
+			assert.Equal(t, "hello", msg.Data)
+		default:
+			t.Fatal("message was not delivered")
+		}
+	}
+	assert.Empty(t, other.C)
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := realtime.NewHub()
+	sub := hub.Subscribe("alice")
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub)
+
+	_, open := <-sub.C
+	assert.False(t, open)
+	assert.Equal(t, 0, hub.Connections("alice"))
+
+	// Publishing to a user without connections is a no-op
+	hub.Publish("alice", realtime.Message{Event: "notification"})
+}
+
+func TestHub_SlowConnectionsDropMessages(t *testing.T) {
+	hub := realtime.NewHub()
+	sub := hub.Subscribe("alice")
+
+	for i := 0; i < 100; i++ {
+		hub.Publish("alice", realtime.Message{Event: "notification", Data: i})
+	}
+
+	require.NotEmpty(t, sub.C)
+	first := <-sub.C
+	assert.Equal(t, 0, first.Data)
+	assert.Less(t, len(sub.C), 100)
+}
@@ -0,0 +1,52 @@
+// internal/repository/attempt_store.go
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AttemptStore counts attempts, such as password guesses, over fixed windows
+type AttemptStore interface {
+	// Attempts returns how many attempts were recorded under a key in its
+	// current window
+	Attempts(ctx context.Context, key string) (int64, error)
+	// AddAttempt records an attempt under a key and returns how many its
+	// window holds. The first attempt starts a window of the given length.
+	AddAttempt(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// RedisAttemptStore implements AttemptStore using Redis
+type RedisAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisAttemptStore(client *redis.Client) AttemptStore {
+	return &RedisAttemptStore{
+		client: client,
+	}
+}
+
+func (s *RedisAttemptStore) Attempts(ctx context.Context, key string) (int64, error) {
+	count, err := s.client.Get(ctx, "attempts:"+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (s *RedisAttemptStore) AddAttempt(ctx context.Context, key string, window time.Duration) (int64, error) {
+	key = "attempts:" + key
+	pipe := s.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	// Only the first attempt sets the expiry, so the window does not slide
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
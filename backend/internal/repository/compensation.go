@@ -0,0 +1,48 @@
+// Package repository internal/repository/compensation.go
+package repository
+
+import (
+	"context"
+	"log"
+)
+
+type compensationKey struct{}
+
+// compensations are the undo steps registered by one unit of work
+type compensations struct {
+	undo []func(ctx context.Context) error
+}
+
+// OnRollback registers undo to run if the unit of work ctx belongs to fails.
+// Inside a real transaction the abort already discards every write and undo
+// is never called; it matters when a TxManager falls back to applying writes
+// one by one, which would otherwise leave a failed unit of work half done.
+func OnRollback(ctx context.Context, undo func(ctx context.Context) error) {
+	if c, ok := ctx.Value(compensationKey{}).(*compensations); ok {
+		c.undo = append(c.undo, undo)
+	}
+}
+
+// RunCompensated runs fn without a transaction. When fn fails, the undo steps
+// it registered with OnRollback run in reverse order and fn's error is
+// returned. Calls made inside fn join the outer unit of work.
+func RunCompensated(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(compensationKey{}).(*compensations); ok {
+		return fn(ctx)
+	}
+
+	c := &compensations{}
+	err := fn(context.WithValue(ctx, compensationKey{}, c))
+	if err == nil {
+		return nil
+	}
+
+	// Undo even when the request was cancelled, which is often why fn failed
+	undoCtx := context.WithoutCancel(ctx)
+	for i := len(c.undo) - 1; i >= 0; i-- {
+		if undoErr := c.undo[i](undoCtx); undoErr != nil {
+			log.Printf("Failed to undo a step of a failed unit of work: %v", undoErr)
+		}
+	}
+	return err
+}
@@ -3,8 +3,16 @@
 import (
 	"context"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"time"
 )
 
+// TxManager runs a unit of work atomically. Repository calls made with the
+// context passed to fn take part in the transaction.
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
 	// Create creates a new user. Returns error if email already exists
 	Create(ctx context.Context, user *models.User) error
@@ -12,40 +20,290 @@ type UserRepository interface {
 	// GetByID retrieves a user by ID. Returns errors.ErrNotFound if user doesn't exist
 	GetByID(ctx context.Context, id string) (*models.User, error)
 
+	// GetByIDs retrieves the users with the given IDs in one query, skipping
+	// IDs that match no user
+	GetByIDs(ctx context.Context, ids []string) ([]*models.User, error)
+
 	// GetByEmail retrieves a user by email. Returns errors.ErrNotFound if user doesn't exist
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
-	// Update updates an existing user. Returns errors.ErrNotFound if user doesn't exist
+	// Update updates an existing user. Returns errors.ErrNotFound if user doesn't exist.
+	// Notification preferences are left alone; see UpdateNotificationPreferences.
 	Update(ctx context.Context, user *models.User) error
+
+	// UpdateNotificationPreferences sets only the choices the input lists, so
+	// concurrent changes to other choices are kept, and returns the stored
+	// preferences
+	UpdateNotificationPreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error)
+
+	// FindDigestRecipients returns up to limit users, in ID order after
+	// afterID, with the digest frequency who were not sent a digest since
+	// sentBefore. Only the fields a digest needs are loaded.
+	FindDigestRecipients(ctx context.Context, frequency models.DigestFrequency, sentBefore time.Time, afterID string, limit int) ([]*models.User, error)
+
+	// ClaimDigest marks the user's digest sent at the given time unless one
+	// was sent since sentBefore, so only one instance sends it. Reports
+	// whether the claim succeeded.
+	ClaimDigest(ctx context.Context, userID string, sentBefore time.Time, at time.Time) (bool, error)
+
+	// ReleaseDigest undoes the claim made at the given time, restoring when
+	// the previous digest was sent, so a digest that failed to send is tried
+	// again
+	ReleaseDigest(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error
 }
 
 type ProjectRepository interface {
 	Create(ctx context.Context, project *models.Project) error
 	GetByID(ctx context.Context, id string) (*models.Project, error)
 	GetByUser(ctx context.Context, userID string) ([]*models.Project, error)
-	Update(ctx context.Context, project *models.Project) error
+	// FindPageByUser returns one page of the projects the user created or is a member of
+	FindPageByUser(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Project], error)
+	// UpdateFields writes the named fields of the project alone, by their
+	// stored names, so an edit cannot undo a concurrent change to another
+	// field. Fields the project leaves empty are removed. The project must
+	// still be unarchived when it is written; returns
+	// errors.ErrProjectArchived otherwise.
+	UpdateFields(ctx context.Context, project *models.Project, fields ...string) error
+	// SetOwners replaces the owners of an unarchived project as long as they
+	// are still the previous ones. Returns errors.ErrVersionConflict when
+	// they changed since they were read.
+	SetOwners(ctx context.Context, id string, previous, owners []string) error
+	// RequestTransfer stores a transfer on an unarchived project. Returns
+	// errors.ErrTransferPending if another one is pending.
+	RequestTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error
+	// ClearTransfer removes the transfer if it is still the pending one, so
+	// it is accepted, declined or cancelled only once. Returns
+	// errors.ErrNoTransfer otherwise.
+	ClearTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error
+	// SetProgress stores computed progress alone, leaving the rest of the
+	// project as it is, so recomputing cannot undo a concurrent edit
+	SetProgress(ctx context.Context, id string, progress int, breakdown *models.ProgressBreakdown) error
+	// AddToTeam adds a user to the team list alone, leaving the rest of the
+	// project as it is. Returns errors.ErrAlreadyInTeam for members and
+	// errors.ErrProjectArchived for archived projects.
+	AddToTeam(ctx context.Context, id string, userID string) error
+	// UpdateTeamList adds and removes users on the team list alone. Returns
+	// errors.ErrProjectArchived for archived projects.
+	UpdateTeamList(ctx context.Context, id string, added, removed []string) error
+	// AssignTeam, UpdateTeamRole and UnassignTeam change one team assignment
+	// alone, on unarchived projects. AssignTeam returns
+	// errors.ErrTeamAssigned if the team is assigned already, the others
+	// errors.ErrTeamNotAssigned if it is not.
+	AssignTeam(ctx context.Context, id string, assignment models.ProjectTeamAssignment) error
+	UpdateTeamRole(ctx context.Context, id string, teamID string, role models.TeamRole) error
+	UnassignTeam(ctx context.Context, id string, teamID string) error
+	// Archive and Unarchive set and clear the archived mark
+	Archive(ctx context.Context, id string, archivedBy string, at time.Time) error
+	Unarchive(ctx context.Context, id string) error
+	// Delete permanently removes a project; use SoftDelete to move it to the trash
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, filter interface{}) ([]*models.Project, error)
+	// GetByAssignedTeam returns the active projects a team is assigned to
+	GetByAssignedTeam(ctx context.Context, teamID string) ([]*models.Project, error)
+	SoftDelete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string) error
+	GetDeletedByID(ctx context.Context, id string) (*models.Project, error)
+	GetDeletedByUser(ctx context.Context, userID string) ([]*models.Project, error)
+	GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Project, error)
 }
 type DocumentRepository interface {
+	// Create keeps an ID set by the caller, for copies whose links were
+	// rewritten ahead of time
 	Create(ctx context.Context, document *models.Document) error
 	CreateVersion(ctx context.Context, version *models.DocumentVersion) error
 	GetByID(ctx context.Context, id string) (*models.Document, error)
 	GetByProject(ctx context.Context, projectID string) ([]*models.Document, error)
+	FindByProject(ctx context.Context, projectID string, filter models.DocumentFilter) ([]*models.Document, error)
 	Update(ctx context.Context, document *models.Document) error
+	// UpdateOrganization changes folder and tags without creating a new version
+	UpdateOrganization(ctx context.Context, id string, folderID string, tags []string) error
+	// Delete permanently removes a document and its versions
 	Delete(ctx context.Context, id string) error
 	GetVersions(ctx context.Context, documentID string) ([]*models.DocumentVersion, error)
+	GetProjectTags(ctx context.Context, projectID string) ([]string, error)
+	// FindPage returns one page of the documents of the given projects
+	FindPage(ctx context.Context, projectIDs []string, filter models.DocumentFilter, q pagination.Query) (*pagination.Page[*models.Document], error)
+	SoftDelete(ctx context.Context, id string, deletedBy string) error
+	// SoftDeleteByProject trashes the active documents of a project as part of a cascade
+	SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error
+	Restore(ctx context.Context, id string) error
+	// RestoreByProject restores the documents trashed by a project cascade
+	RestoreByProject(ctx context.Context, projectID string) error
+	GetDeletedByID(ctx context.Context, id string) (*models.Document, error)
+	GetDeletedByProject(ctx context.Context, projectID string) ([]*models.Document, error)
+	GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Document, error)
+	DeleteByProject(ctx context.Context, projectID string) error
+	// BackfillHistory records the author, last editor and approval time of
+	// documents saved before they were kept apart, returning how many
+	// documents it changed
+	BackfillHistory(ctx context.Context) (int64, error)
+}
+
+type FolderRepository interface {
+	Create(ctx context.Context, folder *models.Folder) error
+	GetByID(ctx context.Context, id string) (*models.Folder, error)
+	GetByProject(ctx context.Context, projectID string) ([]*models.Folder, error)
+	Update(ctx context.Context, folder *models.Folder) error
+	Delete(ctx context.Context, id string) error
+	// SetPositions assigns positions to folders in the given order
+	SetPositions(ctx context.Context, folderIDs []string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+// TemplateRepository stores the project templates of organizations
+type TemplateRepository interface {
+	Create(ctx context.Context, template *models.ProjectTemplate) error
+	GetByID(ctx context.Context, id string) (*models.ProjectTemplate, error)
+	// List returns the templates ordered by name
+	List(ctx context.Context) ([]*models.ProjectTemplate, error)
+	Update(ctx context.Context, template *models.ProjectTemplate) error
+	Delete(ctx context.Context, id string) error
+}
+
+// NotificationRepository stores the notifications of users
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	// FindPageByUser returns one page of a user's notifications
+	FindPageByUser(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Notification], error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead marks one of the user's notifications read. Returns
+	// errors.ErrNotificationNotFound if the user has no such notification.
+	MarkRead(ctx context.Context, id string, userID string, at time.Time) (*models.Notification, error)
+	// MarkAllRead marks every unread notification of the user read and
+	// returns how many there were
+	MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id string) (*models.Webhook, error)
+	GetByProject(ctx context.Context, projectID string) ([]*models.Webhook, error)
+	// GetSubscribed returns the active webhooks of a project subscribed to the event
+	GetSubscribed(ctx context.Context, projectID string, event models.WebhookEvent) ([]*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create queues a delivery. A first delivery of an event already queued
+	// for the webhook is not queued again, and creating it succeeds without
+	// setting its ID.
+	Create(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetByID(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	// FindPageByWebhook returns one page of a webhook's deliveries
+	FindPageByWebhook(ctx context.Context, webhookID string, q pagination.Query) (*pagination.Page[*models.WebhookDelivery], error)
+	// ClaimDue locks the pending delivery that has waited longest past its
+	// attempt time for the lease, so no other worker sends it meanwhile.
+	// Returns nil when none is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	// RecordAttempt saves the attempts, status and next attempt time of a
+	// delivery and releases its lock
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	DeleteByWebhook(ctx context.Context, webhookID string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+// AuditRepository is append-only: entries are never updated or deleted
+type AuditRepository interface {
+	// Create records an entry. An entry for the same event is recorded only
+	// once; recording it again succeeds without adding another.
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	// FindPage returns one page of entries matching the query's filters
+	FindPage(ctx context.Context, q pagination.Query) (*pagination.Page[*models.AuditEntry], error)
+}
+
+type ActivityRepository interface {
+	// Record adds an activity to the entry of the same actor, verb and
+	// resource whose latest change is no older than since, or else starts a
+	// new entry. Activity whose event was already counted is ignored.
+	Record(ctx context.Context, activity *models.Activity, since time.Time) error
+	// FindPage returns one page of the activity in the given projects
+	FindPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Activity], error)
+}
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *models.ShareLink) error
+	// GetByID returns errors.ErrShareLinkNotFound for unknown links
+	GetByID(ctx context.Context, id string) (*models.ShareLink, error)
+	// GetByTokenHash finds a link in any organization, for viewers who are
+	// not signed in
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.ShareLink, error)
+	GetByProject(ctx context.Context, projectID string) ([]*models.ShareLink, error)
+	Revoke(ctx context.Context, id string, revokedBy string, at time.Time) error
+	// RecordView counts a view of the link
+	RecordView(ctx context.Context, id string, at time.Time) error
+}
+
+type StatsRepository interface {
+	// CountProjectsByStatus counts the given projects per status
+	CountProjectsByStatus(ctx context.Context, projectIDs []string) ([]models.KeyCount, error)
+	// DocumentStats counts the documents of the given projects, with the ones
+	// created and approved since a time counted per bucket
+	DocumentStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.DocumentStats, error)
+	// EditStats counts the edits made to the documents of the given projects
+	// since a time
+	EditStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.EditStats, error)
 }
+
+type MilestoneRepository interface {
+	Create(ctx context.Context, milestone *models.Milestone) error
+	GetByID(ctx context.Context, id string) (*models.Milestone, error)
+	// GetByProject returns a project's milestones ordered by due date
+	GetByProject(ctx context.Context, projectID string) ([]*models.Milestone, error)
+	// GetOpenDueBefore returns the incomplete milestones of the given projects
+	// that were due before the cutoff
+	GetOpenDueBefore(ctx context.Context, projectIDs []string, cutoff time.Time) ([]*models.Milestone, error)
+	Update(ctx context.Context, milestone *models.Milestone) error
+	Delete(ctx context.Context, id string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+// TaskRepository stores tasks. Update bumps the task version and returns
+// errors.ErrVersionConflict if the stored version differs.
+type TaskRepository interface {
+	Create(ctx context.Context, task *models.Task) error
+	GetByID(ctx context.Context, id string) (*models.Task, error)
+	// FindByProject returns a project's tasks ordered by rank
+	FindByProject(ctx context.Context, projectID string, filter models.TaskFilter) ([]*models.Task, error)
+	Update(ctx context.Context, task *models.Task) error
+	Delete(ctx context.Context, id string) error
+	// SetRanks assigns new ranks to tasks by ID. Reranking is not an edit
+	// of those tasks, so their versions stay as they are.
+	SetRanks(ctx context.Context, ranks map[string]string) error
+	CountByColumn(ctx context.Context, projectID, columnID string) (int64, error)
+	// ClearMilestone unlinks tasks from a deleted milestone
+	ClearMilestone(ctx context.Context, milestoneID string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+type BoardRepository interface {
+	// Create returns errors.ErrVersionConflict if the project already has a board
+	Create(ctx context.Context, board *models.Board) error
+	// GetByProject returns errors.ErrNotFound if the project has no board yet
+	GetByProject(ctx context.Context, projectID string) (*models.Board, error)
+	// UpdateColumns returns errors.ErrVersionConflict if the stored version differs
+	UpdateColumns(ctx context.Context, board *models.Board) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+// TeamRepository stores standalone teams. Project membership lives in
+// TeamMemberRepository.
 type TeamRepository interface {
-	Create(ctx context.Context, member *models.Team) error
-	GetByID(ctx context.Context, id string) (*models.TeamMember, error)
-	GetByProjectAndUser(ctx context.Context, projectID, userID string) (*models.TeamMember, error)
-	GetProjectMembers(ctx context.Context, projectID string) ([]*models.TeamMember, error)
-	Update(ctx context.Context, member *models.TeamMember) error
+	Create(ctx context.Context, team *models.Team) error
+	GetByID(ctx context.Context, id string) (*models.Team, error)
+	Update(ctx context.Context, team *models.Team) error
 	Delete(ctx context.Context, id string) error
-	GetAll(ctx context.Context) ([]*models.Team, error)
-	CreateTeamMember(ctx context.Context, member *models.TeamMember) error
-	GetTeamMember(ctx context.Context, id string) (*models.TeamMember, error)
+	FindPage(ctx context.Context, q pagination.Query) (*pagination.Page[*models.Team], error)
+	GetByMember(ctx context.Context, userID string) ([]*models.Team, error)
+	// AddMember returns errors.ErrAlreadyInTeam if the user is already a member
+	AddMember(ctx context.Context, teamID string, member models.TeamMembership) error
+	// UpdateMemberRole and RemoveMember return errors.ErrNotInTeam for non-members
+	UpdateMemberRole(ctx context.Context, teamID, userID string, role models.TeamRole) error
+	RemoveMember(ctx context.Context, teamID, userID string) error
+	// MigrateLegacy moves teams stored in the team members collection into
+	// the teams collection and returns how many it moved
+	MigrateLegacy(ctx context.Context) (int, error)
 }
 
 type TeamMemberRepository interface {
@@ -53,18 +311,86 @@ type TeamMemberRepository interface {
 	GetByID(ctx context.Context, id string) (*models.TeamMember, error)
 	GetByProjectAndUser(ctx context.Context, projectID, userID string) (*models.TeamMember, error)
 	GetProjectMembers(ctx context.Context, projectID string) ([]*models.TeamMember, error)
+	// GetAllByProject also returns inactive members, including those excluded
+	// from a team-derived membership
+	GetAllByProject(ctx context.Context, projectID string) ([]*models.TeamMember, error)
 	Update(ctx context.Context, member *models.TeamMember) error
 	Delete(ctx context.Context, id string) error
-	GetByTeamID(ctx context.Context, teamID string) ([]*models.TeamMember, error)
 	CreateTeamMember(ctx context.Context, member *models.TeamMember) error
 	GetTeamMember(ctx context.Context, id string) (*models.TeamMember, error)
+	SoftDeleteByProject(ctx context.Context, projectID string) error
+	RestoreByProject(ctx context.Context, projectID string) error
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	GetByID(ctx context.Context, id string) (*models.Invitation, error)
+	// The GetPending methods skip expired invitations
+	GetPendingByProject(ctx context.Context, projectID string) ([]*models.Invitation, error)
+	GetPendingByTeam(ctx context.Context, teamID string) ([]*models.Invitation, error)
+	// Respond closes a pending invitation; errors.ErrNotFound if it is not pending
+	Respond(ctx context.Context, id string, status models.InvitationStatus, userID string) error
+}
+
+// OrganizationRepository stores organizations. Unlike the other repositories
+// it is not scoped to the tenant of the context.
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	GetByID(ctx context.Context, id string) (*models.Organization, error)
+	// GetDefault returns the organization holding the data created before
+	// organizations existed; errors.ErrNotFound if there is none
+	GetDefault(ctx context.Context) (*models.Organization, error)
+	Update(ctx context.Context, org *models.Organization) error
+	GetByMember(ctx context.Context, userID string) ([]*models.Organization, error)
+	// AddMember returns errors.ErrAlreadyInOrg if the user already belongs to it
+	AddMember(ctx context.Context, orgID string, member models.OrgMembership) error
+	// UpdateMemberRole and RemoveMember return errors.ErrNotInOrg for non-members
+	UpdateMemberRole(ctx context.Context, orgID, userID string, role models.OrgRole) error
+	RemoveMember(ctx context.Context, orgID, userID string) error
+	// CountOrphans and AdoptOrphans deal with records that have no organization
+	CountOrphans(ctx context.Context) (int64, error)
+	AdoptOrphans(ctx context.Context, orgID string) error
 }
 
 type MockupRepository interface {
+	// Create keeps an ID set by the caller, like DocumentRepository.Create
 	Create(ctx context.Context, mockup *models.Mockup) error
 	GetByID(ctx context.Context, id string) (*models.Mockup, error)
 	GetByProject(ctx context.Context, projectID string) ([]*models.Mockup, error)
 	Update(ctx context.Context, mockup *models.Mockup) error
+	// Delete permanently removes a mockup
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context) ([]*models.Mockup, error)
+	// FindPage returns one page of the mockups of the given projects
+	FindPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Mockup], error)
+	SoftDelete(ctx context.Context, id string, deletedBy string) error
+	SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error
+	Restore(ctx context.Context, id string) error
+	RestoreByProject(ctx context.Context, projectID string) error
+	GetDeletedByID(ctx context.Context, id string) (*models.Mockup, error)
+	GetDeletedByProject(ctx context.Context, projectID string) ([]*models.Mockup, error)
+	GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Mockup, error)
+	DeleteByProject(ctx context.Context, projectID string) error
+}
+
+type LinkRepository interface {
+	Create(ctx context.Context, link *models.Link) error
+	GetByID(ctx context.Context, id string) (*models.Link, error)
+	Delete(ctx context.Context, id string) error
+	// GetBySource returns the outgoing links of an item
+	GetBySource(ctx context.Context, sourceType models.LinkItemType, sourceID string) ([]*models.Link, error)
+	// GetByTarget returns the incoming links (backlinks) of an item
+	GetByTarget(ctx context.Context, targetType models.LinkItemType, targetID string) ([]*models.Link, error)
+	GetBrokenByProject(ctx context.Context, projectID string) ([]*models.Link, error)
+	// ReplaceExtracted swaps the extracted links of a source for the given set,
+	// leaving manually created links untouched
+	ReplaceExtracted(ctx context.Context, sourceType models.LinkItemType, sourceID string, links []*models.Link) error
+	// MarkTargetBroken flags every link pointing at the deleted items
+	MarkTargetBroken(ctx context.Context, targetType models.LinkItemType, targetIDs ...string) error
+	// MarkTargetRestored clears the broken flag once trashed items are restored
+	MarkTargetRestored(ctx context.Context, targetType models.LinkItemType, targetIDs ...string) error
+	DeleteBySource(ctx context.Context, sourceType models.LinkItemType, sourceID string) error
+	// DeleteByProject removes the links whose source belongs to a project
+	DeleteByProject(ctx context.Context, projectID string) error
 }
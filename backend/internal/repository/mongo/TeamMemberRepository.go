@@ -7,6 +7,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
 	"time"
@@ -16,31 +18,6 @@ type TeamMemberRepository struct {
 	collection *mongo.Collection
 }
 
-func (r *TeamMemberRepository) GetByTeamID(ctx context.Context, teamID string) ([]*models.TeamMember, error) {
-	// Define the filter for finding team members by their team ID
-	filter := bson.M{"team_id": teamID}
-
-	// Perform the database query to find matching documents
-	cursor, err := r.collection.Find(ctx, filter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch team members: %w", err)
-	}
-	defer func(cursor *mongo.Cursor, ctx context.Context) {
-		err := cursor.Close(ctx)
-		if err != nil {
-			fmt.Printf("failed to close cursor: %v", err)
-		}
-	}(cursor, ctx)
-
-	// Prepare a slice to store the results
-	var teamMembers []*models.TeamMember
-	if err := cursor.All(ctx, &teamMembers); err != nil {
-		return nil, fmt.Errorf("failed to decode team members: %w", err)
-	}
-
-	return teamMembers, nil
-}
-
 func (r *TeamMemberRepository) GetTeamMember(ctx context.Context, id string) (*models.TeamMember, error) {
 	// Convert the ID from string to ObjectID for MongoDB query
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -52,7 +29,7 @@ func (r *TeamMemberRepository) GetTeamMember(ctx context.Context, id string) (*m
 	var teamMember models.TeamMember
 
 	// Query the collection by ID
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&teamMember)
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&teamMember)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, fmt.Errorf("team member not found: %w", err)
@@ -65,24 +42,65 @@ func (r *TeamMemberRepository) GetTeamMember(ctx context.Context, id string) (*m
 }
 
 func NewTeamMemberRepository(db *mongo.Database) *TeamMemberRepository {
-	return &TeamMemberRepository{
+	repo := &TeamMemberRepository{
 		collection: db.Collection("team_members"),
 	}
+
+	// Ensure indexes
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create team member indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *TeamMemberRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "user_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	})
+	return err
 }
 
 func (r *TeamMemberRepository) Create(ctx context.Context, member *models.TeamMember) error {
-	_, err := r.collection.InsertOne(ctx, member)
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = time.Now()
+	member.OrgID = orgFor(ctx, member.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, member)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errs.ErrAlreadyInTeam
+		}
 		return fmt.Errorf("failed to create team member: %w", err)
 	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		member.ID = oid.Hex()
+		undoInsert(ctx, r.collection, oid)
+	}
+
 	return nil
 }
 
 func (r *TeamMemberRepository) GetByID(ctx context.Context, id string) (*models.TeamMember, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrNotFound
+	}
+
 	var member models.TeamMember
-	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&member); err != nil {
+	if err := r.collection.FindOne(ctx, scoped(ctx, active(bson.M{"_id": oid}))).Decode(&member); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, fmt.Errorf("team member not found: %w", err)
+			return nil, errs.ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get team member: %w", err)
 	}
@@ -91,10 +109,10 @@ func (r *TeamMemberRepository) GetByID(ctx context.Context, id string) (*models.
 
 func (r *TeamMemberRepository) GetByProjectAndUser(ctx context.Context, projectID, userID string) (*models.TeamMember, error) {
 	var member models.TeamMember
-	filter := bson.M{"project_id": projectID, "user_id": userID}
-	if err := r.collection.FindOne(ctx, filter).Decode(&member); err != nil {
+	filter := active(bson.M{"project_id": projectID, "user_id": userID})
+	if err := r.collection.FindOne(ctx, scoped(ctx, filter)).Decode(&member); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, fmt.Errorf("team member not found: %w", err)
+			return nil, errs.ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get team member: %w", err)
 	}
@@ -102,7 +120,29 @@ func (r *TeamMemberRepository) GetByProjectAndUser(ctx context.Context, projectI
 }
 
 func (r *TeamMemberRepository) GetProjectMembers(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectID})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, active(bson.M{
+		"project_id": projectID,
+		"status":     models.TeamMemberStatusActive,
+	})))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			fmt.Printf("failed to close cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	var members []*models.TeamMember
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, fmt.Errorf("failed to decode project members: %w", err)
+	}
+	return members, nil
+}
+
+func (r *TeamMemberRepository) GetAllByProject(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, active(bson.M{"project_id": projectID})))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project members: %w", err)
 	}
@@ -121,31 +161,56 @@ func (r *TeamMemberRepository) GetProjectMembers(ctx context.Context, projectID
 }
 
 func (r *TeamMemberRepository) Update(ctx context.Context, member *models.TeamMember) error {
-	filter := bson.M{"_id": member.ID}
+	member.UpdatedAt = time.Now()
+
+	oid, err := primitive.ObjectIDFromHex(member.ID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	filter := bson.M{"_id": oid}
 	update := bson.M{
 		"$set": bson.M{
-			"role":   member.Role,
-			"status": member.Status,
+			"role":       member.Role,
+			"status":     member.Status,
+			"source":     member.Source,
+			"team_ids":   member.TeamIDs,
+			"override":   member.Override,
+			"updated_at": member.UpdatedAt,
 		},
 	}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	if err != nil {
+	var before bson.M
+	if err := r.collection.FindOneAndUpdate(ctx, scoped(ctx, filter), update).Decode(&before); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errs.ErrNotFound
+		}
 		return fmt.Errorf("failed to update team member: %w", err)
 	}
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
 	return nil
 }
 
 func (r *TeamMemberRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	var before bson.M
+	if err := r.collection.FindOneAndDelete(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&before); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errs.ErrNotFound
+		}
 		return fmt.Errorf("failed to delete team member: %w", err)
 	}
+	undoWrite(ctx, r.collection, before)
 	return nil
 }
 
 func (r *TeamMemberRepository) CreateTeamMember(ctx context.Context, member *models.TeamMember) error {
 	member.CreatedAt = time.Now()
 	member.UpdatedAt = time.Now()
+	member.OrgID = orgFor(ctx, member.OrgID)
 
 	_, err := r.collection.InsertOne(ctx, member)
 	if err != nil {
@@ -157,3 +222,33 @@ func (r *TeamMemberRepository) CreateTeamMember(ctx context.Context, member *mod
 
 	return nil
 }
+
+func (r *TeamMemberRepository) SoftDeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, active(bson.M{"project_id": projectID})),
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete project members: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamMemberRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, trashed(bson.M{"project_id": projectID})),
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore project members: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamMemberRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	if err != nil {
+		return fmt.Errorf("failed to delete project members: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package mongo internal/repository/mongo/activity_repository.go
+package mongo
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"time"
+)
+
+// activityEventLimit is how many event IDs an entry remembers; redelivered
+// events arrive soon after the original
+const activityEventLimit = 50
+
+type ActivityRepository struct {
+	collection *mongo.Collection
+}
+
+func NewActivityRepository(db *mongo.Database) *ActivityRepository {
+	repo := &ActivityRepository{
+		collection: db.Collection("activities"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create activity indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *ActivityRepository) ensureIndexes(ctx context.Context) error {
+	// The event index was not unique before; it makes way for the one below
+	if _, err := r.collection.Indexes().DropOne(ctx, "event_ids_1"); err != nil {
+		var cmdErr mongo.CommandError
+		if !stderrors.As(err, &cmdErr) || cmdErr.Name != "IndexNotFound" {
+			return fmt.Errorf("failed to drop activity event index: %w", err)
+		}
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "occurred_at", Value: -1},
+			},
+		},
+		{
+			// Finds the entry a burst of changes adds to
+			Keys: bson.D{
+				{Key: "resource_id", Value: 1},
+				{Key: "actor_id", Value: 1},
+				{Key: "verb", Value: 1},
+				{Key: "occurred_at", Value: -1},
+			},
+		},
+		{
+			// An event is counted in one entry at most, however often and
+			// concurrently it is delivered
+			Keys: bson.D{{Key: "event_ids", Value: 1}},
+			Options: options.Index().SetName("event_ids_unique").SetUnique(true).SetPartialFilterExpression(bson.M{
+				"event_ids.0": bson.M{"$exists": true},
+			}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create activity indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *ActivityRepository) Record(ctx context.Context, activity *models.Activity, since time.Time) error {
+	activity.OrgID = orgFor(ctx, activity.OrgID)
+
+	// Adds to the latest entry of the burst that has not counted the events
+	// yet, or else starts one. An entry that already counted them is left
+	// alone and the new one it would start breaks the unique event index.
+	filter := scoped(ctx, bson.M{
+		"org_id":      activity.OrgID,
+		"resource_id": activity.ResourceID,
+		"actor_id":    activity.ActorID,
+		"verb":        activity.Verb,
+		"occurred_at": bson.M{"$gte": since},
+		"event_ids":   bson.M{"$nin": activity.EventIDs},
+	})
+	update := bson.M{
+		"$inc": bson.M{"count": activity.Count},
+		"$set": bson.M{
+			"project_name": activity.ProjectName,
+			"actor_name":   activity.ActorName,
+			"subject":      activity.Subject,
+			"detail":       activity.Detail,
+			"occurred_at":  activity.OccurredAt,
+		},
+		"$push": bson.M{"event_ids": bson.M{"$each": activity.EventIDs, "$slice": -activityEventLimit}},
+		"$setOnInsert": bson.M{
+			"project_id": activity.ProjectID,
+			"type":       activity.Type,
+			"started_at": activity.StartedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "occurred_at", Value: -1}}).
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var recorded models.Activity
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&recorded)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// The events were counted already
+			return nil
+		}
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	*activity = recorded
+	return nil
+}
+
+func (r *ActivityRepository) FindPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Activity], error) {
+	filter := bson.M{"project_id": bson.M{"$in": projectIDs}}
+	if q.Filter.Actor != "" {
+		filter["actor_id"] = q.Filter.Actor
+	}
+
+	page, err := paginate[models.Activity](ctx, r.collection, scoped(ctx, applyPageFilter(filter, q.Filter)), q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+	return page, nil
+}
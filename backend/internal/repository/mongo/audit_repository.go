@@ -0,0 +1,117 @@
+// Package mongo internal/repository/mongo/audit_repository.go
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+)
+
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	repo := &AuditRepository{
+		collection: db.Collection("audit_log"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create audit log indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *AuditRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Events delivered more than once are recorded once
+			Keys:    bson.D{{Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "org_id", Value: 1},
+				{Key: "occurred_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "occurred_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "resource_id", Value: 1},
+				{Key: "occurred_at", Value: -1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit log indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	entry.OrgID = orgFor(ctx, entry.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		entry.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) FindPage(ctx context.Context, q pagination.Query) (*pagination.Page[*models.AuditEntry], error) {
+	filter := bson.M{}
+	f := q.Filter
+	if f.Actor != "" {
+		filter["actor_id"] = f.Actor
+	}
+	if f.Action != "" {
+		filter["action"] = f.Action
+	}
+	if f.ResourceType != "" {
+		filter["resource_type"] = f.ResourceType
+	}
+	if f.ResourceID != "" {
+		filter["resource_id"] = f.ResourceID
+	}
+	if f.ProjectID != "" {
+		filter["project_id"] = f.ProjectID
+	}
+	if f.From != nil || f.To != nil {
+		occurred := bson.M{}
+		if f.From != nil {
+			occurred["$gte"] = *f.From
+		}
+		if f.To != nil {
+			occurred["$lt"] = *f.To
+		}
+		filter["occurred_at"] = occurred
+	}
+
+	page, err := paginate[models.AuditEntry](ctx, r.collection, scoped(ctx, filter), q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,112 @@
+// Package mongo internal/repository/mongo/board_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type BoardRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBoardRepository(db *mongo.Database) *BoardRepository {
+	repo := &BoardRepository{
+		collection: db.Collection("boards"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create board indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *BoardRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// One board per project
+			Keys:    bson.D{{Key: "project_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create board indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *BoardRepository) Create(ctx context.Context, board *models.Board) error {
+	board.CreatedAt = time.Now()
+	board.UpdatedAt = time.Now()
+	board.Version = 1
+	board.OrgID = orgFor(ctx, board.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, board)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errs.ErrVersionConflict
+		}
+		return fmt.Errorf("failed to create board: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		board.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *BoardRepository) GetByProject(ctx context.Context, projectID string) (*models.Board, error) {
+	var board models.Board
+	err := r.collection.FindOne(ctx, scoped(ctx, bson.M{"project_id": projectID})).Decode(&board)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &board, nil
+}
+
+// UpdateColumns saves the board's columns if nobody changed them since they
+// were read, and bumps its version
+func (r *BoardRepository) UpdateColumns(ctx context.Context, board *models.Board) error {
+	oid, err := primitive.ObjectIDFromHex(board.ID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	updatedAt := time.Now()
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid, "version": board.Version}), bson.M{
+		"$set": bson.M{"columns": board.Columns, "updated_at": updatedAt},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrVersionConflict
+	}
+
+	board.Version++
+	board.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *BoardRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
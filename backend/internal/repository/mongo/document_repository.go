@@ -12,6 +12,8 @@ import (
 	"log"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"sort"
 	"time"
 )
 
@@ -46,6 +48,37 @@ func (r *DocumentRepository) ensureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{Key: "created_at", Value: 1}},
 		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "folder_id", Value: 1},
+				{Key: "title", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "tags", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "type", Value: 1},
+			},
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create document indexes: %w", err)
@@ -78,7 +111,7 @@ func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*models.Do
 	}
 
 	var doc models.Document
-	err = r.documents.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	err = r.documents.FindOne(ctx, scoped(ctx, active(bson.M{"_id": oid}))).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			log.Printf("Document not found: %s", id)
@@ -92,9 +125,25 @@ func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*models.Do
 }
 
 func (r *DocumentRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Document, error) {
-	log.Printf("Getting documents for project: %s", projectID)
+	return r.FindByProject(ctx, projectID, models.DocumentFilter{})
+}
+
+func (r *DocumentRepository) FindByProject(ctx context.Context, projectID string, filter models.DocumentFilter) ([]*models.Document, error) {
+	log.Printf("Getting documents for project: %s, filter: %+v", projectID, filter)
+
+	query := documentQuery(bson.M{"project_id": projectID}, filter)
+
+	pipeline := bson.A{
+		bson.M{"$match": scoped(ctx, query)},
+		bson.M{"$sort": bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+	if filter.ContentLength > 0 {
+		pipeline = append(pipeline, bson.M{"$set": bson.M{
+			"content": bson.M{"$substrCP": bson.A{"$content", 0, filter.ContentLength}},
+		}})
+	}
 
-	cursor, err := r.documents.Find(ctx, bson.M{"project_id": projectID})
+	cursor, err := r.documents.Aggregate(ctx, pipeline)
 	if err != nil {
 		log.Printf("Error querying documents: %v", err)
 		return nil, err
@@ -120,6 +169,32 @@ func (r *DocumentRepository) GetByProject(ctx context.Context, projectID string)
 	return docs, nil
 }
 
+func (r *DocumentRepository) FindPage(ctx context.Context, projectIDs []string, filter models.DocumentFilter, q pagination.Query) (*pagination.Page[*models.Document], error) {
+	query := documentQuery(bson.M{"project_id": bson.M{"$in": projectIDs}}, filter)
+	return paginate[models.Document](ctx, r.documents, scoped(ctx, applyPageFilter(query, q.Filter)), q)
+}
+
+// documentQuery adds the folder, tag and status filters to a query over
+// active documents
+func documentQuery(query bson.M, filter models.DocumentFilter) bson.M {
+	query = active(query)
+	if filter.FolderID != nil {
+		if *filter.FolderID == "" {
+			// Documents created before folders existed have no folder_id field
+			query["folder_id"] = bson.M{"$in": []interface{}{"", nil}}
+		} else {
+			query["folder_id"] = *filter.FolderID
+		}
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
 func (r *DocumentRepository) Update(ctx context.Context, doc *models.Document) error {
 	// Add debug logging
 	log.Printf("Updating document in repository: %+v", doc)
@@ -136,48 +211,96 @@ func (r *DocumentRepository) Update(ctx context.Context, doc *models.Document) e
 	// Create update document with only fields that should be updated
 	updateDoc := bson.M{
 		"$set": bson.M{
-			"title":      doc.Title,
-			"type":       doc.Type,
-			"content":    doc.Content,
-			"version":    doc.Version,
-			"status":     doc.Status,
-			"updated_at": doc.UpdatedAt,
+			"title":       doc.Title,
+			"type":        doc.Type,
+			"content":     doc.Content,
+			"version":     doc.Version,
+			"status":      doc.Status,
+			"folder_id":   doc.FolderID,
+			"tags":        doc.Tags,
+			"approved_at": doc.ApprovedAt,
+			"updated_at":  doc.UpdatedAt,
+			"updated_by":  doc.UpdatedBy,
 		},
 	}
 
-	result, err := r.documents.UpdateOne(
+	var before bson.M
+	err = r.documents.FindOneAndUpdate(
 		ctx,
-		bson.M{"_id": oid},
+		scoped(ctx, active(bson.M{"_id": oid})),
 		updateDoc,
-	)
+	).Decode(&before)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("No document found with ID: %s", doc.ID)
+			return errs.ErrDocumentNotFound
+		}
 		log.Printf("Failed to update document: %v", err)
 		return err
 	}
-
-	if result.MatchedCount == 0 {
-		log.Printf("No document found with ID: %s", doc.ID)
-		return errs.ErrDocumentNotFound
-	}
+	// Without a transaction, a version that fails to save takes the change back
+	undoWrite(ctx, r.documents, before, fieldsOf(updateDoc)...)
 
 	// Create new version
 	version := &models.DocumentVersion{
 		DocumentID: doc.ID,
 		Version:    doc.Version,
 		Content:    doc.Content,
-		CreatedBy:  doc.CreatedBy,
+		Status:     doc.Status,
+		CreatedBy:  doc.UpdatedBy,
 		CreatedAt:  doc.UpdatedAt,
 	}
 
 	if err := r.CreateVersion(ctx, version); err != nil {
 		log.Printf("Failed to create document version: %v", err)
-		// Consider rolling back the document update
 		return fmt.Errorf("failed to create version: %w", err)
 	}
 
 	return nil
 }
 
+func (r *DocumentRepository) UpdateOrganization(ctx context.Context, id string, folderID string, tags []string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %w", err)
+	}
+
+	result, err := r.documents.UpdateOne(ctx, scoped(ctx, active(bson.M{"_id": oid})), bson.M{
+		"$set": bson.M{
+			"folder_id":  folderID,
+			"tags":       tags,
+			"updated_at": time.Now(),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to update document organization: %v", err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+func (r *DocumentRepository) GetProjectTags(ctx context.Context, projectID string) ([]string, error) {
+	values, err := r.documents.Distinct(ctx, "tags", scoped(ctx, active(bson.M{"project_id": projectID})))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(values))
+	for _, v := range values {
+		if tag, ok := v.(string); ok && tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
 func (r *DocumentRepository) Delete(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -185,16 +308,144 @@ func (r *DocumentRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	// Delete document
-	_, err = r.documents.DeleteOne(ctx, bson.M{"_id": oid})
+	_, err = r.documents.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
 	if err != nil {
 		return err
 	}
 
 	// Delete all versions
-	_, err = r.versions.DeleteMany(ctx, bson.M{"document_id": id})
+	_, err = r.versions.DeleteMany(ctx, scoped(ctx, bson.M{"document_id": id}))
+	return err
+}
+
+func (r *DocumentRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrDocumentNotFound
+	}
+
+	result, err := r.documents.UpdateOne(ctx, scoped(ctx, active(bson.M{"_id": oid})), softDeleteUpdate(deletedBy, false))
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+func (r *DocumentRepository) SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error {
+	_, err := r.documents.UpdateMany(ctx, scoped(ctx, active(bson.M{"project_id": projectID})), softDeleteUpdate(deletedBy, true))
+	if err != nil {
+		return fmt.Errorf("failed to delete project documents: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) Restore(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrDocumentNotFound
+	}
+
+	result, err := r.documents.UpdateOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid})), restoreUpdate())
+	if err != nil {
+		return fmt.Errorf("failed to restore document: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+func (r *DocumentRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	_, err := r.documents.UpdateMany(ctx,
+		scoped(ctx, trashed(bson.M{"project_id": projectID, "cascade_deleted": true})),
+		restoreUpdate(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore project documents: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) GetDeletedByID(ctx context.Context, id string) (*models.Document, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrDocumentNotFound
+	}
+
+	var doc models.Document
+	err = r.documents.FindOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid}))).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+func (r *DocumentRepository) GetDeletedByProject(ctx context.Context, projectID string) ([]*models.Document, error) {
+	// Documents trashed with the project are restored with it, so only
+	// individually deleted ones show up in the project trash
+	return r.find(ctx, trashed(bson.M{
+		"project_id":      projectID,
+		"cascade_deleted": bson.M{"$ne": true},
+	}), options.Find().SetSort(bson.M{"deleted_at": -1}))
+}
+
+func (r *DocumentRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Document, error) {
+	return r.find(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+}
+
+func (r *DocumentRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	// Collect IDs first so the versions of every document go too
+	docs, err := r.find(ctx, bson.M{"project_id": projectID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+
+	if len(ids) > 0 {
+		if _, err := r.versions.DeleteMany(ctx, scoped(ctx, bson.M{"document_id": bson.M{"$in": ids}})); err != nil {
+			return fmt.Errorf("failed to delete document versions: %w", err)
+		}
+	}
+
+	_, err = r.documents.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
 	return err
 }
 
+func (r *DocumentRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*models.Document, error) {
+	cursor, err := r.documents.Find(ctx, scoped(ctx, filter), opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	docs := make([]*models.Document, 0)
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
 func (r *DocumentRepository) Create(ctx context.Context, doc *models.Document) error {
 	// Add logging
 	log.Printf("Creating document: %+v", doc)
@@ -202,35 +453,34 @@ func (r *DocumentRepository) Create(ctx context.Context, doc *models.Document) e
 	doc.CreatedAt = time.Now()
 	doc.UpdatedAt = time.Now()
 	doc.Version = 1
+	doc.OrgID = orgFor(ctx, doc.OrgID)
 
 	if doc.Status == "" {
 		doc.Status = models.DocumentStatusDraft
 	}
 
-	result, err := r.documents.InsertOne(ctx, doc)
+	id, err := insertWithID(ctx, r.documents, doc, doc.ID)
 	if err != nil {
 		log.Printf("Error inserting document: %v", err)
 		return err
 	}
-
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		doc.ID = oid.Hex()
-	} else {
-		log.Printf("Warning: InsertedID is not an ObjectID: %v", result.InsertedID)
-	}
+	doc.ID = id
+	// Without a transaction, a version that fails to save takes the document back
+	oid, _ := primitive.ObjectIDFromHex(id)
+	undoInsert(ctx, r.documents, oid)
 
 	// Create initial version
 	version := &models.DocumentVersion{
 		DocumentID: doc.ID,
 		Version:    1,
 		Content:    doc.Content,
+		Status:     doc.Status,
 		CreatedBy:  doc.CreatedBy,
 		CreatedAt:  doc.CreatedAt,
 	}
 
 	if err := r.CreateVersion(ctx, version); err != nil {
 		log.Printf("Error creating document version: %v", err)
-		// Consider rolling back the document creation
 		return err
 	}
 
@@ -240,6 +490,7 @@ func (r *DocumentRepository) Create(ctx context.Context, doc *models.Document) e
 func (r *DocumentRepository) CreateVersion(ctx context.Context, version *models.DocumentVersion) error {
 	// Add logging
 	log.Printf("Creating document version: %+v", version)
+	version.OrgID = orgFor(ctx, version.OrgID)
 
 	result, err := r.versions.InsertOne(ctx, version)
 	if err != nil {
@@ -249,6 +500,7 @@ func (r *DocumentRepository) CreateVersion(ctx context.Context, version *models.
 
 	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
 		version.ID = oid.Hex()
+		undoInsert(ctx, r.versions, oid)
 	} else {
 		log.Printf("Warning: Version InsertedID is not an ObjectID: %v", result.InsertedID)
 	}
@@ -258,7 +510,7 @@ func (r *DocumentRepository) CreateVersion(ctx context.Context, version *models.
 
 func (r *DocumentRepository) GetVersions(ctx context.Context, documentID string) ([]*models.DocumentVersion, error) {
 	cursor, err := r.versions.Find(ctx,
-		bson.M{"document_id": documentID},
+		scoped(ctx, bson.M{"document_id": documentID}),
 		options.Find().SetSort(bson.M{"version": -1}),
 	)
 	if err != nil {
@@ -278,3 +530,60 @@ func (r *DocumentRepository) GetVersions(ctx context.Context, documentID string)
 
 	return versions, nil
 }
+
+// BackfillHistory fills in what documents saved before it was recorded lack,
+// and returns how many it changed. Edits used to overwrite created_by, so the
+// author is taken from the first version and the last editor from the latest.
+// Approved documents get the time of the version that approved them, or of
+// their last save when the versions do not tell.
+func (r *DocumentRepository) BackfillHistory(ctx context.Context) (int64, error) {
+	cursor, err := r.documents.Find(ctx,
+		scoped(ctx, bson.M{"updated_by": bson.M{"$exists": false}}),
+		options.Find().SetProjection(bson.M{"created_by": 1, "updated_at": 1, "status": 1, "approved_at": 1}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	var filled int64
+	for cursor.Next(ctx) {
+		var doc models.Document
+		if err := cursor.Decode(&doc); err != nil {
+			return filled, fmt.Errorf("failed to decode document: %w", err)
+		}
+		versions, err := r.GetVersions(ctx, doc.ID)
+		if err != nil {
+			return filled, fmt.Errorf("failed to fetch versions of document %s: %w", doc.ID, err)
+		}
+
+		set := bson.M{"updated_by": doc.CreatedBy}
+		if len(versions) > 0 {
+			set["updated_by"] = versions[0].CreatedBy
+			if first := versions[len(versions)-1]; first.Version == 1 && first.CreatedBy != "" {
+				set["created_by"] = first.CreatedBy
+			}
+		}
+		if doc.Status == models.DocumentStatusApproved && doc.ApprovedAt == nil {
+			set["approved_at"] = doc.UpdatedAt
+			if approving := models.ApprovingVersion(versions); approving != nil {
+				set["approved_at"] = approving.CreatedAt
+			}
+		}
+
+		oid, err := primitive.ObjectIDFromHex(doc.ID)
+		if err != nil {
+			continue
+		}
+		if _, err := r.documents.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set}); err != nil {
+			return filled, fmt.Errorf("failed to backfill document %s: %w", doc.ID, err)
+		}
+		filled++
+	}
+	return filled, cursor.Err()
+}
@@ -0,0 +1,184 @@
+// Package mongo internal/repository/mongo/folder_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type FolderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFolderRepository(db *mongo.Database) *FolderRepository {
+	repo := &FolderRepository{
+		collection: db.Collection("folders"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create folder indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *FolderRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "parent_id", Value: 1},
+				{Key: "position", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create folder indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) error {
+	folder.CreatedAt = time.Now()
+	folder.UpdatedAt = time.Now()
+	folder.OrgID = orgFor(ctx, folder.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, folder)
+	if err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		folder.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *FolderRepository) GetByID(ctx context.Context, id string) (*models.Folder, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrFolderNotFound
+	}
+
+	var folder models.Folder
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&folder)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrFolderNotFound
+		}
+		return nil, err
+	}
+
+	return &folder, nil
+}
+
+func (r *FolderRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Folder, error) {
+	cursor, err := r.collection.Find(ctx,
+		scoped(ctx, bson.M{"project_id": projectID}),
+		options.Find().SetSort(bson.D{
+			{Key: "parent_id", Value: 1},
+			{Key: "position", Value: 1},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	folders := make([]*models.Folder, 0)
+	if err = cursor.All(ctx, &folders); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+func (r *FolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	oid, err := primitive.ObjectIDFromHex(folder.ID)
+	if err != nil {
+		return errs.ErrFolderNotFound
+	}
+
+	folder.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"name":       folder.Name,
+			"parent_id":  folder.ParentID,
+			"position":   folder.Position,
+			"updated_at": folder.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+func (r *FolderRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrFolderNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+func (r *FolderRepository) SetPositions(ctx context.Context, folderIDs []string) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(folderIDs))
+	for position, id := range folderIDs {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return errs.ErrFolderNotFound
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(scoped(ctx, bson.M{"_id": oid})).
+			SetUpdate(bson.M{"$set": bson.M{"position": position, "updated_at": now}}))
+	}
+
+	if _, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to reorder folders: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FolderRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
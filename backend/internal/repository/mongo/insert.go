@@ -0,0 +1,51 @@
+// Package mongo internal/repository/mongo/insert.go
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// insertWithID inserts a record and returns its ID. Records that arrive with
+// an ID, such as copies whose links were rewritten before they were saved,
+// keep it as their ObjectID; the others get a new one.
+func insertWithID(ctx context.Context, collection *mongo.Collection, record interface{}, id string) (string, error) {
+	if id == "" {
+		result, err := collection.InsertOne(ctx, record)
+		if err != nil {
+			return "", err
+		}
+		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+			return oid.Hex(), nil
+		}
+		return "", fmt.Errorf("inserted ID is not an ObjectID: %v", result.InsertedID)
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid ID %q: %w", id, err)
+	}
+
+	// Models carry their ID as a string, which would be stored as one
+	raw, err := bson.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+	for i := range doc {
+		if doc[i].Key == "_id" {
+			doc[i].Value = oid
+		}
+	}
+
+	if _, err := collection.InsertOne(ctx, doc); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,188 @@
+// Package mongo internal/repository/mongo/invitation_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type InvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInvitationRepository(db *mongo.Database) *InvitationRepository {
+	repo := &InvitationRepository{
+		collection: db.Collection("invitations"),
+	}
+
+	// Ensure indexes
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create invitation indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *InvitationRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "team_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		// One pending invitation per address and project or team, so two
+		// concurrent invites cannot both get through
+		{
+			Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"status":     models.InvitationStatusPending,
+				"project_id": bson.M{"$exists": true},
+			}),
+		},
+		{
+			Keys: bson.D{{Key: "team_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"status":  models.InvitationStatusPending,
+				"team_id": bson.M{"$exists": true},
+			}),
+		},
+	})
+	return err
+}
+
+// Create stores a pending invitation. It returns errors.ErrInvitationExists
+// if the address already has an open one for the same project or team.
+
+func (r *InvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	invitation.CreatedAt = time.Now()
+	invitation.UpdatedAt = time.Now()
+	invitation.OrgID = orgFor(ctx, invitation.OrgID)
+
+	// An expired invitation is still pending as far as the unique index is
+	// concerned; close it so the address can be invited again
+	target := bson.M{"team_id": invitation.TeamID}
+	if invitation.ProjectID != "" {
+		target = bson.M{"project_id": invitation.ProjectID}
+	}
+	target["email"] = invitation.Email
+	target["status"] = models.InvitationStatusPending
+	target["expires_at"] = bson.M{"$lte": invitation.CreatedAt}
+	if _, err := r.collection.UpdateMany(ctx, scoped(ctx, target), bson.M{"$set": bson.M{
+		"status":     models.InvitationStatusExpired,
+		"updated_at": invitation.CreatedAt,
+	}}); err != nil {
+		return fmt.Errorf("failed to expire old invitations: %w", err)
+	}
+
+	result, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errs.ErrInvitationExists
+		}
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		invitation.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *InvitationRepository) GetByID(ctx context.Context, id string) (*models.Invitation, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrNotFound
+	}
+
+	var invitation models.Invitation
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetPendingByProject(ctx context.Context, projectID string) ([]*models.Invitation, error) {
+	return r.findPending(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *InvitationRepository) GetPendingByTeam(ctx context.Context, teamID string) ([]*models.Invitation, error) {
+	return r.findPending(ctx, bson.M{"team_id": teamID})
+}
+
+// findPending returns the unexpired pending invitations matching filter,
+// newest first
+func (r *InvitationRepository) findPending(ctx context.Context, filter bson.M) ([]*models.Invitation, error) {
+	filter["status"] = models.InvitationStatusPending
+	filter["expires_at"] = bson.M{"$gt": time.Now()}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, filter), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invitations: %w", err)
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	var invitations []*models.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to parse invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// Respond moves a pending invitation to its final status. It returns
+// errors.ErrNotFound if the invitation is no longer pending, so two
+// concurrent responses cannot both succeed.
+func (r *InvitationRepository) Respond(ctx context.Context, id string, status models.InvitationStatus, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"status":       status,
+		"responded_at": now,
+		"updated_at":   now,
+	}
+	if userID != "" {
+		set["responded_by"] = userID
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		scoped(ctx, bson.M{"_id": oid, "status": models.InvitationStatusPending}),
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+// Package mongo internal/repository/mongo/link_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type LinkRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLinkRepository(db *mongo.Database) *LinkRepository {
+	repo := &LinkRepository{
+		collection: db.Collection("links"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create link indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *LinkRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "source_type", Value: 1},
+				{Key: "source_id", Value: 1},
+				{Key: "target_type", Value: 1},
+				{Key: "target_id", Value: 1},
+				{Key: "type", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "target_type", Value: 1},
+				{Key: "target_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "broken", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create link indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *LinkRepository) Create(ctx context.Context, link *models.Link) error {
+	link.CreatedAt = time.Now()
+	link.OrgID = orgFor(ctx, link.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, link)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errs.ErrLinkExists
+		}
+		return fmt.Errorf("failed to create link: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		link.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *LinkRepository) GetByID(ctx context.Context, id string) (*models.Link, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrLinkNotFound
+	}
+
+	var link models.Link
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrLinkNotFound
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *LinkRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrLinkNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrLinkNotFound
+	}
+
+	return nil
+}
+
+func (r *LinkRepository) GetBySource(ctx context.Context, sourceType models.LinkItemType, sourceID string) ([]*models.Link, error) {
+	return r.find(ctx, bson.M{
+		"source_type": sourceType,
+		"source_id":   sourceID,
+	})
+}
+
+func (r *LinkRepository) GetByTarget(ctx context.Context, targetType models.LinkItemType, targetID string) ([]*models.Link, error) {
+	return r.find(ctx, bson.M{
+		"target_type": targetType,
+		"target_id":   targetID,
+	})
+}
+
+func (r *LinkRepository) GetBrokenByProject(ctx context.Context, projectID string) ([]*models.Link, error) {
+	return r.find(ctx, bson.M{
+		"project_id": projectID,
+		"broken":     true,
+	})
+}
+
+func (r *LinkRepository) ReplaceExtracted(ctx context.Context, sourceType models.LinkItemType, sourceID string, links []*models.Link) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{
+		"source_type": sourceType,
+		"source_id":   sourceID,
+		"extracted":   true,
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to clear extracted links: %w", err)
+	}
+
+	for _, link := range links {
+		link.Extracted = true
+		if err := r.Create(ctx, link); err != nil {
+			// A manual link of the same type already covers this reference
+			if errors.Is(err, errs.ErrLinkExists) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *LinkRepository) MarkTargetBroken(ctx context.Context, targetType models.LinkItemType, targetIDs ...string) error {
+	if len(targetIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, bson.M{
+			"target_type": targetType,
+			"target_id":   bson.M{"$in": targetIDs},
+			"broken":      false,
+		}),
+		bson.M{"$set": bson.M{"broken": true, "broken_at": now}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark links broken: %w", err)
+	}
+	return nil
+}
+
+func (r *LinkRepository) MarkTargetRestored(ctx context.Context, targetType models.LinkItemType, targetIDs ...string) error {
+	if len(targetIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, bson.M{
+			"target_type": targetType,
+			"target_id":   bson.M{"$in": targetIDs},
+			"broken":      true,
+		}),
+		bson.M{
+			"$set":   bson.M{"broken": false},
+			"$unset": bson.M{"broken_at": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore links: %w", err)
+	}
+	return nil
+}
+
+func (r *LinkRepository) DeleteBySource(ctx context.Context, sourceType models.LinkItemType, sourceID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{
+		"source_type": sourceType,
+		"source_id":   sourceID,
+	}))
+	return err
+}
+
+func (r *LinkRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
+
+func (r *LinkRepository) find(ctx context.Context, filter bson.M) ([]*models.Link, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, filter), options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	links := make([]*models.Link, 0)
+	if err = cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
@@ -0,0 +1,187 @@
+// Package mongo internal/repository/mongo/milestone_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type MilestoneRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMilestoneRepository(db *mongo.Database) *MilestoneRepository {
+	repo := &MilestoneRepository{
+		collection: db.Collection("milestones"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create milestone indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *MilestoneRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "due_date", Value: 1},
+			},
+		},
+		{
+			// Overdue lookups only look at open milestones
+			Keys: bson.D{
+				{Key: "completed", Value: 1},
+				{Key: "due_date", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create milestone indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *MilestoneRepository) Create(ctx context.Context, milestone *models.Milestone) error {
+	milestone.CreatedAt = time.Now()
+	milestone.UpdatedAt = time.Now()
+	milestone.OrgID = orgFor(ctx, milestone.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, milestone)
+	if err != nil {
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		milestone.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *MilestoneRepository) GetByID(ctx context.Context, id string) (*models.Milestone, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrMilestoneNotFound
+	}
+
+	var milestone models.Milestone
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&milestone)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrMilestoneNotFound
+		}
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+func (r *MilestoneRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Milestone, error) {
+	return r.find(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *MilestoneRepository) GetOpenDueBefore(ctx context.Context, projectIDs []string, cutoff time.Time) ([]*models.Milestone, error) {
+	if len(projectIDs) == 0 {
+		return []*models.Milestone{}, nil
+	}
+
+	return r.find(ctx, bson.M{
+		"project_id": bson.M{"$in": projectIDs},
+		"completed":  false,
+		"due_date":   bson.M{"$lt": cutoff},
+	})
+}
+
+func (r *MilestoneRepository) find(ctx context.Context, filter bson.M) ([]*models.Milestone, error) {
+	cursor, err := r.collection.Find(ctx,
+		scoped(ctx, filter),
+		options.Find().SetSort(bson.D{
+			{Key: "due_date", Value: 1},
+			{Key: "title", Value: 1},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	milestones := make([]*models.Milestone, 0)
+	if err = cursor.All(ctx, &milestones); err != nil {
+		return nil, err
+	}
+
+	return milestones, nil
+}
+
+func (r *MilestoneRepository) Update(ctx context.Context, milestone *models.Milestone) error {
+	oid, err := primitive.ObjectIDFromHex(milestone.ID)
+	if err != nil {
+		return errs.ErrMilestoneNotFound
+	}
+
+	milestone.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"title":        milestone.Title,
+			"description":  milestone.Description,
+			"due_date":     milestone.DueDate,
+			"owner_id":     milestone.OwnerID,
+			"completed":    milestone.Completed,
+			"completed_at": milestone.CompletedAt,
+			"completed_by": milestone.CompletedBy,
+			"checklist":    milestone.Checklist,
+			"updated_at":   milestone.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrMilestoneNotFound
+	}
+
+	return nil
+}
+
+func (r *MilestoneRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrMilestoneNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrMilestoneNotFound
+	}
+
+	return nil
+}
+
+func (r *MilestoneRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
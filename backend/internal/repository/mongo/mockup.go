@@ -3,14 +3,15 @@ package mongo
 
 import (
 	"context"
-	"time"
-
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-
+	"log"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository"
+	"time"
 )
 
 type mockupRepository struct {
@@ -18,24 +19,53 @@ type mockupRepository struct {
 }
 
 func NewMockupRepository(db *mongo.Database) repository.MockupRepository {
-	return &mockupRepository{
+	repo := &mockupRepository{
 		collection: db.Collection("mockups"),
 	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create mockup indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *mockupRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "updated_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "type", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create mockup indexes: %w", err)
+	}
+	return nil
 }
 
 func (r *mockupRepository) Create(ctx context.Context, mockup *models.Mockup) error {
 	now := time.Now()
 	mockup.CreatedAt = now
 	mockup.UpdatedAt = now
+	mockup.OrgID = orgFor(ctx, mockup.OrgID)
 
-	result, err := r.collection.InsertOne(ctx, mockup)
+	id, err := insertWithID(ctx, r.collection, mockup, mockup.ID)
 	if err != nil {
 		return err
 	}
-
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		mockup.ID = oid.Hex()
-	}
+	mockup.ID = id
 
 	return nil
 }
@@ -48,7 +78,7 @@ func (r *mockupRepository) GetByID(ctx context.Context, id string) (*models.Mock
 		return nil, err
 	}
 
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mockup)
+	err = r.collection.FindOne(ctx, scoped(ctx, active(bson.M{"_id": oid}))).Decode(&mockup)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +89,7 @@ func (r *mockupRepository) GetByID(ctx context.Context, id string) (*models.Mock
 func (r *mockupRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Mockup, error) {
 	var mockups []*models.Mockup
 
-	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectID})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, active(bson.M{"project_id": projectID})))
 	if err != nil {
 		return nil, err
 	}
@@ -80,12 +110,20 @@ func (r *mockupRepository) Update(ctx context.Context, mockup *models.Mockup) er
 		return err
 	}
 
-	_, err = r.collection.ReplaceOne(
+	result, err := r.collection.ReplaceOne(
 		ctx,
-		bson.M{"_id": oid},
+		scoped(ctx, active(bson.M{"_id": oid})),
 		mockup,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
 }
 
 func (r *mockupRepository) Delete(ctx context.Context, id string) error {
@@ -94,14 +132,116 @@ func (r *mockupRepository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	_, err = r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
 	return err
 }
 
 func (r *mockupRepository) List(ctx context.Context) ([]*models.Mockup, error) {
 	var mockups []*models.Mockup
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, active(bson.M{})))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &mockups); err != nil {
+		return nil, err
+	}
+
+	return mockups, nil
+}
+
+func (r *mockupRepository) FindPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Mockup], error) {
+	query := active(bson.M{"project_id": bson.M{"$in": projectIDs}})
+	return paginate[models.Mockup](ctx, r.collection, scoped(ctx, applyPageFilter(query, q.Filter)), q)
+}
+
+func (r *mockupRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, active(bson.M{"_id": oid})), softDeleteUpdate(deletedBy, false))
+	if err != nil {
+		return fmt.Errorf("failed to delete mockup: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *mockupRepository) SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error {
+	_, err := r.collection.UpdateMany(ctx, scoped(ctx, active(bson.M{"project_id": projectID})), softDeleteUpdate(deletedBy, true))
+	return err
+}
+
+func (r *mockupRepository) Restore(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid})), restoreUpdate())
+	if err != nil {
+		return fmt.Errorf("failed to restore mockup: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *mockupRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, trashed(bson.M{"project_id": projectID, "cascade_deleted": true})),
+		restoreUpdate(),
+	)
+	return err
+}
+
+func (r *mockupRepository) GetDeletedByID(ctx context.Context, id string) (*models.Mockup, error) {
+	var mockup models.Mockup
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.collection.FindOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid}))).Decode(&mockup)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mockup, nil
+}
+
+func (r *mockupRepository) GetDeletedByProject(ctx context.Context, projectID string) ([]*models.Mockup, error) {
+	return r.find(ctx, trashed(bson.M{
+		"project_id":      projectID,
+		"cascade_deleted": bson.M{"$ne": true},
+	}))
+}
+
+func (r *mockupRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Mockup, error) {
+	return r.find(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+}
+
+func (r *mockupRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
+
+func (r *mockupRepository) find(ctx context.Context, filter bson.M) ([]*models.Mockup, error) {
+	mockups := make([]*models.Mockup, 0)
+
+	cursor, err := r.collection.Find(ctx, scoped(ctx, filter))
 	if err != nil {
 		return nil, err
 	}
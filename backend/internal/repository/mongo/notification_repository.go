@@ -0,0 +1,129 @@
+// Package mongo internal/repository/mongo/notification_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"time"
+)
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	repo := &NotificationRepository{
+		collection: db.Collection("notifications"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create notification indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *NotificationRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			// Unread counts are read on every page load
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "read", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	notification.CreatedAt = time.Now()
+	notification.OrgID = orgFor(ctx, notification.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		notification.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) FindPageByUser(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Notification], error) {
+	filter := bson.M{"user_id": userID}
+	if q.Filter.Type != "" {
+		filter["type"] = q.Filter.Type
+	}
+	if q.Filter.Unread {
+		filter["read"] = false
+	}
+
+	page, err := paginate[models.Notification](ctx, r.collection, scoped(ctx, filter), q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+	}
+	return page, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, scoped(ctx, bson.M{"user_id": userID, "read": false}))
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id string, userID string, at time.Time) (*models.Notification, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrNotificationNotFound
+	}
+
+	// Notifications read before keep the time they were first read
+	var notification models.Notification
+	err = r.collection.FindOneAndUpdate(ctx,
+		scoped(ctx, bson.M{"_id": oid, "user_id": userID}),
+		bson.A{bson.M{"$set": bson.M{
+			"read":    true,
+			"read_at": bson.M{"$ifNull": bson.A{"$read_at", at}},
+		}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&notification)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrNotificationNotFound
+		}
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, bson.M{"user_id": userID, "read": false}),
+		bson.M{"$set": bson.M{"read": true, "read_at": at}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
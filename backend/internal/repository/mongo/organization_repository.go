@@ -0,0 +1,244 @@
+// Package mongo internal/repository/mongo/organization_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+// OrganizationRepository stores the organizations themselves, which are not
+// scoped to a tenant
+type OrganizationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewOrganizationRepository(db *mongo.Database) *OrganizationRepository {
+	repo := &OrganizationRepository{
+		db:         db,
+		collection: db.Collection("organizations"),
+	}
+
+	// Ensure indexes
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create organization indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *OrganizationRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "members.user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "default", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	org.CreatedAt = time.Now()
+	org.UpdatedAt = time.Now()
+	if org.Members == nil {
+		org.Members = []models.OrgMembership{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, org)
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		org.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrNotFound
+	}
+
+	return r.findOne(ctx, bson.M{"_id": oid})
+}
+
+func (r *OrganizationRepository) GetDefault(ctx context.Context) (*models.Organization, error) {
+	return r.findOne(ctx, bson.M{"default": true})
+}
+
+func (r *OrganizationRepository) findOne(ctx context.Context, filter bson.M) (*models.Organization, error) {
+	var org models.Organization
+	err := r.collection.FindOne(ctx, filter).Decode(&org)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch organization: %w", err)
+	}
+
+	return &org, nil
+}
+
+// Update saves the organization's details; members are changed through
+// AddMember, UpdateMemberRole and RemoveMember
+func (r *OrganizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	org.UpdatedAt = time.Now()
+
+	oid, err := primitive.ObjectIDFromHex(org.ID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": oid},
+		bson.M{"$set": bson.M{
+			"name":       org.Name,
+			"updated_at": org.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByMember returns the organizations a user belongs to, oldest first
+func (r *OrganizationRepository) GetByMember(ctx context.Context, userID string) ([]*models.Organization, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"members.user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	var orgs []*models.Organization
+	if err := cursor.All(ctx, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to parse organization records: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// AddMember appends a membership unless the user already belongs to the
+// organization
+func (r *OrganizationRepository) AddMember(ctx context.Context, orgID string, member models.OrgMembership) error {
+	oid, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	var before bson.M
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": oid, "members.user_id": bson.M{"$ne": member.UserID}}, update).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.missingOrgOr(ctx, oid, errs.ErrAlreadyInOrg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+	// Joining with a project or team is undone with it
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
+
+	return nil
+}
+
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID string, role models.OrgRole) error {
+	oid, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": oid, "members.user_id": userID},
+		bson.M{"$set": bson.M{
+			"members.$.role": role,
+			"updated_at":     time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return r.missingOrgOr(ctx, oid, errs.ErrNotInOrg)
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": oid, "members.user_id": userID},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"user_id": userID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return r.missingOrgOr(ctx, oid, errs.ErrNotInOrg)
+	}
+
+	return nil
+}
+
+// CountOrphans counts the records created before organizations existed
+func (r *OrganizationRepository) CountOrphans(ctx context.Context) (int64, error) {
+	return countOrphans(ctx, r.db)
+}
+
+// AdoptOrphans moves the records created before organizations existed into
+// the organization
+func (r *OrganizationRepository) AdoptOrphans(ctx context.Context, orgID string) error {
+	return adoptOrphans(ctx, r.db, orgID)
+}
+
+// missingOrgOr explains a member update that matched nothing: either the
+// organization does not exist or the membership condition failed
+func (r *OrganizationRepository) missingOrgOr(ctx context.Context, oid primitive.ObjectID, memberErr error) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return fmt.Errorf("failed to fetch organization: %w", err)
+	}
+	if count == 0 {
+		return errs.ErrNotFound
+	}
+	return memberErr
+}
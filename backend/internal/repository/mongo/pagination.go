@@ -0,0 +1,155 @@
+// Package mongo internal/repository/mongo/pagination.go
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/pagination"
+)
+
+// applyPageFilter adds the generic list filters to a query filter
+func applyPageFilter(filter bson.M, f pagination.Filter) bson.M {
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.Type != "" {
+		filter["type"] = f.Type
+	}
+	if f.CreatedBy != "" {
+		filter["created_by"] = f.CreatedBy
+	}
+	if f.UpdatedSince != nil {
+		filter["updated_at"] = bson.M{"$gte": *f.UpdatedSince}
+	}
+	return filter
+}
+
+// paginate runs a keyset-paginated find: items are ordered by the sort field
+// with _id as tie-breaker, and the cursor resumes strictly after the last
+// item of the previous page
+func paginate[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, q pagination.Query) (*pagination.Page[*T], error) {
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	findFilter := filter
+	if q.AfterID != "" {
+		after, err := keysetFilter(q)
+		if err != nil {
+			return nil, err
+		}
+		findFilter = bson.M{"$and": []bson.M{filter, after}}
+	}
+
+	direction := 1
+	if q.Desc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: q.Sort.Field, Value: direction},
+			{Key: "_id", Value: direction},
+		}).
+		SetLimit(int64(q.Limit + 1))
+
+	cursor, err := collection.Find(ctx, findFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	var raws []bson.Raw
+	if err := cursor.All(ctx, &raws); err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page[*T]{
+		Items: make([]*T, 0, min(len(raws), q.Limit)),
+		Total: total,
+	}
+
+	// One extra item is fetched to learn whether another page follows
+	if len(raws) > q.Limit {
+		raws = raws[:q.Limit]
+		last := raws[len(raws)-1]
+		id, _ := last.Lookup("_id").ObjectIDOK()
+		page.NextCursor = q.NextCursor(sortValue(last.Lookup(q.Sort.Field), q.Sort.Kind), id.Hex())
+	}
+
+	for _, raw := range raws {
+		item := new(T)
+		if err := bson.Unmarshal(raw, item); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+
+	return page, nil
+}
+
+// keysetFilter selects the items sorting after the cursor position
+func keysetFilter(q pagination.Query) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(q.AfterID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+	}
+
+	op := "$gt"
+	if q.Desc {
+		op = "$lt"
+	}
+	field := q.Sort.Field
+
+	// Missing and null values sort before every other value, and range
+	// operators never match them, so they get conditions of their own
+	if q.AfterValue == nil {
+		tie := bson.M{field: nil, "_id": bson.M{op: oid}}
+		if q.Desc {
+			return tie, nil
+		}
+		return bson.M{"$or": []bson.M{tie, {field: bson.M{"$ne": nil}}}}, nil
+	}
+
+	after := []bson.M{
+		{field: bson.M{op: q.AfterValue}},
+		{field: q.AfterValue, "_id": bson.M{op: oid}},
+	}
+	if q.Desc {
+		after = append(after, bson.M{field: nil})
+	}
+	return bson.M{"$or": after}, nil
+}
+
+// sortValue converts a stored sort field into the Go value used in cursors,
+// nil when the field is missing or null
+func sortValue(value bson.RawValue, kind pagination.SortKind) interface{} {
+	if value.Type == 0 || value.Type == bson.TypeNull {
+		return nil
+	}
+	switch kind {
+	case pagination.SortTime:
+		t, _ := value.TimeOK()
+		return t
+	case pagination.SortNumber:
+		if f, ok := value.DoubleOK(); ok {
+			return f
+		}
+		n, _ := value.AsInt64OK()
+		return float64(n)
+	default:
+		s, _ := value.StringValueOK()
+		return s
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"log"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"regexp"
 	"time"
 )
 
@@ -19,14 +21,55 @@ type ProjectRepository struct {
 }
 
 func NewProjectRepository(db *mongo.Database) *ProjectRepository {
-	return &ProjectRepository{
+	repo := &ProjectRepository{
 		collection: db.Collection("projects"),
 	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create project indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *ProjectRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "created_by", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "team", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "teams.team_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "owners", Value: 1}, {Key: "updated_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "updated_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create project indexes: %w", err)
+	}
+	return nil
 }
 
 func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
 	project.CreatedAt = time.Now()
 	project.UpdatedAt = time.Now()
+	project.OrgID = orgFor(ctx, project.OrgID)
 
 	result, err := r.collection.InsertOne(ctx, project)
 	if err != nil {
@@ -48,7 +91,7 @@ func (r *ProjectRepository) GetByID(ctx context.Context, id string) (*models.Pro
 	}
 
 	var project models.Project
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&project)
+	err = r.collection.FindOne(ctx, scoped(ctx, active(bson.M{"_id": oid}))).Decode(&project)
 	if err != nil {
 		return nil, err
 	}
@@ -57,12 +100,9 @@ func (r *ProjectRepository) GetByID(ctx context.Context, id string) (*models.Pro
 }
 
 func (r *ProjectRepository) GetByUser(ctx context.Context, userID string) ([]*models.Project, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"$or": []bson.M{
-			{"created_by": userID},
-			{"team": userID},
-		},
-	})
+	cursor, err := r.collection.Find(ctx, scoped(ctx, active(bson.M{
+		"$or": accessibleBy(userID),
+	})))
 	if err != nil {
 		return nil, err
 	}
@@ -81,35 +121,278 @@ func (r *ProjectRepository) GetByUser(ctx context.Context, userID string) ([]*mo
 	return projects, nil
 }
 
-func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
-	log.Printf("Updating project in repository: %+v", project)
+func (r *ProjectRepository) FindPageByUser(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Project], error) {
+	filter := applyPageFilter(active(bson.M{
+		"$or": accessibleBy(userID),
+	}), q.Filter)
 
-	oid, err := primitive.ObjectIDFromHex(project.ID)
+	// Archived projects stay out of the way unless asked for or searched
+	archived := q.Filter.Archived
+	if archived == "" && q.Filter.Search == "" {
+		archived = pagination.ArchivedExclude
+	}
+	switch archived {
+	case pagination.ArchivedExclude:
+		filter["archived_at"] = nil
+	case pagination.ArchivedOnly:
+		filter["archived_at"] = bson.M{"$ne": nil}
+	}
+	if q.Filter.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q.Filter.Search), Options: "i"}
+		filter["$and"] = bson.A{bson.M{"$or": bson.A{
+			bson.M{"name": pattern},
+			bson.M{"description": pattern},
+		}}}
+	}
+
+	return paginate[models.Project](ctx, r.collection, scoped(ctx, filter), q)
+}
+
+// editableFields are the fields UpdateFields may write. Team, owners and
+// transfers have writes of their own.
+var editableFields = map[string]bool{
+	"name":               true,
+	"description":        true,
+	"status":             true,
+	"progress":           true,
+	"start_date":         true,
+	"end_date":           true,
+	"progress_settings":  true,
+	"progress_breakdown": true,
+}
+
+func (r *ProjectRepository) UpdateFields(ctx context.Context, project *models.Project, fields ...string) error {
+	// The stored form of the project gives each field its stored value
+	raw, err := bson.Marshal(project)
 	if err != nil {
-		return fmt.Errorf("invalid project ID: %w", err)
+		return fmt.Errorf("failed to encode project: %w", err)
+	}
+	var stored bson.M
+	if err := bson.Unmarshal(raw, &stored); err != nil {
+		return fmt.Errorf("failed to encode project: %w", err)
 	}
 
 	project.UpdatedAt = time.Now()
+	set := bson.M{"updated_at": project.UpdatedAt}
+	unset := bson.M{}
+	for _, field := range fields {
+		if !editableFields[field] {
+			return fmt.Errorf("project field %q cannot be updated", field)
+		}
+		if value, ok := stored[field]; ok {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
 
-	updateDoc := bson.M{
-		"$set": bson.M{
-			"name":        project.Name,
-			"description": project.Description,
-			"status":      project.Status,
-			"progress":    project.Progress,
-			"team":        project.Team,
-			"updated_at":  project.UpdatedAt,
+	return r.updateUnarchived(ctx, project.ID, bson.M{}, update, errs.ErrProjectNotFound)
+}
+
+// ownersAre matches projects whose stored owners are exactly the given ones.
+// Projects from before owners were stored have none.
+func ownersAre(owners []string) interface{} {
+	if len(owners) == 0 {
+		return bson.M{"$in": bson.A{nil, bson.A{}}}
+	}
+	return owners
+}
+
+func (r *ProjectRepository) SetOwners(ctx context.Context, id string, previous, owners []string) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"owners": ownersAre(previous)},
+		bson.M{"$set": bson.M{"owners": owners, "updated_at": time.Now()}},
+		errs.ErrVersionConflict,
+	)
+}
+
+func (r *ProjectRepository) RequestTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"pending_transfer": nil},
+		bson.M{"$set": bson.M{"pending_transfer": transfer, "updated_at": time.Now()}},
+		errs.ErrTransferPending,
+	)
+}
+
+func (r *ProjectRepository) ClearTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrProjectNotFound
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"pending_transfer": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+	var before bson.M
+	err = r.collection.FindOneAndUpdate(ctx,
+		scoped(ctx, active(bson.M{
+			"_id":                   oid,
+			"pending_transfer.from": transfer.From,
+			"pending_transfer.to":   transfer.To,
+		})),
+		update,
+	).Decode(&before)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errs.ErrNoTransfer
+		}
+		return fmt.Errorf("failed to clear ownership transfer: %w", err)
+	}
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
+
+	return nil
+}
+
+// SetProgress only writes while progress is still computed, so a switch to
+// manual progress made since the computation started is kept
+func (r *ProjectRepository) SetProgress(ctx context.Context, id string, progress int, breakdown *models.ProgressBreakdown) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrProjectNotFound
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		scoped(ctx, active(bson.M{"_id": oid, "progress_settings.mode": models.ProgressModeAuto})),
+		bson.M{"$set": bson.M{
+			"progress":           progress,
+			"progress_breakdown": breakdown,
+			"updated_at":         time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update project progress: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) AddToTeam(ctx context.Context, id string, userID string) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"team": bson.M{"$ne": userID}},
+		bson.M{
+			"$push": bson.M{"team": userID},
+			"$set":  bson.M{"updated_at": time.Now()},
 		},
+		errs.ErrAlreadyInTeam,
+	)
+}
+
+func (r *ProjectRepository) UpdateTeamList(ctx context.Context, id string, added, removed []string) error {
+	// One update cannot both add to and remove from the same list
+	if len(added) > 0 {
+		update := bson.M{
+			"$addToSet": bson.M{"team": bson.M{"$each": added}},
+			"$set":      bson.M{"updated_at": time.Now()},
+		}
+		if err := r.updateUnarchived(ctx, id, bson.M{}, update, errs.ErrProjectNotFound); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		update := bson.M{
+			"$pull": bson.M{"team": bson.M{"$in": removed}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		}
+		if err := r.updateUnarchived(ctx, id, bson.M{}, update, errs.ErrProjectNotFound); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+func (r *ProjectRepository) AssignTeam(ctx context.Context, id string, assignment models.ProjectTeamAssignment) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"teams.team_id": bson.M{"$ne": assignment.TeamID}},
+		bson.M{
+			"$push": bson.M{"teams": assignment},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		errs.ErrTeamAssigned,
+	)
+}
 
-	result, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": oid},
-		updateDoc,
+func (r *ProjectRepository) UpdateTeamRole(ctx context.Context, id string, teamID string, role models.TeamRole) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"teams.team_id": teamID},
+		bson.M{"$set": bson.M{"teams.$.role": role, "updated_at": time.Now()}},
+		errs.ErrTeamNotAssigned,
+	)
+}
+
+func (r *ProjectRepository) UnassignTeam(ctx context.Context, id string, teamID string) error {
+	return r.updateUnarchived(ctx, id,
+		bson.M{"teams.team_id": teamID},
+		bson.M{
+			"$pull": bson.M{"teams": bson.M{"team_id": teamID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		errs.ErrTeamNotAssigned,
 	)
+}
+
+// updateUnarchived applies update to an unarchived project that meets the
+// conditions, and registers putting the fields it wrote back should the unit
+// of work fail. When none matches, it tells a missing or archived project
+// apart and otherwise returns unmatched.
+func (r *ProjectRepository) updateUnarchived(ctx context.Context, id string, conditions bson.M, update bson.M, unmatched error) error {
+	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Printf("Failed to update project: %v", err)
-		return err
+		return errs.ErrProjectNotFound
+	}
+
+	conditions["_id"] = oid
+	conditions["archived_at"] = nil
+	var before bson.M
+	err = r.collection.FindOneAndUpdate(ctx, scoped(ctx, active(conditions)), update).Decode(&before)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	if err != nil {
+		project, err := r.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return errs.ErrProjectNotFound
+			}
+			return err
+		}
+		if project.IsArchived() {
+			return errs.ErrProjectArchived
+		}
+		return unmatched
+	}
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
+
+	return nil
+}
+
+func (r *ProjectRepository) Archive(ctx context.Context, id string, archivedBy string, at time.Time) error {
+	return r.setArchived(ctx, id, bson.M{
+		"$set": bson.M{"archived_at": at, "archived_by": archivedBy, "updated_at": at},
+	})
+}
+
+func (r *ProjectRepository) Unarchive(ctx context.Context, id string) error {
+	return r.setArchived(ctx, id, bson.M{
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"archived_at": "", "archived_by": ""},
+	})
+}
+
+func (r *ProjectRepository) setArchived(ctx context.Context, id string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrProjectNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, active(bson.M{"_id": oid})), update)
+	if err != nil {
+		return fmt.Errorf("failed to archive project: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
@@ -125,12 +408,80 @@ func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	_, err = r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
 	return err
 }
 
+func (r *ProjectRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrProjectNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, active(bson.M{"_id": oid})), softDeleteUpdate(deletedBy, false))
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) Restore(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrProjectNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid})), restoreUpdate())
+	if err != nil {
+		return fmt.Errorf("failed to restore project: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) GetDeletedByID(ctx context.Context, id string) (*models.Project, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrProjectNotFound
+	}
+
+	var project models.Project
+	err = r.collection.FindOne(ctx, scoped(ctx, trashed(bson.M{"_id": oid}))).Decode(&project)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *ProjectRepository) GetDeletedByUser(ctx context.Context, userID string) ([]*models.Project, error) {
+	return r.List(ctx, trashed(bson.M{"$or": ownedBy(userID)}))
+}
+
+// GetByAssignedTeam returns the active projects a team is assigned to
+func (r *ProjectRepository) GetByAssignedTeam(ctx context.Context, teamID string) ([]*models.Project, error) {
+	return r.List(ctx, active(bson.M{"teams.team_id": teamID}))
+}
+
+func (r *ProjectRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Project, error) {
+	return r.List(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+}
+
 func (r *ProjectRepository) List(ctx context.Context, filter interface{}) ([]*models.Project, error) {
-	cursor, err := r.collection.Find(ctx, filter)
+	// The filter may be any query document, so the tenant condition goes next to it
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": bson.A{filter, scoped(ctx, bson.M{})}})
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +511,7 @@ func (r *ProjectRepository) CheckUserAccess(ctx context.Context, projectID strin
 	}
 
 	var project models.Project
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&project)
+	err = r.collection.FindOne(ctx, scoped(ctx, active(bson.M{"_id": oid}))).Decode(&project)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			log.Printf("Project not found: %s", projectID)
@@ -170,16 +521,19 @@ func (r *ProjectRepository) CheckUserAccess(ctx context.Context, projectID strin
 		return false, err
 	}
 
-	// Check if user is project creator or team member
-	if project.CreatedBy == userID {
-		return true, nil
-	}
+	return project.HasAccess(userID), nil
+}
 
-	for _, memberID := range project.Team {
-		if memberID == userID {
-			return true, nil
-		}
+// ownedBy matches the projects a user owns, treating the creator as owner of
+// projects without stored owners
+func ownedBy(userID string) []bson.M {
+	return []bson.M{
+		{"owners": userID},
+		{"created_by": userID, "owners": bson.M{"$in": bson.A{nil, bson.A{}}}},
 	}
+}
 
-	return false, nil
+// accessibleBy matches the projects a user owns or is on the team of
+func accessibleBy(userID string) []bson.M {
+	return append(ownedBy(userID), bson.M{"team": userID})
 }
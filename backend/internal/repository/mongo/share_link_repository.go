@@ -0,0 +1,147 @@
+// Package mongo internal/repository/mongo/share_link_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type ShareLinkRepository struct {
+	collection *mongo.Collection
+}
+
+func NewShareLinkRepository(db *mongo.Database) *ShareLinkRepository {
+	repo := &ShareLinkRepository{
+		collection: db.Collection("share_links"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create share link indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *ShareLinkRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create share link indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *ShareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	link.CreatedAt = time.Now()
+	link.UpdatedAt = time.Now()
+	link.OrgID = orgFor(ctx, link.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, link)
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		link.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *ShareLinkRepository) GetByID(ctx context.Context, id string) (*models.ShareLink, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrShareLinkNotFound
+	}
+	return r.findOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+}
+
+func (r *ShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.ShareLink, error) {
+	return r.findOne(ctx, bson.M{"token_hash": tokenHash})
+}
+
+func (r *ShareLinkRepository) findOne(ctx context.Context, filter bson.M) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := r.collection.FindOne(ctx, filter).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) GetByProject(ctx context.Context, projectID string) ([]*models.ShareLink, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"project_id": projectID}), options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	links := []*models.ShareLink{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *ShareLinkRepository) Revoke(ctx context.Context, id string, revokedBy string, at time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrShareLinkNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"revoked_at": at,
+			"revoked_by": revokedBy,
+			"updated_at": at,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrShareLinkNotFound
+	}
+
+	return nil
+}
+
+func (r *ShareLinkRepository) RecordView(ctx context.Context, id string, at time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrShareLinkNotFound
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$inc": bson.M{"views": 1},
+		"$set": bson.M{"last_viewed_at": at},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count share link view: %w", err)
+	}
+	return nil
+}
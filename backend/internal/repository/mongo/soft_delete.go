@@ -0,0 +1,45 @@
+// Package mongo internal/repository/mongo/soft_delete.go
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+// active adds the soft delete condition to a filter so trashed records are
+// skipped. A nil deleted_at also matches records written before soft delete.
+func active(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// trashed adds the condition selecting soft-deleted records to a filter
+func trashed(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$ne": nil}
+	return filter
+}
+
+// softDeleteUpdate marks records as deleted; cascade records that they were
+// trashed along with their project
+func softDeleteUpdate(deletedBy string, cascade bool) bson.M {
+	set := bson.M{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	}
+	if cascade {
+		set["cascade_deleted"] = true
+	}
+	return bson.M{"$set": set}
+}
+
+// restoreUpdate clears the soft delete markers
+func restoreUpdate() bson.M {
+	return bson.M{
+		"$unset": bson.M{
+			"deleted_at":      "",
+			"deleted_by":      "",
+			"cascade_deleted": "",
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+}
@@ -0,0 +1,253 @@
+// Package mongo internal/repository/mongo/stats_repository.go
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/models"
+	"time"
+)
+
+// millisPerHour turns the milliseconds between two dates into hours
+const millisPerHour = 3600000
+
+// StatsRepository computes the statistics with aggregation pipelines over
+// the collections of the other repositories
+type StatsRepository struct {
+	projects  *mongo.Collection
+	documents *mongo.Collection
+	versions  *mongo.Collection
+}
+
+func NewStatsRepository(db *mongo.Database) *StatsRepository {
+	repo := &StatsRepository{
+		projects:  db.Collection("projects"),
+		documents: db.Collection("documents"),
+		versions:  db.Collection("document_versions"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create stats indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *StatsRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.documents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "approved_at", Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create document stats indexes: %w", err)
+	}
+
+	_, err = r.versions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "document_id", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create version stats indexes: %w", err)
+	}
+	return nil
+}
+
+// bucketOf is the expression truncating a date field to the start of its
+// bucket
+func bucketOf(field string, bucket models.StatsBucket) bson.M {
+	trunc := bson.M{"date": "$" + field, "unit": string(bucket)}
+	if bucket == models.StatsBucketWeek {
+		trunc["startOfWeek"] = "monday"
+	}
+	return bson.M{"$dateTrunc": trunc}
+}
+
+// countPer is the pipeline counting records per bucket of a date field
+func countPer(field string, bucket models.StatsBucket) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": bucketOf(field, bucket), "value": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+}
+
+// countBy is the pipeline counting records per value of a field
+func countBy(field string) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+// aggregateOne runs a pipeline whose single result decodes into out
+func aggregateOne(ctx context.Context, coll *mongo.Collection, pipeline bson.A, out interface{}) error {
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	if cursor.Next(ctx) {
+		return cursor.Decode(out)
+	}
+	return cursor.Err()
+}
+
+func (r *StatsRepository) CountProjectsByStatus(ctx context.Context, projectIDs []string) ([]models.KeyCount, error) {
+	oids := make([]primitive.ObjectID, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+
+	pipeline := append(bson.A{
+		bson.M{"$match": scoped(ctx, active(bson.M{"_id": bson.M{"$in": oids}}))},
+	}, countBy("status")...)
+
+	cursor, err := r.projects.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", err)
+	}
+	counts := []models.KeyCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *StatsRepository) DocumentStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.DocumentStats, error) {
+	created := bson.M{"$match": bson.M{"created_at": bson.M{"$gte": since}}}
+	approved := bson.M{"$match": bson.M{"approved_at": bson.M{"$gte": since}}}
+	hours := func(op string) bson.M {
+		return bson.M{op: bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{"$approved_at", "$created_at"}},
+			millisPerHour,
+		}}}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": scoped(ctx, active(bson.M{"project_id": bson.M{"$in": projectIDs}}))},
+		bson.M{"$facet": bson.M{
+			"by_status": countBy("status"),
+			"by_type":   countBy("type"),
+			"created":   append(bson.A{created}, countPer("created_at", bucket)...),
+			"approved":  append(bson.A{approved}, countPer("approved_at", bucket)...),
+			"lead_time": bson.A{approved, bson.M{"$group": bson.M{
+				"_id":           nil,
+				"approvals":     bson.M{"$sum": 1},
+				"average_hours": hours("$avg"),
+				"min_hours":     hours("$min"),
+				"max_hours":     hours("$max"),
+			}}},
+		}},
+	}
+
+	var result struct {
+		ByStatus []models.KeyCount         `bson:"by_status"`
+		ByType   []models.KeyCount         `bson:"by_type"`
+		Created  []models.TrendPoint       `bson:"created"`
+		Approved []models.TrendPoint       `bson:"approved"`
+		LeadTime []models.ApprovalLeadTime `bson:"lead_time"`
+	}
+	if err := aggregateOne(ctx, r.documents, pipeline, &result); err != nil {
+		return nil, fmt.Errorf("failed to compute document statistics: %w", err)
+	}
+
+	// Authors are counted from first versions, which edits never rewrite
+	documentIDs, err := r.documentIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	authors := append(bson.A{
+		bson.M{"$match": scoped(ctx, bson.M{
+			"document_id": bson.M{"$in": documentIDs},
+			"version":     1,
+			"created_at":  bson.M{"$gte": since},
+		})},
+	}, countBy("created_by")...)
+	cursor, err := r.versions.Aggregate(ctx, authors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count document authors: %w", err)
+	}
+	creators := []models.KeyCount{}
+	if err := cursor.All(ctx, &creators); err != nil {
+		return nil, fmt.Errorf("failed to count document authors: %w", err)
+	}
+
+	stats := &models.DocumentStats{
+		ByStatus: result.ByStatus,
+		ByType:   result.ByType,
+		Created:  result.Created,
+		Approved: result.Approved,
+		Creators: creators,
+	}
+	if len(result.LeadTime) > 0 {
+		stats.LeadTime = result.LeadTime[0]
+	}
+	return stats, nil
+}
+
+// documentIDs returns the active documents of the projects. Versions only
+// know their document, so statistics over versions look them up first.
+func (r *StatsRepository) documentIDs(ctx context.Context, projectIDs []string) ([]string, error) {
+	values, err := r.documents.Distinct(ctx, "_id", scoped(ctx, active(bson.M{"project_id": bson.M{"$in": projectIDs}})))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	documentIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if oid, ok := value.(primitive.ObjectID); ok {
+			documentIDs = append(documentIDs, oid.Hex())
+		}
+	}
+	return documentIDs, nil
+}
+
+func (r *StatsRepository) EditStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.EditStats, error) {
+	documentIDs, err := r.documentIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": scoped(ctx, bson.M{
+			"document_id": bson.M{"$in": documentIDs},
+			"version":     bson.M{"$gt": 1},
+			"created_at":  bson.M{"$gte": since},
+		})},
+		bson.M{"$facet": bson.M{
+			"edits":    countPer("created_at", bucket),
+			"per_week": countPer("created_at", models.StatsBucketWeek),
+			"editors":  countBy("created_by"),
+		}},
+	}
+
+	var result struct {
+		Edits   []models.TrendPoint `bson:"edits"`
+		PerWeek []models.TrendPoint `bson:"per_week"`
+		Editors []models.KeyCount   `bson:"editors"`
+	}
+	if err := aggregateOne(ctx, r.versions, pipeline, &result); err != nil {
+		return nil, fmt.Errorf("failed to compute edit statistics: %w", err)
+	}
+
+	return &models.EditStats{
+		Edits:   result.Edits,
+		PerWeek: result.PerWeek,
+		Editors: result.Editors,
+	}, nil
+}
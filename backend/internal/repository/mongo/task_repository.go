@@ -0,0 +1,237 @@
+// Package mongo internal/repository/mongo/task_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type TaskRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskRepository(db *mongo.Database) *TaskRepository {
+	repo := &TaskRepository{
+		collection: db.Collection("tasks"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create task indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *TaskRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "project_id", Value: 1},
+				{Key: "column_id", Value: 1},
+				{Key: "rank", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "assignee_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "milestone_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create task indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	task.Version = 1
+	task.OrgID = orgFor(ctx, task.OrgID)
+	if task.DocumentIDs == nil {
+		task.DocumentIDs = []string{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		task.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrTaskNotFound
+	}
+
+	var task models.Task
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&task)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (r *TaskRepository) FindByProject(ctx context.Context, projectID string, filter models.TaskFilter) ([]*models.Task, error) {
+	query := bson.M{"project_id": projectID}
+	if filter.ColumnID != "" {
+		query["column_id"] = filter.ColumnID
+	}
+	if filter.AssigneeID != "" {
+		query["assignee_id"] = filter.AssigneeID
+	}
+	if filter.MilestoneID != "" {
+		query["milestone_id"] = filter.MilestoneID
+	}
+	if filter.Priority != "" {
+		query["priority"] = filter.Priority
+	}
+
+	cursor, err := r.collection.Find(ctx,
+		scoped(ctx, query),
+		options.Find().SetSort(bson.D{
+			{Key: "rank", Value: 1},
+			{Key: "_id", Value: 1},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	tasks := make([]*models.Task, 0)
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Update saves a task if nobody changed it since it was read, and bumps its
+// version
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	oid, err := primitive.ObjectIDFromHex(task.ID)
+	if err != nil {
+		return errs.ErrTaskNotFound
+	}
+
+	updatedAt := time.Now()
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid, "version": task.Version}), bson.M{
+		"$set": bson.M{
+			"title":        task.Title,
+			"description":  task.Description,
+			"assignee_id":  task.AssigneeID,
+			"column_id":    task.ColumnID,
+			"rank":         task.Rank,
+			"priority":     task.Priority,
+			"estimate":     task.Estimate,
+			"due_date":     task.DueDate,
+			"milestone_id": task.MilestoneID,
+			"document_ids": task.DocumentIDs,
+			"updated_at":   updatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		if _, err := r.GetByID(ctx, task.ID); err != nil {
+			return err
+		}
+		return errs.ErrVersionConflict
+	}
+
+	task.Version++
+	task.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrTaskNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) SetRanks(ctx context.Context, ranks map[string]string) error {
+	if len(ranks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(ranks))
+	for id, rank := range ranks {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return errs.ErrTaskNotFound
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(scoped(ctx, bson.M{"_id": oid})).
+			SetUpdate(bson.M{"$set": bson.M{"rank": rank, "updated_at": now}}))
+	}
+
+	if _, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to rerank tasks: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) CountByColumn(ctx context.Context, projectID, columnID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, scoped(ctx, bson.M{"project_id": projectID, "column_id": columnID}))
+}
+
+func (r *TaskRepository) ClearMilestone(ctx context.Context, milestoneID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		scoped(ctx, bson.M{"milestone_id": milestoneID}),
+		bson.M{
+			"$set": bson.M{"milestone_id": "", "updated_at": time.Now()},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	return err
+}
+
+func (r *TaskRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
@@ -8,25 +8,29 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
 	errs "projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"time"
 )
 
 type TeamRepository struct {
 	collection *mongo.Collection
+	// legacy is where teams were kept, among project members, before they
+	// had a collection of their own
+	legacy *mongo.Collection
 }
 
 func NewTeamRepository(db *mongo.Database) *TeamRepository {
 	repo := &TeamRepository{
-		collection: db.Collection("team_members"),
+		collection: db.Collection("teams"),
+		legacy:     db.Collection("team_members"),
 	}
 
 	// Ensure indexes
 	if err := repo.ensureIndexes(context.Background()); err != nil {
-		log.Printf("Warning: Failed to create team member indexes: %v", err)
+		log.Printf("Warning: Failed to create team indexes: %v", err)
 	}
 
 	return repo
@@ -35,14 +39,16 @@ func NewTeamRepository(db *mongo.Database) *TeamRepository {
 func (r *TeamRepository) ensureIndexes(ctx context.Context) error {
 	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys: bson.D{
-				{Key: "project_id", Value: 1},
-				{Key: "user_id", Value: 1},
-			},
-			Options: options.Index().SetUnique(true),
+			Keys: bson.D{{Key: "members.user_id", Value: 1}},
 		},
 		{
-			Keys: bson.D{{Key: "status", Value: 1}},
+			Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "name", Value: 1}},
 		},
 	})
 	return err
@@ -51,12 +57,13 @@ func (r *TeamRepository) ensureIndexes(ctx context.Context) error {
 func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 	team.CreatedAt = time.Now()
 	team.UpdatedAt = time.Now()
+	team.OrgID = orgFor(ctx, team.OrgID)
+	if team.Members == nil {
+		team.Members = []models.TeamMembership{}
+	}
 
 	result, err := r.collection.InsertOne(ctx, team)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return errs.ErrAlreadyInTeam
-		}
 		return fmt.Errorf("failed to create team: %w", err)
 	}
 
@@ -67,91 +74,51 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 	return nil
 }
 
-func (r *TeamRepository) GetByID(ctx context.Context, id string) (*models.TeamMember, error) {
+func (r *TeamRepository) GetByID(ctx context.Context, id string) (*models.Team, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, errs.ErrNotFound
 	}
 
-	var member models.TeamMember
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&member)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errs.ErrNotFound
-		}
-		return nil, err
-	}
-
-	return &member, nil
-}
-
-func (r *TeamRepository) GetByProjectAndUser(ctx context.Context, projectID, userID string) (*models.TeamMember, error) {
-	var member models.TeamMember
-	err := r.collection.FindOne(ctx, bson.M{
-		"project_id": projectID,
-		"user_id":    userID,
-	}).Decode(&member)
-
+	var team models.Team
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&team)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, errs.ErrNotFound
 		}
-		return nil, err
-	}
-
-	return &member, nil
-}
-
-func (r *TeamRepository) GetProjectMembers(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"project_id": projectID,
-		"status":     models.TeamMemberStatusActive,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer func(cursor *mongo.Cursor, ctx context.Context) {
-		err := cursor.Close(ctx)
-		if err != nil {
-			log.Printf("Error closing cursor: %v", err)
-		}
-	}(cursor, ctx)
-
-	var members []*models.TeamMember
-	if err = cursor.All(ctx, &members); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch team: %w", err)
 	}
 
-	return members, nil
+	return &team, nil
 }
 
-func (r *TeamRepository) Update(ctx context.Context, member *models.TeamMember) error {
-	member.UpdatedAt = time.Now()
+// Update saves the team's details; members are changed through AddMember,
+// UpdateMemberRole and RemoveMember
+func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
+	team.UpdatedAt = time.Now()
 
-	oid, err := primitive.ObjectIDFromHex(member.ID)
+	oid, err := primitive.ObjectIDFromHex(team.ID)
 	if err != nil {
 		return errs.ErrNotFound
 	}
 
-	result, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": oid},
-		bson.M{
-			"$set": bson.M{
-				"role":       member.Role,
-				"status":     member.Status,
-				"updated_at": member.UpdatedAt,
-			},
+	update := bson.M{
+		"$set": bson.M{
+			"name":        team.Name,
+			"description": team.Description,
+			"lead":        team.Lead,
+			"updated_at":  team.UpdatedAt,
 		},
-	)
-
-	if err != nil {
-		return err
 	}
-
-	if result.MatchedCount == 0 {
+	var before bson.M
+	err = r.collection.FindOneAndUpdate(ctx, scoped(ctx, bson.M{"_id": oid}), update).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return errs.ErrNotFound
 	}
+	if err != nil {
+		return fmt.Errorf("failed to update team: %w", err)
+	}
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
 
 	return nil
 }
@@ -162,20 +129,30 @@ func (r *TeamRepository) Delete(ctx context.Context, id string) error {
 		return errs.ErrNotFound
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
+	var before bson.M
+	err = r.collection.FindOneAndDelete(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return errs.ErrNotFound
 	}
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+	undoWrite(ctx, r.collection, before)
 
 	return nil
 }
 
-func (r *TeamRepository) GetAll(ctx context.Context) ([]*models.Team, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+func (r *TeamRepository) FindPage(ctx context.Context, q pagination.Query) (*pagination.Page[*models.Team], error) {
+	page, err := paginate[models.Team](ctx, r.collection, scoped(ctx, applyPageFilter(bson.M{}, q.Filter)), q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	return page, nil
+}
+
+// GetByMember returns the teams a user belongs to
+func (r *TeamRepository) GetByMember(ctx context.Context, userID string) ([]*models.Team, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{"members.user_id": userID}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch teams: %w", err)
 	}
@@ -193,34 +170,164 @@ func (r *TeamRepository) GetAll(ctx context.Context) ([]*models.Team, error) {
 	return teams, nil
 }
 
-func (r *TeamRepository) CreateTeamMember(ctx context.Context, member *models.TeamMember) error {
-	member.CreatedAt = time.Now()
-	member.UpdatedAt = time.Now()
+// AddMember appends a membership unless the user is already in the team
+func (r *TeamRepository) AddMember(ctx context.Context, teamID string, member models.TeamMembership) error {
+	oid, err := primitive.ObjectIDFromHex(teamID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
 
-	_, err := r.collection.InsertOne(ctx, member)
+	return r.updateMembers(ctx, oid,
+		bson.M{"members.user_id": bson.M{"$ne": member.UserID}},
+		bson.M{
+			"$push": bson.M{"members": member},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		errs.ErrAlreadyInTeam,
+	)
+}
+
+func (r *TeamRepository) UpdateMemberRole(ctx context.Context, teamID, userID string, role models.TeamRole) error {
+	oid, err := primitive.ObjectIDFromHex(teamID)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return errs.ErrAlreadyInTeam
-		}
-		return fmt.Errorf("failed to create team member: %w", err)
+		return errs.ErrNotFound
+	}
+
+	return r.updateMembers(ctx, oid,
+		bson.M{"members.user_id": userID},
+		bson.M{"$set": bson.M{
+			"members.$.role": role,
+			"updated_at":     time.Now(),
+		}},
+		errs.ErrNotInTeam,
+	)
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(teamID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	return r.updateMembers(ctx, oid,
+		bson.M{"members.user_id": userID},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"user_id": userID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		errs.ErrNotInTeam,
+	)
+}
+
+// updateMembers applies a member update to the team if it meets the
+// membership conditions, and registers putting the members back should the
+// unit of work fail
+func (r *TeamRepository) updateMembers(ctx context.Context, oid primitive.ObjectID, conditions bson.M, update bson.M, memberErr error) error {
+	conditions["_id"] = oid
+	var before bson.M
+	err := r.collection.FindOneAndUpdate(ctx, scoped(ctx, conditions), update).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.missingTeamOr(ctx, oid, memberErr)
 	}
+	if err != nil {
+		return fmt.Errorf("failed to update team members: %w", err)
+	}
+	undoWrite(ctx, r.collection, before, fieldsOf(update)...)
+
 	return nil
 }
 
-func (r *TeamRepository) GetTeamMember(ctx context.Context, id string) (*models.TeamMember, error) {
-	oid, err := primitive.ObjectIDFromHex(id)
+// missingTeamOr explains a member update that matched nothing: either the
+// team does not exist or the membership condition failed
+func (r *TeamRepository) missingTeamOr(ctx context.Context, oid primitive.ObjectID, memberErr error) error {
+	count, err := r.collection.CountDocuments(ctx, scoped(ctx, bson.M{"_id": oid}))
 	if err != nil {
-		return nil, errs.ErrNotFound
+		return fmt.Errorf("failed to fetch team: %w", err)
+	}
+	if count == 0 {
+		return errs.ErrNotFound
 	}
+	return memberErr
+}
+
+// legacyTeam is a team as it was stored in the team members collection, with
+// whole member records embedded
+type legacyTeam struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	OrgID       string             `bson:"org_id,omitempty"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Lead        string             `bson:"lead"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	Members     []struct {
+		UserID    string          `bson:"user_id"`
+		Role      models.TeamRole `bson:"role"`
+		CreatedAt time.Time       `bson:"created_at"`
+	} `bson:"members"`
+}
 
-	var member models.TeamMember
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&member)
+func (r *TeamRepository) MigrateLegacy(ctx context.Context) (int, error) {
+	// Member records belong to a project; teams are the ones with members
+	filter := bson.M{"members": bson.M{"$exists": true}, "project_id": bson.M{"$exists": false}}
+	cursor, err := r.legacy.Find(ctx, filter)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errs.ErrNotFound
+		return 0, fmt.Errorf("failed to find legacy teams: %w", err)
+	}
+	var teams []legacyTeam
+	if err := cursor.All(ctx, &teams); err != nil {
+		return 0, fmt.Errorf("failed to read legacy teams: %w", err)
+	}
+
+	for _, old := range teams {
+		members := make([]models.TeamMembership, 0, len(old.Members)+1)
+		for _, m := range old.Members {
+			if m.UserID == "" {
+				continue
+			}
+			joinedAt := m.CreatedAt
+			if joinedAt.IsZero() {
+				joinedAt = old.CreatedAt
+			}
+			members = append(members, models.TeamMembership{UserID: m.UserID, Role: m.Role, JoinedAt: joinedAt})
+		}
+		// The lead is always one of the members now
+		lead := old.Lead
+		if lead != "" && !hasMember(members, lead) {
+			members = append(members, models.TeamMembership{UserID: lead, Role: models.TeamRoleOwner, JoinedAt: old.CreatedAt})
+		}
+
+		// The team keeps its ID, so references to it stay valid
+		doc := bson.M{
+			"_id":         old.ID,
+			"name":        old.Name,
+			"description": old.Description,
+			"lead":        lead,
+			"created_by":  lead,
+			"created_at":  old.CreatedAt,
+			"updated_at":  old.UpdatedAt,
+			"members":     members,
+		}
+		if old.OrgID != "" {
+			doc["org_id"] = old.OrgID
+		}
+		// A team copied by an earlier, interrupted run only needs removing
+		if _, err := r.collection.InsertOne(ctx, doc); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, fmt.Errorf("failed to move team %s: %w", old.ID.Hex(), err)
+		}
+		if _, err := r.legacy.DeleteOne(ctx, bson.M{"_id": old.ID}); err != nil {
+			return 0, fmt.Errorf("failed to remove legacy team %s: %w", old.ID.Hex(), err)
 		}
-		return nil, fmt.Errorf("failed to fetch team member: %w", err)
 	}
 
-	return &member, nil
+	return len(teams), nil
+}
+
+func hasMember(members []models.TeamMembership, userID string) bool {
+	for _, m := range members {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,152 @@
+// Package mongo internal/repository/mongo/template_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type TemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTemplateRepository(db *mongo.Database) *TemplateRepository {
+	repo := &TemplateRepository{
+		collection: db.Collection("project_templates"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create template indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *TemplateRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "org_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create template indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, template *models.ProjectTemplate) error {
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+	template.OrgID = orgFor(ctx, template.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		template.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.ProjectTemplate, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrTemplateNotFound
+	}
+
+	var template models.ProjectTemplate
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *TemplateRepository) List(ctx context.Context) ([]*models.ProjectTemplate, error) {
+	cursor, err := r.collection.Find(ctx,
+		scoped(ctx, bson.M{}),
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}(cursor, ctx)
+
+	templates := make([]*models.ProjectTemplate, 0)
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *TemplateRepository) Update(ctx context.Context, template *models.ProjectTemplate) error {
+	oid, err := primitive.ObjectIDFromHex(template.ID)
+	if err != nil {
+		return errs.ErrTemplateNotFound
+	}
+
+	template.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"name":        template.Name,
+			"description": template.Description,
+			"status":      template.Status,
+			"documents":   template.Documents,
+			"members":     template.Members,
+			"teams":       template.Teams,
+			"updated_at":  template.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrTemplateNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrTemplateNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package mongo internal/repository/mongo/tenant.go
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/tenant"
+)
+
+// tenantCollections hold records owned by an organization
+var tenantCollections = []string{"projects", "teams", "team_members", "documents", "document_versions", "mockups", "folders", "links", "invitations", "milestones", "tasks", "boards", "project_templates", "notifications", "webhooks", "webhook_deliveries", "audit_log", "activities", "share_links"}
+
+// scoped restricts a filter to the organization of the context. A context
+// without a tenant matches nothing, so a request that lost its tenant fails
+// closed; background jobs opt in to every organization with tenant.Unscoped.
+func scoped(ctx context.Context, filter bson.M) bson.M {
+	switch orgID := tenant.OrgID(ctx); {
+	case orgID != "":
+		filter["org_id"] = orgID
+	case !tenant.IsUnscoped(ctx):
+		filter["org_id"] = bson.M{"$in": bson.A{}}
+	}
+	return filter
+}
+
+// orgFor returns the organization a new record belongs to: the one it names,
+// or else the organization of the context
+func orgFor(ctx context.Context, orgID string) string {
+	if orgID != "" {
+		return orgID
+	}
+	return tenant.OrgID(ctx)
+}
+
+// countOrphans counts the records created before organizations existed
+func countOrphans(ctx context.Context, db *mongo.Database) (int64, error) {
+	var total int64
+	for _, name := range tenantCollections {
+		count, err := db.Collection(name).CountDocuments(ctx, bson.M{"org_id": bson.M{"$exists": false}})
+		if err != nil {
+			return 0, fmt.Errorf("failed to count %s without organization: %w", name, err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// adoptOrphans moves the records created before organizations existed into
+// an organization
+func adoptOrphans(ctx context.Context, db *mongo.Database, orgID string) error {
+	for _, name := range tenantCollections {
+		_, err := db.Collection(name).UpdateMany(ctx,
+			bson.M{"org_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"org_id": orgID}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move %s into organization: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// internal/repository/mongo/activity_repository_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActivityRepository_RecordCountsEventsOnce(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewActivityRepository(db)
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+	now := time.Now()
+	activity := func(eventID string) *models.Activity {
+		return &models.Activity{
+			ProjectID:  "project",
+			ActorID:    "actor",
+			Type:       models.ActivityTypeDocument,
+			Verb:       models.ActivityDocumentEdited,
+			ResourceID: "doc",
+			Count:      1,
+			EventIDs:   []string{eventID},
+			StartedAt:  now,
+			OccurredAt: now,
+		}
+	}
+
+	require.NoError(t, repo.Record(ctx, activity("first"), now.Add(-time.Minute)))
+
+	// The second edit is delivered several times at once
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.Record(ctx, activity("second"), now.Add(-time.Minute)))
+		}()
+	}
+	wg.Wait()
+
+	var entries []models.Activity
+	cursor, err := db.Collection("activities").Find(ctx, map[string]string{"resource_id": "doc"})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, 2, entries[0].Count)
+	assert.Equal(t, "org", entries[0].OrgID)
+	assert.Equal(t, "project", entries[0].ProjectID)
+
+	// An edit after the burst is over starts an entry of its own
+	later := activity("third")
+	later.OccurredAt = now.Add(time.Hour)
+	require.NoError(t, repo.Record(ctx, later, now.Add(time.Minute)))
+	assert.Equal(t, 1, later.Count)
+	count, err := db.Collection("activities").CountDocuments(ctx, map[string]string{"resource_id": "doc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
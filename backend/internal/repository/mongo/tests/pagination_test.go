@@ -0,0 +1,65 @@
+// internal/repository/mongo/pagination_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/url"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+func TestPaginate_MissingSortValues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.Unscoped(context.Background())
+	repo := mongo2.NewMockupRepository(db)
+	projectID := "64b7f0c2a1b2c3d4e5f6a001"
+
+	// Two mockups have no type at all and one has it set to null
+	_, err := db.Collection("mockups").InsertMany(ctx, []interface{}{
+		bson.M{"project_id": projectID, "name": "a", "type": "web"},
+		bson.M{"project_id": projectID, "name": "b"},
+		bson.M{"project_id": projectID, "name": "c", "type": "mobile"},
+		bson.M{"project_id": projectID, "name": "d", "type": nil},
+		bson.M{"project_id": projectID, "name": "e"},
+	})
+	require.NoError(t, err)
+
+	for _, sort := range []string{"type", "-type"} {
+		t.Run(sort, func(t *testing.T) {
+			q, err := pagination.Parse(url.Values{"sort": {sort}, "limit": {"1"}}, models.MockupListSpec)
+			require.NoError(t, err)
+
+			names := make([]string, 0)
+			for {
+				page, err := repo.FindPage(ctx, []string{projectID}, q)
+				require.NoError(t, err)
+				for _, mockup := range page.Items {
+					names = append(names, mockup.Name)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				q, err = q.Resume(page.NextCursor)
+				require.NoError(t, err)
+			}
+
+			// Every mockup is listed once, the ones without a type first
+			// in ascending order and last in descending order
+			require.Len(t, names, 5)
+			assert.ElementsMatch(t, []string{"a", "b", "c", "d", "e"}, names)
+			if sort == "type" {
+				assert.Equal(t, []string{"c", "a"}, names[3:])
+			} else {
+				assert.Equal(t, []string{"a", "c"}, names[:2])
+			}
+		})
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"projectnexus/internal/errors"
 	"projectnexus/internal/models"
 	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
 	"testing"
 	"time"
 )
@@ -34,7 +36,7 @@ func TestProjectRepository_Create(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	project := &models.Project{
 		Name:        "Test Project",
@@ -58,7 +60,7 @@ func TestProjectRepository_GetByID(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	// Create test project
 	project := &models.Project{
@@ -88,7 +90,7 @@ func TestProjectRepository_GetByUser(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	// Create test projects
 	project1 := &models.Project{
@@ -123,7 +125,7 @@ func TestProjectRepository_Update(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	// Create test project
 	project := &models.Project{
@@ -141,7 +143,7 @@ func TestProjectRepository_Update(t *testing.T) {
 
 	project.Name = "Updated Project"
 	project.Status = models.ProjectStatusInProgress
-	err = repo.Update(ctx, project)
+	err = repo.UpdateFields(ctx, project, "name", "status")
 	assert.NoError(t, err)
 
 	// Verify update
@@ -157,7 +159,7 @@ func TestProjectRepository_Delete(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	// Create test project
 	project := &models.Project{
@@ -182,7 +184,7 @@ func TestProjectRepository_List(t *testing.T) {
 	defer cleanup()
 
 	repo := mongo2.NewProjectRepository(db)
-	ctx := context.Background()
+	ctx := tenant.Unscoped(context.Background())
 
 	// Create test projects
 	projects := []*models.Project{
@@ -223,3 +225,102 @@ func TestProjectRepository_List(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, user1Projects, 2)
 }
+
+func TestProjectRepository_SetProgress(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewProjectRepository(db)
+	ctx := tenant.Unscoped(context.Background())
+
+	project := &models.Project{
+		Name:             "Launch",
+		Status:           models.ProjectStatusPlanning,
+		CreatedBy:        "user1",
+		ProgressSettings: &models.ProgressSettings{Mode: models.ProgressModeAuto},
+	}
+	require.NoError(t, repo.Create(ctx, project))
+
+	// A rename saved while progress was being computed survives it
+	renamed := *project
+	renamed.Name = "Relaunch"
+	require.NoError(t, repo.UpdateFields(ctx, &renamed, "name"))
+	require.NoError(t, repo.SetProgress(ctx, project.ID, 40, &models.ProgressBreakdown{Progress: 40, Computed: 40}))
+
+	stored, err := repo.GetByID(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Relaunch", stored.Name)
+	assert.Equal(t, 40, stored.Progress)
+	require.NotNil(t, stored.ProgressBreakdown)
+	assert.Equal(t, 40, stored.ProgressBreakdown.Computed)
+
+	// Progress set by hand since then is not overwritten
+	stored.ProgressSettings.Mode = models.ProgressModeManual
+	stored.Progress = 75
+	require.NoError(t, repo.UpdateFields(ctx, stored, "progress", "progress_settings"))
+	require.NoError(t, repo.SetProgress(ctx, project.ID, 40, &models.ProgressBreakdown{Progress: 40}))
+
+	stored, err = repo.GetByID(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 75, stored.Progress)
+}
+
+func TestProjectRepository_UpdateFieldsSkipsArchived(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.Unscoped(context.Background())
+	repo := mongo2.NewProjectRepository(db)
+	project := &models.Project{Name: "Launch", Status: models.ProjectStatusPlanning, CreatedBy: "user1"}
+	require.NoError(t, repo.Create(ctx, project))
+
+	// The project is archived after the caller checked it was writable
+	require.NoError(t, repo.Archive(ctx, project.ID, "user1", time.Now()))
+
+	renamed := *project
+	renamed.Name = "Relaunch"
+	assert.ErrorIs(t, repo.UpdateFields(ctx, &renamed, "name"), errors.ErrProjectArchived)
+	assert.ErrorIs(t, repo.AddToTeam(ctx, project.ID, "user2"), errors.ErrProjectArchived)
+
+	stored, err := repo.GetByID(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Launch", stored.Name)
+	assert.Empty(t, stored.Team)
+
+	require.NoError(t, repo.Unarchive(ctx, project.ID))
+	assert.NoError(t, repo.UpdateFields(ctx, &renamed, "name"))
+}
+
+func TestProjectRepository_EditsKeepOwnersAndTransfers(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.Unscoped(context.Background())
+	repo := mongo2.NewProjectRepository(db)
+	project := &models.Project{Name: "Launch", Status: models.ProjectStatusPlanning, CreatedBy: "user1"}
+	require.NoError(t, repo.Create(ctx, project))
+
+	// A rename read before a transfer was requested and accepted
+	renamed := *project
+	renamed.Name = "Relaunch"
+
+	transfer := models.OwnershipTransfer{From: "user1", To: "user2", RequestedAt: time.Now()}
+	require.NoError(t, repo.RequestTransfer(ctx, project.ID, transfer))
+	assert.ErrorIs(t, repo.RequestTransfer(ctx, project.ID, transfer), errors.ErrTransferPending)
+	require.NoError(t, repo.ClearTransfer(ctx, project.ID, transfer))
+	assert.ErrorIs(t, repo.ClearTransfer(ctx, project.ID, transfer), errors.ErrNoTransfer)
+	require.NoError(t, repo.SetOwners(ctx, project.ID, nil, []string{"user2"}))
+	require.NoError(t, repo.UpdateTeamList(ctx, project.ID, []string{"user2"}, nil))
+
+	require.NoError(t, repo.UpdateFields(ctx, &renamed, "name"))
+
+	stored, err := repo.GetByID(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Relaunch", stored.Name)
+	assert.Equal(t, []string{"user2"}, stored.Owners)
+	assert.Equal(t, []string{"user2"}, stored.Team)
+	assert.Nil(t, stored.PendingTransfer)
+
+	// Owners changed since they were read are not overwritten
+	assert.ErrorIs(t, repo.SetOwners(ctx, project.ID, nil, []string{"user1", "user3"}), errors.ErrVersionConflict)
+}
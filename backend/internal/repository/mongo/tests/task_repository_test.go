@@ -0,0 +1,105 @@
+// internal/repository/mongo/task_repository_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"sync"
+	"testing"
+)
+
+func TestTaskRepository_ConcurrentMovesOneWins(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+	repo := mongo2.NewTaskRepository(db)
+	task := &models.Task{ProjectID: "project", ColumnID: "todo", Title: "Spec", Rank: "m"}
+	require.NoError(t, repo.Create(ctx, task))
+
+	// Every client dragged the same version of the task
+	const clients = 8
+	results := make(chan error, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			moved := *task
+			moved.ColumnID = "done"
+			results <- repo.Update(ctx, &moved)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var succeeded int
+	for err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, errors.ErrVersionConflict)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	stored, err := repo.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func TestTaskRepository_SetRanksKeepsVersions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+	repo := mongo2.NewTaskRepository(db)
+	task := &models.Task{ProjectID: "project", ColumnID: "todo", Title: "Spec", Rank: "m"}
+	require.NoError(t, repo.Create(ctx, task))
+
+	require.NoError(t, repo.SetRanks(ctx, map[string]string{task.ID: "g"}))
+
+	// A client holding the task from before the rerank can still edit it
+	stored, err := repo.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "g", stored.Rank)
+	assert.Equal(t, task.Version, stored.Version)
+	assert.NoError(t, repo.Update(ctx, task))
+}
+
+func TestTxManager_WriteConflictIsVersionConflict(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := db.Collection("tx_test")
+	_, err := collection.InsertOne(ctx, bson.M{"name": "contended", "count": 0})
+	require.NoError(t, err)
+
+	tx := mongo2.NewTxManager(db.Client())
+	var concurrent error
+	err = tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if mongo.SessionFromContext(txCtx) == nil {
+			t.Skip("write conflicts need a replica set")
+		}
+		if _, err := collection.UpdateOne(txCtx, bson.M{"name": "contended"}, bson.M{"$inc": bson.M{"count": 1}}); err != nil {
+			return err
+		}
+
+		// A second transaction writing the same document loses the race
+		concurrent = tx.WithTransaction(ctx, func(ctx context.Context) error {
+			_, err := collection.UpdateOne(ctx, bson.M{"name": "contended"}, bson.M{"$inc": bson.M{"count": 1}})
+			return err
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, concurrent, errors.ErrVersionConflict)
+}
@@ -0,0 +1,64 @@
+// internal/repository/mongo/team_repository_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/models"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"testing"
+	"time"
+)
+
+func TestTeamRepository_MigrateLegacy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := tenant.Unscoped(context.Background())
+	repo := mongo2.NewTeamRepository(db)
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	// A team as it used to be stored, next to a project member record
+	teamID := primitive.NewObjectID()
+	_, err := db.Collection("team_members").InsertMany(ctx, []interface{}{
+		bson.M{
+			"_id":         teamID,
+			"name":        "Platform",
+			"description": "Keeps the lights on",
+			"lead":        "lead",
+			"created_at":  createdAt,
+			"updated_at":  createdAt,
+			"members": []bson.M{
+				{"user_id": "member", "role": "member", "created_at": createdAt},
+			},
+		},
+		bson.M{"project_id": "project", "user_id": "member", "role": "member"},
+	})
+	require.NoError(t, err)
+
+	moved, err := repo.MigrateLegacy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	team, err := repo.GetByID(ctx, teamID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", team.Name)
+	assert.Equal(t, "lead", team.Lead)
+	require.Len(t, team.Members, 2)
+	assert.Equal(t, models.TeamMembership{UserID: "member", Role: models.TeamRoleMember, JoinedAt: createdAt.UTC()}, team.Members[0])
+	assert.Equal(t, "lead", team.Members[1].UserID)
+	assert.Equal(t, models.TeamRoleOwner, team.Members[1].Role)
+
+	// Member records stay where they are and a second run has nothing to do
+	count, err := db.Collection("team_members").CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	moved, err = repo.MigrateLegacy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, moved)
+}
@@ -0,0 +1,89 @@
+// internal/repository/mongo/tenant_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+func TestTenantIsolation_Projects(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	orgA := tenant.WithOrg(context.Background(), "org-a", models.OrgRoleMember)
+	orgB := tenant.WithOrg(context.Background(), "org-b", models.OrgRoleMember)
+	repo := mongo2.NewProjectRepository(db)
+
+	project := &models.Project{Name: "Secret", Status: models.ProjectStatusPlanning, CreatedBy: "user"}
+	require.NoError(t, repo.Create(orgA, project))
+	assert.Equal(t, "org-a", project.OrgID)
+
+	_, err := repo.GetByID(orgA, project.ID)
+	require.NoError(t, err)
+
+	// Another organization, and a context that lost its tenant, see nothing
+	for name, ctx := range map[string]context.Context{"other org": orgB, "no tenant": context.Background()} {
+		_, err := repo.GetByID(ctx, project.ID)
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments, name)
+
+		projects, err := repo.GetByUser(ctx, "user")
+		require.NoError(t, err, name)
+		assert.Empty(t, projects, name)
+	}
+
+	// Background jobs see every organization once they ask to
+	_, err = repo.GetByID(tenant.Unscoped(context.Background()), project.ID)
+	assert.NoError(t, err)
+}
+
+func TestTenantIsolation_Documents(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	orgA := tenant.WithOrg(context.Background(), "org-a", models.OrgRoleMember)
+	orgB := tenant.WithOrg(context.Background(), "org-b", models.OrgRoleMember)
+	repo := mongo2.NewDocumentRepository(db)
+
+	doc := &models.Document{Title: "Plan", ProjectID: "64b7f0c2a1b2c3d4e5f6a001", CreatedBy: "user"}
+	require.NoError(t, repo.Create(orgA, doc))
+
+	for name, ctx := range map[string]context.Context{"other org": orgB, "no tenant": context.Background()} {
+		_, err := repo.GetByID(ctx, doc.ID)
+		assert.ErrorIs(t, err, errors.ErrDocumentNotFound, name)
+
+		docs, err := repo.GetByProject(ctx, doc.ProjectID)
+		require.NoError(t, err, name)
+		assert.Empty(t, docs, name)
+	}
+
+	docs, err := repo.GetByProject(orgA, doc.ProjectID)
+	require.NoError(t, err)
+	assert.Len(t, docs, 1)
+}
+
+func TestTenantIsolation_Teams(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	orgA := tenant.WithOrg(context.Background(), "org-a", models.OrgRoleMember)
+	orgB := tenant.WithOrg(context.Background(), "org-b", models.OrgRoleMember)
+	repo := mongo2.NewTeamRepository(db)
+
+	team := &models.Team{Name: "Platform", Lead: "lead"}
+	require.NoError(t, repo.Create(orgA, team))
+
+	_, err := repo.GetByID(orgB, team.ID)
+	assert.Error(t, err)
+	_, err = repo.GetByID(context.Background(), team.ID)
+	assert.Error(t, err)
+
+	_, err = repo.GetByID(orgA, team.ID)
+	assert.NoError(t, err)
+}
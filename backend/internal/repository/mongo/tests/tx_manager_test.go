@@ -0,0 +1,182 @@
+// internal/repository/mongo/tx_manager_test.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+// These tests pass against a replica set, where the manager uses real
+// transactions, and against a standalone server, where it falls back to
+// applying writes one by one and undoing them on failure
+
+func TestTxManager_NestedCallsJoin(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tx := mongo2.NewTxManager(db.Client())
+	err := tx.WithTransaction(context.Background(), func(outer context.Context) error {
+		return tx.WithTransaction(outer, func(inner context.Context) error {
+			// Both see the same session, or both run without one
+			assert.Equal(t, mongo.SessionFromContext(outer), mongo.SessionFromContext(inner))
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+}
+
+func TestTxManager_FailedWorkLeavesNoWrites(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := db.Collection("tx_test")
+	_, err := collection.InsertOne(ctx, bson.M{"name": "kept"})
+	require.NoError(t, err)
+
+	tx := mongo2.NewTxManager(db.Client())
+	failure := errors.New("later write failed")
+	err = tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := collection.InsertOne(ctx, bson.M{"name": "outer"}); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			_, err := collection.DeleteOne(ctx, bson.M{"name": "outer"})
+			return err
+		})
+
+		// A nested unit of work is undone along with the outer one
+		err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+			if _, err := collection.UpdateOne(ctx, bson.M{"name": "kept"}, bson.M{"$set": bson.M{"touched": true}}); err != nil {
+				return err
+			}
+			repository.OnRollback(ctx, func(ctx context.Context) error {
+				_, err := collection.UpdateOne(ctx, bson.M{"name": "kept"}, bson.M{"$unset": bson.M{"touched": ""}})
+				return err
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return failure
+	})
+	assert.Equal(t, failure, err)
+
+	count, err := collection.CountDocuments(ctx, bson.M{"name": "outer"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	count, err = collection.CountDocuments(ctx, bson.M{"touched": true})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestTxManager_SuccessfulWorkIsKept(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := db.Collection("tx_test")
+
+	tx := mongo2.NewTxManager(db.Client())
+	err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := collection.InsertOne(ctx, bson.M{"name": "kept"})
+		return err
+	})
+	require.NoError(t, err)
+
+	count, err := collection.CountDocuments(ctx, bson.M{"name": "kept"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// rejectVersions makes every later write of a document version fail, as if
+// the server went away between the document and its version
+func rejectVersions(t *testing.T, db *mongo.Database) {
+	err := db.RunCommand(context.Background(), bson.D{
+		{Key: "collMod", Value: "document_versions"},
+		{Key: "validator", Value: bson.M{"version": bson.M{"$lt": 0}}},
+	}).Err()
+	require.NoError(t, err)
+}
+
+func TestTxManager_FallbackUndoesDocumentWithoutVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewDocumentRepository(db)
+	ctx := tenant.Unscoped(context.Background())
+	rejectVersions(t, db)
+
+	doc := &models.Document{ProjectID: "project", Title: "Spec", Content: "v1", Version: 1}
+	err := repository.RunCompensated(ctx, func(ctx context.Context) error {
+		return repo.Create(ctx, doc)
+	})
+	require.Error(t, err)
+
+	count, err := db.Collection("documents").CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestTxManager_FallbackUndoesDocumentEditWithoutVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewDocumentRepository(db)
+	ctx := tenant.Unscoped(context.Background())
+
+	doc := &models.Document{ProjectID: "project", Title: "Spec", Content: "v1", Version: 1}
+	require.NoError(t, repo.Create(ctx, doc))
+	rejectVersions(t, db)
+
+	err := repository.RunCompensated(ctx, func(ctx context.Context) error {
+		edited := *doc
+		edited.Title = "Spec, revised"
+		edited.Content = "v2"
+		edited.Version = 2
+		return repo.Update(ctx, &edited)
+	})
+	require.Error(t, err)
+
+	stored, err := repo.GetByID(ctx, doc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Spec", stored.Title)
+	assert.Equal(t, "v1", stored.Content)
+	assert.Equal(t, 1, stored.Version)
+}
+
+func TestTxManager_FallbackUndoesTeamMembership(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewTeamRepository(db)
+	ctx := tenant.Unscoped(context.Background())
+
+	team := &models.Team{Name: "Platform", Lead: "lead"}
+	require.NoError(t, repo.Create(ctx, team))
+
+	failure := errors.New("project sync failed")
+	err := repository.RunCompensated(ctx, func(ctx context.Context) error {
+		if err := repo.AddMember(ctx, team.ID, models.TeamMembership{UserID: "member", Role: models.TeamRoleMember}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	stored, err := repo.GetByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Members)
+}
@@ -0,0 +1,40 @@
+// internal/repository/mongo/webhook_delivery_repository_test.go
+package mongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	mongo2 "projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+func TestWebhookDeliveryRepository_CreateQueuesEventOnce(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongo2.NewWebhookDeliveryRepository(db)
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+
+	first := &models.WebhookDelivery{WebhookID: "hook", EventID: "event", Status: models.WebhookDeliveryPending}
+	require.NoError(t, repo.Create(ctx, first))
+	assert.NotEmpty(t, first.ID)
+
+	// The same event handled again queues nothing
+	again := &models.WebhookDelivery{WebhookID: "hook", EventID: "event", Status: models.WebhookDeliveryPending}
+	require.NoError(t, repo.Create(ctx, again))
+	assert.Empty(t, again.ID)
+
+	// Redeliveries asked for by users are queued every time
+	for i := 0; i < 2; i++ {
+		redelivery := &models.WebhookDelivery{WebhookID: "hook", EventID: "event", RedeliveryOf: first.ID, Status: models.WebhookDeliveryPending}
+		require.NoError(t, repo.Create(ctx, redelivery))
+		assert.NotEmpty(t, redelivery.ID)
+	}
+
+	count, err := db.Collection("webhook_deliveries").CountDocuments(ctx, map[string]string{"event_id": "event"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
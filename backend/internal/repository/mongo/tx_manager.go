@@ -0,0 +1,127 @@
+// Package mongo internal/repository/mongo/tx_manager.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/repository"
+	"sync"
+)
+
+// maxCommitRetries bounds how often a commit with an unknown outcome is retried
+const maxCommitRetries = 3
+
+// writeConflictCode is the error MongoDB reports when a concurrent
+// transaction changed a document this one writes
+const writeConflictCode = 112
+
+// TxManager runs units of work inside MongoDB multi-document transactions.
+//
+// Transactions require a replica set or sharded cluster. Against a standalone
+// server the manager falls back to running the work without a transaction:
+// writes are applied one by one, and when the work fails the undo steps it
+// registered with repository.OnRollback run instead of an abort. The fallback
+// is logged once at the first use.
+type TxManager struct {
+	client *mongo.Client
+
+	once      sync.Once
+	supported bool
+}
+
+func NewTxManager(client *mongo.Client) *TxManager {
+	return &TxManager{
+		client: client,
+	}
+}
+
+// WithTransaction runs fn in a transaction. Repository calls must use the
+// context passed to fn to take part in it. fn runs at most once: write
+// conflicts abort the transaction and are returned to the caller as
+// errors.ErrVersionConflict. Calls made while a transaction is already open
+// join the outer transaction.
+func (m *TxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	m.once.Do(func() {
+		m.supported = m.detectSupport(context.Background())
+		if !m.supported {
+			log.Printf("Warning: MongoDB is running standalone; multi-document writes will not be atomic")
+		}
+	})
+	if !m.supported {
+		return repository.RunCompensated(ctx, fn)
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		if err := sc.StartTransaction(); err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+
+		if err := fn(sc); err != nil {
+			if abortErr := sc.AbortTransaction(context.Background()); abortErr != nil {
+				log.Printf("Failed to abort transaction: %v", abortErr)
+			}
+			return conflictError(err)
+		}
+
+		return conflictError(m.commit(sc))
+	})
+}
+
+// conflictError reports a write conflict as errors.ErrVersionConflict: the
+// work lost a race with a concurrent change and can be retried by the client
+func conflictError(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(writeConflictCode) {
+		return fmt.Errorf("%w: %v", errs.ErrVersionConflict, err)
+	}
+	return err
+}
+
+// commit commits the open transaction, retrying when the server reports that
+// the outcome of the commit is unknown
+func (m *TxManager) commit(sc mongo.SessionContext) error {
+	var err error
+	for attempt := 0; attempt < maxCommitRetries; attempt++ {
+		err = sc.CommitTransaction(sc)
+		var serverErr mongo.ServerError
+		if err == nil || !errors.As(err, &serverErr) || !serverErr.HasErrorLabel("UnknownTransactionCommitResult") {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// detectSupport reports whether the deployment is a replica set or a mongos
+// router, the topologies that support transactions
+func (m *TxManager) detectSupport(ctx context.Context) bool {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	err := m.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		log.Printf("Warning: Failed to detect MongoDB topology: %v", err)
+		return false
+	}
+
+	return hello.SetName != "" || hello.Msg == "isdbgrid"
+}
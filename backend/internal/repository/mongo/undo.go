@@ -0,0 +1,76 @@
+// Package mongo internal/repository/mongo/undo.go
+package mongo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"projectnexus/internal/repository"
+	"strings"
+)
+
+// The helpers below register undo steps with repository.OnRollback, so the
+// writes of a unit of work the TxManager runs without a transaction are put
+// back when it fails. Inside a real transaction they never run.
+
+// undoInsert removes a record the unit of work inserted
+func undoInsert(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID) {
+	repository.OnRollback(ctx, func(ctx context.Context) error {
+		_, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+		return err
+	})
+}
+
+// undoWrite puts back a record the unit of work changed or deleted, as it
+// was before. Given fields, only those are put back, so writes made to the
+// rest of the record meanwhile are kept.
+func undoWrite(ctx context.Context, collection *mongo.Collection, before bson.M, fields ...string) {
+	id := before["_id"]
+	repository.OnRollback(ctx, func(ctx context.Context) error {
+		if len(fields) == 0 {
+			_, err := collection.ReplaceOne(ctx, bson.M{"_id": id}, before, options.Replace().SetUpsert(true))
+			return err
+		}
+
+		set := bson.M{}
+		unset := bson.M{}
+		for _, field := range fields {
+			if value, ok := before[field]; ok {
+				set[field] = value
+			} else {
+				unset[field] = ""
+			}
+		}
+		update := bson.M{}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+		return err
+	})
+}
+
+// fieldsOf lists the top-level fields an update document writes
+func fieldsOf(update bson.M) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, operation := range update {
+		values, ok := operation.(bson.M)
+		if !ok {
+			continue
+		}
+		for path := range values {
+			field, _, _ := strings.Cut(path, ".")
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
@@ -5,6 +5,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"projectnexus/internal/models"
 	"time"
 )
@@ -50,6 +51,30 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	return &user, nil
 }
 
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []string) ([]*models.User, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return []*models.User{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []*models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
@@ -70,10 +95,11 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 
 	// Create update document without _id field
 	updateDoc := bson.M{
-		"email":         user.Email,
-		"name":          user.Name,
-		"password_hash": user.PasswordHash,
-		"updated_at":    user.UpdatedAt,
+		"email":          user.Email,
+		"name":           user.Name,
+		"password_hash":  user.PasswordHash,
+		"current_org_id": user.CurrentOrgID,
+		"updated_at":     user.UpdatedAt,
 	}
 
 	_, err = r.collection.UpdateOne(
@@ -83,3 +109,107 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	)
 	return err
 }
+
+func (r *UserRepository) UpdateNotificationPreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	for t, enabled := range input.Types {
+		set["notification_preferences.types."+string(t)] = enabled
+	}
+	for t, enabled := range input.Email {
+		set["notification_preferences.email."+string(t)] = enabled
+	}
+	if input.Digest != "" {
+		set["notification_preferences.digest"] = input.Digest
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"notification_preferences": 1})
+	var user models.User
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": oid}, bson.M{"$set": set}, opts).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user.NotificationPreferences, nil
+}
+
+// notSentSince matches users who were never sent a digest or were last sent
+// one before the given time
+func notSentSince(sentBefore time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"digest_sent_at": bson.M{"$exists": false}},
+		{"digest_sent_at": bson.M{"$lt": sentBefore}},
+	}}
+}
+
+func (r *UserRepository) FindDigestRecipients(ctx context.Context, frequency models.DigestFrequency, sentBefore time.Time, afterID string, limit int) ([]*models.User, error) {
+	var frequencyFilter bson.M
+	if frequency == models.DefaultDigest {
+		// Users who never chose a frequency get the default one
+		frequencyFilter = bson.M{"$or": []bson.M{
+			{"notification_preferences.digest": frequency},
+			{"notification_preferences.digest": bson.M{"$exists": false}},
+		}}
+	} else {
+		frequencyFilter = bson.M{"notification_preferences.digest": frequency}
+	}
+
+	filter := bson.M{"$and": []bson.M{frequencyFilter, notSentSince(sentBefore)}}
+	if afterID != "" {
+		after, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"email": 1, "name": 1, "notification_preferences": 1, "digest_sent_at": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) ClaimDigest(ctx context.Context, userID string, sentBefore time.Time, at time.Time) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return false, err
+	}
+
+	filter := notSentSince(sentBefore)
+	filter["_id"] = oid
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"digest_sent_at": at}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *UserRepository) ReleaseDigest(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$unset": bson.M{"digest_sent_at": ""}}
+	if previous != nil {
+		update = bson.M{"$set": bson.M{"digest_sent_at": *previous}}
+	}
+	// Another run may have claimed the digest since
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid, "digest_sent_at": claimedAt}, update)
+	return err
+}
@@ -147,3 +147,50 @@ func TestUserRepository_Update(t *testing.T) {
 		t.Error("Update() did not update UpdatedAt timestamp")
 	}
 }
+
+func TestUserRepository_UpdateNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+
+	// Clean up before test
+	_ = testDB.Collection("users").Drop(ctx)
+
+	user := &models.User{Email: "test@example.com", Name: "Test User"}
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two changes made from different sessions keep each other's choices
+	_, err := userRepo.UpdateNotificationPreferences(ctx, user.ID, models.UpdateNotificationPreferencesInput{
+		Types: map[models.NotificationType]bool{models.NotificationMention: false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs, err := userRepo.UpdateNotificationPreferences(ctx, user.ID, models.UpdateNotificationPreferencesInput{
+		Email:  map[models.NotificationType]bool{models.NotificationDocumentStatus: true},
+		Digest: models.DigestDaily,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prefs.Wants(models.NotificationMention) {
+		t.Error("UpdateNotificationPreferences() lost the earlier in-app choice")
+	}
+	if !prefs.WantsEmail(models.NotificationDocumentStatus) || prefs.DigestFrequency() != models.DigestDaily {
+		t.Errorf("UpdateNotificationPreferences() email choices not stored, got = %+v", prefs)
+	}
+
+	// Saving the user does not touch the preferences
+	user.Name = "Updated Name"
+	if err := userRepo.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	found, err := userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.NotificationPreferences.Wants(models.NotificationMention) {
+		t.Error("Update() overwrote the notification preferences")
+	}
+}
@@ -0,0 +1,185 @@
+// Package mongo internal/repository/mongo/webhook_delivery_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"time"
+)
+
+// WebhookDeliveryRepository is the durable queue of webhook deliveries. The
+// queue survives restarts: pending deliveries are picked up again once their
+// attempt time comes, and locks left by a crashed worker expire.
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database) *WebhookDeliveryRepository {
+	repo := &WebhookDeliveryRepository{
+		collection: db.Collection("webhook_deliveries"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create webhook delivery indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *WebhookDeliveryRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Workers look for pending deliveries whose time has come
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_attempt_at", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "webhook_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			// Events are delivered at least once, so one handled twice must
+			// not queue its deliveries twice; redeliveries are asked for
+			Keys: bson.D{
+				{Key: "webhook_id", Value: 1},
+				{Key: "event_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"redelivery_of": ""}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.CreatedAt = time.Now()
+	delivery.UpdatedAt = time.Now()
+	delivery.OrgID = orgFor(ctx, delivery.OrgID)
+	if delivery.Attempts == nil {
+		delivery.Attempts = []models.WebhookAttempt{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, delivery)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		delivery.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrWebhookDeliveryNotFound
+	}
+
+	var delivery models.WebhookDelivery
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) FindPageByWebhook(ctx context.Context, webhookID string, q pagination.Query) (*pagination.Page[*models.WebhookDelivery], error) {
+	filter := applyPageFilter(bson.M{"webhook_id": webhookID}, q.Filter)
+
+	page, err := paginate[models.WebhookDelivery](ctx, r.collection, scoped(ctx, filter), q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+	}
+	return page, nil
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          models.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"locked_until": bson.M{"$exists": false}},
+			{"locked_until": bson.M{"$lt": now}},
+		},
+	}
+
+	var delivery models.WebhookDelivery
+	err := r.collection.FindOneAndUpdate(ctx,
+		scoped(ctx, filter),
+		bson.M{"$set": bson.M{"locked_until": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	oid, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return errs.ErrWebhookDeliveryNotFound
+	}
+
+	delivery.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"updated_at":      delivery.UpdatedAt,
+		},
+		"$unset": bson.M{"locked_until": ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"webhook_id": webhookID}))
+	return err
+}
+
+func (r *WebhookDeliveryRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
@@ -0,0 +1,153 @@
+// Package mongo internal/repository/mongo/webhook_repository.go
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"time"
+)
+
+type WebhookRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) *WebhookRepository {
+	repo := &WebhookRepository{
+		collection: db.Collection("webhooks"),
+	}
+
+	// Ensure indexes are created
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create webhook indexes: %v", err)
+	}
+
+	return repo
+}
+
+func (r *WebhookRepository) ensureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "project_id", Value: 1},
+			{Key: "events", Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+	webhook.OrgID = orgFor(ctx, webhook.OrgID)
+
+	result, err := r.collection.InsertOne(ctx, webhook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		webhook.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	var webhook models.Webhook
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": oid})).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Webhook, error) {
+	return r.find(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *WebhookRepository) GetSubscribed(ctx context.Context, projectID string, event models.WebhookEvent) ([]*models.Webhook, error) {
+	return r.find(ctx, bson.M{"project_id": projectID, "events": event, "active": true})
+}
+
+func (r *WebhookRepository) find(ctx context.Context, filter bson.M) ([]*models.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, scoped(ctx, filter), options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []*models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	oid, err := primitive.ObjectIDFromHex(webhook.ID)
+	if err != nil {
+		return errs.ErrWebhookNotFound
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, scoped(ctx, bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{
+			"url":        webhook.URL,
+			"events":     webhook.Events,
+			"active":     webhook.Active,
+			"updated_at": webhook.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.ErrWebhookNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, scoped(ctx, bson.M{"project_id": projectID}))
+	return err
+}
@@ -0,0 +1,95 @@
+// internal/repository/compensation_test.go
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/repository"
+	"testing"
+)
+
+func TestRunCompensated_UndoesInReverseOnFailure(t *testing.T) {
+	var undone []string
+	failure := errors.New("second write failed")
+
+	err := repository.RunCompensated(context.Background(), func(ctx context.Context) error {
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			undone = append(undone, "first")
+			return nil
+		})
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			undone = append(undone, "second")
+			return errors.New("undo failed")
+		})
+		return failure
+	})
+
+	assert.Equal(t, failure, err)
+	// A failing undo does not stop the others
+	assert.Equal(t, []string{"second", "first"}, undone)
+}
+
+func TestRunCompensated_KeepsWritesOnSuccess(t *testing.T) {
+	undone := false
+	err := repository.RunCompensated(context.Background(), func(ctx context.Context) error {
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			undone = true
+			return nil
+		})
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, undone)
+}
+
+func TestRunCompensated_NestedCallsJoin(t *testing.T) {
+	var undone []string
+
+	err := repository.RunCompensated(context.Background(), func(ctx context.Context) error {
+		inner := repository.RunCompensated(ctx, func(ctx context.Context) error {
+			repository.OnRollback(ctx, func(ctx context.Context) error {
+				undone = append(undone, "inner")
+				return nil
+			})
+			return nil
+		})
+		assert.NoError(t, inner)
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			undone = append(undone, "outer")
+			return nil
+		})
+		return errors.New("outer failed")
+	})
+
+	assert.Error(t, err)
+	// The inner unit of work succeeded, yet is undone with the outer one
+	assert.Equal(t, []string{"outer", "inner"}, undone)
+}
+
+func TestRunCompensated_UndoesAfterCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var undoErr error
+
+	err := repository.RunCompensated(ctx, func(ctx context.Context) error {
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			undoErr = ctx.Err()
+			return nil
+		})
+		cancel()
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, undoErr)
+}
+
+func TestOnRollback_OutsideUnitOfWork(t *testing.T) {
+	called := false
+	repository.OnRollback(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+}
@@ -4,6 +4,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -13,6 +14,12 @@ import (
 type TokenStore interface {
 	Blacklist(ctx context.Context, token string, expiration time.Duration) error
 	IsBlacklisted(ctx context.Context, token string) (bool, error)
+	// SaveTicket keeps a one-time ticket standing for value until it is
+	// redeemed or expires
+	SaveTicket(ctx context.Context, ticket string, value string, expiration time.Duration) error
+	// RedeemTicket returns the value of a ticket and forgets the ticket. It
+	// reports false for tickets that are unknown, used or expired.
+	RedeemTicket(ctx context.Context, ticket string) (string, bool, error)
 }
 
 // RedisTokenStore implements TokenStore using Redis
@@ -39,3 +46,20 @@ func (s *RedisTokenStore) IsBlacklisted(ctx context.Context, token string) (bool
 	}
 	return exists > 0, nil
 }
+
+func (s *RedisTokenStore) SaveTicket(ctx context.Context, ticket string, value string, expiration time.Duration) error {
+	key := "ticket:" + ticket
+	return s.client.Set(ctx, key, value, expiration).Err()
+}
+
+func (s *RedisTokenStore) RedeemTicket(ctx context.Context, ticket string) (string, bool, error) {
+	key := "ticket:" + ticket
+	value, err := s.client.GetDel(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
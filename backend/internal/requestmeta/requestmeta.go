@@ -0,0 +1,29 @@
+// Package requestmeta internal/requestmeta/requestmeta.go
+//
+// Request metadata is where a change came from: the client's address and
+// user agent. The request metadata middleware puts it on the request context
+// so that events, and the audit log built from them, can record it without
+// every service passing it along.
+package requestmeta
+
+import "context"
+
+type contextKey struct{}
+
+// Meta describes the client a request came from
+type Meta struct {
+	IP        string
+	UserAgent string
+}
+
+// With returns a context carrying the metadata
+func With(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, contextKey{}, meta)
+}
+
+// From returns the metadata of a context; contexts that did not come from a
+// request, such as background jobs, have none
+func From(ctx context.Context) Meta {
+	meta, _ := ctx.Value(contextKey{}).(Meta)
+	return meta
+}
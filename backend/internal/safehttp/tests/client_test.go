@@ -0,0 +1,51 @@
+// internal/safehttp/client_test.go
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"projectnexus/internal/safehttp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, safehttp.IsPublic(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "2606:4700:4700::1111"} {
+		assert.True(t, safehttp.IsPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestNewClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Names are checked once resolved, so localhost is refused as well
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := safehttp.NewClient(time.Second).Get(url)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, safehttp.ErrForbiddenAddress)
+	}
+}
+
+func TestNewClient_DoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data/", http.StatusFound))
+	defer server.Close()
+
+	// The test server itself is on loopback, so the check is lifted here by
+	// using the redirect policy on an ordinary transport
+	client := safehttp.NewClient(time.Second)
+	client.Transport = http.DefaultTransport
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
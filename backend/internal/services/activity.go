@@ -0,0 +1,291 @@
+// Package services internal/services/activity.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+)
+
+type ActivityService interface {
+	// Subscribe records the activity feeds from the changes to projects,
+	// documents, mockups and project teams published on the bus
+	Subscribe(bus *events.Bus)
+
+	// ListActivity returns one page of the activity in every project the
+	// user can access
+	ListActivity(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Activity], error)
+	// ListProjectActivity returns one page of a project's activity, for its
+	// members
+	ListProjectActivity(ctx context.Context, projectID string, userID string, q pagination.Query) (*pagination.Page[*models.Activity], error)
+}
+
+type activityService struct {
+	activityRepo repository.ActivityRepository
+	projectRepo  repository.ProjectRepository
+	userRepo     repository.UserRepository
+	teamRepo     repository.TeamRepository
+}
+
+func NewActivityService(activityRepo repository.ActivityRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository, teamRepo repository.TeamRepository) ActivityService {
+	return &activityService{
+		activityRepo: activityRepo,
+		projectRepo:  projectRepo,
+		userRepo:     userRepo,
+		teamRepo:     teamRepo,
+	}
+}
+
+func (s *activityService) Subscribe(bus *events.Bus) {
+	const name = "activity"
+
+	// Projects; updates that changed the status are told by the status change
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectCreated) error {
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectCreated))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectUpdated) error {
+		if e.Project.Status != e.Previous.Status {
+			return nil
+		}
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectUpdated))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectStatusChanged) error {
+		activity := projectActivity(e.Project, models.ActivityProjectStatus)
+		activity.Detail = string(e.Project.Status)
+		return s.record(ctx, env, activity)
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectArchived) error {
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectArchived))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectUnarchived) error {
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectUnarchived))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectDeleted) error {
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectDeleted))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.ProjectRestored) error {
+		return s.record(ctx, env, projectActivity(e.Project, models.ActivityProjectRestored))
+	})
+
+	// Documents
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.DocumentCreated) error {
+		return s.record(ctx, env, documentActivity(e.Document, models.ActivityDocumentCreated))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.DocumentUpdated) error {
+		if e.Document.Status != e.Previous.Status {
+			return nil
+		}
+		return s.record(ctx, env, documentActivity(e.Document, models.ActivityDocumentEdited))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.DocumentStatusChanged) error {
+		if e.Document.Status == models.DocumentStatusApproved {
+			return s.record(ctx, env, documentActivity(e.Document, models.ActivityDocumentApproved))
+		}
+		activity := documentActivity(e.Document, models.ActivityDocumentStatus)
+		activity.Detail = string(e.Document.Status)
+		return s.record(ctx, env, activity)
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.DocumentDeleted) error {
+		return s.record(ctx, env, documentActivity(e.Document, models.ActivityDocumentDeleted))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.DocumentRestored) error {
+		return s.record(ctx, env, documentActivity(e.Document, models.ActivityDocumentRestored))
+	})
+
+	// Mockups
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MockupCreated) error {
+		return s.record(ctx, env, mockupActivity(e.Mockup, models.ActivityMockupCreated))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MockupUpdated) error {
+		return s.record(ctx, env, mockupActivity(e.Mockup, models.ActivityMockupEdited))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MockupDeleted) error {
+		return s.record(ctx, env, mockupActivity(e.Mockup, models.ActivityMockupDeleted))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MockupRestored) error {
+		return s.record(ctx, env, mockupActivity(e.Mockup, models.ActivityMockupRestored))
+	})
+
+	// Project teams
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MemberAdded) error {
+		return s.record(ctx, env, s.memberActivity(ctx, e.ProjectID, e.UserID, models.ActivityMemberAdded))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MemberUpdated) error {
+		if e.Member.Role == e.Previous.Role {
+			return nil
+		}
+		activity := s.memberActivity(ctx, e.Member.ProjectID, e.Member.UserID, models.ActivityMemberUpdated)
+		activity.Detail = string(e.Member.Role)
+		return s.record(ctx, env, activity)
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.MemberRemoved) error {
+		return s.record(ctx, env, s.memberActivity(ctx, e.ProjectID, e.UserID, models.ActivityMemberRemoved))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.TeamAssigned) error {
+		return s.record(ctx, env, s.teamActivity(ctx, e.ProjectID, e.Assignment.TeamID, models.ActivityTeamAssigned))
+	})
+	events.On(bus, name, func(ctx context.Context, env events.Envelope, e events.TeamUnassigned) error {
+		return s.record(ctx, env, s.teamActivity(ctx, e.ProjectID, e.Assignment.TeamID, models.ActivityTeamUnassigned))
+	})
+}
+
+func projectActivity(project models.Project, verb models.ActivityVerb) *models.Activity {
+	return &models.Activity{
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Type:        models.ActivityTypeProject,
+		Verb:        verb,
+		ResourceID:  project.ID,
+		Subject:     project.Name,
+	}
+}
+
+func documentActivity(doc models.Document, verb models.ActivityVerb) *models.Activity {
+	return &models.Activity{
+		ProjectID:  doc.ProjectID,
+		Type:       models.ActivityTypeDocument,
+		Verb:       verb,
+		ResourceID: doc.ID,
+		Subject:    doc.Title,
+	}
+}
+
+func mockupActivity(mockup models.Mockup, verb models.ActivityVerb) *models.Activity {
+	return &models.Activity{
+		ProjectID:  mockup.ProjectID,
+		Type:       models.ActivityTypeMockup,
+		Verb:       verb,
+		ResourceID: mockup.ID,
+		Subject:    mockup.Name,
+	}
+}
+
+func (s *activityService) memberActivity(ctx context.Context, projectID, userID string, verb models.ActivityVerb) *models.Activity {
+	return &models.Activity{
+		ProjectID:  projectID,
+		Type:       models.ActivityTypeTeam,
+		Verb:       verb,
+		ResourceID: userID,
+		Subject:    s.userName(ctx, userID),
+	}
+}
+
+func (s *activityService) teamActivity(ctx context.Context, projectID, teamID string, verb models.ActivityVerb) *models.Activity {
+	activity := &models.Activity{
+		ProjectID:  projectID,
+		Type:       models.ActivityTypeTeam,
+		Verb:       verb,
+		ResourceID: teamID,
+	}
+	if team, err := s.teamRepo.GetByID(ctx, teamID); err == nil {
+		activity.Subject = team.Name
+	} else {
+		log.Printf("Failed to look up team %s for activity: %v", teamID, err)
+	}
+	return activity
+}
+
+// userName returns the name the feeds show for a user, or "" when the user
+// cannot be found
+func (s *activityService) userName(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to look up user %s for activity: %v", userID, err)
+		return ""
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
+
+// record adds an event's activity to the feeds, joining the entry of a burst
+// of the same changes when there is one. Names are kept as they were at the
+// time, so the feeds still read well after things are renamed or removed.
+func (s *activityService) record(ctx context.Context, env events.Envelope, activity *models.Activity) error {
+	activity.OrgID = env.OrgID
+	activity.ActorID = env.ActorID
+	activity.ActorName = s.userName(ctx, env.ActorID)
+	activity.Count = 1
+	activity.EventIDs = []string{env.ID}
+	activity.StartedAt = env.OccurredAt
+	activity.OccurredAt = env.OccurredAt
+
+	if activity.ProjectName == "" {
+		if project, err := s.projectRepo.GetByID(ctx, activity.ProjectID); err == nil {
+			activity.ProjectName = project.Name
+		} else {
+			log.Printf("Failed to look up project %s for activity: %v", activity.ProjectID, err)
+		}
+	}
+
+	return s.activityRepo.Record(ctx, activity, env.OccurredAt.Add(-models.ActivityBurstWindow))
+}
+
+func (s *activityService) ListActivity(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Activity], error) {
+	if err := checkActivityQuery(q); err != nil {
+		return nil, err
+	}
+
+	// Only activity in projects the user can access now is shown
+	projects, err := s.projectRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		if q.Filter.ProjectID == "" || q.Filter.ProjectID == project.ID {
+			projectIDs = append(projectIDs, project.ID)
+		}
+	}
+
+	return s.findPage(ctx, projectIDs, q)
+}
+
+func (s *activityService) ListProjectActivity(ctx context.Context, projectID string, userID string, q pagination.Query) (*pagination.Page[*models.Activity], error) {
+	if err := checkActivityQuery(q); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return s.findPage(ctx, []string{project.ID}, q)
+}
+
+func (s *activityService) findPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Activity], error) {
+	page, err := s.activityRepo.FindPage(ctx, projectIDs, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range page.Items {
+		activity.Message = activity.Describe()
+	}
+	return page, nil
+}
+
+func checkActivityQuery(q pagination.Query) error {
+	if q.Filter.Type != "" && !models.ActivityType(q.Filter.Type).IsValid() {
+		return fmt.Errorf("%w: invalid activity type: %s", errors.ErrInvalidInput, q.Filter.Type)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package services internal/services/archive.go
+package services
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"time"
+)
+
+// ArchiveProject makes a project read-only and hides it from default
+// listings. Only owners can archive or unarchive a project.
+func (s *projectService) ArchiveProject(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.IsOwner(userID) {
+		return nil, errs.ErrUnauthorized
+	}
+	if project.IsArchived() {
+		return project, nil
+	}
+
+	now := time.Now()
+	if err := s.projectRepo.Archive(ctx, projectID, userID, now); err != nil {
+		return nil, err
+	}
+
+	project.ArchivedAt = &now
+	project.ArchivedBy = userID
+	project.UpdatedAt = now
+	s.bus.Publish(ctx, userID, events.ProjectArchived{Project: *project})
+	return project, nil
+}
+
+func (s *projectService) UnarchiveProject(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.IsOwner(userID) {
+		return nil, errs.ErrUnauthorized
+	}
+	if !project.IsArchived() {
+		return project, nil
+	}
+
+	if err := s.projectRepo.Unarchive(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	project, err = s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.ProjectUnarchived{Project: *project})
+	return project, nil
+}
+
+// checkWritable refuses changes to the content of an archived project
+func checkWritable(ctx context.Context, projectRepo repository.ProjectRepository, projectID string) error {
+	project, err := projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return errs.ErrProjectNotFound
+		}
+		return err
+	}
+	return project.CheckWritable()
+}
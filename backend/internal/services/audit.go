@@ -0,0 +1,255 @@
+// Package services internal/services/audit.go
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/tenant"
+	"strings"
+	"time"
+)
+
+// auditCSVHeader names the columns of a CSV export
+var auditCSVHeader = []string{"occurredAt", "action", "actorId", "actorEmail", "resourceType", "resourceId", "projectId", "ip", "userAgent", "changes"}
+
+type AuditService interface {
+	// Subscribe records every event on the bus in the audit log
+	Subscribe(bus *events.Bus)
+
+	// ListEntries returns one page of the organization's audit log; only
+	// organization admins may read it
+	ListEntries(ctx context.Context, q pagination.Query) (*pagination.Page[*models.AuditEntry], error)
+	// ListProjectEntries returns one page of a project's audit log, for its
+	// owners and organization admins
+	ListProjectEntries(ctx context.Context, projectID string, userID string, q pagination.Query) (*pagination.Page[*models.AuditEntry], error)
+
+	// ExportEntries writes the organization's entries matching the query's
+	// filters to w, latest first. Nothing is written when the caller may not
+	// read them.
+	ExportEntries(ctx context.Context, q pagination.Query, format models.AuditExportFormat, w io.Writer) error
+	// ExportProjectEntries writes a project's entries like ExportEntries
+	ExportProjectEntries(ctx context.Context, projectID string, userID string, q pagination.Query, format models.AuditExportFormat, w io.Writer) error
+}
+
+type auditService struct {
+	auditRepo   repository.AuditRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) AuditService {
+	return &auditService{
+		auditRepo:   auditRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *auditService) Subscribe(bus *events.Bus) {
+	bus.SubscribeAll("audit", s.record)
+}
+
+// record adds the entry for an event. Failing to store it has the event
+// redelivered when a transport is in use.
+func (s *auditService) record(ctx context.Context, env events.Envelope) error {
+	event, err := env.Event()
+	if err != nil {
+		// Retrying cannot decode it either
+		log.Printf("Failed to audit event %s: %v", env.ID, err)
+		return nil
+	}
+
+	entry := &models.AuditEntry{
+		OrgID:        env.OrgID,
+		EventID:      env.ID,
+		Action:       env.Name,
+		ActorID:      env.ActorID,
+		ResourceType: env.Subject.Type,
+		ResourceID:   env.Subject.ID,
+		ProjectID:    env.Subject.ProjectID,
+		Changes:      []models.AuditChange{},
+		IP:           env.IP,
+		UserAgent:    env.UserAgent,
+		OccurredAt:   env.OccurredAt,
+	}
+	if change, ok := event.(events.Change); ok {
+		entry.Changes = models.AuditDiff(change.Change())
+	}
+	if env.ActorID != "" {
+		if actor, err := s.userRepo.GetByID(ctx, env.ActorID); err == nil {
+			entry.ActorEmail = actor.Email
+		} else {
+			log.Printf("Failed to look up actor %s of event %s: %v", env.ActorID, env.ID, err)
+		}
+	}
+
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// checkOrgAdmin refuses callers who do not administer the organization they
+// act in
+func checkOrgAdmin(ctx context.Context) error {
+	scope, ok := tenant.FromContext(ctx)
+	if !ok || scope.Role != models.OrgRoleAdmin {
+		return errors.ErrUnauthorized
+	}
+	return nil
+}
+
+// checkProjectAuditor verifies the user may read the project's audit log:
+// its owners and the organization's admins may
+func (s *auditService) checkProjectAuditor(ctx context.Context, projectID string, userID string) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return errors.ErrProjectNotFound
+		}
+		return err
+	}
+
+	if project.IsOwner(userID) || checkOrgAdmin(ctx) == nil {
+		return nil
+	}
+	return errors.ErrUnauthorized
+}
+
+func (s *auditService) ListEntries(ctx context.Context, q pagination.Query) (*pagination.Page[*models.AuditEntry], error) {
+	if err := checkOrgAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.auditRepo.FindPage(ctx, q)
+}
+
+func (s *auditService) ListProjectEntries(ctx context.Context, projectID string, userID string, q pagination.Query) (*pagination.Page[*models.AuditEntry], error) {
+	if err := s.checkProjectAuditor(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	q.Filter.ProjectID = projectID
+	return s.auditRepo.FindPage(ctx, q)
+}
+
+func (s *auditService) ExportEntries(ctx context.Context, q pagination.Query, format models.AuditExportFormat, w io.Writer) error {
+	if !format.IsValid() {
+		return fmt.Errorf("%w: format must be csv or json", errors.ErrInvalidInput)
+	}
+	if err := checkOrgAdmin(ctx); err != nil {
+		return err
+	}
+
+	return s.export(ctx, q, format, w)
+}
+
+func (s *auditService) ExportProjectEntries(ctx context.Context, projectID string, userID string, q pagination.Query, format models.AuditExportFormat, w io.Writer) error {
+	if !format.IsValid() {
+		return fmt.Errorf("%w: format must be csv or json", errors.ErrInvalidInput)
+	}
+	if err := s.checkProjectAuditor(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	q.Filter.ProjectID = projectID
+	return s.export(ctx, q, format, w)
+}
+
+// export writes every matching entry page by page, so large exports are
+// never held in memory at once
+func (s *auditService) export(ctx context.Context, q pagination.Query, format models.AuditExportFormat, w io.Writer) error {
+	var write func(entry *models.AuditEntry) error
+	var finish func() error
+
+	switch format {
+	case models.AuditExportCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(auditCSVHeader); err != nil {
+			return err
+		}
+		write = func(entry *models.AuditEntry) error {
+			changes, err := json.Marshal(entry.Changes)
+			if err != nil {
+				return err
+			}
+			return out.Write([]string{
+				entry.OccurredAt.UTC().Format(time.RFC3339),
+				csvCell(entry.Action),
+				csvCell(entry.ActorID),
+				csvCell(entry.ActorEmail),
+				csvCell(entry.ResourceType),
+				csvCell(entry.ResourceID),
+				csvCell(entry.ProjectID),
+				csvCell(entry.IP),
+				csvCell(entry.UserAgent),
+				csvCell(string(changes)),
+			})
+		}
+		finish = func() error {
+			out.Flush()
+			return out.Error()
+		}
+	default:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		first := true
+		write = func(entry *models.AuditEntry) error {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if !first {
+				data = append([]byte(","), data...)
+			}
+			first = false
+			_, err = w.Write(data)
+			return err
+		}
+		finish = func() error {
+			_, err := io.WriteString(w, "]")
+			return err
+		}
+	}
+
+	q.Limit = pagination.MaxLimit
+	for {
+		page, err := s.auditRepo.FindPage(ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, entry := range page.Items {
+			if err := write(entry); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		if q, err = q.Resume(page.NextCursor); err != nil {
+			return fmt.Errorf("failed to page through entries: %w", err)
+		}
+	}
+
+	return finish()
+}
+
+// csvCell keeps a value from being read as a formula by spreadsheets, which
+// would run what users typed into titles or their user agent
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -3,6 +3,9 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"log"
@@ -18,21 +21,43 @@ type AuthService interface {
 	Register(ctx context.Context, input models.RegisterInput) (*models.AuthResponse, error)
 	Login(ctx context.Context, input models.LoginInput) (*models.AuthResponse, error)
 	ValidateToken(token string) (*models.User, error)
+	// Authenticate validates a token and checks that it was not revoked by
+	// logging out
+	Authenticate(ctx context.Context, token string) (*models.User, error)
 	RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error)
 	Logout(ctx context.Context, token string) error
+	// IssueStreamTicket hands out a ticket standing for the session in the
+	// organization, to open an event stream with
+	IssueStreamTicket(ctx context.Context, token string, orgID string) (*models.StreamTicket, error)
+	// RedeemStreamTicket uses up a ticket and returns its user and session.
+	// It fails with errors.ErrInvalidToken for unknown, used or expired
+	// tickets and for sessions that ended since.
+	RedeemStreamTicket(ctx context.Context, ticket string) (*models.User, *models.StreamSession, error)
+}
+
+// streamTicketTTL is how long a stream ticket can wait to be used; clients
+// ask for one right before opening the stream
+const streamTicketTTL = 30 * time.Second
+
+// RegistrationHook is told about every newly registered user and what they
+// signed up with
+type RegistrationHook interface {
+	UserRegistered(ctx context.Context, user *models.User, input models.RegisterInput) error
 }
 
 type authService struct {
-	userRepo   repository.UserRepository
-	jwtSecret  []byte
-	tokenStore repository.TokenStore
+	userRepo          repository.UserRepository
+	jwtSecret         []byte
+	tokenStore        repository.TokenStore
+	registrationHooks []RegistrationHook
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtSecret string, tokenStore repository.TokenStore) AuthService {
+func NewAuthService(userRepo repository.UserRepository, jwtSecret string, tokenStore repository.TokenStore, registrationHooks ...RegistrationHook) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		jwtSecret:  []byte(jwtSecret),
-		tokenStore: tokenStore,
+		userRepo:          userRepo,
+		jwtSecret:         []byte(jwtSecret),
+		tokenStore:        tokenStore,
+		registrationHooks: registrationHooks,
 	}
 }
 
@@ -57,6 +82,13 @@ func (s *authService) Register(ctx context.Context, input models.RegisterInput)
 		return nil, err
 	}
 
+	// Hooks run after the account exists; their failures do not undo it
+	for _, hook := range s.registrationHooks {
+		if err := hook.UserRegistered(ctx, user, input); err != nil {
+			log.Printf("Registration hook failed for user %s: %v", user.ID, err)
+		}
+	}
+
 	// Generate JWT token
 	token, err := s.generateToken(user)
 	if err != nil {
@@ -128,9 +160,7 @@ func (s *authService) generateToken(user *models.User) (string, error) {
 	return token.SignedString(s.jwtSecret)
 }
 
-// RefreshToken implementation
-func (s *authService) RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error) {
-	// Validate the existing token
+func (s *authService) Authenticate(ctx context.Context, token string) (*models.User, error) {
 	user, err := s.ValidateToken(token)
 	if err != nil {
 		return nil, err
@@ -144,6 +174,16 @@ func (s *authService) RefreshToken(ctx context.Context, token string) (*models.A
 	if isBlacklisted {
 		return nil, errors.ErrInvalidToken
 	}
+	return user, nil
+}
+
+// RefreshToken implementation
+func (s *authService) RefreshToken(ctx context.Context, token string) (*models.AuthResponse, error) {
+	// Validate the existing token
+	user, err := s.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate new token
 	newToken, err := s.generateToken(user)
@@ -203,3 +243,44 @@ func (s *authService) Logout(ctx context.Context, token string) error {
 
 	return nil
 }
+
+func (s *authService) IssueStreamTicket(ctx context.Context, token string, orgID string) (*models.StreamTicket, error) {
+	if _, err := s.Authenticate(ctx, token); err != nil {
+		return nil, err
+	}
+
+	var raw [32]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate stream ticket: %w", err)
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(raw[:])
+	session, err := json.Marshal(models.StreamSession{Token: token, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenStore.SaveTicket(ctx, ticket, string(session), streamTicketTTL); err != nil {
+		return nil, fmt.Errorf("failed to save stream ticket: %w", err)
+	}
+
+	return &models.StreamTicket{Ticket: ticket, ExpiresAt: time.Now().Add(streamTicketTTL)}, nil
+}
+
+func (s *authService) RedeemStreamTicket(ctx context.Context, ticket string) (*models.User, *models.StreamSession, error) {
+	value, ok, err := s.tokenStore.RedeemTicket(ctx, ticket)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, errors.ErrInvalidToken
+	}
+
+	var session models.StreamSession
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, nil, errors.ErrInvalidToken
+	}
+	user, err := s.Authenticate(ctx, session.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, &session, nil
+}
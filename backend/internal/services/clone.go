@@ -0,0 +1,353 @@
+// Package services internal/services/clone.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"sort"
+	"strings"
+)
+
+// projectCopy is the content a new project starts with. Documents and
+// mockups carry the IDs they have in the source, which links refer to; the
+// copies get new IDs and their links are rewritten to match.
+type projectCopy struct {
+	// sourceID is the ID of the copied project, if any
+	sourceID  string
+	documents []*models.Document
+	mockups   []*models.Mockup
+	// links are the links created by hand between the copied items
+	links    []*models.Link
+	members  []models.TemplateMember
+	teams    []models.TemplateTeam
+	progress *models.ProgressSettings
+}
+
+func (s *templateService) CloneProject(ctx context.Context, projectID string, input models.CloneProjectInput, userID string) (*models.Project, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	source, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &projectCopy{sourceID: source.ID}
+	content.documents, err = s.selectDocuments(ctx, projectID, input.Documents)
+	if err != nil {
+		return nil, err
+	}
+	if input.Mockups() {
+		content.mockups, err = s.mockupRepo.GetByProject(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mockups: %w", err)
+		}
+	}
+	content.links, err = s.manualLinks(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	if input.Members() {
+		content.members, content.teams, err = s.membership(ctx, source, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A copy computes its own progress instead of keeping a pinned value
+	if source.ProgressSettings != nil {
+		settings := *source.ProgressSettings
+		settings.Override = nil
+		content.progress = &settings
+	}
+
+	name := input.Name
+	if name == "" {
+		name = "Copy of " + source.Name
+	}
+	description := source.Description
+	if strings.TrimSpace(description) == "" {
+		description = name
+	}
+
+	return s.createProject(ctx, models.CreateProjectInput{
+		Name:        name,
+		Description: description,
+		Status:      source.Status,
+		StartDate:   source.StartDate,
+		EndDate:     source.EndDate,
+	}, content, userID)
+}
+
+// createProject creates a project owned by the user and fills it with the
+// content, all or nothing
+func (s *templateService) createProject(ctx context.Context, input models.CreateProjectInput, content *projectCopy, userID string) (*models.Project, error) {
+	var project *models.Project
+	// The project's events wait for the content to be committed with it
+	txCtx, held := events.Hold(ctx)
+	err := s.txManager.WithTransaction(txCtx, func(ctx context.Context) error {
+		var err error
+		project, err = s.projects.CreateProject(ctx, input, userID)
+		if err != nil {
+			return err
+		}
+
+		if content.progress != nil {
+			project.ProgressSettings = content.progress
+			if err := s.projectRepo.UpdateFields(ctx, project, "progress_settings"); err != nil {
+				return fmt.Errorf("failed to update project: %w", err)
+			}
+		}
+
+		return s.fill(ctx, project, content, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	held.Release(ctx)
+
+	refreshProgress(ctx, s.progress, project.ID)
+	for _, member := range content.members {
+		notifyProjectAdded(ctx, s.notifications, project, member.UserID, userID)
+	}
+
+	// Members and teams changed the project after it was created
+	return s.projectRepo.GetByID(ctx, project.ID)
+}
+
+// fill copies documents, mockups, links and membership into a new project
+func (s *templateService) fill(ctx context.Context, project *models.Project, content *projectCopy, userID string) error {
+	ids := make(map[string]string, len(content.documents)+len(content.mockups)+1)
+	if content.sourceID != "" {
+		ids[content.sourceID] = project.ID
+	}
+	for _, doc := range content.documents {
+		ids[doc.ID] = primitive.NewObjectID().Hex()
+	}
+	for _, mockup := range content.mockups {
+		ids[mockup.ID] = primitive.NewObjectID().Hex()
+	}
+
+	docs := make([]*models.Document, 0, len(content.documents))
+	for _, source := range content.documents {
+		doc := &models.Document{
+			ID:        ids[source.ID],
+			OrgID:     project.OrgID,
+			ProjectID: project.ID,
+			Title:     source.Title,
+			Type:      source.Type,
+			Content:   models.RemapLinks(source.Content, ids),
+			Status:    source.Status,
+			Tags:      source.Tags,
+			CreatedBy: userID,
+		}
+		if err := s.documentRepo.Create(ctx, doc); err != nil {
+			return fmt.Errorf("failed to copy document %s: %w", source.ID, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	for _, source := range content.mockups {
+		mockup := &models.Mockup{
+			ID:        ids[source.ID],
+			OrgID:     project.OrgID,
+			ProjectID: project.ID,
+			Name:      source.Name,
+			Type:      source.Type,
+			Tool:      source.Tool,
+			Thumbnail: source.Thumbnail,
+			Status:    source.Status,
+			CreatedBy: userID,
+		}
+		if err := s.mockupRepo.Create(ctx, mockup); err != nil {
+			return fmt.Errorf("failed to copy mockup %s: %w", source.ID, err)
+		}
+	}
+
+	// Links in content are extracted once every copy exists
+	for _, doc := range docs {
+		if err := s.linkService.SyncDocumentLinks(ctx, doc, userID); err != nil {
+			return fmt.Errorf("failed to sync links of document %s: %w", doc.ID, err)
+		}
+	}
+	for _, source := range content.links {
+		link := &models.Link{
+			OrgID:      project.OrgID,
+			ProjectID:  project.ID,
+			SourceType: source.SourceType,
+			SourceID:   ids[source.SourceID],
+			TargetType: source.TargetType,
+			TargetID:   ids[source.TargetID],
+			Type:       source.Type,
+			CreatedBy:  userID,
+		}
+		if err := s.linkRepo.Create(ctx, link); err != nil && !stderrors.Is(err, errors.ErrLinkExists) {
+			return err
+		}
+	}
+
+	// Only people in the project's organization are added; anyone else would
+	// need an invitation they could turn down
+	var org *models.Organization
+	if len(content.members) > 0 {
+		var err error
+		if org, err = s.orgRepo.GetByID(ctx, project.OrgID); err != nil {
+			return fmt.Errorf("failed to get organization: %w", err)
+		}
+	}
+	added := make([]models.TemplateMember, 0, len(content.members))
+	for _, member := range content.members {
+		if member.UserID == userID {
+			continue
+		}
+		if org.Member(member.UserID) == nil {
+			log.Printf("Skipping member %s of new project %s: not a member of the organization", member.UserID, project.ID)
+			continue
+		}
+		if _, err := s.userRepo.GetByID(ctx, member.UserID); err != nil {
+			if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+				log.Printf("Skipping member %s of new project %s: user no longer exists", member.UserID, project.ID)
+				continue
+			}
+			return err
+		}
+		if _, err := s.teams.JoinProject(ctx, project.ID, member.UserID, member.Role); err != nil && !stderrors.Is(err, errors.ErrAlreadyInTeam) {
+			return fmt.Errorf("failed to add member %s: %w", member.UserID, err)
+		}
+		added = append(added, member)
+	}
+	// Only the members who were added are told about the project
+	content.members = added
+	for _, team := range content.teams {
+		_, err := s.teams.AssignTeam(ctx, project.ID, models.AssignTeamInput{TeamID: team.TeamID, Role: team.Role}, userID)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrTeamNotFound) {
+				log.Printf("Skipping team %s of new project %s: team no longer exists", team.TeamID, project.ID)
+				continue
+			}
+			return fmt.Errorf("failed to assign team %s: %w", team.TeamID, err)
+		}
+	}
+
+	return nil
+}
+
+// selectDocuments returns the documents of a project a copy takes. Approved
+// copies take each document as it was last approved, which may be an older
+// version than the current one.
+func (s *templateService) selectDocuments(ctx context.Context, projectID string, selection models.DocumentSelection) ([]*models.Document, error) {
+	if selection == models.DocumentSelectionNone {
+		return nil, nil
+	}
+	docs, err := s.documentRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	if selection != models.DocumentSelectionApproved {
+		return docs, nil
+	}
+
+	selected := make([]*models.Document, 0, len(docs))
+	for _, doc := range docs {
+		if selection.Includes(doc) {
+			selected = append(selected, doc)
+			continue
+		}
+		versions, err := s.documentRepo.GetVersions(ctx, doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get versions of document %s: %w", doc.ID, err)
+		}
+		// Versions come newest first
+		for _, version := range versions {
+			if version.Status == models.DocumentStatusApproved {
+				approved := *doc
+				approved.Content = version.Content
+				approved.Status = models.DocumentStatusApproved
+				selected = append(selected, &approved)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
+
+// manualLinks returns the links created by hand between the items of a copy.
+// Extracted links are left out; they are found again in the copied content.
+func (s *templateService) manualLinks(ctx context.Context, content *projectCopy) ([]*models.Link, error) {
+	copied := make(map[string]bool, len(content.documents)+len(content.mockups)+1)
+	copied[content.sourceID] = true
+	sources := make([]models.LinkRef, 0, len(content.documents)+len(content.mockups))
+	for _, doc := range content.documents {
+		copied[doc.ID] = true
+		sources = append(sources, models.LinkRef{Type: models.LinkItemDocument, ID: doc.ID})
+	}
+	for _, mockup := range content.mockups {
+		copied[mockup.ID] = true
+		sources = append(sources, models.LinkRef{Type: models.LinkItemMockup, ID: mockup.ID})
+	}
+
+	links := make([]*models.Link, 0)
+	for _, source := range sources {
+		outgoing, err := s.linkRepo.GetBySource(ctx, source.Type, source.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get links: %w", err)
+		}
+		for _, link := range outgoing {
+			if !link.Extracted && !link.Broken && copied[link.TargetID] {
+				links = append(links, link)
+			}
+		}
+	}
+	return links, nil
+}
+
+// membership lists who a project is shared with: its direct members, with
+// owners as owners, and its assigned teams. The user asking is left out, as
+// they own whatever is created from it.
+func (s *templateService) membership(ctx context.Context, project *models.Project, userID string) ([]models.TemplateMember, []models.TemplateTeam, error) {
+	records, err := s.teamMemberRepo.GetAllByProject(ctx, project.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+
+	roles := make(map[string]models.TeamRole)
+	known := make(map[string]bool, len(records))
+	for _, m := range records {
+		known[m.UserID] = true
+		if m.IsDirect() && m.Status == models.TeamMemberStatusActive {
+			roles[m.UserID] = m.Role
+		}
+	}
+	// Members added before member records existed are only on the team list
+	for _, id := range project.Team {
+		if !known[id] {
+			roles[id] = models.TeamRoleMember
+		}
+	}
+	for _, id := range project.Owners {
+		roles[id] = models.TeamRoleOwner
+	}
+	delete(roles, userID)
+
+	members := make([]models.TemplateMember, 0, len(roles))
+	for id, role := range roles {
+		members = append(members, models.TemplateMember{UserID: id, Role: role})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	teams := make([]models.TemplateTeam, 0, len(project.Teams))
+	for _, assignment := range project.Teams {
+		teams = append(teams, models.TemplateTeam{TeamID: assignment.TeamID, Role: assignment.Role})
+	}
+	return members, teams, nil
+}
@@ -0,0 +1,249 @@
+// Package services internal/services/digest.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"projectnexus/internal/mail"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+	"time"
+)
+
+const (
+	// digestDocumentLimit caps the documents looked at for one digest
+	digestDocumentLimit = 100
+	// digestDocumentsPerProject caps the documents listed under each project;
+	// the rest are counted
+	digestDocumentsPerProject = 5
+	// digestBatchSize is the number of recipients loaded at a time
+	digestBatchSize = 100
+)
+
+type DigestService interface {
+	// SendDigests emails the digests that are due at the given time. Daily
+	// digests go out every day at the digest hour and weekly ones on Mondays.
+	SendDigests(ctx context.Context, now time.Time) error
+
+	// RunDigests sends the due digests every interval until the context is
+	// done
+	RunDigests(ctx context.Context, interval time.Duration)
+}
+
+type digestService struct {
+	userRepo         repository.UserRepository
+	projectRepo      repository.ProjectRepository
+	documentRepo     repository.DocumentRepository
+	notificationRepo repository.NotificationRepository
+	mailer           mail.Mailer
+	secret           []byte
+	appURL           string
+	// hour is the hour of the day, in UTC, digests are sent at
+	hour int
+}
+
+func NewDigestService(userRepo repository.UserRepository, projectRepo repository.ProjectRepository, documentRepo repository.DocumentRepository, notificationRepo repository.NotificationRepository, mailer mail.Mailer, secret string, appURL string, hour int) DigestService {
+	return &digestService{
+		userRepo:         userRepo,
+		projectRepo:      projectRepo,
+		documentRepo:     documentRepo,
+		notificationRepo: notificationRepo,
+		mailer:           mailer,
+		secret:           []byte(secret),
+		appURL:           appURL,
+		hour:             hour,
+	}
+}
+
+// digestProject lists the changed documents of one project
+type digestProject struct {
+	Name      string
+	Link      string
+	Documents []digestDocument
+	More      int
+}
+
+type digestDocument struct {
+	Title  string
+	Status models.DocumentStatus
+	Link   string
+	// New marks documents created during the period
+	New bool
+}
+
+func (s *digestService) SendDigests(ctx context.Context, now time.Time) error {
+	for _, frequency := range []models.DigestFrequency{models.DigestDaily, models.DigestWeekly} {
+		due := s.lastDue(frequency, now)
+		afterID := ""
+		for {
+			users, err := s.userRepo.FindDigestRecipients(ctx, frequency, due, afterID, digestBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to find %s digest recipients: %w", frequency, err)
+			}
+
+			for _, user := range users {
+				if err := s.sendDigest(ctx, user, frequency, due, now); err != nil {
+					log.Printf("Failed to send %s digest to user %s: %v", frequency, user.ID, err)
+				}
+			}
+			if len(users) < digestBatchSize {
+				break
+			}
+			afterID = users[len(users)-1].ID
+		}
+	}
+	return nil
+}
+
+func (s *digestService) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SendDigests(ctx, time.Now()); err != nil {
+			log.Printf("Digest run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastDue returns the latest time a digest of the frequency was due. Users
+// not sent one since then are still owed it, which also covers runs missed
+// while the server was down.
+func (s *digestService) lastDue(frequency models.DigestFrequency, now time.Time) time.Time {
+	now = now.UTC()
+	due := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, time.UTC)
+	if due.After(now) {
+		due = due.AddDate(0, 0, -1)
+	}
+	if frequency == models.DigestWeekly {
+		daysSinceMonday := (int(due.Weekday()) + 6) % 7
+		due = due.AddDate(0, 0, -daysSinceMonday)
+	}
+	return due
+}
+
+// sendDigest claims the user's digest, so no other instance sends it too,
+// and emails it unless nothing happened during the period. The claim is
+// released when the digest cannot be sent, so the next run tries again.
+func (s *digestService) sendDigest(ctx context.Context, user *models.User, frequency models.DigestFrequency, due time.Time, now time.Time) error {
+	claimed, err := s.userRepo.ClaimDigest(ctx, user.ID, due, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := s.composeAndSend(ctx, user, frequency, due); err != nil {
+		if releaseErr := s.userRepo.ReleaseDigest(ctx, user.ID, now, user.DigestSentAt); releaseErr != nil {
+			log.Printf("Failed to release %s digest of user %s: %v", frequency, user.ID, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *digestService) composeAndSend(ctx context.Context, user *models.User, frequency models.DigestFrequency, due time.Time) error {
+	since := due.Add(-frequency.Period())
+	projects, err := s.changedProjects(ctx, user.ID, since)
+	if err != nil {
+		return err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	if len(projects) == 0 && unread == 0 {
+		return nil
+	}
+
+	period := "over the last week"
+	if frequency == models.DigestDaily {
+		period = "over the last day"
+	}
+	unsubscribe := unsubscribeLink(s.secret, s.appURL, user.ID)
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Your %s ProjectNexus summary", frequency),
+		Headers: map[string]string{"List-Unsubscribe": "<" + unsubscribe + ">"},
+	}
+	err = mail.Render(&msg, "digest", map[string]interface{}{
+		"Name":            user.Name,
+		"Period":          period,
+		"Projects":        projects,
+		"Unread":          unread,
+		"Link":            s.appURL,
+		"Frequency":       string(frequency),
+		"UnsubscribeLink": unsubscribe,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
+}
+
+// changedProjects groups the documents updated since the given time by
+// project, most recently changed first. Archived projects are left out.
+func (s *digestService) changedProjects(ctx context.Context, userID string, since time.Time) ([]*digestProject, error) {
+	projects, err := s.projectRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	byID := make(map[string]*models.Project, len(projects))
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		if project.IsArchived() {
+			continue
+		}
+		byID[project.ID] = project
+		projectIDs = append(projectIDs, project.ID)
+	}
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	page, err := s.documentRepo.FindPage(ctx, projectIDs, models.DocumentFilter{}, pagination.Query{
+		Limit:  digestDocumentLimit,
+		Sort:   pagination.SortField{Key: "updatedAt", Field: "updated_at", Kind: pagination.SortTime},
+		Desc:   true,
+		Filter: pagination.Filter{UpdatedSince: &since},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed documents: %w", err)
+	}
+
+	var changed []*digestProject
+	grouped := make(map[string]*digestProject)
+	for _, doc := range page.Items {
+		group, ok := grouped[doc.ProjectID]
+		if !ok {
+			project := byID[doc.ProjectID]
+			group = &digestProject{
+				Name: project.Name,
+				Link: fmt.Sprintf("%s/projects/%s", s.appURL, project.ID),
+			}
+			grouped[doc.ProjectID] = group
+			changed = append(changed, group)
+		}
+
+		if len(group.Documents) == digestDocumentsPerProject {
+			group.More++
+			continue
+		}
+		group.Documents = append(group.Documents, digestDocument{
+			Title:  doc.Title,
+			Status: doc.Status,
+			Link:   fmt.Sprintf("%s/documents/%s", s.appURL, doc.ID),
+			New:    !doc.CreatedAt.Before(since),
+		})
+	}
+
+	return changed, nil
+}
@@ -8,8 +8,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"log"
 	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository"
+	"time"
 )
 
 type DocumentService interface {
@@ -17,23 +20,56 @@ type DocumentService interface {
 	GetDocument(ctx context.Context, id string, userID string) (*models.Document, error)
 	UpdateDocument(ctx context.Context, id string, input models.UpdateDocumentInput, userID string) (*models.Document, error)
 	DeleteDocument(ctx context.Context, id string, userID string) error
-	ListDocuments(ctx context.Context, userID string) ([]*models.Document, error)
-	GetProjectDocuments(ctx context.Context, projectID string, userID string) ([]*models.Document, error)
+	ListDocuments(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Document], error)
+	GetProjectDocuments(ctx context.Context, projectID string, filter models.DocumentFilter, q pagination.Query, userID string) (*pagination.Page[*models.Document], error)
 	GetDocumentVersions(ctx context.Context, documentID string, userID string) ([]*models.DocumentVersion, error)
+	GetProjectTags(ctx context.Context, projectID string, userID string) ([]string, error)
+
+	// MigrateHistory fills in the author, last editor and approval time of
+	// documents saved before they were recorded apart
+	MigrateHistory(ctx context.Context) error
 }
 
 type documentService struct {
-	documentRepo repository.DocumentRepository
-	projectRepo  repository.ProjectRepository
+	documentRepo  repository.DocumentRepository
+	projectRepo   repository.ProjectRepository
+	folderRepo    repository.FolderRepository
+	linkService   LinkService
+	progress      ProgressService
+	notifications NotificationService
+	bus           *events.Bus
+	txManager     repository.TxManager
 }
 
-func NewDocumentService(documentRepo repository.DocumentRepository, projectRepo repository.ProjectRepository) DocumentService {
+func NewDocumentService(documentRepo repository.DocumentRepository, projectRepo repository.ProjectRepository, folderRepo repository.FolderRepository, linkService LinkService, progress ProgressService, notifications NotificationService, bus *events.Bus, txManager repository.TxManager) DocumentService {
 	return &documentService{
-		documentRepo: documentRepo,
-		projectRepo:  projectRepo,
+		documentRepo:  documentRepo,
+		projectRepo:   projectRepo,
+		folderRepo:    folderRepo,
+		linkService:   linkService,
+		progress:      progress,
+		notifications: notifications,
+		bus:           bus,
+		txManager:     txManager,
 	}
 }
 
+// checkFolder verifies a folder exists in the given project; an empty ID
+// means the project root and is always valid
+func (s *documentService) checkFolder(ctx context.Context, projectID, folderID string) error {
+	if folderID == "" {
+		return nil
+	}
+	folder, err := s.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return err
+	}
+	if folder.ProjectID != projectID {
+		return errors.ErrFolderNotFound
+	}
+	return nil
+}
+
 // Helper function to check if user has access to project
 func (s *documentService) hasProjectAccess(ctx context.Context, projectID string, userID string) (bool, error) {
 	// Add debug logging
@@ -59,18 +95,8 @@ func (s *documentService) hasProjectAccess(ctx context.Context, projectID string
 		return false, err
 	}
 
-	// Check if user is project creator or team member
-	if project.CreatedBy == userID {
-		return true, nil
-	}
-
-	for _, memberID := range project.Team {
-		if memberID == userID {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	// Check if user is a project owner or team member
+	return project.HasAccess(userID), nil
 }
 
 // CreateDocument creates a new document
@@ -85,7 +111,7 @@ func (s *documentService) CreateDocument(ctx context.Context, input models.Creat
 	// Validate input
 	if err := input.Validate(); err != nil {
 		log.Printf("Input validation failed: %v", err)
-		return nil, fmt.Errorf("invalid input: %w", err)
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
 	}
 
 	// Check project access
@@ -99,6 +125,17 @@ func (s *documentService) CreateDocument(ctx context.Context, input models.Creat
 	if !hasAccess {
 		return nil, errors.ErrUnauthorized
 	}
+	if err := checkWritable(ctx, s.projectRepo, input.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkFolder(ctx, input.ProjectID, input.FolderID); err != nil {
+		return nil, err
+	}
+	tags, err := models.NormalizeTags(input.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
 
 	doc := &models.Document{
 		ProjectID: input.ProjectID,
@@ -107,14 +144,33 @@ func (s *documentService) CreateDocument(ctx context.Context, input models.Creat
 		Content:   input.Content,
 		Version:   1,
 		Status:    input.Status,
+		FolderID:  input.FolderID,
+		Tags:      tags,
 		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if doc.Status == models.DocumentStatusApproved {
+		now := time.Now()
+		doc.ApprovedAt = &now
 	}
 
-	if err := s.documentRepo.Create(ctx, doc); err != nil {
+	// The document and its first version are written together
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.documentRepo.Create(ctx, doc)
+	})
+	if err != nil {
 		log.Printf("Error creating document: %v", err)
 		return nil, fmt.Errorf("failed to create document: %w", err)
 	}
 
+	// Link extraction is best effort; the document itself is already saved
+	if err := s.linkService.SyncDocumentLinks(ctx, doc, userID); err != nil {
+		log.Printf("Failed to sync links for document %s: %v", doc.ID, err)
+	}
+	refreshProgress(ctx, s.progress, doc.ProjectID)
+	s.notifyChanges(ctx, nil, doc, userID)
+	s.bus.Publish(ctx, userID, events.DocumentCreated{Document: *doc})
+
 	return doc, nil
 }
 
@@ -150,18 +206,8 @@ func (s *documentService) GetDocument(ctx context.Context, id string, userID str
 		return nil, err
 	}
 
-	// Check if user is project creator or team member
-	hasAccess := project.CreatedBy == userID
-	if !hasAccess {
-		for _, memberID := range project.Team {
-			if memberID == userID {
-				hasAccess = true
-				break
-			}
-		}
-	}
-
-	if !hasAccess {
+	// Check if user is a project owner or team member
+	if !project.HasAccess(userID) {
 		log.Printf("User %s not authorized to access document %s", userID, id)
 		return nil, errors.ErrUnauthorized
 	}
@@ -180,6 +226,34 @@ func (s *documentService) UpdateDocument(ctx context.Context, id string, input m
 		log.Printf("Failed to get existing document: %v", err)
 		return nil, err
 	}
+	if err := checkWritable(ctx, s.projectRepo, doc.ProjectID); err != nil {
+		return nil, err
+	}
+	previous := *doc
+
+	if input.FolderID != nil {
+		if err := s.checkFolder(ctx, doc.ProjectID, *input.FolderID); err != nil {
+			return nil, err
+		}
+		doc.FolderID = *input.FolderID
+	}
+	if input.Tags != nil {
+		tags, err := models.NormalizeTags(*input.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+		}
+		doc.Tags = tags
+	}
+
+	// Moving or tagging a document does not create a new version
+	if input.OnlyOrganization() {
+		if err := s.documentRepo.UpdateOrganization(ctx, doc.ID, doc.FolderID, doc.Tags); err != nil {
+			log.Printf("Failed to update document organization: %v", err)
+			return nil, fmt.Errorf("failed to update document: %w", err)
+		}
+		s.bus.Publish(ctx, userID, events.DocumentUpdated{Document: *doc, Previous: previous})
+		return doc, nil
+	}
 
 	// Update fields if provided
 	if input.Title != nil {
@@ -196,18 +270,95 @@ func (s *documentService) UpdateDocument(ctx context.Context, id string, input m
 	}
 	if input.Status != nil {
 		doc.Status = *input.Status
+		if doc.Status == models.DocumentStatusApproved && previous.Status != models.DocumentStatusApproved {
+			now := time.Now()
+			doc.ApprovedAt = &now
+		}
 	}
-	// Ensure CreatedBy is still set
-	doc.CreatedBy = userID
+	doc.UpdatedBy = userID
 
-	if err := s.documentRepo.Update(ctx, doc); err != nil {
+	// The document and its new version are written together
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.documentRepo.Update(ctx, doc)
+	})
+	if err != nil {
 		log.Printf("Failed to update document in repository: %v", err)
 		return nil, fmt.Errorf("failed to update document: %w", err)
 	}
 
+	if input.Content != nil {
+		if err := s.linkService.SyncDocumentLinks(ctx, doc, userID); err != nil {
+			log.Printf("Failed to sync links for document %s: %v", doc.ID, err)
+		}
+	}
+	if input.Status != nil || input.Type != nil {
+		refreshProgress(ctx, s.progress, doc.ProjectID)
+	}
+	s.notifyChanges(ctx, &previous, doc, userID)
+	s.bus.Publish(ctx, userID, events.DocumentUpdated{Document: *doc, Previous: previous})
+	if doc.Status != previous.Status {
+		s.bus.Publish(ctx, userID, events.DocumentStatusChanged{Document: *doc, PreviousStatus: previous.Status})
+	}
+
 	return doc, nil
 }
 
+// notifyChanges tells users about the mentions a change added to a document
+// and about a new status. previous is nil for new documents.
+func (s *documentService) notifyChanges(ctx context.Context, previous *models.Document, doc *models.Document, actorID string) {
+	previousContent := ""
+	if previous != nil {
+		previousContent = previous.Content
+	}
+	mentioned := models.NewMentions(previousContent, doc.Content)
+	statusChanged := previous != nil && previous.Status != doc.Status
+	if len(mentioned) == 0 && !statusChanged {
+		return
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, doc.ProjectID)
+	if err != nil {
+		log.Printf("Failed to load project %s for notifications: %v", doc.ProjectID, err)
+		return
+	}
+
+	notification := models.Notification{
+		OrgID:      project.OrgID,
+		ActorID:    actorID,
+		ProjectID:  project.ID,
+		DocumentID: doc.ID,
+		Subject:    doc.Title,
+	}
+
+	// Mentions of people outside the project would leak the document
+	recipients := make([]string, 0, len(mentioned))
+	for _, userID := range mentioned {
+		if project.HasAccess(userID) {
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) > 0 {
+		mention := notification
+		mention.Type = models.NotificationMention
+		notify(ctx, s.notifications, mention, recipients...)
+	}
+
+	if !statusChanged {
+		return
+	}
+	status := notification
+	status.Status = doc.Status
+	if doc.Status == models.DocumentStatusApproved {
+		status.Type = models.NotificationDocumentApproved
+		members := append(append([]string{}, project.OwnerIDs()...), project.Team...)
+		notify(ctx, s.notifications, status, members...)
+		return
+	}
+	// Other status changes go to the document's author and the project owners
+	status.Type = models.NotificationDocumentStatus
+	notify(ctx, s.notifications, status, append([]string{previous.CreatedBy}, project.OwnerIDs()...)...)
+}
+
 // DeleteDocument deletes a document
 func (s *documentService) DeleteDocument(ctx context.Context, id string, userID string) error {
 	doc, err := s.GetDocument(ctx, id, userID)
@@ -221,35 +372,49 @@ func (s *documentService) DeleteDocument(ctx context.Context, id string, userID
 		return err
 	}
 
-	if doc.CreatedBy != userID && project.CreatedBy != userID {
+	if doc.CreatedBy != userID && !project.IsOwner(userID) {
 		return errors.ErrUnauthorized
 	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
 
-	return s.documentRepo.Delete(ctx, id)
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.documentRepo.SoftDelete(ctx, id, userID); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.documentRepo.Restore(ctx, id)
+		})
+		return s.linkService.MarkItemDeleted(ctx, models.LinkItemDocument, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	refreshProgress(ctx, s.progress, doc.ProjectID)
+	s.bus.Publish(ctx, userID, events.DocumentDeleted{Document: *doc})
+	return nil
 }
 
-// ListDocuments lists all documents accessible to a user
-func (s *documentService) ListDocuments(ctx context.Context, userID string) ([]*models.Document, error) {
+// ListDocuments lists one page of the documents accessible to a user
+func (s *documentService) ListDocuments(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Document], error) {
 	// Get all projects the user has access to
 	projects, err := s.projectRepo.GetByUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	var allDocs []*models.Document
+	projectIDs := make([]string, 0, len(projects))
 	for _, project := range projects {
-		docs, err := s.documentRepo.GetByProject(ctx, project.ID)
-		if err != nil {
-			return nil, err
-		}
-		allDocs = append(allDocs, docs...)
+		projectIDs = append(projectIDs, project.ID)
 	}
 
-	return allDocs, nil
+	return s.documentRepo.FindPage(ctx, projectIDs, models.DocumentFilter{}, q)
 }
 
-// GetProjectDocuments gets all documents in a project
-func (s *documentService) GetProjectDocuments(ctx context.Context, projectID string, userID string) ([]*models.Document, error) {
+// GetProjectDocuments gets one page of the documents in a project
+func (s *documentService) GetProjectDocuments(ctx context.Context, projectID string, filter models.DocumentFilter, q pagination.Query, userID string) (*pagination.Page[*models.Document], error) {
 	log.Printf("Getting project documents - ProjectID: %s, UserID: %s", projectID, userID)
 
 	// Validate project ID
@@ -270,29 +435,19 @@ func (s *documentService) GetProjectDocuments(ctx context.Context, projectID str
 	}
 
 	// Check if user has access
-	hasAccess := project.CreatedBy == userID
-	if !hasAccess {
-		for _, memberID := range project.Team {
-			if memberID == userID {
-				hasAccess = true
-				break
-			}
-		}
-	}
-
-	if !hasAccess {
+	if !project.HasAccess(userID) {
 		log.Printf("User %s not authorized to access project %s", userID, projectID)
 		return nil, errors.ErrUnauthorized
 	}
 
 	// Get project documents
-	docs, err := s.documentRepo.GetByProject(ctx, projectID)
+	page, err := s.documentRepo.FindPage(ctx, []string{projectID}, filter, q)
 	if err != nil {
 		log.Printf("Error fetching project documents: %v", err)
 		return nil, err
 	}
 
-	return docs, nil
+	return page, nil
 }
 
 // GetDocumentVersions gets the version history of a document
@@ -310,3 +465,30 @@ func (s *documentService) GetDocumentVersions(ctx context.Context, documentID st
 
 	return versions, nil
 }
+
+// GetProjectTags lists the distinct tags used by documents in a project
+func (s *documentService) GetProjectTags(ctx context.Context, projectID string, userID string) ([]string, error) {
+	hasAccess, err := s.hasProjectAccess(ctx, projectID, userID)
+	if err != nil {
+		if err == errors.ErrProjectNotFound || err == mongo.ErrNoDocuments {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !hasAccess {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return s.documentRepo.GetProjectTags(ctx, projectID)
+}
+
+func (s *documentService) MigrateHistory(ctx context.Context) error {
+	filled, err := s.documentRepo.BackfillHistory(ctx)
+	if err != nil {
+		return err
+	}
+	if filled > 0 {
+		log.Printf("Filled in the history of %d documents", filled)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+// Package services internal/services/folder.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"strings"
+)
+
+type FolderService interface {
+	ListFolders(ctx context.Context, projectID string, userID string) ([]*models.FolderNode, error)
+	CreateFolder(ctx context.Context, projectID string, input models.CreateFolderInput, userID string) (*models.Folder, error)
+	RenameFolder(ctx context.Context, projectID, folderID string, input models.RenameFolderInput, userID string) (*models.Folder, error)
+	MoveFolder(ctx context.Context, projectID, folderID string, input models.MoveFolderInput, userID string) (*models.Folder, error)
+	ReorderFolders(ctx context.Context, projectID string, input models.ReorderFoldersInput, userID string) error
+	DeleteFolder(ctx context.Context, projectID, folderID string, userID string) error
+}
+
+type folderService struct {
+	folderRepo   repository.FolderRepository
+	documentRepo repository.DocumentRepository
+	projectRepo  repository.ProjectRepository
+	bus          *events.Bus
+	txManager    repository.TxManager
+}
+
+func NewFolderService(folderRepo repository.FolderRepository, documentRepo repository.DocumentRepository, projectRepo repository.ProjectRepository, bus *events.Bus, txManager repository.TxManager) FolderService {
+	return &folderService{
+		folderRepo:   folderRepo,
+		documentRepo: documentRepo,
+		projectRepo:  projectRepo,
+		bus:          bus,
+		txManager:    txManager,
+	}
+}
+
+// projectForMember loads a project and verifies the user is a member of it
+func (s *folderService) projectForMember(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+// checkAccess verifies the project exists and the user is a member of it
+func (s *folderService) checkAccess(ctx context.Context, projectID string, userID string) error {
+	_, err := s.projectForMember(ctx, projectID, userID)
+	return err
+}
+
+// checkWriteAccess also refuses changes to archived projects
+func (s *folderService) checkWriteAccess(ctx context.Context, projectID string, userID string) error {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	return project.CheckWritable()
+}
+
+// getProjectFolder loads a folder and verifies it belongs to the project
+func (s *folderService) getProjectFolder(ctx context.Context, projectID, folderID string) (*models.Folder, error) {
+	folder, err := s.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder.ProjectID != projectID {
+		return nil, errors.ErrFolderNotFound
+	}
+	return folder, nil
+}
+
+func (s *folderService) ListFolders(ctx context.Context, projectID string, userID string) ([]*models.FolderNode, error) {
+	if err := s.checkAccess(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.folderRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	return models.BuildFolderTree(folders), nil
+}
+
+func (s *folderService) CreateFolder(ctx context.Context, projectID string, input models.CreateFolderInput, userID string) (*models.Folder, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+	if err := s.checkWriteAccess(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != "" {
+		if _, err := s.getProjectFolder(ctx, projectID, input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	siblings, err := s.siblings(ctx, projectID, input.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &models.Folder{
+		ProjectID: projectID,
+		ParentID:  input.ParentID,
+		Name:      strings.TrimSpace(input.Name),
+		Position:  len(siblings),
+		CreatedBy: userID,
+	}
+
+	if err := s.folderRepo.Create(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.FolderCreated{Folder: *folder})
+	return folder, nil
+}
+
+func (s *folderService) RenameFolder(ctx context.Context, projectID, folderID string, input models.RenameFolderInput, userID string) (*models.Folder, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+	if err := s.checkWriteAccess(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	folder, err := s.getProjectFolder(ctx, projectID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *folder
+	folder.Name = strings.TrimSpace(input.Name)
+	if err := s.folderRepo.Update(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.FolderUpdated{Folder: *folder, Previous: previous})
+	return folder, nil
+}
+
+func (s *folderService) MoveFolder(ctx context.Context, projectID, folderID string, input models.MoveFolderInput, userID string) (*models.Folder, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+	if err := s.checkWriteAccess(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.folderRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Folder, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+
+	folder, ok := byID[folderID]
+	if !ok {
+		return nil, errors.ErrFolderNotFound
+	}
+
+	// Walk up from the new parent to make sure we don't create a cycle
+	if input.ParentID != "" {
+		if _, ok := byID[input.ParentID]; !ok {
+			return nil, errors.ErrFolderNotFound
+		}
+		id := input.ParentID
+		for steps := 0; id != "" && steps <= len(folders); steps++ {
+			if id == folderID {
+				return nil, errors.ErrInvalidMove
+			}
+			parent, ok := byID[id]
+			if !ok {
+				break
+			}
+			id = parent.ParentID
+		}
+	}
+
+	// Build the new sibling order with the folder inserted at the requested position
+	order := make([]string, 0)
+	for _, f := range folders {
+		if f.ParentID == input.ParentID && f.ID != folderID {
+			order = append(order, f.ID)
+		}
+	}
+	position := len(order)
+	if input.Position != nil && *input.Position < position {
+		position = *input.Position
+	}
+	order = append(order[:position], append([]string{folderID}, order[position:]...)...)
+
+	// The sibling orders before the move, to put back if it fails half way
+	siblings := func(parentID string) []string {
+		ids := make([]string, 0)
+		for _, f := range folders {
+			if f.ParentID == parentID {
+				ids = append(ids, f.ID)
+			}
+		}
+		return ids
+	}
+	previousOrder := siblings(input.ParentID)
+
+	previous := *folder
+	oldParentID := folder.ParentID
+	folder.ParentID = input.ParentID
+	folder.Position = position
+
+	// The folder and both sibling orders change together
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.folderRepo.Update(ctx, folder); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.folderRepo.Update(ctx, &previous)
+		})
+
+		if err := s.folderRepo.SetPositions(ctx, order); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.folderRepo.SetPositions(ctx, previousOrder)
+		})
+
+		// Close the gap left behind in the old parent
+		if oldParentID != input.ParentID {
+			remaining := make([]string, 0)
+			for _, f := range folders {
+				if f.ParentID == oldParentID && f.ID != folderID {
+					remaining = append(remaining, f.ID)
+				}
+			}
+			if err := s.folderRepo.SetPositions(ctx, remaining); err != nil {
+				return err
+			}
+			repository.OnRollback(ctx, func(ctx context.Context) error {
+				return s.folderRepo.SetPositions(ctx, siblings(oldParentID))
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.FolderUpdated{Folder: *folder, Previous: previous})
+	return folder, nil
+}
+
+func (s *folderService) ReorderFolders(ctx context.Context, projectID string, input models.ReorderFoldersInput, userID string) error {
+	if err := s.checkWriteAccess(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	siblings, err := s.siblings(ctx, projectID, input.ParentID)
+	if err != nil {
+		return err
+	}
+
+	// The new order must be a permutation of the current siblings
+	if len(siblings) != len(input.FolderIDs) {
+		return fmt.Errorf("%w: folder list must contain every folder under the parent exactly once", errors.ErrInvalidInput)
+	}
+	current := make(map[string]bool, len(siblings))
+	for _, f := range siblings {
+		current[f.ID] = true
+	}
+	for _, id := range input.FolderIDs {
+		if !current[id] {
+			return fmt.Errorf("%w: folder list must contain every folder under the parent exactly once", errors.ErrInvalidInput)
+		}
+		delete(current, id)
+	}
+
+	if err := s.folderRepo.SetPositions(ctx, input.FolderIDs); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.FoldersReordered{ProjectID: projectID, ParentID: input.ParentID, FolderIDs: input.FolderIDs})
+	return nil
+}
+
+func (s *folderService) DeleteFolder(ctx context.Context, projectID, folderID string, userID string) error {
+	if err := s.checkWriteAccess(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	folder, err := s.getProjectFolder(ctx, projectID, folderID)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.siblings(ctx, projectID, folderID)
+	if err != nil {
+		return err
+	}
+	docs, err := s.documentRepo.FindByProject(ctx, projectID, models.DocumentFilter{FolderID: &folderID})
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 || len(docs) > 0 {
+		return errors.ErrFolderNotEmpty
+	}
+
+	if err := s.folderRepo.Delete(ctx, folderID); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.FolderDeleted{Folder: *folder})
+	return nil
+}
+
+// siblings returns the folders directly under parentID ("" for top level)
+func (s *folderService) siblings(ctx context.Context, projectID, parentID string) ([]*models.Folder, error) {
+	folders, err := s.folderRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Folder, 0)
+	for _, f := range folders {
+		if f.ParentID == parentID {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,376 @@
+// Package services internal/services/invitation.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"net/url"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/mail"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/signedtoken"
+	"projectnexus/internal/tenant"
+	"time"
+)
+
+// invitationTokenPurpose scopes signed tokens to invitations
+const invitationTokenPurpose = "invitation"
+
+type InvitationService interface {
+	InviteToProject(ctx context.Context, projectID string, input models.CreateInvitationInput, inviterID string) (*models.Invitation, error)
+	InviteToTeam(ctx context.Context, teamID string, input models.CreateInvitationInput, inviterID string) (*models.Invitation, error)
+	ListProjectInvitations(ctx context.Context, projectID string, userID string) ([]*models.Invitation, error)
+	ListTeamInvitations(ctx context.Context, teamID string, userID string) ([]*models.Invitation, error)
+	RevokeProjectInvitation(ctx context.Context, projectID, invitationID string, userID string) error
+	RevokeTeamInvitation(ctx context.Context, teamID, invitationID string, userID string) error
+	// Accept joins the signed-in user to the project or team; the user's
+	// email must match the invited address
+	Accept(ctx context.Context, token string, userID string) (*models.Invitation, error)
+	// Decline needs only the token, so people can turn down an invitation
+	// without an account
+	Decline(ctx context.Context, token string) error
+	// UserRegistered accepts the invitation whose token the new user signed
+	// up with. Owning the invited address is not enough on its own, since
+	// nobody has verified it yet.
+	UserRegistered(ctx context.Context, user *models.User, input models.RegisterInput) error
+}
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	projectRepo    repository.ProjectRepository
+	teamRepo       repository.TeamRepository
+	userRepo       repository.UserRepository
+	teamService    TeamService
+	mailer         mail.Mailer
+	bus            *events.Bus
+	txManager      repository.TxManager
+	secret         []byte
+	ttl            time.Duration
+	appURL         string
+}
+
+func NewInvitationService(invitationRepo repository.InvitationRepository, projectRepo repository.ProjectRepository, teamRepo repository.TeamRepository, userRepo repository.UserRepository, teamService TeamService, mailer mail.Mailer, bus *events.Bus, txManager repository.TxManager, secret string, ttl time.Duration, appURL string) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		projectRepo:    projectRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		teamService:    teamService,
+		mailer:         mailer,
+		bus:            bus,
+		txManager:      txManager,
+		secret:         []byte(secret),
+		ttl:            ttl,
+		appURL:         appURL,
+	}
+}
+
+func (s *invitationService) InviteToProject(ctx context.Context, projectID string, input models.CreateInvitationInput, inviterID string) (*models.Invitation, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForOwner(ctx, projectID, inviterID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.invitationRepo.GetPendingByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{ProjectID: projectID}
+	return s.invite(ctx, invitation, pending, input, inviterID, "the project "+project.Name)
+}
+
+func (s *invitationService) InviteToTeam(ctx context.Context, teamID string, input models.CreateInvitationInput, inviterID string) (*models.Invitation, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	team, err := s.teamForManager(ctx, teamID, inviterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.invitationRepo.GetPendingByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{TeamID: teamID}
+	return s.invite(ctx, invitation, pending, input, inviterID, "the team "+team.Name)
+}
+
+// invite stores a new invitation and emails its link. A failed email is
+// logged rather than returned, since the invitation itself was created.
+func (s *invitationService) invite(ctx context.Context, invitation *models.Invitation, pending []*models.Invitation, input models.CreateInvitationInput, inviterID string, target string) (*models.Invitation, error) {
+	for _, existing := range pending {
+		if existing.Email == input.Email {
+			return nil, errors.ErrInvitationExists
+		}
+	}
+
+	invitation.Email = input.Email
+	invitation.Role = input.Role
+	invitation.InvitedBy = inviterID
+	invitation.Status = models.InvitationStatusPending
+	invitation.ExpiresAt = time.Now().Add(s.ttl)
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, inviterID, events.InvitationCreated{Invitation: *invitation})
+
+	inviterName := "A colleague"
+	if inviter, err := s.userRepo.GetByID(ctx, inviterID); err == nil && inviter.Name != "" {
+		inviterName = inviter.Name
+	}
+
+	token := signedtoken.Sign(s.secret, invitationTokenPurpose, invitation.ID, invitation.ExpiresAt)
+	link := fmt.Sprintf("%s/invitations/accept?token=%s", s.appURL, url.QueryEscape(token))
+	signup := fmt.Sprintf("%s/register?invitation=%s", s.appURL, url.QueryEscape(token))
+	err := s.mailer.Send(ctx, mail.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s invited you to %s on ProjectNexus", inviterName, target),
+		Body: fmt.Sprintf("%s invited you to join %s as %s.\n\nAccept the invitation: %s\n\nNew to ProjectNexus? Sign up with it: %s\n\nThe links expire on %s.",
+			inviterName, target, invitation.Role, link, signup, invitation.ExpiresAt.Format("January 2, 2006")),
+	})
+	if err != nil {
+		log.Printf("Error sending invitation %s: %v", invitation.ID, err)
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) ListProjectInvitations(ctx context.Context, projectID string, userID string) ([]*models.Invitation, error) {
+	if _, err := s.projectForOwner(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.GetPendingByProject(ctx, projectID)
+}
+
+func (s *invitationService) ListTeamInvitations(ctx context.Context, teamID string, userID string) ([]*models.Invitation, error) {
+	if _, err := s.teamForManager(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.GetPendingByTeam(ctx, teamID)
+}
+
+func (s *invitationService) RevokeProjectInvitation(ctx context.Context, projectID, invitationID string, userID string) error {
+	if _, err := s.projectForOwner(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	invitation, err := s.getInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.ProjectID != projectID {
+		return errors.ErrInvitationNotFound
+	}
+
+	return s.respond(ctx, invitation, models.InvitationStatusRevoked, userID)
+}
+
+func (s *invitationService) RevokeTeamInvitation(ctx context.Context, teamID, invitationID string, userID string) error {
+	if _, err := s.teamForManager(ctx, teamID, userID); err != nil {
+		return err
+	}
+
+	invitation, err := s.getInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.TeamID != teamID {
+		return errors.ErrInvitationNotFound
+	}
+
+	return s.respond(ctx, invitation, models.InvitationStatusRevoked, userID)
+}
+
+func (s *invitationService) Accept(ctx context.Context, token string, userID string) (*models.Invitation, error) {
+	// The token, not the organization the user is working in, decides what
+	// they get to join
+	ctx = tenant.Unscoped(ctx)
+
+	invitation, err := s.fromToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if models.NormalizeEmail(user.Email) != invitation.Email {
+		return nil, errors.ErrInvitationEmailMismatch
+	}
+
+	if err := s.accept(ctx, invitation, user.ID); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) Decline(ctx context.Context, token string) error {
+	// As with Accept, the token decides which invitation is declined
+	ctx = tenant.Unscoped(ctx)
+
+	invitation, err := s.fromToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.respond(ctx, invitation, models.InvitationStatusDeclined, "")
+}
+
+func (s *invitationService) UserRegistered(ctx context.Context, user *models.User, input models.RegisterInput) error {
+	if input.InvitationToken == "" {
+		return nil
+	}
+
+	ctx = tenant.Unscoped(ctx)
+	invitation, err := s.fromToken(ctx, input.InvitationToken)
+	if err != nil {
+		return err
+	}
+	if models.NormalizeEmail(user.Email) != invitation.Email {
+		return errors.ErrInvitationEmailMismatch
+	}
+
+	return s.accept(ctx, invitation, user.ID)
+}
+
+// accept adds the user with the invited role and closes the invitation. The
+// changes are announced in the invitation's organization, even when the
+// token was accepted from another one.
+func (s *invitationService) accept(ctx context.Context, invitation *models.Invitation, userID string) error {
+	txCtx, held := events.Hold(ctx)
+	err := s.txManager.WithTransaction(txCtx, func(ctx context.Context) error {
+		var err error
+		if invitation.ProjectID != "" {
+			_, err = s.teamService.JoinProject(ctx, invitation.ProjectID, userID, invitation.Role)
+		} else {
+			_, err = s.teamService.JoinTeam(ctx, invitation.TeamID, userID, invitation.Role)
+		}
+		// Someone already in the project or team has nothing left to join
+		if err != nil && !stderrors.Is(err, errors.ErrAlreadyInTeam) {
+			return err
+		}
+
+		return s.respond(ctx, invitation, models.InvitationStatusAccepted, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	held.Release(tenant.WithOrg(ctx, invitation.OrgID, ""))
+	return nil
+}
+
+func (s *invitationService) respond(ctx context.Context, invitation *models.Invitation, status models.InvitationStatus, userID string) error {
+	if err := s.invitationRepo.Respond(ctx, invitation.ID, status, userID); err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return errors.ErrInvitationClosed
+		}
+		return err
+	}
+
+	now := time.Now()
+	invitation.Status = status
+	invitation.RespondedBy = userID
+	invitation.RespondedAt = &now
+
+	var event events.Event
+	switch status {
+	case models.InvitationStatusAccepted:
+		event = events.InvitationAccepted{Invitation: *invitation}
+	case models.InvitationStatusDeclined:
+		event = events.InvitationDeclined{Invitation: *invitation}
+	default:
+		event = events.InvitationRevoked{Invitation: *invitation}
+	}
+	if tenant.OrgID(ctx) == "" {
+		ctx = tenant.WithOrg(ctx, invitation.OrgID, "")
+	}
+	s.bus.Publish(ctx, userID, event)
+	return nil
+}
+
+// fromToken resolves a signed token to its invitation, which must still be open
+func (s *invitationService) fromToken(ctx context.Context, token string) (*models.Invitation, error) {
+	invitationID, err := signedtoken.Verify(s.secret, invitationTokenPurpose, token, time.Now())
+	if err != nil {
+		if stderrors.Is(err, errors.ErrTokenExpired) {
+			return nil, errors.ErrInvitationClosed
+		}
+		return nil, err
+	}
+
+	invitation, err := s.getInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if !invitation.IsOpen(time.Now()) {
+		return nil, errors.ErrInvitationClosed
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) getInvitation(ctx context.Context, id string) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.GetByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) projectForOwner(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	// Only project owners can manage invitations
+	if !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return project, nil
+}
+
+func (s *invitationService) teamForManager(ctx context.Context, teamID string, userID string) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrTeamNotFound
+		}
+		return nil, err
+	}
+
+	if !team.CanManage(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return team, nil
+}
@@ -0,0 +1,315 @@
+// Package services internal/services/link.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+)
+
+type LinkService interface {
+	CreateLink(ctx context.Context, input models.CreateLinkInput, userID string) (*models.Link, error)
+	DeleteLink(ctx context.Context, id string, userID string) error
+	GetLinks(ctx context.Context, itemType models.LinkItemType, itemID string, userID string) ([]*models.Link, error)
+	GetBacklinks(ctx context.Context, itemType models.LinkItemType, itemID string, userID string) ([]*models.Link, error)
+	GetBrokenLinks(ctx context.Context, projectID string, userID string) ([]*models.Link, error)
+	// SyncDocumentLinks re-extracts a document's links as saved by userID
+	SyncDocumentLinks(ctx context.Context, doc *models.Document, userID string) error
+	MarkItemDeleted(ctx context.Context, itemType models.LinkItemType, itemID string) error
+	MarkItemRestored(ctx context.Context, itemType models.LinkItemType, itemID string) error
+	// MarkProjectDeleted breaks the links pointing at a project and at its
+	// active documents and mockups; call it before they are trashed
+	MarkProjectDeleted(ctx context.Context, projectID string) error
+	// MarkProjectRestored mends the links pointing at a project and at the
+	// documents and mockups active again after a restore
+	MarkProjectRestored(ctx context.Context, projectID string) error
+	PurgeItem(ctx context.Context, itemType models.LinkItemType, itemID string) error
+}
+
+type linkService struct {
+	linkRepo     repository.LinkRepository
+	documentRepo repository.DocumentRepository
+	mockupRepo   repository.MockupRepository
+	projectRepo  repository.ProjectRepository
+	bus          *events.Bus
+}
+
+func NewLinkService(linkRepo repository.LinkRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, projectRepo repository.ProjectRepository, bus *events.Bus) LinkService {
+	return &linkService{
+		linkRepo:     linkRepo,
+		documentRepo: documentRepo,
+		mockupRepo:   mockupRepo,
+		projectRepo:  projectRepo,
+		bus:          bus,
+	}
+}
+
+// resolveProject returns the project an item belongs to, or ErrNotFound
+// when the item does not exist
+func (s *linkService) resolveProject(ctx context.Context, itemType models.LinkItemType, itemID string) (*models.Project, error) {
+	if _, err := primitive.ObjectIDFromHex(itemID); err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	projectID := itemID
+	switch itemType {
+	case models.LinkItemDocument:
+		doc, err := s.documentRepo.GetByID(ctx, itemID)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrDocumentNotFound) {
+				return nil, errors.ErrNotFound
+			}
+			return nil, err
+		}
+		projectID = doc.ProjectID
+	case models.LinkItemMockup:
+		mockup, err := s.mockupRepo.GetByID(ctx, itemID)
+		if err != nil {
+			return nil, errors.ErrNotFound
+		}
+		projectID = mockup.ProjectID
+	case models.LinkItemProject:
+	default:
+		return nil, errors.ErrInvalidInput
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	return project, nil
+}
+
+// authorize resolves the item's project and checks the user belongs to it
+func (s *linkService) authorize(ctx context.Context, itemType models.LinkItemType, itemID string, userID string) (*models.Project, error) {
+	project, err := s.resolveProject(ctx, itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+func (s *linkService) CreateLink(ctx context.Context, input models.CreateLinkInput, userID string) (*models.Link, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.authorize(ctx, input.SourceType, input.SourceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Items of archived projects can still be the target of a link
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, input.TargetType, input.TargetID, userID); err != nil {
+		return nil, err
+	}
+
+	link := &models.Link{
+		ProjectID:  project.ID,
+		SourceType: input.SourceType,
+		SourceID:   input.SourceID,
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		Type:       input.Type,
+		CreatedBy:  userID,
+	}
+
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.LinkCreated{Link: *link})
+	return link, nil
+}
+
+func (s *linkService) DeleteLink(ctx context.Context, id string, userID string) error {
+	link, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, link.ProjectID)
+	if err != nil {
+		return errors.ErrProjectNotFound
+	}
+	if !project.HasAccess(userID) {
+		return errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	// Extracted links follow the document content and are removed by editing it
+	if link.Extracted {
+		return errors.ErrInvalidInput
+	}
+
+	if err := s.linkRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.LinkDeleted{Link: *link})
+	return nil
+}
+
+func (s *linkService) GetLinks(ctx context.Context, itemType models.LinkItemType, itemID string, userID string) ([]*models.Link, error) {
+	if _, err := s.authorize(ctx, itemType, itemID, userID); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.GetBySource(ctx, itemType, itemID)
+}
+
+func (s *linkService) GetBacklinks(ctx context.Context, itemType models.LinkItemType, itemID string, userID string) ([]*models.Link, error) {
+	if _, err := s.authorize(ctx, itemType, itemID, userID); err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.GetByTarget(ctx, itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Backlinks can come from other projects; only show the ones whose
+	// source the reader can see
+	access := make(map[string]bool)
+	visible := make([]*models.Link, 0, len(links))
+	for _, link := range links {
+		allowed, ok := access[link.ProjectID]
+		if !ok {
+			project, err := s.projectRepo.GetByID(ctx, link.ProjectID)
+			allowed = err == nil && project.HasAccess(userID)
+			access[link.ProjectID] = allowed
+		}
+		if allowed {
+			visible = append(visible, link)
+		}
+	}
+	return visible, nil
+}
+
+func (s *linkService) GetBrokenLinks(ctx context.Context, projectID string, userID string) ([]*models.Link, error) {
+	if _, err := s.authorize(ctx, models.LinkItemProject, projectID, userID); err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return s.linkRepo.GetBrokenByProject(ctx, projectID)
+}
+
+// SyncDocumentLinks re-extracts the internal links of a document's content.
+// References to items that cannot be resolved, or that live in projects the
+// user cannot access, are stored as broken links.
+func (s *linkService) SyncDocumentLinks(ctx context.Context, doc *models.Document, userID string) error {
+	refs := models.ExtractLinkRefs(doc.Content)
+	links := make([]*models.Link, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Type == models.LinkItemDocument && ref.ID == doc.ID {
+			continue
+		}
+
+		link := &models.Link{
+			ProjectID:  doc.ProjectID,
+			SourceType: models.LinkItemDocument,
+			SourceID:   doc.ID,
+			TargetType: ref.Type,
+			TargetID:   ref.ID,
+			Type:       models.LinkTypeReferences,
+			CreatedBy:  userID,
+		}
+		if _, err := s.authorize(ctx, ref.Type, ref.ID, userID); err != nil {
+			if !stderrors.Is(err, errors.ErrNotFound) && !stderrors.Is(err, errors.ErrUnauthorized) {
+				return err
+			}
+			link.Broken = true
+		}
+		links = append(links, link)
+	}
+
+	log.Printf("Syncing %d extracted links for document %s", len(links), doc.ID)
+	return s.linkRepo.ReplaceExtracted(ctx, models.LinkItemDocument, doc.ID, links)
+}
+
+// MarkItemDeleted flags links pointing at a trashed item as broken. The
+// item's own outgoing links are kept so a restore brings them back.
+func (s *linkService) MarkItemDeleted(ctx context.Context, itemType models.LinkItemType, itemID string) error {
+	return s.linkRepo.MarkTargetBroken(ctx, itemType, itemID)
+}
+
+// MarkItemRestored clears the broken flag on links pointing at a restored item
+func (s *linkService) MarkItemRestored(ctx context.Context, itemType models.LinkItemType, itemID string) error {
+	return s.linkRepo.MarkTargetRestored(ctx, itemType, itemID)
+}
+
+func (s *linkService) MarkProjectDeleted(ctx context.Context, projectID string) error {
+	documentIDs, mockupIDs, err := s.projectItems(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := s.linkRepo.MarkTargetBroken(ctx, models.LinkItemProject, projectID); err != nil {
+		return err
+	}
+	if err := s.linkRepo.MarkTargetBroken(ctx, models.LinkItemDocument, documentIDs...); err != nil {
+		return err
+	}
+	return s.linkRepo.MarkTargetBroken(ctx, models.LinkItemMockup, mockupIDs...)
+}
+
+func (s *linkService) MarkProjectRestored(ctx context.Context, projectID string) error {
+	documentIDs, mockupIDs, err := s.projectItems(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := s.linkRepo.MarkTargetRestored(ctx, models.LinkItemProject, projectID); err != nil {
+		return err
+	}
+	if err := s.linkRepo.MarkTargetRestored(ctx, models.LinkItemDocument, documentIDs...); err != nil {
+		return err
+	}
+	return s.linkRepo.MarkTargetRestored(ctx, models.LinkItemMockup, mockupIDs...)
+}
+
+// projectItems returns the IDs of a project's active documents and mockups
+func (s *linkService) projectItems(ctx context.Context, projectID string) ([]string, []string, error) {
+	documents, err := s.documentRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	mockups, err := s.mockupRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documentIDs := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		documentIDs = append(documentIDs, doc.ID)
+	}
+	mockupIDs := make([]string, 0, len(mockups))
+	for _, mockup := range mockups {
+		mockupIDs = append(mockupIDs, mockup.ID)
+	}
+	return documentIDs, mockupIDs, nil
+}
+
+// PurgeItem drops the outgoing links of a permanently deleted item; links
+// pointing at it stay broken. Purging a project drops the links of every
+// item it contained.
+func (s *linkService) PurgeItem(ctx context.Context, itemType models.LinkItemType, itemID string) error {
+	if err := s.linkRepo.MarkTargetBroken(ctx, itemType, itemID); err != nil {
+		return err
+	}
+	if itemType == models.LinkItemProject {
+		return s.linkRepo.DeleteByProject(ctx, itemID)
+	}
+	return s.linkRepo.DeleteBySource(ctx, itemType, itemID)
+}
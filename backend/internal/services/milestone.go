@@ -0,0 +1,290 @@
+// Package services internal/services/milestone.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"time"
+)
+
+type MilestoneService interface {
+	// ListMilestones returns a project's milestones in timeline order, with
+	// their status as of now
+	ListMilestones(ctx context.Context, projectID string, filter models.MilestoneFilter, userID string) ([]*models.Milestone, error)
+	GetMilestone(ctx context.Context, projectID, milestoneID string, userID string) (*models.Milestone, error)
+	CreateMilestone(ctx context.Context, projectID string, input models.CreateMilestoneInput, userID string) (*models.Milestone, error)
+	UpdateMilestone(ctx context.Context, projectID, milestoneID string, input models.UpdateMilestoneInput, userID string) (*models.Milestone, error)
+	DeleteMilestone(ctx context.Context, projectID, milestoneID string, userID string) error
+	// ListOverdue returns the overdue milestones across the user's projects
+	ListOverdue(ctx context.Context, userID string) ([]*models.Milestone, error)
+}
+
+type milestoneService struct {
+	milestoneRepo repository.MilestoneRepository
+	taskRepo      repository.TaskRepository
+	projectRepo   repository.ProjectRepository
+	bus           *events.Bus
+	txManager     repository.TxManager
+	now           func() time.Time
+}
+
+func NewMilestoneService(milestoneRepo repository.MilestoneRepository, taskRepo repository.TaskRepository, projectRepo repository.ProjectRepository, bus *events.Bus, txManager repository.TxManager) MilestoneService {
+	return &milestoneService{
+		milestoneRepo: milestoneRepo,
+		taskRepo:      taskRepo,
+		projectRepo:   projectRepo,
+		bus:           bus,
+		txManager:     txManager,
+		now:           time.Now,
+	}
+}
+
+// projectForMember loads a project and verifies the user is a member of it
+func (s *milestoneService) projectForMember(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+// getProjectMilestone loads a milestone and verifies it belongs to the project
+func (s *milestoneService) getProjectMilestone(ctx context.Context, projectID, milestoneID string) (*models.Milestone, error) {
+	milestone, err := s.milestoneRepo.GetByID(ctx, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.ProjectID != projectID {
+		return nil, errors.ErrMilestoneNotFound
+	}
+	return milestone, nil
+}
+
+func (s *milestoneService) ListMilestones(ctx context.Context, projectID string, filter models.MilestoneFilter, userID string) ([]*models.Milestone, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: invalid milestone status: %s", errors.ErrInvalidInput, filter.Status)
+	}
+	if _, err := s.projectForMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.milestoneRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestones: %w", err)
+	}
+
+	// Status depends on the whole timeline, so filter after annotating
+	models.AnnotateMilestones(milestones, s.now())
+	result := make([]*models.Milestone, 0, len(milestones))
+	for _, m := range milestones {
+		if filter.Status != "" && m.Status != filter.Status {
+			continue
+		}
+		if filter.OwnerID != "" && m.OwnerID != filter.OwnerID {
+			continue
+		}
+		result = append(result, m)
+	}
+
+	return result, nil
+}
+
+func (s *milestoneService) GetMilestone(ctx context.Context, projectID, milestoneID string, userID string) (*models.Milestone, error) {
+	milestones, err := s.ListMilestones(ctx, projectID, models.MilestoneFilter{}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range milestones {
+		if m.ID == milestoneID {
+			return m, nil
+		}
+	}
+	return nil, errors.ErrMilestoneNotFound
+}
+
+func (s *milestoneService) CreateMilestone(ctx context.Context, projectID string, input models.CreateMilestoneInput, userID string) (*models.Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+	if err := checkMilestoneOwner(project, input.OwnerID); err != nil {
+		return nil, err
+	}
+
+	checklist, err := mergeChecklist(nil, input.Checklist)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone := &models.Milestone{
+		OrgID:       project.OrgID,
+		ProjectID:   projectID,
+		Title:       input.Title,
+		Description: input.Description,
+		DueDate:     input.DueDate,
+		OwnerID:     input.OwnerID,
+		Checklist:   checklist,
+		CreatedBy:   userID,
+	}
+
+	if err := s.milestoneRepo.Create(ctx, milestone); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.MilestoneCreated{Milestone: *milestone})
+
+	return s.GetMilestone(ctx, projectID, milestone.ID, userID)
+}
+
+func (s *milestoneService) UpdateMilestone(ctx context.Context, projectID, milestoneID string, input models.UpdateMilestoneInput, userID string) (*models.Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	milestone, err := s.getProjectMilestone(ctx, projectID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	previous := *milestone
+	previous.Checklist = append([]models.ChecklistItem(nil), milestone.Checklist...)
+
+	if input.Title != nil {
+		milestone.Title = *input.Title
+	}
+	if input.Description != nil {
+		milestone.Description = *input.Description
+	}
+	if input.DueDate != nil {
+		milestone.DueDate = *input.DueDate
+	}
+	if input.OwnerID != nil {
+		if err := checkMilestoneOwner(project, *input.OwnerID); err != nil {
+			return nil, err
+		}
+		milestone.OwnerID = *input.OwnerID
+	}
+	if input.Checklist != nil {
+		milestone.Checklist, err = mergeChecklist(milestone.Checklist, *input.Checklist)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.Completed != nil {
+		milestone.SetCompleted(*input.Completed, userID, s.now())
+	}
+
+	if err := s.milestoneRepo.Update(ctx, milestone); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.MilestoneUpdated{Milestone: *milestone, Previous: previous})
+
+	return s.GetMilestone(ctx, projectID, milestoneID, userID)
+}
+
+func (s *milestoneService) DeleteMilestone(ctx context.Context, projectID, milestoneID string, userID string) error {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	milestone, err := s.getProjectMilestone(ctx, projectID, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.milestoneRepo.Delete(ctx, milestoneID); err != nil {
+			return err
+		}
+		return s.taskRepo.ClearMilestone(ctx, milestoneID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.MilestoneDeleted{Milestone: *milestone})
+	return nil
+}
+
+func (s *milestoneService) ListOverdue(ctx context.Context, userID string) ([]*models.Milestone, error) {
+	projects, err := s.projectRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	now := s.now()
+	milestones, err := s.milestoneRepo.GetOpenDueBefore(ctx, projectIDs, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue milestones: %w", err)
+	}
+
+	for _, m := range milestones {
+		m.Overdue = true
+		m.Status = models.MilestoneStatusOverdue
+	}
+	return milestones, nil
+}
+
+// checkMilestoneOwner verifies a milestone owner is on the project
+func checkMilestoneOwner(project *models.Project, ownerID string) error {
+	if ownerID != "" && !project.HasAccess(ownerID) {
+		return fmt.Errorf("%w: milestone owner must be a project member", errors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// mergeChecklist builds a checklist from input, keeping the IDs of existing
+// items and giving new items fresh ones
+func mergeChecklist(current []models.ChecklistItem, input []models.ChecklistItemInput) ([]models.ChecklistItem, error) {
+	known := make(map[string]bool, len(current))
+	for _, item := range current {
+		known[item.ID] = true
+	}
+
+	checklist := make([]models.ChecklistItem, 0, len(input))
+	seen := make(map[string]bool, len(input))
+	for _, item := range input {
+		id := item.ID
+		switch {
+		case id == "":
+			id = primitive.NewObjectID().Hex()
+		case !known[id] || seen[id]:
+			return nil, fmt.Errorf("%w: unknown checklist item %s", errors.ErrInvalidInput, id)
+		}
+		seen[id] = true
+		checklist = append(checklist, models.ChecklistItem{ID: id, Title: item.Title, Done: item.Done})
+	}
+	return checklist, nil
+}
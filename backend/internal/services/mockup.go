@@ -4,86 +4,187 @@ package services
 import (
 	"context"
 	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/events"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository"
 )
 
+// MockupService is limited to the owners and team members of a mockup's
+// project, like the document service
 type MockupService interface {
-	CreateMockup(ctx context.Context, mockup *models.Mockup) error
-	GetMockupByID(ctx context.Context, id string) (*models.Mockup, error)
-	GetProjectMockups(ctx context.Context, projectID string) ([]*models.Mockup, error)
-	UpdateMockup(ctx context.Context, mockup *models.Mockup) error
-	DeleteMockup(ctx context.Context, id string) error
-	ListMockups(ctx context.Context) ([]*models.Mockup, error)
+	CreateMockup(ctx context.Context, mockup *models.Mockup, userID string) error
+	GetMockupByID(ctx context.Context, id string, userID string) (*models.Mockup, error)
+	GetProjectMockups(ctx context.Context, projectID string, userID string) ([]*models.Mockup, error)
+	UpdateMockup(ctx context.Context, mockup *models.Mockup, userID string) error
+	DeleteMockup(ctx context.Context, id string, userID string) error
+	// ListMockups returns one page of the mockups in the user's projects
+	ListMockups(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Mockup], error)
 }
 
 type mockupService struct {
 	mockupRepo  repository.MockupRepository
 	projectRepo repository.ProjectRepository
+	linkService LinkService
+	progress    ProgressService
+	bus         *events.Bus
 }
 
-func NewMockupService(mockupRepo repository.MockupRepository, projectRepo repository.ProjectRepository) MockupService {
+func NewMockupService(mockupRepo repository.MockupRepository, projectRepo repository.ProjectRepository, linkService LinkService, progress ProgressService, bus *events.Bus) MockupService {
 	return &mockupService{
 		mockupRepo:  mockupRepo,
 		projectRepo: projectRepo,
+		linkService: linkService,
+		progress:    progress,
+		bus:         bus,
 	}
 }
 
-func (s *mockupService) CreateMockup(ctx context.Context, mockup *models.Mockup) error {
-	// Verify project exists
-	_, err := s.projectRepo.GetByID(ctx, mockup.ProjectID)
+func (s *mockupService) CreateMockup(ctx context.Context, mockup *models.Mockup, userID string) error {
+	project, err := s.projectFor(ctx, mockup.ProjectID, userID)
 	if err != nil {
-		return errors.New("project not found")
+		return err
 	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	mockup.CreatedBy = userID
+	// Mockups are only trashed through DeleteMockup
+	mockup.DeletedAt = nil
+	mockup.DeletedBy = ""
 
-	return s.mockupRepo.Create(ctx, mockup)
+	if err := s.mockupRepo.Create(ctx, mockup); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, mockup.CreatedBy, events.MockupCreated{Mockup: *mockup})
+	refreshProgress(ctx, s.progress, mockup.ProjectID)
+	return nil
 }
 
-func (s *mockupService) GetMockupByID(ctx context.Context, id string) (*models.Mockup, error) {
-	return s.mockupRepo.GetByID(ctx, id)
+func (s *mockupService) GetMockupByID(ctx context.Context, id string, userID string) (*models.Mockup, error) {
+	mockup, _, err := s.getMockup(ctx, id, userID)
+	return mockup, err
 }
 
-func (s *mockupService) GetProjectMockups(ctx context.Context, projectID string) ([]*models.Mockup, error) {
-	// Verify project exists
-	_, err := s.projectRepo.GetByID(ctx, projectID)
-	if err != nil {
-		return nil, errors.New("project not found")
+func (s *mockupService) GetProjectMockups(ctx context.Context, projectID string, userID string) ([]*models.Mockup, error) {
+	if _, err := s.projectFor(ctx, projectID, userID); err != nil {
+		return nil, err
 	}
 
 	return s.mockupRepo.GetByProject(ctx, projectID)
 }
 
-func (s *mockupService) UpdateMockup(ctx context.Context, mockup *models.Mockup) error {
-	// Verify mockup exists
-	existingMockup, err := s.mockupRepo.GetByID(ctx, mockup.ID)
+func (s *mockupService) UpdateMockup(ctx context.Context, mockup *models.Mockup, userID string) error {
+	existingMockup, previousProject, err := s.getMockup(ctx, mockup.ID, userID)
 	if err != nil {
-		return errors.New("mockup not found")
+		return err
+	}
+	if err := previousProject.CheckWritable(); err != nil {
+		return err
 	}
 
-	// Verify project exists
-	_, err = s.projectRepo.GetByID(ctx, mockup.ProjectID)
-	if err != nil {
-		return errors.New("project not found")
+	// Moving a mockup needs access to the project it moves to as well
+	if existingMockup.ProjectID != mockup.ProjectID {
+		project, err := s.projectFor(ctx, mockup.ProjectID, userID)
+		if err != nil {
+			return err
+		}
+		if err := project.CheckWritable(); err != nil {
+			return err
+		}
 	}
 
 	// Preserve created by and timestamps
 	mockup.CreatedBy = existingMockup.CreatedBy
 	mockup.CreatedAt = existingMockup.CreatedAt
+	mockup.DeletedAt = nil
+	mockup.DeletedBy = ""
+
+	if err := s.mockupRepo.Update(ctx, mockup); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.MockupUpdated{Mockup: *mockup, Previous: *existingMockup})
+	refreshProgress(ctx, s.progress, mockup.ProjectID)
+	if existingMockup.ProjectID != mockup.ProjectID {
+		refreshProgress(ctx, s.progress, existingMockup.ProjectID)
+	}
+	return nil
+}
+
+func (s *mockupService) DeleteMockup(ctx context.Context, id string, userID string) error {
+	mockup, project, err := s.getMockup(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	if err := s.mockupRepo.SoftDelete(ctx, id, userID); err != nil {
+		return err
+	}
+	s.bus.Publish(ctx, userID, events.MockupDeleted{Mockup: *mockup})
+
+	if err := s.linkService.MarkItemDeleted(ctx, models.LinkItemMockup, id); err != nil {
+		log.Printf("Failed to mark links to mockup %s as broken: %v", id, err)
+	}
+	refreshProgress(ctx, s.progress, mockup.ProjectID)
 
-	return s.mockupRepo.Update(ctx, mockup)
+	return nil
 }
 
-func (s *mockupService) DeleteMockup(ctx context.Context, id string) error {
-	// Verify mockup exists
-	_, err := s.mockupRepo.GetByID(ctx, id)
+func (s *mockupService) ListMockups(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Mockup], error) {
+	projects, err := s.projectRepo.GetByUser(ctx, userID)
 	if err != nil {
-		return errors.New("mockup not found")
+		return nil, err
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
 	}
 
-	return s.mockupRepo.Delete(ctx, id)
+	return s.mockupRepo.FindPage(ctx, projectIDs, q)
 }
 
-func (s *mockupService) ListMockups(ctx context.Context) ([]*models.Mockup, error) {
-	// We'll add this method to the repository interface
-	return s.mockupRepo.List(ctx)
+// getMockup fetches a mockup and its project, provided the user can access
+// the project
+func (s *mockupService) getMockup(ctx context.Context, id string, userID string) (*models.Mockup, *models.Project, error) {
+	mockup, err := s.mockupRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return nil, nil, errs.ErrMockupNotFound
+		}
+		return nil, nil, err
+	}
+
+	project, err := s.projectFor(ctx, mockup.ProjectID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mockup, project, nil
+}
+
+// projectFor fetches a project the user owns or is a team member of
+func (s *mockupService) projectFor(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errs.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errs.ErrUnauthorized
+	}
+
+	return project, nil
 }
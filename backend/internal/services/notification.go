@@ -0,0 +1,337 @@
+// Package services internal/services/notification.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"net/url"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/mail"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/realtime"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/signedtoken"
+	"projectnexus/internal/tenant"
+	"time"
+)
+
+// Events sent to connected clients
+const (
+	// EventNotification carries a new notification
+	EventNotification = "notification"
+	// EventUnreadCount carries the number of unread notifications after some
+	// were read
+	EventUnreadCount = "unread"
+)
+
+const (
+	unsubscribeTokenPurpose = "unsubscribe"
+	// unsubscribeTokenTTL keeps the links in old emails working for a while
+	unsubscribeTokenTTL = 365 * 24 * time.Hour
+)
+
+type NotificationService interface {
+	ListNotifications(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Notification], error)
+	UnreadCount(ctx context.Context, userID string) (*models.UnreadCount, error)
+	MarkRead(ctx context.Context, id string, userID string) (*models.Notification, error)
+	MarkAllRead(ctx context.Context, userID string) (*models.UnreadCount, error)
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error)
+
+	// Stream delivers the user's notifications and unread counts for the
+	// organization of the context as they happen. The channel is closed once
+	// the context is done.
+	Stream(ctx context.Context, userID string) <-chan realtime.Message
+
+	// Notify records a notification for each recipient who wants notifications
+	// of its type and delivers it to their connected clients. The actor is
+	// never notified of their own change.
+	//
+	// Recipients who also want email for the type are emailed right away.
+	Notify(ctx context.Context, notification models.Notification, recipients []string) error
+
+	// Unsubscribe turns off every email of the user named by the token of an
+	// unsubscribe link
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	hub              *realtime.Hub
+	emails           *mail.Queue
+	bus              *events.Bus
+	// secret signs unsubscribe links
+	secret []byte
+	// appURL is the base of the links in emails
+	appURL string
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, hub *realtime.Hub, emails *mail.Queue, bus *events.Bus, secret string, appURL string) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		hub:              hub,
+		emails:           emails,
+		bus:              bus,
+		secret:           []byte(secret),
+		appURL:           appURL,
+	}
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Notification], error) {
+	if q.Filter.Type != "" && !models.NotificationType(q.Filter.Type).IsValid() {
+		return nil, fmt.Errorf("%w: invalid notification type: %s", errors.ErrInvalidInput, q.Filter.Type)
+	}
+	return s.notificationRepo.FindPageByUser(ctx, userID, q)
+}
+
+func (s *notificationService) UnreadCount(ctx context.Context, userID string) (*models.UnreadCount, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &models.UnreadCount{Count: count}, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, id string, userID string) (*models.Notification, error) {
+	notification, err := s.notificationRepo.MarkRead(ctx, id, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishUnreadCount(ctx, userID)
+	return notification, nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID string) (*models.UnreadCount, error) {
+	if _, err := s.notificationRepo.MarkAllRead(ctx, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	s.publishUnreadCount(ctx, userID)
+	return s.UnreadCount(ctx, userID)
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := user.NotificationPreferences.Resolved()
+	return &preferences, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	stored, err := s.updatePreferences(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := stored.Resolved()
+	return &preferences, nil
+}
+
+func (s *notificationService) Stream(ctx context.Context, userID string) <-chan realtime.Message {
+	orgID := tenant.OrgID(ctx)
+	sub := s.hub.Subscribe(userID)
+	out := make(chan realtime.Message)
+
+	go func() {
+		defer close(out)
+		defer s.hub.Unsubscribe(sub)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if msg.OrgID != "" && orgID != "" && msg.OrgID != orgID {
+					continue
+				}
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func (s *notificationService) Notify(ctx context.Context, notification models.Notification, recipients []string) error {
+	actorName := ""
+	if actor, err := s.getUser(ctx, notification.ActorID); err == nil {
+		actorName = actor.Name
+	}
+
+	var errs []error
+	seen := map[string]bool{notification.ActorID: true, "": true}
+	for _, userID := range recipients {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			if !stderrors.Is(err, errors.ErrUserNotFound) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		preferences := user.NotificationPreferences
+		n := notification
+		n.UserID = userID
+		n.Message = n.Describe(actorName)
+
+		if !preferences.Wants(n.Type) {
+			continue
+		}
+		if preferences.WantsEmail(n.Type) {
+			s.sendEmail(user, n)
+		}
+
+		if err := s.notificationRepo.Create(ctx, &n); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify user %s: %w", userID, err))
+			continue
+		}
+
+		s.hub.Publish(userID, realtime.Message{Event: EventNotification, OrgID: n.OrgID, Data: &n})
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func (s *notificationService) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := signedtoken.Verify(s.secret, unsubscribeTokenPurpose, token, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updatePreferences(ctx, userID, models.EmailOptOut())
+	return err
+}
+
+// updatePreferences changes only the listed choices of a user, leaving the
+// ones changed meanwhile elsewhere alone. The change is published in the
+// organization of the context, or the user's current one for unsubscribe
+// links, which are followed signed out.
+func (s *notificationService) updatePreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.userRepo.UpdateNotificationPreferences(ctx, userID, input)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+
+	if tenant.OrgID(ctx) == "" && user.CurrentOrgID != "" {
+		ctx = tenant.WithOrg(ctx, user.CurrentOrgID, "")
+	}
+	s.bus.Publish(ctx, userID, events.NotificationPreferencesUpdated{
+		UserID:      userID,
+		Preferences: preferences.Resolved(),
+		Previous:    user.NotificationPreferences.Resolved(),
+	})
+	return preferences, nil
+}
+
+// sendEmail queues the email of a notification, so a slow mail server does
+// not hold up the change that caused it
+func (s *notificationService) sendEmail(user *models.User, n models.Notification) {
+	unsubscribe := unsubscribeLink(s.secret, s.appURL, user.ID)
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: n.Message,
+		Headers: map[string]string{"List-Unsubscribe": "<" + unsubscribe + ">"},
+	}
+	err := mail.Render(&msg, "notification", map[string]string{
+		"Name":            user.Name,
+		"Message":         n.Message,
+		"Link":            notificationLink(s.appURL, &n),
+		"UnsubscribeLink": unsubscribe,
+	})
+	if err != nil {
+		log.Printf("Failed to email %s notification to user %s: %v", n.Type, user.ID, err)
+		return
+	}
+
+	if !s.emails.Enqueue(msg) {
+		log.Printf("Email queue full, dropped %s notification to user %s", n.Type, user.ID)
+	}
+}
+
+// publishUnreadCount tells the user's other open clients how many
+// notifications are left unread
+func (s *notificationService) publishUnreadCount(ctx context.Context, userID string) {
+	count, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to publish unread count of user %s: %v", userID, err)
+		return
+	}
+	s.hub.Publish(userID, realtime.Message{Event: EventUnreadCount, OrgID: tenant.OrgID(ctx), Data: count})
+}
+
+func (s *notificationService) getUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// notificationLink points to what a notification is about
+func notificationLink(appURL string, n *models.Notification) string {
+	if n.DocumentID != "" {
+		return fmt.Sprintf("%s/documents/%s", appURL, n.DocumentID)
+	}
+	return fmt.Sprintf("%s/projects/%s", appURL, n.ProjectID)
+}
+
+// unsubscribeLink lets a user turn off emails without signing in
+func unsubscribeLink(secret []byte, appURL string, userID string) string {
+	token := signedtoken.Sign(secret, unsubscribeTokenPurpose, userID, time.Now().Add(unsubscribeTokenTTL))
+	return fmt.Sprintf("%s/unsubscribe?token=%s", appURL, url.QueryEscape(token))
+}
+
+// notify records notifications without failing the change that caused them
+func notify(ctx context.Context, notifications NotificationService, notification models.Notification, recipients ...string) {
+	if err := notifications.Notify(ctx, notification, recipients); err != nil {
+		log.Printf("Failed to send %s notifications: %v", notification.Type, err)
+	}
+}
+
+// notifyProjectAdded tells a user that someone else added them to a project
+func notifyProjectAdded(ctx context.Context, notifications NotificationService, project *models.Project, userID string, actorID string) {
+	notify(ctx, notifications, models.Notification{
+		OrgID:     project.OrgID,
+		Type:      models.NotificationProjectAdded,
+		ActorID:   actorID,
+		ProjectID: project.ID,
+		Subject:   project.Name,
+	}, userID)
+}
@@ -0,0 +1,372 @@
+// Package services internal/services/organization.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/tenant"
+	"time"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, input models.CreateOrganizationInput, creatorID string) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, userID string) ([]*models.Organization, error)
+	GetOrganization(ctx context.Context, id string, userID string) (*models.Organization, error)
+	UpdateOrganization(ctx context.Context, id string, input models.UpdateOrganizationInput, userID string) (*models.Organization, error)
+	AddMember(ctx context.Context, orgID string, input models.AddOrgMemberInput, adderID string) (*models.OrgMembership, error)
+	UpdateMember(ctx context.Context, orgID, userID string, input models.UpdateOrgMemberInput, updaterID string) (*models.OrgMembership, error)
+	// RemoveMember is open to admins, and to members leaving by themselves
+	RemoveMember(ctx context.Context, orgID, userID string, removerID string) error
+	// SwitchOrganization makes an organization the user's current one
+	SwitchOrganization(ctx context.Context, orgID string, userID string) (*models.Organization, error)
+	// Resolve picks the organization a request acts in: the requested one,
+	// else the user's current one, else the first they belong to. Users who
+	// belong to none get one.
+	Resolve(ctx context.Context, user *models.User, requestedOrgID string) (*models.Organization, models.OrgRole, error)
+	// UserRegistered gives a new user a workspace of their own
+	UserRegistered(ctx context.Context, user *models.User, input models.RegisterInput) error
+	// MigrateLegacyData moves everything created before organizations existed
+	// into a default organization
+	MigrateLegacyData(ctx context.Context) error
+}
+
+type organizationService struct {
+	orgRepo  repository.OrganizationRepository
+	userRepo repository.UserRepository
+	bus      *events.Bus
+	// defaultAdmin is the email of the operator who administers the default
+	// organization; without one it has no admin until one is assigned
+	defaultAdmin string
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, userRepo repository.UserRepository, bus *events.Bus, defaultAdmin string) OrganizationService {
+	return &organizationService{
+		orgRepo:      orgRepo,
+		userRepo:     userRepo,
+		bus:          bus,
+		defaultAdmin: models.NormalizeEmail(defaultAdmin),
+	}
+}
+
+// publish announces a change in the organization it was made to, which need
+// not be the one the request acts in
+func (s *organizationService) publish(ctx context.Context, orgID string, actorID string, event events.Event) {
+	s.bus.Publish(tenant.WithOrg(ctx, orgID, ""), actorID, event)
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, input models.CreateOrganizationInput, creatorID string) (*models.Organization, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	org := &models.Organization{
+		Name:      input.Name,
+		CreatedBy: creatorID,
+		Members:   []models.OrgMembership{{UserID: creatorID, Role: models.OrgRoleAdmin, JoinedAt: time.Now()}},
+	}
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, org.ID, creatorID, events.OrganizationCreated{Organization: *org})
+
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context, userID string) ([]*models.Organization, error) {
+	return s.orgRepo.GetByMember(ctx, userID)
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, id string, userID string) (*models.Organization, error) {
+	org, err := s.getOrg(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if org.Member(userID) == nil {
+		return nil, errors.ErrOrgNotFound
+	}
+
+	return org, nil
+}
+
+func (s *organizationService) UpdateOrganization(ctx context.Context, id string, input models.UpdateOrganizationInput, userID string) (*models.Organization, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	org, err := s.orgForAdmin(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *org
+	org.Name = input.Name
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, org.ID, userID, events.OrganizationUpdated{Organization: *org, Previous: previous})
+
+	return org, nil
+}
+
+func (s *organizationService) AddMember(ctx context.Context, orgID string, input models.AddOrgMemberInput, adderID string) (*models.OrgMembership, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	if _, err := s.orgForAdmin(ctx, orgID, adderID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	member := models.OrgMembership{UserID: input.UserID, Role: input.Role, JoinedAt: time.Now()}
+	if err := s.orgRepo.AddMember(ctx, orgID, member); err != nil {
+		return nil, s.orgError(err)
+	}
+	s.publish(ctx, orgID, adderID, events.OrgMemberAdded{OrgID: orgID, UserID: member.UserID, Role: member.Role})
+
+	return &member, nil
+}
+
+func (s *organizationService) UpdateMember(ctx context.Context, orgID, userID string, input models.UpdateOrgMemberInput, updaterID string) (*models.OrgMembership, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	org, err := s.orgForAdmin(ctx, orgID, updaterID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := org.Member(userID)
+	if member == nil {
+		return nil, errors.ErrNotInOrg
+	}
+	if member.Role == models.OrgRoleAdmin && input.Role != models.OrgRoleAdmin && org.AdminCount() == 1 {
+		return nil, errors.ErrLastAdmin
+	}
+
+	if err := s.orgRepo.UpdateMemberRole(ctx, orgID, userID, input.Role); err != nil {
+		return nil, s.orgError(err)
+	}
+	s.publish(ctx, orgID, updaterID, events.OrgMemberUpdated{OrgID: orgID, UserID: userID, Role: input.Role, PreviousRole: member.Role})
+
+	member.Role = input.Role
+	return member, nil
+}
+
+func (s *organizationService) RemoveMember(ctx context.Context, orgID, userID string, removerID string) error {
+	org, err := s.GetOrganization(ctx, orgID, removerID)
+	if err != nil {
+		return err
+	}
+
+	if userID != removerID && !org.IsAdmin(removerID) {
+		return errors.ErrUnauthorized
+	}
+
+	member := org.Member(userID)
+	if member == nil {
+		return errors.ErrNotInOrg
+	}
+	if member.Role == models.OrgRoleAdmin && org.AdminCount() == 1 {
+		return errors.ErrLastAdmin
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, orgID, userID); err != nil {
+		return s.orgError(err)
+	}
+	s.publish(ctx, orgID, removerID, events.OrgMemberRemoved{OrgID: orgID, UserID: userID})
+
+	return nil
+}
+
+func (s *organizationService) SwitchOrganization(ctx context.Context, orgID string, userID string) (*models.Organization, error) {
+	org, err := s.GetOrganization(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	previous := user.CurrentOrgID
+	user.CurrentOrgID = org.ID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	s.publish(ctx, org.ID, userID, events.OrganizationSwitched{UserID: userID, OrgID: org.ID, PreviousID: previous})
+
+	return org, nil
+}
+
+func (s *organizationService) Resolve(ctx context.Context, user *models.User, requestedOrgID string) (*models.Organization, models.OrgRole, error) {
+	orgs, err := s.orgRepo.GetByMember(ctx, user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if requestedOrgID != "" {
+		for _, org := range orgs {
+			if org.ID == requestedOrgID {
+				return org, org.Member(user.ID).Role, nil
+			}
+		}
+		return nil, "", errors.ErrNotInOrg
+	}
+
+	for _, org := range orgs {
+		if org.ID == user.CurrentOrgID {
+			return org, org.Member(user.ID).Role, nil
+		}
+	}
+	if len(orgs) > 0 {
+		return orgs[0], orgs[0].Member(user.ID).Role, nil
+	}
+
+	org, err := s.provision(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return org, org.Member(user.ID).Role, nil
+}
+
+// provision finds an organization for a user who has none. Users from before
+// organizations existed join the default organization holding their data;
+// everyone else, including new users whose workspace could not be created
+// when they registered, gets a workspace of their own.
+func (s *organizationService) provision(ctx context.Context, user *models.User) (*models.Organization, error) {
+	org, err := s.orgRepo.GetDefault(ctx)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return s.createPersonal(ctx, user)
+		}
+		return nil, err
+	}
+	if user.CreatedAt.After(org.CreatedAt) {
+		return s.createPersonal(ctx, user)
+	}
+
+	// Only the configured operator runs the default organization, not
+	// whoever happens to sign in first
+	role := models.OrgRoleMember
+	if s.defaultAdmin != "" && models.NormalizeEmail(user.Email) == s.defaultAdmin {
+		role = models.OrgRoleAdmin
+	}
+	member := models.OrgMembership{UserID: user.ID, Role: role, JoinedAt: time.Now()}
+	if err := s.orgRepo.AddMember(ctx, org.ID, member); err != nil {
+		if !stderrors.Is(err, errors.ErrAlreadyInOrg) {
+			return nil, s.orgError(err)
+		}
+	} else {
+		s.publish(ctx, org.ID, user.ID, events.OrgMemberAdded{OrgID: org.ID, UserID: user.ID, Role: role})
+	}
+	org.Members = append(org.Members, member)
+
+	return org, nil
+}
+
+// UserRegistered gives a new user a workspace of their own, where their
+// registration is recorded
+func (s *organizationService) UserRegistered(ctx context.Context, user *models.User, _ models.RegisterInput) error {
+	org, err := s.createPersonal(ctx, user)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, org.ID, user.ID, events.UserRegistered{User: *user})
+	return nil
+}
+
+func (s *organizationService) createPersonal(ctx context.Context, user *models.User) (*models.Organization, error) {
+	name := "Personal workspace"
+	if user.Name != "" {
+		name = user.Name + "'s workspace"
+	}
+
+	return s.CreateOrganization(ctx, models.CreateOrganizationInput{Name: name}, user.ID)
+}
+
+func (s *organizationService) MigrateLegacyData(ctx context.Context) error {
+	orphans, err := s.orgRepo.CountOrphans(ctx)
+	if err != nil {
+		return err
+	}
+	if orphans == 0 {
+		return nil
+	}
+
+	org, err := s.orgRepo.GetDefault(ctx)
+	if stderrors.Is(err, errors.ErrNotFound) {
+		org = &models.Organization{Name: "Default workspace", Default: true}
+		err = s.orgRepo.Create(ctx, org)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Moving %d records without an organization into %s", orphans, org.ID)
+	return s.orgRepo.AdoptOrphans(ctx, org.ID)
+}
+
+func (s *organizationService) getOrg(ctx context.Context, id string) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrOrgNotFound
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationService) orgForAdmin(ctx context.Context, id string, userID string) (*models.Organization, error) {
+	org, err := s.GetOrganization(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !org.IsAdmin(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return org, nil
+}
+
+// orgError maps repository errors from member updates to service errors
+func (s *organizationService) orgError(err error) error {
+	switch {
+	case stderrors.Is(err, errors.ErrNotFound):
+		return errors.ErrOrgNotFound
+	case stderrors.Is(err, errors.ErrAlreadyInOrg), stderrors.Is(err, errors.ErrNotInOrg):
+		return err
+	default:
+		return fmt.Errorf("failed to update organization members: %w", err)
+	}
+}
+
+// joinOrg adds a user to an organization as a guest, unless they already
+// belong to it. Records made before organizations existed have no orgID and
+// need nothing.
+func joinOrg(ctx context.Context, orgRepo repository.OrganizationRepository, orgID, userID string) error {
+	if orgID == "" {
+		return nil
+	}
+
+	member := models.OrgMembership{UserID: userID, Role: models.OrgRoleGuest, JoinedAt: time.Now()}
+	err := orgRepo.AddMember(ctx, orgID, member)
+	if err != nil && !stderrors.Is(err, errors.ErrAlreadyInOrg) {
+		return fmt.Errorf("failed to add user to organization: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Package services internal/services/ownership.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"time"
+)
+
+// RequestOwnershipTransfer offers ownership of a project to another user. It
+// takes effect only once that user accepts it.
+func (s *projectService) RequestOwnershipTransfer(ctx context.Context, projectID string, input models.TransferOwnershipInput, requesterID string) (*models.Project, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only owners can hand out ownership
+	if !project.IsOwner(requesterID) {
+		return nil, errs.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+	if project.PendingTransfer != nil {
+		return nil, errs.ErrTransferPending
+	}
+	if project.IsOwner(input.UserID) {
+		return nil, fmt.Errorf("%w: user is already an owner", errs.ErrInvalidInput)
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, input.UserID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errs.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// The new owner has to reach the project to accept it
+	if err := joinOrg(ctx, s.orgRepo, project.OrgID, input.UserID); err != nil {
+		return nil, err
+	}
+
+	project.PendingTransfer = &models.OwnershipTransfer{
+		From:          requesterID,
+		To:            input.UserID,
+		KeepOwnership: input.KeepOwnership,
+		RequestedAt:   time.Now(),
+	}
+	// Only the first of two concurrent requests is stored
+	if err := s.projectRepo.RequestTransfer(ctx, project.ID, *project.PendingTransfer); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, requesterID, events.OwnershipTransferRequested{ProjectID: project.ID, Transfer: *project.PendingTransfer})
+	return project, nil
+}
+
+// AcceptOwnershipTransfer makes the invited user an owner and, unless the
+// transfer keeps it, takes ownership away from the user who offered it
+func (s *projectService) AcceptOwnershipTransfer(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	var project *models.Project
+	var accepted models.OwnershipTransfer
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		project, err = s.getProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+
+		transfer := project.PendingTransfer
+		if transfer == nil || transfer.To != userID {
+			return errs.ErrNoTransfer
+		}
+		if err := project.CheckWritable(); err != nil {
+			return err
+		}
+		// Clearing the transfer first accepts it only once
+		if err := s.projectRepo.ClearTransfer(ctx, project.ID, *transfer); err != nil {
+			return err
+		}
+
+		previousOwners := project.Owners
+		project.AddOwner(transfer.To)
+		if !containsString(project.Team, transfer.To) {
+			if err := s.projectRepo.AddToTeam(ctx, project.ID, transfer.To); err != nil && !errors.Is(err, errs.ErrAlreadyInTeam) {
+				return err
+			}
+			project.Team = append(project.Team, transfer.To)
+		}
+		if err := s.setMemberRole(ctx, project, transfer.To, models.TeamRoleOwner); err != nil {
+			return err
+		}
+
+		// The previous owner stays on the team as a regular member
+		if !transfer.KeepOwnership {
+			removed, err := project.RemoveOwner(transfer.From)
+			if err != nil {
+				return err
+			}
+			if removed {
+				if err := s.setMemberRole(ctx, project, transfer.From, models.TeamRoleMember); err != nil {
+					return err
+				}
+			}
+		}
+
+		accepted = *transfer
+		project.PendingTransfer = nil
+		return s.projectRepo.SetOwners(ctx, project.ID, previousOwners, project.Owners)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.OwnershipTransferAccepted{Project: *project, Transfer: accepted})
+	return project, nil
+}
+
+// DeclineOwnershipTransfer lets the invited user turn a transfer down
+func (s *projectService) DeclineOwnershipTransfer(ctx context.Context, projectID string, userID string) error {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	transfer := project.PendingTransfer
+	if transfer == nil || transfer.To != userID {
+		return errs.ErrNoTransfer
+	}
+
+	if err := s.projectRepo.ClearTransfer(ctx, project.ID, *transfer); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.OwnershipTransferDeclined{ProjectID: project.ID, Transfer: *transfer})
+	return nil
+}
+
+// CancelOwnershipTransfer withdraws a pending transfer; any owner may do so
+func (s *projectService) CancelOwnershipTransfer(ctx context.Context, projectID string, userID string) error {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if !project.IsOwner(userID) {
+		return errs.ErrUnauthorized
+	}
+	transfer := project.PendingTransfer
+	if transfer == nil {
+		return errs.ErrNoTransfer
+	}
+
+	if err := s.projectRepo.ClearTransfer(ctx, project.ID, *transfer); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.OwnershipTransferCancelled{ProjectID: project.ID, Transfer: *transfer})
+	return nil
+}
+
+// setMemberRole gives a user a direct, active member record with the role,
+// creating the record if the user has none
+func (s *projectService) setMemberRole(ctx context.Context, project *models.Project, userID string, role models.TeamRole) error {
+	members, err := s.teamMemberRepo.GetAllByProject(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.UserID != userID {
+			continue
+		}
+		member.Role = role
+		member.Status = models.TeamMemberStatusActive
+		member.Source = models.TeamMemberSourceDirect
+		member.Override = false
+		if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+			return fmt.Errorf("failed to update team member: %w", err)
+		}
+		return nil
+	}
+
+	member := &models.TeamMember{
+		OrgID:     project.OrgID,
+		ProjectID: project.ID,
+		UserID:    userID,
+		Role:      role,
+		Status:    models.TeamMemberStatusActive,
+		Source:    models.TeamMemberSourceDirect,
+	}
+	if err := s.teamMemberRepo.Create(ctx, member); err != nil {
+		return fmt.Errorf("failed to create team member: %w", err)
+	}
+	return nil
+}
+
+func (s *projectService) getProject(ctx context.Context, projectID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errs.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
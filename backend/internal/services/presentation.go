@@ -0,0 +1,295 @@
+// Package services internal/services/presentation.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"sort"
+	"time"
+)
+
+type PresentationService interface {
+	// GetPresentation gathers a project's team, approved documents, mockups
+	// and timeline with a fixed number of queries, however large the project
+	GetPresentation(ctx context.Context, projectID string, userID string) (*models.Presentation, error)
+}
+
+type presentationService struct {
+	projectRepo    repository.ProjectRepository
+	teamMemberRepo repository.TeamMemberRepository
+	userRepo       repository.UserRepository
+	documentRepo   repository.DocumentRepository
+	mockupRepo     repository.MockupRepository
+	milestoneRepo  repository.MilestoneRepository
+}
+
+func NewPresentationService(projectRepo repository.ProjectRepository, teamMemberRepo repository.TeamMemberRepository, userRepo repository.UserRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, milestoneRepo repository.MilestoneRepository) PresentationService {
+	return &presentationService{
+		projectRepo:    projectRepo,
+		teamMemberRepo: teamMemberRepo,
+		userRepo:       userRepo,
+		documentRepo:   documentRepo,
+		mockupRepo:     mockupRepo,
+		milestoneRepo:  milestoneRepo,
+	}
+}
+
+func (s *presentationService) GetPresentation(ctx context.Context, projectID string, userID string) (*models.Presentation, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	members, err := s.teamMemberRepo.GetProjectMembers(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	// Approved documents are those approved now, shown as they are now.
+	// Only the start of their content is loaded, with room for the markup
+	// the preview drops.
+	approved, err := s.documentRepo.FindByProject(ctx, projectID, models.DocumentFilter{
+		Status:        models.DocumentStatusApproved,
+		ContentLength: 4 * models.DocumentPreviewLength,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mockups, err := s.mockupRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	milestones, err := s.milestoneRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Everyone shown by name is looked up at once: the team, the authors of
+	// the documents and the owners of the milestones
+	userIDs := newIDSet()
+	userIDs.add(project.OwnerIDs()...)
+	userIDs.add(project.Team...)
+	for _, member := range members {
+		userIDs.add(member.UserID)
+	}
+	for _, doc := range approved {
+		userIDs.add(doc.CreatedBy)
+	}
+	for _, milestone := range milestones {
+		userIDs.add(milestone.OwnerID)
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs.ids)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	return &models.Presentation{
+		Project:   project,
+		Team:      presentationTeam(project, members, usersByID),
+		Documents: presentationDocuments(approved, usersByID),
+		Mockups:   presentationMockups(mockups),
+		Timeline:  presentationTimeline(project, milestones, usersByID, time.Now()),
+	}, nil
+}
+
+// idSet keeps IDs in the order they were first added
+type idSet struct {
+	ids  []string
+	seen map[string]bool
+}
+
+func newIDSet() *idSet {
+	return &idSet{ids: []string{}, seen: make(map[string]bool)}
+}
+
+func (s *idSet) add(ids ...string) {
+	for _, id := range ids {
+		if id == "" || s.seen[id] {
+			continue
+		}
+		s.seen[id] = true
+		s.ids = append(s.ids, id)
+	}
+}
+
+// displayName is how a user is shown; users that no longer exist show as
+// fallback
+func displayName(user *models.User, fallback string) string {
+	switch {
+	case user == nil:
+		return fallback
+	case user.Name != "":
+		return user.Name
+	default:
+		return user.Email
+	}
+}
+
+// presentationTeam lists the owners first, then the members by name. Members
+// stored on the project alone, from before member records existed, count as
+// plain members.
+func presentationTeam(project *models.Project, members []*models.TeamMember, usersByID map[string]*models.User) []models.PresentationMember {
+	roles := make(map[string]models.TeamRole)
+	fallbacks := make(map[string]*models.TeamMember)
+	ids := newIDSet()
+	for _, member := range members {
+		roles[member.UserID] = member.Role
+		fallbacks[member.UserID] = member
+		ids.add(member.UserID)
+	}
+	for _, id := range project.Team {
+		if _, ok := roles[id]; !ok {
+			roles[id] = models.TeamRoleMember
+		}
+		ids.add(id)
+	}
+	for _, id := range project.OwnerIDs() {
+		roles[id] = models.TeamRoleOwner
+		ids.add(id)
+	}
+
+	team := make([]models.PresentationMember, 0, len(ids.ids))
+	for _, id := range ids.ids {
+		entry := models.PresentationMember{UserID: id, Role: roles[id]}
+		if user := usersByID[id]; user != nil {
+			entry.Name = displayName(user, "")
+			entry.Email = user.Email
+		} else if member := fallbacks[id]; member != nil {
+			entry.Name = member.Name
+			entry.Email = member.Email
+		} else {
+			continue
+		}
+		entry.Initials = models.Initials(entry.Name, entry.Email)
+		team = append(team, entry)
+	}
+
+	sort.SliceStable(team, func(i, j int) bool {
+		iOwner, jOwner := team[i].Role == models.TeamRoleOwner, team[j].Role == models.TeamRoleOwner
+		if iOwner != jOwner {
+			return iOwner
+		}
+		return team[i].Name < team[j].Name
+	})
+	return team
+}
+
+// presentationDocuments lists the approved documents, most recently approved
+// first
+func presentationDocuments(documents []*models.Document, usersByID map[string]*models.User) []models.PresentationDocument {
+	result := make([]models.PresentationDocument, 0, len(documents))
+	for _, doc := range documents {
+		tags := doc.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		result = append(result, models.PresentationDocument{
+			ID:         doc.ID,
+			Title:      doc.Title,
+			Type:       doc.Type,
+			Version:    doc.Version,
+			Preview:    models.DocumentPreview(doc.Content, models.DocumentPreviewLength),
+			Tags:       tags,
+			AuthorID:   doc.CreatedBy,
+			AuthorName: displayName(usersByID[doc.CreatedBy], "Former member"),
+			ApprovedAt: doc.ApprovedAt,
+			UpdatedAt:  doc.UpdatedAt,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return approvedOrUpdated(result[i]).After(approvedOrUpdated(result[j]))
+	})
+	return result
+}
+
+// approvedOrUpdated stands in the last update for documents approved before
+// approval times were kept
+func approvedOrUpdated(doc models.PresentationDocument) time.Time {
+	if doc.ApprovedAt != nil {
+		return *doc.ApprovedAt
+	}
+	return doc.UpdatedAt
+}
+
+func presentationMockups(mockups []*models.Mockup) []models.PresentationMockup {
+	result := make([]models.PresentationMockup, 0, len(mockups))
+	for _, mockup := range mockups {
+		result = append(result, models.PresentationMockup{
+			ID:        mockup.ID,
+			Name:      mockup.Name,
+			Type:      mockup.Type,
+			Tool:      mockup.Tool,
+			Thumbnail: mockup.Thumbnail,
+			Status:    mockup.Status,
+			UpdatedAt: mockup.UpdatedAt,
+		})
+	}
+	return result
+}
+
+// presentationTimeline puts the project's start and end dates around its
+// milestones. The dates are completed once they have passed.
+func presentationTimeline(project *models.Project, milestones []*models.Milestone, usersByID map[string]*models.User, now time.Time) []models.TimelineEvent {
+	models.AnnotateMilestones(milestones, now)
+
+	dateStatus := func(date time.Time) models.MilestoneStatus {
+		if date.After(now) {
+			return models.MilestoneStatusUpcoming
+		}
+		return models.MilestoneStatusCompleted
+	}
+
+	timeline := make([]models.TimelineEvent, 0, len(milestones)+2)
+	if project.StartDate != nil {
+		timeline = append(timeline, models.TimelineEvent{
+			ID:     "start",
+			Kind:   models.TimelineEventStart,
+			Title:  "Project start",
+			Date:   *project.StartDate,
+			Status: dateStatus(*project.StartDate),
+		})
+	}
+	for _, milestone := range milestones {
+		event := models.TimelineEvent{
+			ID:         milestone.ID,
+			Kind:       models.TimelineEventMilestone,
+			Title:      milestone.Title,
+			Date:       milestone.DueDate,
+			Status:     milestone.Status,
+			AssigneeID: milestone.OwnerID,
+		}
+		if milestone.OwnerID != "" {
+			event.AssigneeName = displayName(usersByID[milestone.OwnerID], "Former member")
+		}
+		timeline = append(timeline, event)
+	}
+	if project.EndDate != nil {
+		timeline = append(timeline, models.TimelineEvent{
+			ID:     "end",
+			Kind:   models.TimelineEventEnd,
+			Title:  "Project end",
+			Date:   *project.EndDate,
+			Status: dateStatus(*project.EndDate),
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Date.Before(timeline[j].Date)
+	})
+	return timeline
+}
@@ -0,0 +1,172 @@
+// Package services internal/services/progress.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"time"
+)
+
+type ProgressService interface {
+	// GetProgress computes the progress breakdown of a project from its
+	// current documents and mockups
+	GetProgress(ctx context.Context, projectID string, userID string) (*models.ProgressBreakdown, error)
+	UpdateSettings(ctx context.Context, projectID string, input models.UpdateProgressSettingsInput, userID string) (*models.Project, error)
+	// Recompute refreshes the stored progress of a project that computes it;
+	// other projects are left alone
+	Recompute(ctx context.Context, projectID string) error
+}
+
+type progressService struct {
+	projectRepo  repository.ProjectRepository
+	documentRepo repository.DocumentRepository
+	mockupRepo   repository.MockupRepository
+	bus          *events.Bus
+}
+
+func NewProgressService(projectRepo repository.ProjectRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, bus *events.Bus) ProgressService {
+	return &progressService{
+		projectRepo:  projectRepo,
+		documentRepo: documentRepo,
+		mockupRepo:   mockupRepo,
+		bus:          bus,
+	}
+}
+
+func (s *progressService) GetProgress(ctx context.Context, projectID string, userID string) (*models.ProgressBreakdown, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	breakdown, err := s.compute(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Manual projects show what they would compute next to the entered value
+	if !project.ProgressSettings.Auto() {
+		breakdown.Progress = project.Progress
+		breakdown.Overridden = true
+	}
+	return breakdown, nil
+}
+
+func (s *progressService) UpdateSettings(ctx context.Context, projectID string, input models.UpdateProgressSettingsInput, userID string) (*models.Project, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+	// Only owners decide how progress is measured
+	if !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	previous := *project
+	if project.ProgressSettings != nil {
+		previousSettings := *project.ProgressSettings
+		previous.ProgressSettings = &previousSettings
+	}
+
+	settings := project.ProgressSettings
+	if settings == nil {
+		settings = &models.ProgressSettings{Mode: models.ProgressModeManual}
+	}
+	if input.Mode != nil {
+		settings.Mode = *input.Mode
+	}
+	if input.TypeWeights != nil {
+		settings.TypeWeights = *input.TypeWeights
+	}
+	if input.RequiredTypes != nil {
+		settings.RequiredTypes = *input.RequiredTypes
+	}
+	if input.MockupShare != nil {
+		settings.MockupShare = input.MockupShare
+	}
+	if input.ClearOverride {
+		settings.Override = nil
+	}
+	project.ProgressSettings = settings
+
+	fields := []string{"progress_settings", "progress_breakdown"}
+	if settings.Auto() {
+		if err := s.apply(ctx, project); err != nil {
+			return nil, err
+		}
+		fields = append(fields, "progress")
+	} else {
+		// Manual progress starts from the last value shown
+		project.ProgressBreakdown = nil
+	}
+
+	if err := s.projectRepo.UpdateFields(ctx, project, fields...); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, userID, events.ProjectUpdated{Project: *project, Previous: previous})
+	return project, nil
+}
+
+func (s *progressService) Recompute(ctx context.Context, projectID string) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.ErrProjectNotFound
+	}
+	if !project.ProgressSettings.Auto() {
+		return nil
+	}
+
+	if err := s.apply(ctx, project); err != nil {
+		return err
+	}
+	return s.projectRepo.SetProgress(ctx, project.ID, project.Progress, project.ProgressBreakdown)
+}
+
+// apply computes a project's progress and stores it on the project
+func (s *progressService) apply(ctx context.Context, project *models.Project) error {
+	breakdown, err := s.compute(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	project.ProgressBreakdown = breakdown
+	project.Progress = breakdown.Progress
+	return nil
+}
+
+func (s *progressService) compute(ctx context.Context, project *models.Project) (*models.ProgressBreakdown, error) {
+	documents, err := s.documentRepo.GetByProject(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	mockups, err := s.mockupRepo.GetByProject(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mockups: %w", err)
+	}
+
+	return models.ComputeProgress(project.ProgressSettings, documents, mockups, time.Now()), nil
+}
+
+// refreshProgress recomputes a project's progress after one of its documents
+// or mockups changed. It is best effort; the change itself is already saved.
+func refreshProgress(ctx context.Context, progress ProgressService, projectID string) {
+	if err := progress.Recompute(ctx, projectID); err != nil {
+		log.Printf("Failed to recompute progress of project %s: %v", projectID, err)
+	}
+}
@@ -4,12 +4,16 @@ package services
 import (
 	"context"
 	"errors" // Standard library for `errors.Is`
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"log"
 	errs "projectnexus/internal/errors"
-	"projectnexus/internal/models"     // For project models
+	"projectnexus/internal/events"
+	"projectnexus/internal/models" // For project models
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository" // For common errors
+	"projectnexus/internal/tenant"
 	"time"
 )
 
@@ -18,20 +22,43 @@ type ProjectService interface {
 	GetProject(ctx context.Context, id string, userID string) (*models.Project, error)
 	UpdateProject(ctx context.Context, id string, input models.UpdateProjectInput, userID string) (*models.Project, error)
 	DeleteProject(ctx context.Context, id string, userID string) error
-	ListProjects(ctx context.Context, userID string) ([]*models.Project, error)
+	ListProjects(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Project], error)
 	AddTeamMember(ctx context.Context, projectID string, userID string, adderID string) error
 	RemoveTeamMember(ctx context.Context, projectID string, userID string, removerID string) error
+	RequestOwnershipTransfer(ctx context.Context, projectID string, input models.TransferOwnershipInput, requesterID string) (*models.Project, error)
+	AcceptOwnershipTransfer(ctx context.Context, projectID string, userID string) (*models.Project, error)
+	DeclineOwnershipTransfer(ctx context.Context, projectID string, userID string) error
+	CancelOwnershipTransfer(ctx context.Context, projectID string, userID string) error
+	// Archived projects are read-only and hidden from default listings
+	ArchiveProject(ctx context.Context, projectID string, userID string) (*models.Project, error)
+	UnarchiveProject(ctx context.Context, projectID string, userID string) (*models.Project, error)
 }
 
 type projectService struct {
-	projectRepo repository.ProjectRepository
-	userRepo    repository.UserRepository // Add userRepo
+	projectRepo    repository.ProjectRepository
+	userRepo       repository.UserRepository // Add userRepo
+	documentRepo   repository.DocumentRepository
+	mockupRepo     repository.MockupRepository
+	teamMemberRepo repository.TeamMemberRepository
+	orgRepo        repository.OrganizationRepository
+	linkService    LinkService
+	notifications  NotificationService
+	bus            *events.Bus
+	txManager      repository.TxManager
 }
 
-func NewProjectService(projectRepo repository.ProjectRepository, userRepo repository.UserRepository) ProjectService {
+func NewProjectService(projectRepo repository.ProjectRepository, userRepo repository.UserRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, teamMemberRepo repository.TeamMemberRepository, orgRepo repository.OrganizationRepository, linkService LinkService, notifications NotificationService, bus *events.Bus, txManager repository.TxManager) ProjectService {
 	return &projectService{
-		projectRepo: projectRepo,
-		userRepo:    userRepo, // Initialize userRepo
+		projectRepo:    projectRepo,
+		userRepo:       userRepo, // Initialize userRepo
+		documentRepo:   documentRepo,
+		mockupRepo:     mockupRepo,
+		teamMemberRepo: teamMemberRepo,
+		orgRepo:        orgRepo,
+		linkService:    linkService,
+		notifications:  notifications,
+		bus:            bus,
+		txManager:      txManager,
 	}
 }
 
@@ -41,19 +68,32 @@ func (s *projectService) CreateProject(ctx context.Context, input models.CreateP
 		return nil, errs.ErrInvalidStatus
 	}
 
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+	}
+
+	// Guests work only in projects they were added to
+	if tenant.IsGuest(ctx) {
+		return nil, errs.ErrUnauthorized
+	}
+
 	project := &models.Project{
 		Name:        input.Name,
 		Description: input.Description,
 		Status:      input.Status,
 		Progress:    0,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
 		Team:        []string{userID},
 		CreatedBy:   userID,
+		Owners:      []string{userID},
 	}
 
 	if err := s.projectRepo.Create(ctx, project); err != nil {
 		return nil, err
 	}
 
+	s.bus.Publish(ctx, userID, events.ProjectCreated{Project: *project})
 	return project, nil
 }
 func (s *projectService) GetProject(ctx context.Context, id string, userID string) (*models.Project, error) {
@@ -63,36 +103,74 @@ func (s *projectService) GetProject(ctx context.Context, id string, userID strin
 	}
 
 	// Check if user is part of the project team
-	if !containsString(project.Team, userID) && project.CreatedBy != userID {
+	if !project.HasAccess(userID) {
 		return nil, errs.ErrUnauthorized
 	}
 
 	return project, nil
 }
 func (s *projectService) UpdateProject(ctx context.Context, id string, input models.UpdateProjectInput, userID string) (*models.Project, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+	}
+
 	project, err := s.GetProject(ctx, id, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+	previous := *project
 
-	// Update fields if provided
+	// Update fields if provided, and write only those
+	var fields []string
 	if input.Name != nil {
 		project.Name = *input.Name
+		fields = append(fields, "name")
 	}
 	if input.Description != nil {
 		project.Description = *input.Description
+		fields = append(fields, "description")
 	}
 	if input.Status != nil {
 		project.Status = *input.Status
+		fields = append(fields, "status")
 	}
 	if input.Progress != nil {
 		project.Progress = *input.Progress
+		fields = append(fields, "progress")
+		// Computed progress can still be pinned by hand
+		if project.ProgressSettings.Auto() {
+			project.ProgressSettings.Override = input.Progress
+			if project.ProgressBreakdown != nil {
+				project.ProgressBreakdown.Progress = *input.Progress
+				project.ProgressBreakdown.Overridden = true
+			}
+			fields = append(fields, "progress_settings", "progress_breakdown")
+		}
+	}
+	if input.StartDate != nil || input.ClearStartDate {
+		project.StartDate = input.StartDate
+		fields = append(fields, "start_date")
+	}
+	if input.EndDate != nil || input.ClearEndDate {
+		project.EndDate = input.EndDate
+		fields = append(fields, "end_date")
+	}
+	if err := project.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
 	}
 
-	if err := s.projectRepo.Update(ctx, project); err != nil {
+	if err := s.projectRepo.UpdateFields(ctx, project, fields...); err != nil {
 		return nil, err
 	}
 
+	s.bus.Publish(ctx, userID, events.ProjectUpdated{Project: *project, Previous: previous})
+	if project.Status != previous.Status {
+		s.bus.Publish(ctx, userID, events.ProjectStatusChanged{Project: *project, PreviousStatus: previous.Status})
+	}
+
 	return project, nil
 }
 
@@ -102,72 +180,150 @@ func (s *projectService) DeleteProject(ctx context.Context, id string, userID st
 		return err
 	}
 
-	// Only project creator can delete the project
-	if project.CreatedBy != userID {
+	// Only project owners can delete the project
+	if !project.IsOwner(userID) {
 		return errs.ErrUnauthorized
 	}
 
-	return s.projectRepo.Delete(ctx, id)
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// Links are broken while the project's items are still active, so the
+		// ones pointing at its documents and mockups are found
+		if err := s.linkService.MarkProjectDeleted(ctx, id); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.linkService.MarkProjectRestored(ctx, id)
+		})
+
+		if err := s.projectRepo.SoftDelete(ctx, id, userID); err != nil {
+			return err
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.projectRepo.Restore(ctx, id)
+		})
+
+		// Move the project's children to the trash with it; they are flagged as
+		// cascade deletes so restoring the project brings back exactly these
+		if err := s.documentRepo.SoftDeleteByProject(ctx, id, userID); err != nil {
+			return fmt.Errorf("failed to trash project documents: %w", err)
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.documentRepo.RestoreByProject(ctx, id)
+		})
+		if err := s.mockupRepo.SoftDeleteByProject(ctx, id, userID); err != nil {
+			return fmt.Errorf("failed to trash project mockups: %w", err)
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.mockupRepo.RestoreByProject(ctx, id)
+		})
+		if err := s.teamMemberRepo.SoftDeleteByProject(ctx, id); err != nil {
+			return fmt.Errorf("failed to trash project members: %w", err)
+		}
+		repository.OnRollback(ctx, func(ctx context.Context) error {
+			return s.teamMemberRepo.RestoreByProject(ctx, id)
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, userID, events.ProjectDeleted{Project: *project})
+	return nil
 }
 
-func (s *projectService) ListProjects(ctx context.Context, userID string) ([]*models.Project, error) {
-	return s.projectRepo.GetByUser(ctx, userID)
+func (s *projectService) ListProjects(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Project], error) {
+	return s.projectRepo.FindPageByUser(ctx, userID, q)
 }
 
 func (s *projectService) AddTeamMember(ctx context.Context, projectID string, memberID string, adderID string) error {
+	// The org join and the team change succeed or fail together. The team
+	// change itself is a single update, so it keeps concurrent project edits.
+	var project *models.Project
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		project, err = s.addTeamMember(ctx, projectID, memberID, adderID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	notifyProjectAdded(ctx, s.notifications, project, memberID, adderID)
+	s.bus.Publish(ctx, adderID, events.MemberAdded{ProjectID: projectID, UserID: memberID})
+	return nil
+}
+
+func (s *projectService) addTeamMember(ctx context.Context, projectID string, memberID string, adderID string) (*models.Project, error) {
 	log.Printf("Adding team member - ProjectID: %s, MemberID: %s, AdderID: %s", projectID, memberID, adderID)
 
 	// Validate project ID
 	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
-		return errs.ErrProjectNotFound
+		return nil, errs.ErrProjectNotFound
 	}
 
 	// Get project
 	project, err := s.projectRepo.GetByID(ctx, projectID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return errs.ErrProjectNotFound
+			return nil, errs.ErrProjectNotFound
 		}
-		return err
+		return nil, err
 	}
 
-	// Check if adder is project creator
-	if project.CreatedBy != adderID {
+	// Check if adder is a project owner
+	if !project.IsOwner(adderID) {
 		log.Printf("User %s is not authorized to add members to project %s", adderID, projectID)
-		return errs.ErrUnauthorized
+		return nil, errs.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
 	}
 
 	// Verify member exists
 	_, err = s.userRepo.GetByID(ctx, memberID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return errs.ErrUserNotFound
+			return nil, errs.ErrUserNotFound
 		}
-		return err
+		return nil, err
 	}
 	// Check if member is already in team
 	for _, existingMemberID := range project.Team {
 		if existingMemberID == memberID {
-			return errs.ErrAlreadyInTeam
+			return nil, errs.ErrAlreadyInTeam
 		}
 	}
 
-	// Add member to team
+	// Members of other organizations join this one as guests
+	if err := joinOrg(ctx, s.orgRepo, project.OrgID, memberID); err != nil {
+		return nil, err
+	}
+
+	if err := s.projectRepo.AddToTeam(ctx, projectID, memberID); err != nil {
+		log.Printf("Failed to add member to project: %v", err)
+		return nil, err
+	}
 	project.Team = append(project.Team, memberID)
 	project.UpdatedAt = time.Now()
 
-	// Update project
-	err = s.projectRepo.Update(ctx, project)
+	log.Printf("Successfully added member %s to project %s", memberID, projectID)
+	return project, nil
+}
+
+func (s *projectService) RemoveTeamMember(ctx context.Context, projectID string, memberID string, removerID string) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.removeTeamMember(ctx, projectID, memberID, removerID)
+	})
 	if err != nil {
-		log.Printf("Failed to update project: %v", err)
 		return err
 	}
 
-	log.Printf("Successfully added member %s to project %s", memberID, projectID)
+	s.bus.Publish(ctx, removerID, events.MemberRemoved{ProjectID: projectID, UserID: memberID})
 	return nil
 }
 
-func (s *projectService) RemoveTeamMember(ctx context.Context, projectID string, memberID string, removerID string) error {
+func (s *projectService) removeTeamMember(ctx context.Context, projectID string, memberID string, removerID string) error {
 	log.Printf("Removing team member - ProjectID: %s, MemberID: %s, RemoverID: %s", projectID, memberID, removerID)
 
 	// Validate project ID
@@ -184,16 +340,21 @@ func (s *projectService) RemoveTeamMember(ctx context.Context, projectID string,
 		return err
 	}
 
-	// Check if remover is project creator
-	if project.CreatedBy != removerID {
+	// Check if remover is a project owner
+	if !project.IsOwner(removerID) {
 		log.Printf("User %s is not authorized to remove members from project %s", removerID, projectID)
 		return errs.ErrUnauthorized
 	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
 
-	// Cannot remove project creator
-	if memberID == project.CreatedBy {
-		log.Printf("Attempted to remove project creator %s from project %s", memberID, projectID)
-		return errs.ErrCannotRemoveOwner
+	// Owners can leave as long as another owner remains
+	previousOwners := project.Owners
+	ownersChanged, err := project.RemoveOwner(memberID)
+	if err != nil {
+		log.Printf("Attempted to remove the last owner %s from project %s", memberID, projectID)
+		return err
 	}
 
 	// Verify member exists
@@ -206,26 +367,16 @@ func (s *projectService) RemoveTeamMember(ctx context.Context, projectID string,
 	}
 
 	// Check if member is in team
-	memberFound := false
-	newTeam := make([]string, 0, len(project.Team))
-	for _, existingMemberID := range project.Team {
-		if existingMemberID == memberID {
-			memberFound = true
-			continue
-		}
-		newTeam = append(newTeam, existingMemberID)
-	}
-
-	if !memberFound {
+	if !containsString(project.Team, memberID) {
 		return errs.ErrNotInTeam
 	}
 
-	// Update project with new team
-	project.Team = newTeam
-	project.UpdatedAt = time.Now()
-
-	err = s.projectRepo.Update(ctx, project)
-	if err != nil {
+	if ownersChanged {
+		if err := s.projectRepo.SetOwners(ctx, projectID, previousOwners, project.Owners); err != nil {
+			return err
+		}
+	}
+	if err := s.projectRepo.UpdateTeamList(ctx, projectID, nil, []string{memberID}); err != nil {
 		log.Printf("Failed to update project: %v", err)
 		return err
 	}
@@ -0,0 +1,398 @@
+// Package services internal/services/realtime.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/realtime"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/tenant"
+	"sync"
+	"time"
+)
+
+// Events sent on change streams
+const (
+	// EventChange carries a realtime.Change
+	EventChange = "change"
+	// EventReset tells a resuming client that the changes it missed are no
+	// longer known, so it has to reload what it shows
+	EventReset = "reset"
+	// EventRevoked is sent before a stream is closed because the client may
+	// no longer follow it
+	EventRevoked = "revoked"
+)
+
+// streamRecheckInterval is how often an open change stream checks that its
+// client is still signed in and allowed to see what it follows
+const streamRecheckInterval = time.Minute
+
+// streamedResources are the kinds of resources change streams carry
+var streamedResources = map[string]bool{
+	events.ResourceProject:   true,
+	events.ResourceDocument:  true,
+	events.ResourceMockup:    true,
+	events.ResourceFolder:    true,
+	events.ResourceLink:      true,
+	events.ResourceMilestone: true,
+	events.ResourceTask:      true,
+	events.ResourceBoard:     true,
+}
+
+type RealtimeService interface {
+	// Subscribe feeds the changes published on the bus to the change
+	// streams. Every instance hears of every change, so streams see all of
+	// them and resume on any instance.
+	Subscribe(bus *events.Bus)
+
+	// Stream follows the changes to projects and documents the user can
+	// access, starting after input.LastEventID when it is given. The channel
+	// is closed when ctx is done, when the client falls too far behind and
+	// has to resume, or after EventRevoked.
+	Stream(ctx context.Context, userID string, token string, input models.ChangeStreamInput) (<-chan realtime.Message, error)
+}
+
+type realtimeService struct {
+	feed         *realtime.Feed
+	authService  AuthService
+	projectRepo  repository.ProjectRepository
+	documentRepo repository.DocumentRepository
+	orgRepo      repository.OrganizationRepository
+	changed      *changedProjects
+}
+
+func NewRealtimeService(feed *realtime.Feed, authService AuthService, projectRepo repository.ProjectRepository, documentRepo repository.DocumentRepository, orgRepo repository.OrganizationRepository) RealtimeService {
+	return &realtimeService{
+		feed:         feed,
+		authService:  authService,
+		projectRepo:  projectRepo,
+		documentRepo: documentRepo,
+		orgRepo:      orgRepo,
+		changed:      &changedProjects{lookups: make(map[string]*projectLookup)},
+	}
+}
+
+func (s *realtimeService) Subscribe(bus *events.Bus) {
+	bus.SubscribeLocal("realtime", func(ctx context.Context, env events.Envelope) error {
+		if !streamedResources[env.Subject.Type] || env.Subject.ProjectID == "" {
+			return nil
+		}
+		event, err := env.Event()
+		if err != nil {
+			return err
+		}
+
+		// Clients get the resource as it is now, or as it was for deletions
+		var data interface{} = event
+		if change, ok := event.(events.Change); ok {
+			before, after := change.Change()
+			data = after
+			if after == nil {
+				data = before
+			}
+		}
+
+		s.feed.Publish(realtime.Change{
+			ID:           env.ID,
+			Event:        env.Name,
+			OrgID:        env.OrgID,
+			ResourceType: env.Subject.Type,
+			ResourceID:   env.Subject.ID,
+			ProjectID:    env.Subject.ProjectID,
+			ActorID:      env.ActorID,
+			OccurredAt:   env.OccurredAt,
+			Data:         data,
+		})
+		return nil
+	})
+}
+
+// changeStream is what one connection follows
+type changeStream struct {
+	userID string
+	token  string
+	orgID  string
+	// projects and documents are the ones asked for; following neither
+	// means following everything accessible
+	projects  map[string]bool
+	documents map[string]bool
+	// docProjects are the projects of the followed documents
+	docProjects map[string]bool
+	// access holds the projects the user can access, as last checked
+	access map[string]bool
+}
+
+func (cs *changeStream) followsAll() bool {
+	return len(cs.projects) == 0 && len(cs.documents) == 0
+}
+
+// visible reports whether the stream's client may see a change
+func (cs *changeStream) visible(change realtime.Change) bool {
+	if change.OrgID != "" && cs.orgID != "" && change.OrgID != cs.orgID {
+		return false
+	}
+	if !cs.access[change.ProjectID] {
+		return false
+	}
+	if cs.followsAll() || cs.projects[change.ProjectID] {
+		return true
+	}
+	return change.ResourceType == events.ResourceDocument && cs.documents[change.ResourceID]
+}
+
+// follows reports whether a change is to a project the stream follows, or
+// could follow once the user gains access to it
+func (cs *changeStream) follows(change realtime.Change) bool {
+	if change.OrgID != "" && cs.orgID != "" && change.OrgID != cs.orgID {
+		return false
+	}
+	return cs.followsAll() || cs.projects[change.ProjectID] || cs.docProjects[change.ProjectID]
+}
+
+// lost reports whether nothing the stream asked for is accessible any more
+func (cs *changeStream) lost() bool {
+	if cs.followsAll() {
+		return false
+	}
+	for _, projectIDs := range []map[string]bool{cs.projects, cs.docProjects} {
+		for projectID := range projectIDs {
+			if cs.access[projectID] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *realtimeService) Stream(ctx context.Context, userID string, token string, input models.ChangeStreamInput) (<-chan realtime.Message, error) {
+	cs := &changeStream{
+		userID:      userID,
+		token:       token,
+		orgID:       tenant.OrgID(ctx),
+		projects:    make(map[string]bool),
+		documents:   make(map[string]bool),
+		docProjects: make(map[string]bool),
+	}
+	if err := s.loadAccess(ctx, cs); err != nil {
+		return nil, err
+	}
+
+	for _, projectID := range input.ProjectIDs {
+		if err := s.checkProject(ctx, cs, projectID); err != nil {
+			return nil, err
+		}
+		cs.projects[projectID] = true
+	}
+	for _, documentID := range input.DocumentIDs {
+		doc, err := s.documentRepo.GetByID(ctx, documentID)
+		if err != nil {
+			if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+				return nil, errors.ErrDocumentNotFound
+			}
+			return nil, err
+		}
+		if err := s.checkProject(ctx, cs, doc.ProjectID); err != nil {
+			return nil, err
+		}
+		cs.documents[doc.ID] = true
+		cs.docProjects[doc.ProjectID] = true
+	}
+
+	sub, missed, ok := s.feed.Subscribe(input.LastEventID)
+	out := make(chan realtime.Message)
+
+	go func() {
+		defer close(out)
+		defer s.feed.Unsubscribe(sub)
+
+		send := func(msg realtime.Message) bool {
+			select {
+			case out <- msg:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// deliver sends a change the client may see. Changes to projects can
+		// change what the user can access, which is checked again for the
+		// streams that follow the project; the change itself goes to those
+		// who could see the project before or after it.
+		deliver := func(change realtime.Change) bool {
+			shown := cs.visible(change)
+			if change.ResourceType == events.ResourceProject && cs.follows(change) {
+				s.recheckProject(ctx, cs, change)
+				shown = shown || cs.visible(change)
+			}
+			if !shown {
+				return true
+			}
+			return send(realtime.Message{ID: change.ID, Event: EventChange, OrgID: change.OrgID, Data: change})
+		}
+		revoke := func(reason string) {
+			send(realtime.Message{Event: EventRevoked, Data: map[string]string{"reason": reason}})
+		}
+
+		if !ok {
+			if !send(realtime.Message{Event: EventReset, Data: map[string]string{"reason": "The changes since your last event are no longer available"}}) {
+				return
+			}
+		}
+		for _, change := range missed {
+			if !deliver(change) {
+				return
+			}
+		}
+
+		recheck := time.NewTicker(streamRecheckInterval)
+		defer recheck.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, open := <-sub.C:
+				if !open {
+					return
+				}
+				if !deliver(change) {
+					return
+				}
+			case <-recheck.C:
+				if reason := s.recheck(ctx, cs); reason != "" {
+					revoke(reason)
+					return
+				}
+			}
+			if cs.lost() {
+				revoke("You no longer have access to what this stream follows")
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// checkProject makes sure the user may follow a project
+func (s *realtimeService) checkProject(ctx context.Context, cs *changeStream, projectID string) error {
+	if cs.access[projectID] {
+		return nil
+	}
+	if _, err := s.projectRepo.GetByID(ctx, projectID); err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return errors.ErrProjectNotFound
+		}
+		return err
+	}
+	return errors.ErrUnauthorized
+}
+
+// loadAccess reloads the projects the stream's user can access
+func (s *realtimeService) loadAccess(ctx context.Context, cs *changeStream) error {
+	projects, err := s.projectRepo.GetByUser(ctx, cs.userID)
+	if err != nil {
+		return err
+	}
+	access := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		access[project.ID] = true
+	}
+	cs.access = access
+	return nil
+}
+
+// changedProjects shares the lookups of changed projects between the open
+// streams, so a project change costs one query however many streams follow
+// it
+type changedProjects struct {
+	mu      sync.Mutex
+	lookups map[string]*projectLookup
+	// order holds the change IDs of the lookups, oldest first
+	order []string
+}
+
+// changedProjectsKept is how many recent lookups are kept for streams that
+// have not handled their change yet
+const changedProjectsKept = 32
+
+type projectLookup struct {
+	once    sync.Once
+	project *models.Project
+	err     error
+}
+
+// get returns the project of a change, loading it once per change
+func (c *changedProjects) get(change realtime.Change, load func() (*models.Project, error)) (*models.Project, error) {
+	c.mu.Lock()
+	lookup, ok := c.lookups[change.ID]
+	if !ok {
+		lookup = &projectLookup{}
+		c.lookups[change.ID] = lookup
+		c.order = append(c.order, change.ID)
+		if len(c.order) > changedProjectsKept {
+			delete(c.lookups, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.mu.Unlock()
+
+	lookup.once.Do(func() {
+		lookup.project, lookup.err = load()
+	})
+	return lookup.project, lookup.err
+}
+
+// recheckProject updates whether the stream's user can access a changed
+// project
+func (s *realtimeService) recheckProject(ctx context.Context, cs *changeStream, change realtime.Change) {
+	project, err := s.changed.get(change, func() (*models.Project, error) {
+		// The lookup is shared, so it must not end with one stream's request
+		lookupCtx := tenant.WithOrg(context.WithoutCancel(ctx), change.OrgID, "")
+		if change.OrgID == "" {
+			lookupCtx = context.WithoutCancel(ctx)
+		}
+		return s.projectRepo.GetByID(lookupCtx, change.ProjectID)
+	})
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			delete(cs.access, change.ProjectID)
+			return
+		}
+		log.Printf("Failed to recheck access of user %s to project %s: %v", cs.userID, change.ProjectID, err)
+		return
+	}
+	if project.HasAccess(cs.userID) {
+		cs.access[project.ID] = true
+	} else {
+		delete(cs.access, project.ID)
+	}
+}
+
+// recheck makes sure the stream's client is still signed in, still belongs
+// to the organization and still has its project access. It returns why the
+// stream has to close, or "" when it may stay open.
+func (s *realtimeService) recheck(ctx context.Context, cs *changeStream) string {
+	if _, err := s.authService.Authenticate(ctx, cs.token); err != nil {
+		return "Your session has ended"
+	}
+	if cs.orgID != "" {
+		org, err := s.orgRepo.GetByID(ctx, cs.orgID)
+		if err != nil && !stderrors.Is(err, errors.ErrNotFound) {
+			// Keep the stream open through passing database trouble
+			log.Printf("Failed to recheck organization %s of user %s: %v", cs.orgID, cs.userID, err)
+			return ""
+		}
+		if err != nil || org.Member(cs.userID) == nil {
+			return "You are no longer a member of this organization"
+		}
+	}
+	if err := s.loadAccess(ctx, cs); err != nil {
+		log.Printf("Failed to recheck project access of user %s: %v", cs.userID, err)
+	}
+	return ""
+}
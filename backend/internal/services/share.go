@@ -0,0 +1,366 @@
+// Package services internal/services/share.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/requestmeta"
+	"projectnexus/internal/tenant"
+	"time"
+)
+
+// Password guesses on share links are limited, as viewers are not signed in
+// and every guess costs a bcrypt comparison. An address is locked out of a
+// link after too many wrong passwords for it, and out of every link after
+// too many passwords. Wrong passwords from one address never lock out
+// another.
+const (
+	shareAttemptWindow     = 15 * time.Minute
+	shareLinkFailureLimit  = 10
+	shareAddressCheckLimit = 30
+)
+
+type ShareService interface {
+	// CreateProjectLink shares a project's presentation with the documents
+	// and mockups picked in the input
+	CreateProjectLink(ctx context.Context, projectID string, input models.CreateShareLinkInput, userID string) (*models.CreatedShareLink, error)
+	// CreateDocumentLink shares a single document
+	CreateDocumentLink(ctx context.Context, documentID string, input models.CreateShareLinkInput, userID string) (*models.CreatedShareLink, error)
+	ListLinks(ctx context.Context, projectID string, userID string) ([]*models.ShareLink, error)
+	// RevokeLink stops a link from working; its creator and the project
+	// owners may revoke it
+	RevokeLink(ctx context.Context, projectID, linkID string, userID string) (*models.ShareLink, error)
+
+	// View returns what a link shows to someone who is not signed in, and
+	// counts the view
+	View(ctx context.Context, token string, password string) (*models.SharedView, error)
+}
+
+type shareService struct {
+	shareRepo      repository.ShareLinkRepository
+	projectRepo    repository.ProjectRepository
+	teamMemberRepo repository.TeamMemberRepository
+	documentRepo   repository.DocumentRepository
+	mockupRepo     repository.MockupRepository
+	attempts       repository.AttemptStore
+	bus            *events.Bus
+	appURL         string
+}
+
+func NewShareService(shareRepo repository.ShareLinkRepository, projectRepo repository.ProjectRepository, teamMemberRepo repository.TeamMemberRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, attempts repository.AttemptStore, bus *events.Bus, appURL string) ShareService {
+	return &shareService{
+		shareRepo:      shareRepo,
+		projectRepo:    projectRepo,
+		teamMemberRepo: teamMemberRepo,
+		documentRepo:   documentRepo,
+		mockupRepo:     mockupRepo,
+		attempts:       attempts,
+		bus:            bus,
+		appURL:         appURL,
+	}
+}
+
+// projectForMember loads a project the user can access
+func (s *shareService) projectForMember(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+// projectForSharer loads a project the user may share. Sharing publishes
+// content outside the organization, so viewers of the project may not.
+// Members stored on the project alone, from before member records existed,
+// count as plain members.
+func (s *shareService) projectForSharer(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if project.IsOwner(userID) {
+		return project, nil
+	}
+
+	member, err := s.teamMemberRepo.GetByProjectAndUser(ctx, project.ID, userID)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			return project, nil
+		}
+		return nil, err
+	}
+	if member.Role.Rank() < models.TeamRoleMember.Rank() {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+func (s *shareService) CreateProjectLink(ctx context.Context, projectID string, input models.CreateShareLinkInput, userID string) (*models.CreatedShareLink, error) {
+	if err := input.Validate(time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForSharer(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the project's own documents and mockups can be shown
+	for _, documentID := range input.DocumentIDs {
+		doc, err := s.documentRepo.GetByID(ctx, documentID)
+		if err != nil || doc.ProjectID != project.ID {
+			return nil, fmt.Errorf("%w: document %s is not in the project", errors.ErrInvalidInput, documentID)
+		}
+	}
+	for _, mockupID := range input.MockupIDs {
+		mockup, err := s.mockupRepo.GetByID(ctx, mockupID)
+		if err != nil || mockup.ProjectID != project.ID {
+			return nil, fmt.Errorf("%w: mockup %s is not in the project", errors.ErrInvalidInput, mockupID)
+		}
+	}
+
+	link := &models.ShareLink{
+		OrgID:       project.OrgID,
+		ProjectID:   project.ID,
+		Kind:        models.ShareLinkPresentation,
+		DocumentIDs: input.DocumentIDs,
+		MockupIDs:   input.MockupIDs,
+	}
+	return s.create(ctx, link, input, userID)
+}
+
+func (s *shareService) CreateDocumentLink(ctx context.Context, documentID string, input models.CreateShareLinkInput, userID string) (*models.CreatedShareLink, error) {
+	if err := input.Validate(time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	doc, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrDocumentNotFound) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	project, err := s.projectForSharer(ctx, doc.ProjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.ShareLink{
+		OrgID:       project.OrgID,
+		ProjectID:   project.ID,
+		Kind:        models.ShareLinkDocument,
+		DocumentIDs: []string{doc.ID},
+		MockupIDs:   []string{},
+	}
+	return s.create(ctx, link, input, userID)
+}
+
+// create gives a link its token and password and stores it
+func (s *shareService) create(ctx context.Context, link *models.ShareLink, input models.CreateShareLinkInput, userID string) (*models.CreatedShareLink, error) {
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	link.TokenHash = models.HashShareToken(token)
+	link.ExpiresAt = input.ExpiresAt
+	link.CreatedBy = userID
+	if input.Password != "" {
+		if err := link.SetPassword(input.Password); err != nil {
+			return nil, fmt.Errorf("failed to set share link password: %w", err)
+		}
+	}
+
+	if err := s.shareRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.ShareLinkCreated{ShareLink: *link})
+
+	return &models.CreatedShareLink{
+		ShareLink: link,
+		Token:     token,
+		URL:       fmt.Sprintf("%s/share/%s", s.appURL, token),
+	}, nil
+}
+
+func (s *shareService) ListLinks(ctx context.Context, projectID string, userID string) ([]*models.ShareLink, error) {
+	if _, err := s.projectForMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.shareRepo.GetByProject(ctx, projectID)
+}
+
+func (s *shareService) RevokeLink(ctx context.Context, projectID, linkID string, userID string) (*models.ShareLink, error) {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.shareRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.ProjectID != project.ID {
+		return nil, errors.ErrShareLinkNotFound
+	}
+	if link.CreatedBy != userID && !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	// Revoking twice keeps the first revocation
+	if link.RevokedAt != nil {
+		return link, nil
+	}
+
+	previous := *link
+	now := time.Now()
+	if err := s.shareRepo.Revoke(ctx, link.ID, userID, now); err != nil {
+		return nil, err
+	}
+	link.RevokedAt = &now
+	link.RevokedBy = userID
+	link.UpdatedAt = now
+	s.bus.Publish(ctx, userID, events.ShareLinkRevoked{ShareLink: *link, Previous: previous})
+
+	return link, nil
+}
+
+func (s *shareService) View(ctx context.Context, token string, password string) (*models.SharedView, error) {
+	// The token is looked up in every organization, as nothing else says
+	// which one it belongs to
+	link, err := s.shareRepo.GetByTokenHash(tenant.Unscoped(ctx), models.HashShareToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !link.IsActive(time.Now()) {
+		return nil, errors.ErrShareLinkInactive
+	}
+	if link.PasswordProtected {
+		if password == "" {
+			return nil, errors.ErrSharePasswordRequired
+		}
+		if err := s.checkPassword(ctx, link, password); err != nil {
+			return nil, err
+		}
+	}
+
+	// Viewers are not signed in, so the link decides the organization
+	ctx = tenant.WithOrg(ctx, link.OrgID, "")
+
+	project, err := s.projectRepo.GetByID(ctx, link.ProjectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) {
+			// Links stop working while their project is in the trash
+			return nil, errors.ErrShareLinkInactive
+		}
+		return nil, err
+	}
+
+	view := &models.SharedView{
+		Kind: link.Kind,
+		Project: models.SharedProject{
+			Name:        project.Name,
+			Description: project.Description,
+			Status:      project.Status,
+			Progress:    project.Progress,
+			StartDate:   project.StartDate,
+			EndDate:     project.EndDate,
+		},
+		Documents: []models.SharedDocument{},
+		Mockups:   []models.SharedMockup{},
+		ExpiresAt: link.ExpiresAt,
+	}
+
+	// Documents and mockups deleted or moved since the link was made are
+	// left out
+	for _, documentID := range link.DocumentIDs {
+		doc, err := s.documentRepo.GetByID(ctx, documentID)
+		if err != nil || doc.ProjectID != project.ID {
+			continue
+		}
+		view.Documents = append(view.Documents, models.SharedDocument{
+			ID:        doc.ID,
+			Title:     doc.Title,
+			Type:      doc.Type,
+			Status:    doc.Status,
+			Version:   doc.Version,
+			Content:   doc.Content,
+			UpdatedAt: doc.UpdatedAt,
+		})
+	}
+	if link.Kind == models.ShareLinkDocument && len(view.Documents) == 0 {
+		return nil, errors.ErrShareLinkInactive
+	}
+	for _, mockupID := range link.MockupIDs {
+		mockup, err := s.mockupRepo.GetByID(ctx, mockupID)
+		if err != nil || mockup.ProjectID != project.ID {
+			continue
+		}
+		view.Mockups = append(view.Mockups, models.SharedMockup{
+			ID:        mockup.ID,
+			Name:      mockup.Name,
+			Type:      mockup.Type,
+			Tool:      mockup.Tool,
+			Thumbnail: mockup.Thumbnail,
+			Status:    mockup.Status,
+			UpdatedAt: mockup.UpdatedAt,
+		})
+	}
+
+	if err := s.shareRepo.RecordView(ctx, link.ID, time.Now()); err != nil {
+		log.Printf("Failed to count view of share link %s: %v", link.ID, err)
+	}
+
+	return view, nil
+}
+
+// checkPassword compares a password with the link's, within the attempt
+// limits. Every check counts against the viewer's address, wrong passwords
+// also against the address on that link.
+func (s *shareService) checkPassword(ctx context.Context, link *models.ShareLink, password string) error {
+	address := requestmeta.From(ctx).IP
+	checks, err := s.attempts.AddAttempt(ctx, "share-address:"+address, shareAttemptWindow)
+	if err != nil {
+		return fmt.Errorf("failed to count share link password attempt: %w", err)
+	}
+	failuresKey := "share-link:" + link.ID + ":" + address
+	failures, err := s.attempts.Attempts(ctx, failuresKey)
+	if err != nil {
+		return fmt.Errorf("failed to count share link password attempts: %w", err)
+	}
+	if checks > shareAddressCheckLimit || failures >= shareLinkFailureLimit {
+		return errors.ErrShareTooManyAttempts
+	}
+
+	if !link.CheckPassword(password) {
+		if _, err := s.attempts.AddAttempt(ctx, failuresKey, shareAttemptWindow); err != nil {
+			log.Printf("Failed to count wrong password for share link %s: %v", link.ID, err)
+		}
+		return errors.ErrSharePasswordInvalid
+	}
+	return nil
+}
+
+// newShareToken returns a random token no one can guess
+func newShareToken() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,231 @@
+// Package services internal/services/stats.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"sort"
+	"time"
+)
+
+// statsContributorLimit is how many members the contribution counts list
+const statsContributorLimit = 10
+
+type StatsService interface {
+	// GetDashboardStats returns the statistics of every project the user can
+	// access over a window
+	GetDashboardStats(ctx context.Context, userID string, window models.StatsWindow) (*models.Stats, error)
+	// GetProjectMetrics returns the statistics of one project, for its
+	// members
+	GetProjectMetrics(ctx context.Context, projectID string, userID string, window models.StatsWindow) (*models.Stats, error)
+}
+
+type statsService struct {
+	statsRepo   repository.StatsRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+}
+
+func NewStatsService(statsRepo repository.StatsRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) StatsService {
+	return &statsService{
+		statsRepo:   statsRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *statsService) GetDashboardStats(ctx context.Context, userID string, window models.StatsWindow) (*models.Stats, error) {
+	window, err := checkStatsWindow(window)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.compute(ctx, projects, window)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.ProjectsByStatus, err = s.statsRepo.CountProjectsByStatus(ctx, projectIDsOf(projects))
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *statsService) GetProjectMetrics(ctx context.Context, projectID string, userID string, window models.StatsWindow) (*models.Stats, error) {
+	window, err := checkStatsWindow(window)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	stats, err := s.compute(ctx, []*models.Project{project}, window)
+	if err != nil {
+		return nil, err
+	}
+	stats.ProjectID = project.ID
+	return stats, nil
+}
+
+// compute gathers the statistics shared by the dashboard and the project
+// metrics. The window starts at the beginning of a bucket, so that the first
+// point of the trends counts a whole day or week.
+func (s *statsService) compute(ctx context.Context, projects []*models.Project, window models.StatsWindow) (*models.Stats, error) {
+	bucket := window.Bucket()
+	to := time.Now().UTC()
+	from := bucket.Start(to.Add(-window.Duration()))
+	projectIDs := projectIDsOf(projects)
+
+	docs, err := s.statsRepo.DocumentStats(ctx, projectIDs, from, bucket)
+	if err != nil {
+		return nil, err
+	}
+	edits, err := s.statsRepo.EditStats(ctx, projectIDs, from, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.Stats{
+		Window:            window,
+		Bucket:            bucket,
+		From:              from,
+		To:                to,
+		DocumentsByStatus: docs.ByStatus,
+		DocumentsByType:   docs.ByType,
+		ApprovalLeadTime:  docs.LeadTime,
+		EditsPerWeek:      models.FillTrend(edits.PerWeek, from, to, models.StatsBucketWeek),
+		Contributions:     s.contributions(ctx, docs.Creators, edits.Editors),
+		Trends: models.StatsTrends{
+			DocumentsCreated: models.FillTrend(docs.Created, from, to, bucket),
+			Edits:            models.FillTrend(edits.Edits, from, to, bucket),
+			Approvals:        models.FillTrend(docs.Approved, from, to, bucket),
+		},
+	}
+
+	for _, count := range docs.ByStatus {
+		stats.Summary.Documents += count.Count
+		if count.Key == string(models.DocumentStatusInReview) {
+			stats.Summary.PendingReviews = count.Count
+		}
+	}
+	stats.Summary.TeamMembers = countMembers(projects)
+	stats.Summary.DocumentsCreated = sumTrend(docs.Created)
+	stats.Summary.Edits = sumTrend(edits.Edits)
+	stats.Summary.Approvals = docs.LeadTime.Approvals
+
+	return stats, nil
+}
+
+// contributions joins the documents created and the edits made per member,
+// keeping the most active ones
+func (s *statsService) contributions(ctx context.Context, creators, editors []models.KeyCount) []models.MemberContribution {
+	byUser := make(map[string]*models.MemberContribution)
+	contribution := func(userID string) *models.MemberContribution {
+		c, ok := byUser[userID]
+		if !ok {
+			c = &models.MemberContribution{UserID: userID}
+			byUser[userID] = c
+		}
+		return c
+	}
+	for _, count := range creators {
+		if count.Key != "" {
+			contribution(count.Key).DocumentsCreated = count.Count
+		}
+	}
+	for _, count := range editors {
+		if count.Key != "" {
+			contribution(count.Key).Edits = count.Count
+		}
+	}
+
+	contributions := make([]models.MemberContribution, 0, len(byUser))
+	for _, c := range byUser {
+		contributions = append(contributions, *c)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		a, b := contributions[i], contributions[j]
+		if a.DocumentsCreated+a.Edits != b.DocumentsCreated+b.Edits {
+			return a.DocumentsCreated+a.Edits > b.DocumentsCreated+b.Edits
+		}
+		return a.UserID < b.UserID
+	})
+	if len(contributions) > statsContributorLimit {
+		contributions = contributions[:statsContributorLimit]
+	}
+
+	for i := range contributions {
+		user, err := s.userRepo.GetByID(ctx, contributions[i].UserID)
+		if err != nil {
+			log.Printf("Failed to look up user %s for statistics: %v", contributions[i].UserID, err)
+			continue
+		}
+		contributions[i].Name = user.Name
+		if user.Name == "" {
+			contributions[i].Name = user.Email
+		}
+	}
+	return contributions
+}
+
+func checkStatsWindow(window models.StatsWindow) (models.StatsWindow, error) {
+	if window == "" {
+		return models.DefaultStatsWindow, nil
+	}
+	if !window.IsValid() {
+		return "", fmt.Errorf("%w: window must be 7d, 30d, 90d or 365d", errors.ErrInvalidInput)
+	}
+	return window, nil
+}
+
+func projectIDsOf(projects []*models.Project) []string {
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	return ids
+}
+
+// countMembers counts the distinct owners and team members of the projects
+func countMembers(projects []*models.Project) int {
+	members := make(map[string]bool)
+	for _, project := range projects {
+		for _, id := range project.OwnerIDs() {
+			members[id] = true
+		}
+		for _, id := range project.Team {
+			members[id] = true
+		}
+	}
+	return len(members)
+}
+
+func sumTrend(points []models.TrendPoint) int64 {
+	var total int64
+	for _, p := range points {
+		total += p.Value
+	}
+	return total
+}
@@ -0,0 +1,497 @@
+// Package services internal/services/task.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/rank"
+	"projectnexus/internal/repository"
+)
+
+type TaskService interface {
+	// GetBoard returns the project's board with its tasks, creating the board
+	// with the default columns on first use
+	GetBoard(ctx context.Context, projectID string, userID string) (*models.BoardView, error)
+	UpdateBoardColumns(ctx context.Context, projectID string, input models.UpdateBoardColumnsInput, userID string) (*models.Board, error)
+	ListTasks(ctx context.Context, projectID string, filter models.TaskFilter, userID string) ([]*models.Task, error)
+	GetTask(ctx context.Context, projectID, taskID string, userID string) (*models.Task, error)
+	CreateTask(ctx context.Context, projectID string, input models.CreateTaskInput, userID string) (*models.Task, error)
+	UpdateTask(ctx context.Context, projectID, taskID string, input models.UpdateTaskInput, userID string) (*models.Task, error)
+	// MoveTask places a task between two neighbours, possibly in another
+	// column. It fails with errors.ErrVersionConflict when the task or its
+	// neighbours changed since the client read them.
+	MoveTask(ctx context.Context, projectID, taskID string, input models.MoveTaskInput, userID string) (*models.Task, error)
+	DeleteTask(ctx context.Context, projectID, taskID string, userID string) error
+}
+
+type taskService struct {
+	taskRepo      repository.TaskRepository
+	boardRepo     repository.BoardRepository
+	projectRepo   repository.ProjectRepository
+	milestoneRepo repository.MilestoneRepository
+	documentRepo  repository.DocumentRepository
+	bus           *events.Bus
+	txManager     repository.TxManager
+}
+
+func NewTaskService(taskRepo repository.TaskRepository, boardRepo repository.BoardRepository, projectRepo repository.ProjectRepository, milestoneRepo repository.MilestoneRepository, documentRepo repository.DocumentRepository, bus *events.Bus, txManager repository.TxManager) TaskService {
+	return &taskService{
+		taskRepo:      taskRepo,
+		boardRepo:     boardRepo,
+		projectRepo:   projectRepo,
+		milestoneRepo: milestoneRepo,
+		documentRepo:  documentRepo,
+		bus:           bus,
+		txManager:     txManager,
+	}
+}
+
+// projectForMember loads a project and verifies the user is a member of it
+func (s *taskService) projectForMember(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+// projectForWriter also refuses changes to archived projects
+func (s *taskService) projectForWriter(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// board returns the project's board, creating it if the project has none
+func (s *taskService) board(ctx context.Context, project *models.Project) (*models.Board, error) {
+	board, err := s.boardRepo.GetByProject(ctx, project.ID)
+	if err == nil || !stderrors.Is(err, errors.ErrNotFound) {
+		return board, err
+	}
+
+	board = &models.Board{
+		OrgID:     project.OrgID,
+		ProjectID: project.ID,
+		Columns:   models.DefaultBoardColumns(),
+	}
+	if err := s.boardRepo.Create(ctx, board); err != nil {
+		// Another request created it first
+		if stderrors.Is(err, errors.ErrVersionConflict) {
+			return s.boardRepo.GetByProject(ctx, project.ID)
+		}
+		return nil, err
+	}
+	return board, nil
+}
+
+// getProjectTask loads a task and verifies it belongs to the project
+func (s *taskService) getProjectTask(ctx context.Context, projectID, taskID string) (*models.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID != projectID {
+		return nil, errors.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (s *taskService) GetBoard(ctx context.Context, projectID string, userID string) (*models.BoardView, error) {
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.board(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+
+	tasks, err := s.taskRepo.FindByProject(ctx, projectID, models.TaskFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	return models.BuildBoardView(board, tasks), nil
+}
+
+func (s *taskService) UpdateBoardColumns(ctx context.Context, projectID string, input models.UpdateBoardColumnsInput, userID string) (*models.Board, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForWriter(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only owners reshape the board
+	if !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	board, err := s.board(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	if input.Version != 0 && input.Version != board.Version {
+		return nil, errors.ErrVersionConflict
+	}
+
+	columns := make([]models.BoardColumn, 0, len(input.Columns))
+	kept := make(map[string]bool, len(input.Columns))
+	for _, column := range input.Columns {
+		id := column.ID
+		switch {
+		case id == "":
+			id = primitive.NewObjectID().Hex()
+		case board.Column(id) == nil || kept[id]:
+			return nil, errors.ErrColumnNotFound
+		}
+		kept[id] = true
+		columns = append(columns, models.BoardColumn{ID: id, Name: column.Name})
+	}
+
+	// Columns can only be removed once their tasks have moved elsewhere
+	for _, column := range board.Columns {
+		if kept[column.ID] {
+			continue
+		}
+		count, err := s.taskRepo.CountByColumn(ctx, projectID, column.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, errors.ErrColumnNotEmpty
+		}
+	}
+
+	previous := *board
+	board.Columns = columns
+	if err := s.boardRepo.UpdateColumns(ctx, board); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.BoardUpdated{Board: *board, Previous: previous})
+
+	return board, nil
+}
+
+func (s *taskService) ListTasks(ctx context.Context, projectID string, filter models.TaskFilter, userID string) ([]*models.Task, error) {
+	if filter.Priority != "" && !filter.Priority.IsValid() {
+		return nil, fmt.Errorf("%w: invalid task priority: %s", errors.ErrInvalidInput, filter.Priority)
+	}
+	if _, err := s.projectForMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.FindByProject(ctx, projectID, filter)
+}
+
+func (s *taskService) GetTask(ctx context.Context, projectID, taskID string, userID string) (*models.Task, error) {
+	if _, err := s.projectForMember(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getProjectTask(ctx, projectID, taskID)
+}
+
+func (s *taskService) CreateTask(ctx context.Context, projectID string, input models.CreateTaskInput, userID string) (*models.Task, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForWriter(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkReferences(ctx, project, input.AssigneeID, input.MilestoneID, input.DocumentIDs); err != nil {
+		return nil, err
+	}
+
+	board, err := s.board(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	columnID := input.ColumnID
+	if columnID == "" {
+		columnID = board.Columns[0].ID
+	}
+	if board.Column(columnID) == nil {
+		return nil, errors.ErrColumnNotFound
+	}
+
+	// New tasks go to the bottom of their column
+	column, err := s.taskRepo.FindByProject(ctx, projectID, models.TaskFilter{ColumnID: columnID})
+	if err != nil {
+		return nil, err
+	}
+	last := ""
+	if len(column) > 0 {
+		last = column[len(column)-1].Rank
+	}
+	taskRank, err := rank.Between(last, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank task: %w", err)
+	}
+
+	task := &models.Task{
+		OrgID:       project.OrgID,
+		ProjectID:   projectID,
+		Title:       input.Title,
+		Description: input.Description,
+		AssigneeID:  input.AssigneeID,
+		ColumnID:    columnID,
+		Rank:        taskRank,
+		Priority:    input.Priority,
+		Estimate:    input.Estimate,
+		DueDate:     input.DueDate,
+		MilestoneID: input.MilestoneID,
+		DocumentIDs: input.DocumentIDs,
+		CreatedBy:   userID,
+	}
+
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TaskCreated{Task: *task})
+
+	return task, nil
+}
+
+func (s *taskService) UpdateTask(ctx context.Context, projectID, taskID string, input models.UpdateTaskInput, userID string) (*models.Task, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForWriter(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.getProjectTask(ctx, projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Version != nil && *input.Version != task.Version {
+		return nil, errors.ErrVersionConflict
+	}
+	previous := *task
+
+	if input.Title != nil {
+		task.Title = *input.Title
+	}
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+	if input.AssigneeID != nil {
+		task.AssigneeID = *input.AssigneeID
+	}
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+	if input.Estimate != nil {
+		task.Estimate = input.Estimate
+	}
+	if input.DueDate != nil {
+		task.DueDate = input.DueDate
+	}
+	if input.MilestoneID != nil {
+		task.MilestoneID = *input.MilestoneID
+	}
+	if input.DocumentIDs != nil {
+		task.DocumentIDs = *input.DocumentIDs
+	}
+	if err := s.checkReferences(ctx, project, task.AssigneeID, task.MilestoneID, task.DocumentIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TaskUpdated{Task: *task, Previous: previous})
+
+	return task, nil
+}
+
+func (s *taskService) MoveTask(ctx context.Context, projectID, taskID string, input models.MoveTaskInput, userID string) (*models.Task, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForWriter(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.board(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	if board.Column(input.ColumnID) == nil {
+		return nil, errors.ErrColumnNotFound
+	}
+
+	var task *models.Task
+	var previous models.Task
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.getProjectTask(ctx, projectID, taskID)
+		if err != nil {
+			return err
+		}
+		if input.Version != task.Version {
+			return errors.ErrVersionConflict
+		}
+		previous = *task
+
+		column, err := s.taskRepo.FindByProject(ctx, projectID, models.TaskFilter{ColumnID: input.ColumnID})
+		if err != nil {
+			return err
+		}
+		others := make([]*models.Task, 0, len(column))
+		for _, t := range column {
+			if t.ID != taskID {
+				others = append(others, t)
+			}
+		}
+
+		position, err := insertPosition(others, input.AfterID, input.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		taskRank, err := rankAt(others, position)
+		if err != nil {
+			// Concurrent moves left neighbours with the same rank; spread the
+			// column out again and retry
+			if err := s.rerank(ctx, others); err != nil {
+				return err
+			}
+			if taskRank, err = rankAt(others, position); err != nil {
+				return fmt.Errorf("failed to rank task: %w", err)
+			}
+		}
+
+		task.ColumnID = input.ColumnID
+		task.Rank = taskRank
+		return s.taskRepo.Update(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TaskUpdated{Task: *task, Previous: previous})
+
+	return task, nil
+}
+
+func (s *taskService) DeleteTask(ctx context.Context, projectID, taskID string, userID string) error {
+	if _, err := s.projectForWriter(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	task, err := s.getProjectTask(ctx, projectID, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.Delete(ctx, taskID); err != nil {
+		return err
+	}
+	s.bus.Publish(ctx, userID, events.TaskDeleted{Task: *task})
+	return nil
+}
+
+// rerank gives the tasks of a column fresh, evenly spaced ranks in their
+// current order
+func (s *taskService) rerank(ctx context.Context, tasks []*models.Task) error {
+	ranks := rank.Spread(len(tasks))
+	updates := make(map[string]string, len(tasks))
+	for i, t := range tasks {
+		t.Rank = ranks[i]
+		updates[t.ID] = ranks[i]
+	}
+	return s.taskRepo.SetRanks(ctx, updates)
+}
+
+// checkReferences verifies that the assignee is on the project and that the
+// milestone and documents belong to it
+func (s *taskService) checkReferences(ctx context.Context, project *models.Project, assigneeID, milestoneID string, documentIDs []string) error {
+	if assigneeID != "" && !project.HasAccess(assigneeID) {
+		return fmt.Errorf("%w: assignee must be a project member", errors.ErrInvalidInput)
+	}
+
+	if milestoneID != "" {
+		milestone, err := s.milestoneRepo.GetByID(ctx, milestoneID)
+		if err != nil || milestone.ProjectID != project.ID {
+			return fmt.Errorf("%w: milestone %s is not in this project", errors.ErrInvalidInput, milestoneID)
+		}
+	}
+
+	for _, id := range documentIDs {
+		document, err := s.documentRepo.GetByID(ctx, id)
+		if err != nil || document.ProjectID != project.ID {
+			return fmt.Errorf("%w: document %s is not in this project", errors.ErrInvalidInput, id)
+		}
+	}
+	return nil
+}
+
+// insertPosition finds where a task goes among the other tasks of its new
+// column. Neighbours that are missing or no longer adjacent mean the client's
+// view of the column is stale.
+func insertPosition(others []*models.Task, afterID, beforeID string) (int, error) {
+	index := func(id string) int {
+		for i, t := range others {
+			if t.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	switch {
+	case afterID != "":
+		after := index(afterID)
+		if after < 0 {
+			return 0, errors.ErrVersionConflict
+		}
+		if beforeID != "" && index(beforeID) != after+1 {
+			return 0, errors.ErrVersionConflict
+		}
+		return after + 1, nil
+	case beforeID != "":
+		before := index(beforeID)
+		if before < 0 {
+			return 0, errors.ErrVersionConflict
+		}
+		return before, nil
+	default:
+		return len(others), nil
+	}
+}
+
+// rankAt returns a rank between the tasks on either side of position
+func rankAt(others []*models.Task, position int) (string, error) {
+	prev, next := "", ""
+	if position > 0 {
+		prev = others[position-1].Rank
+	}
+	if position < len(others) {
+		next = others[position].Rank
+	}
+	return rank.Between(prev, next)
+}
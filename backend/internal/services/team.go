@@ -5,10 +5,15 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
 	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository"
-	"projectnexus/internal/repository/mongo"
+	"projectnexus/internal/tenant"
 	"time"
 )
 
@@ -16,14 +21,40 @@ type TeamService interface {
 	AddTeamMember(ctx context.Context, projectID string, input models.AddTeamMemberInput, adderID string) (*models.TeamMember, error)
 	UpdateTeamMember(ctx context.Context, projectID, memberID string, input models.UpdateTeamMemberInput, updaterID string) (*models.TeamMember, error)
 	RemoveTeamMember(ctx context.Context, projectID, memberID string, removerID string) error // Make sure error is here
-	GetTeamMember(ctx context.Context, projectID, memberID string) (*models.TeamMember, error)
-	GetProjectTeam(ctx context.Context, projectID string) ([]*models.TeamMember, error)
-	CreateTeam(ctx context.Context, input models.CreateTeamInput, id string) (interface{}, interface{})
-	GetAllTeams(ctx context.Context) (interface{}, interface{})
-	GetTeamByID(ctx context.Context, id string) (interface{}, interface{})
-	UpdateTeam(ctx context.Context, id string, input models.UpdateTeamInput) (interface{}, interface{})
-	DeleteTeam(ctx context.Context, id string) interface{}
-	GetTeamMembers(ctx context.Context, id string) (interface{}, interface{})
+	// GetTeamMember and GetProjectTeam show a project's members to those with
+	// access to the project
+	GetTeamMember(ctx context.Context, projectID, memberID string, userID string) (*models.TeamMember, error)
+	GetProjectTeam(ctx context.Context, projectID string, userID string) ([]*models.TeamMember, error)
+
+	CreateTeam(ctx context.Context, input models.CreateTeamInput, creatorID string) (*models.Team, error)
+	GetAllTeams(ctx context.Context, q pagination.Query) (*pagination.Page[*models.Team], error)
+	// GetTeamByID and GetTeamMembers show a team to the members of its
+	// organization, and to guests who belong to it
+	GetTeamByID(ctx context.Context, id string, userID string) (*models.Team, error)
+	UpdateTeam(ctx context.Context, id string, input models.UpdateTeamInput, updaterID string) (*models.Team, error)
+	DeleteTeam(ctx context.Context, id string, deleterID string) error
+	GetTeamMembers(ctx context.Context, id string, userID string) ([]models.TeamMembership, error)
+	GetMember(ctx context.Context, teamID, memberID string, userID string) (*models.TeamMembership, error)
+	AddMember(ctx context.Context, teamID string, input models.AddTeamMemberInput, adderID string) (*models.TeamMembership, error)
+	UpdateMember(ctx context.Context, teamID, userID string, input models.UpdateTeamMembershipInput, updaterID string) (*models.TeamMembership, error)
+	// RemoveMember lets team managers remove anyone but the lead, and members leave
+	RemoveMember(ctx context.Context, teamID, userID string, removerID string) error
+
+	// Assigned teams make their members project members with a default role;
+	// membership follows the teams as people join or leave them
+	GetProjectTeams(ctx context.Context, projectID string, userID string) ([]models.ProjectTeamAssignment, error)
+	AssignTeam(ctx context.Context, projectID string, input models.AssignTeamInput, assignerID string) (*models.ProjectTeamAssignment, error)
+	UpdateTeamAssignment(ctx context.Context, projectID, teamID string, input models.UpdateTeamAssignmentInput, updaterID string) (*models.ProjectTeamAssignment, error)
+	UnassignTeam(ctx context.Context, projectID, teamID string, removerID string) error
+
+	// JoinProject and JoinTeam add a user without checking who asked for it;
+	// callers such as invitations have already authorized the change
+	JoinProject(ctx context.Context, projectID, userID string, role models.TeamRole) (*models.TeamMember, error)
+	JoinTeam(ctx context.Context, teamID, userID string, role models.TeamRole) (*models.TeamMembership, error)
+
+	// MigrateLegacyTeams moves the teams stored among project members before
+	// teams had a collection of their own
+	MigrateLegacyTeams(ctx context.Context) error
 }
 
 type teamService struct {
@@ -31,73 +62,106 @@ type teamService struct {
 	teamMemberRepo repository.TeamMemberRepository
 	projectRepo    repository.ProjectRepository
 	userRepo       repository.UserRepository
+	orgRepo        repository.OrganizationRepository
+	notifications  NotificationService
+	bus            *events.Bus
+	txManager      repository.TxManager
 }
 
-func NewTeamService(teamRepo *mongo.TeamRepository, teamMemberRepo *mongo.TeamMemberRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) TeamService {
+func NewTeamService(teamRepo repository.TeamRepository, teamMemberRepo repository.TeamMemberRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository, orgRepo repository.OrganizationRepository, notifications NotificationService, bus *events.Bus, txManager repository.TxManager) TeamService {
 	return &teamService{
 		teamRepo:       teamRepo,
 		teamMemberRepo: teamMemberRepo,
 		projectRepo:    projectRepo,
 		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		notifications:  notifications,
+		bus:            bus,
+		txManager:      txManager,
 	}
 }
 
-func (s *teamService) GetAllTeams(ctx context.Context) (interface{}, interface{}) {
-	teams, err := s.teamRepo.GetAll(ctx)
+func (s *teamService) GetAllTeams(ctx context.Context, q pagination.Query) (*pagination.Page[*models.Team], error) {
+	// Guests only see the teams they are in, through their projects
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	teams, err := s.teamRepo.FindPage(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch teams: %w", err)
 	}
+	if err := s.describeMembers(ctx, teams.Items...); err != nil {
+		return nil, err
+	}
 
 	return teams, nil
 }
 
-func (s *teamService) CreateTeam(ctx context.Context, input models.CreateTeamInput, id string) (interface{}, interface{}) {
+func (s *teamService) CreateTeam(ctx context.Context, input models.CreateTeamInput, creatorID string) (*models.Team, error) {
 	// Validate input
 	if input.Name == "" || input.Description == "" {
 		return nil, errors.ErrInvalidInput
 	}
 
-	// Create team model
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	lead := input.Lead
+	if lead == "" {
+		lead = creatorID
+	}
+	if err := s.validateLead(ctx, lead); err != nil {
+		return nil, err
+	}
+
+	// The lead and the creator start out as owners of the team
+	now := time.Now()
+	members := []models.TeamMembership{{UserID: lead, Role: models.TeamRoleOwner, JoinedAt: now}}
+	if creatorID != lead {
+		members = append(members, models.TeamMembership{UserID: creatorID, Role: models.TeamRoleOwner, JoinedAt: now})
+	}
+
 	team := &models.Team{
-		ID:          id,
 		Name:        input.Name,
 		Description: input.Description,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Lead:        lead,
+		CreatedBy:   creatorID,
+		Members:     members,
 	}
 
-	// Save team to the repository
-	err := s.teamRepo.Create(ctx, team)
-	if err != nil {
+	if err := s.teamRepo.Create(ctx, team); err != nil {
 		return nil, fmt.Errorf("failed to create team: %w", err)
 	}
+	s.bus.Publish(ctx, creatorID, events.TeamCreated{Team: *team})
 
 	return team, nil
 }
 
-func (s *teamService) GetTeamByID(ctx context.Context, id string) (interface{}, interface{}) {
-	// Fetch the team by ID from the repository
-	team, err := s.teamRepo.GetByID(ctx, id)
+func (s *teamService) GetTeamByID(ctx context.Context, id string, userID string) (*models.Team, error) {
+	team, err := s.teamForViewer(ctx, id, userID)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrNotFound // Return an appropriate error
-		}
-		return nil, fmt.Errorf("failed to fetch team by ID: %w", err)
+		return nil, err
+	}
+	if err := s.describeMembers(ctx, team); err != nil {
+		return nil, err
 	}
 
-	return team, nil // Return the team
+	return team, nil
 }
 
-func (s *teamService) UpdateTeam(ctx context.Context, id string, input models.UpdateTeamInput) (interface{}, interface{}) {
-	// Fetch the existing team
-	team, err := s.teamRepo.GetByID(ctx, id)
+func (s *teamService) UpdateTeam(ctx context.Context, id string, input models.UpdateTeamInput, updaterID string) (*models.Team, error) {
+	team, err := s.getTeam(ctx, id)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrNotFound // Team not found
-		}
-		return nil, fmt.Errorf("failed to fetch team: %w", err) // Other errors
+		return nil, err
 	}
 
+	if !team.CanManage(updaterID) {
+		return nil, errors.ErrUnauthorized
+	}
+	previous := *team
+
 	// Update team fields if they are provided in the input
 	if input.Name != nil && *input.Name != "" {
 		team.Name = *input.Name
@@ -107,91 +171,418 @@ func (s *teamService) UpdateTeam(ctx context.Context, id string, input models.Up
 		team.Description = *input.Description
 	}
 
-	// Update the timestamp for modification
-	team.UpdatedAt = time.Now()
+	newLead := input.Lead != nil && *input.Lead != "" && *input.Lead != team.Lead
+	if newLead {
+		if err := s.validateLead(ctx, *input.Lead); err != nil {
+			return nil, err
+		}
+		team.Lead = *input.Lead
+	}
 
-	// Save the updated team back to the repository
-	if err := s.teamRepo.Update(ctx, team); err != nil {
-		return nil, fmt.Errorf("failed to update team: %w", err)
+	// A new lead joins the team as owner if they were not a member yet
+	var additions []projectAdditions
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.Update(ctx, team); err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+		if !newLead || team.Member(team.Lead) != nil {
+			return nil
+		}
+
+		member := models.TeamMembership{UserID: team.Lead, Role: models.TeamRoleOwner, JoinedAt: time.Now()}
+		if err := s.teamRepo.AddMember(ctx, team.ID, member); err != nil {
+			return fmt.Errorf("failed to add team lead: %w", err)
+		}
+		team.Members = append(team.Members, member)
+		var err error
+		additions, err = s.syncTeamProjects(ctx, team.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.bus.Publish(ctx, updaterID, events.TeamUpdated{Team: *team, Previous: previous})
+	s.notifyAdded(ctx, additions, updaterID)
 
-	return team, nil // Return the updated team
+	return team, nil
 }
 
-func (s *teamService) DeleteTeam(ctx context.Context, id string) interface{} {
-	// Check if the team exists (optional step)
-	_, err := s.teamRepo.GetByID(ctx, id)
+func (s *teamService) DeleteTeam(ctx context.Context, id string, deleterID string) error {
+	team, err := s.getTeam(ctx, id)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return errors.ErrNotFound // Team not found
+		return err
+	}
+
+	if !team.CanManage(deleterID) {
+		return errors.ErrUnauthorized
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.Delete(ctx, id); err != nil {
+			if stderrors.Is(err, errors.ErrNotFound) {
+				return errors.ErrTeamNotFound
+			}
+			return fmt.Errorf("failed to delete team: %w", err)
 		}
-		return fmt.Errorf("failed to fetch team: %w", err) // Other errors
+		return s.unassignTeamEverywhere(ctx, id)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Proceed to delete the team
-	if err := s.teamRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete team: %w", err)
+	s.bus.Publish(ctx, deleterID, events.TeamDeleted{Team: *team})
+	return nil
+}
+
+func (s *teamService) GetTeamMembers(ctx context.Context, id string, userID string) ([]models.TeamMembership, error) {
+	team, err := s.GetTeamByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Return a success response
-	return "Team successfully deleted"
+	return team.Members, nil
 }
-func (s *teamService) GetTeamMembers(ctx context.Context, id string) (interface{}, interface{}) {
-	// Check if the team exists
-	_, err := s.teamRepo.GetByID(ctx, id)
+
+func (s *teamService) GetMember(ctx context.Context, teamID, memberID string, userID string) (*models.TeamMembership, error) {
+	team, err := s.GetTeamByID(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := team.Member(memberID)
+	if member == nil {
+		return nil, errors.ErrNotInTeam
+	}
+	return member, nil
+}
+
+func (s *teamService) AddMember(ctx context.Context, teamID string, input models.AddTeamMemberInput, adderID string) (*models.TeamMembership, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	team, err := s.getTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !team.CanManage(adderID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	if _, err := s.getUser(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	return s.joinTeam(ctx, team, input.UserID, input.Role, adderID)
+}
+
+func (s *teamService) JoinTeam(ctx context.Context, teamID, userID string, role models.TeamRole) (*models.TeamMembership, error) {
+	team, err := s.getTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.joinTeam(ctx, team, userID, role, userID)
+}
+
+// joinTeam adds a user to a team, and to its organization as a guest if they
+// are new there
+func (s *teamService) joinTeam(ctx context.Context, team *models.Team, userID string, role models.TeamRole, actorID string) (*models.TeamMembership, error) {
+	member := models.TeamMembership{
+		UserID:   userID,
+		Role:     role,
+		JoinedAt: time.Now(),
+	}
+
+	var additions []projectAdditions
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.AddMember(ctx, team.ID, member); err != nil {
+			return s.teamError(err)
+		}
+		if err := joinOrg(ctx, s.orgRepo, team.OrgID, userID); err != nil {
+			return err
+		}
+		var err error
+		additions, err = s.syncTeamProjects(ctx, team.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, actorID, events.TeamMemberAdded{TeamID: team.ID, UserID: userID, Role: role})
+	s.notifyAdded(ctx, additions, actorID)
+
+	return &member, nil
+}
+
+func (s *teamService) UpdateMember(ctx context.Context, teamID, userID string, input models.UpdateTeamMembershipInput, updaterID string) (*models.TeamMembership, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	team, err := s.getTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !team.CanManage(updaterID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	member := team.Member(userID)
+	if member == nil {
+		return nil, errors.ErrNotInTeam
+	}
+
+	if err := s.teamRepo.UpdateMemberRole(ctx, teamID, userID, input.Role); err != nil {
+		return nil, s.teamError(err)
+	}
+	s.bus.Publish(ctx, updaterID, events.TeamMemberUpdated{TeamID: teamID, UserID: userID, Role: input.Role, PreviousRole: member.Role})
+
+	member.Role = input.Role
+	return member, nil
+}
+
+func (s *teamService) RemoveMember(ctx context.Context, teamID, userID string, removerID string) error {
+	team, err := s.getTeam(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	if userID != removerID && !team.CanManage(removerID) {
+		return errors.ErrUnauthorized
+	}
+
+	// The lead has to hand over the team before leaving it
+	if userID == team.Lead {
+		return errors.ErrCannotRemoveLead
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.RemoveMember(ctx, teamID, userID); err != nil {
+			return s.teamError(err)
+		}
+		_, err := s.syncTeamProjects(ctx, teamID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, removerID, events.TeamMemberRemoved{TeamID: teamID, UserID: userID})
+	return nil
+}
+
+// teamForViewer loads a team the user may see: members of the organization
+// see all of its teams, guests only the ones they belong to
+func (s *teamService) teamForViewer(ctx context.Context, id string, userID string) (*models.Team, error) {
+	team, err := s.getTeam(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.IsGuest(ctx) && team.Member(userID) == nil {
+		return nil, errors.ErrUnauthorized
+	}
+	return team, nil
+}
+
+// describeMembers fills in the names and emails of the teams' members with
+// a single user lookup
+func (s *teamService) describeMembers(ctx context.Context, teams ...*models.Team) error {
+	userIDs := newIDSet()
+	for _, team := range teams {
+		for _, member := range team.Members {
+			userIDs.add(member.UserID)
+		}
+	}
+	if len(userIDs.ids) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, userIDs.ids)
+	if err != nil {
+		return fmt.Errorf("failed to fetch team members: %w", err)
+	}
+	usersByID := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, team := range teams {
+		for i := range team.Members {
+			if user := usersByID[team.Members[i].UserID]; user != nil {
+				team.Members[i].Name = user.Name
+				team.Members[i].Email = user.Email
+			}
+		}
+	}
+	return nil
+}
+
+func (s *teamService) MigrateLegacyTeams(ctx context.Context) error {
+	moved, err := s.teamRepo.MigrateLegacy(ctx)
+	if err != nil {
+		return err
+	}
+	if moved > 0 {
+		log.Printf("Moved %d teams out of the team members collection", moved)
+	}
+	return nil
+}
+
+func (s *teamService) getTeam(ctx context.Context, id string) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, id)
 	if err != nil {
 		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrNotFound // Return team not found error
+			return nil, errors.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch team: %w", err)
+	}
+
+	return team, nil
+}
+
+// validateLead checks that the proposed team lead is a real user
+func (s *teamService) validateLead(ctx context.Context, userID string) error {
+	if _, err := s.getUser(ctx, userID); err != nil {
+		if stderrors.Is(err, errors.ErrUserNotFound) {
+			return errors.ErrInvalidTeamLead
 		}
-		return nil, fmt.Errorf("failed to fetch team: %w", err) // Handle other errors
+		return fmt.Errorf("failed to fetch team lead: %w", err)
 	}
+	return nil
+}
 
-	// Fetch the team members from the repository
-	members, err := s.teamMemberRepo.GetByTeamID(ctx, id)
+// getUser looks up a user, mapping unknown and malformed IDs to ErrUserNotFound
+func (s *teamService) getUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch team members: %w", err)
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, errors.ErrNotFound) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, err
 	}
+	return user, nil
+}
 
-	// Return the list of members
-	return members, nil
+// teamError maps repository errors from member updates to service errors
+func (s *teamService) teamError(err error) error {
+	switch {
+	case stderrors.Is(err, errors.ErrNotFound):
+		return errors.ErrTeamNotFound
+	case stderrors.Is(err, errors.ErrAlreadyInTeam), stderrors.Is(err, errors.ErrNotInTeam):
+		return err
+	default:
+		return fmt.Errorf("failed to update team members: %w", err)
+	}
 }
+
 func (s *teamService) AddTeamMember(ctx context.Context, projectID string, input models.AddTeamMemberInput, adderID string) (*models.TeamMember, error) {
 	// Check if project exists and adder has permission
-	project, err := s.projectRepo.GetByID(ctx, projectID)
+	project, err := s.getProject(ctx, projectID)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrProjectNotFound
-		}
 		return nil, err
 	}
 
-	// Only project owner can add team members
-	if project.CreatedBy != adderID {
+	// Only project owners can add team members
+	if !project.IsOwner(adderID) {
 		return nil, errors.ErrUnauthorized
 	}
 
 	// Check if user exists
-	user, err := s.userRepo.GetByID(ctx, input.UserID)
+	user, err := s.getUser(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.joinProject(ctx, project, user.ID, input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, adderID, events.MemberAdded{ProjectID: project.ID, UserID: user.ID, Role: member.Role})
+	notifyProjectAdded(ctx, s.notifications, project, user.ID, adderID)
+	return member, nil
+}
+
+func (s *teamService) JoinProject(ctx context.Context, projectID, userID string, role models.TeamRole) (*models.TeamMember, error) {
+	project, err := s.getProject(ctx, projectID)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrUserNotFound
-		}
 		return nil, err
 	}
 
-	// Create team member
-	member := &models.TeamMember{
-		ProjectID: projectID,
-		UserID:    user.ID,
-		Role:      input.Role,
-		Status:    models.TeamMemberStatusActive,
+	member, err := s.joinProject(ctx, project, userID, role)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.teamMemberRepo.Create(ctx, member); err != nil {
-		if stderrors.Is(err, errors.ErrAlreadyInTeam) {
-			return nil, errors.ErrAlreadyInTeam
+	s.bus.Publish(ctx, userID, events.MemberAdded{ProjectID: project.ID, UserID: userID, Role: member.Role})
+	return member, nil
+}
+
+// joinProject makes a user a direct project member. A user who is a member
+// through a team becomes a direct member, and the owner role makes the user
+// a project owner. Users new to the project's organization join it as guests.
+func (s *teamService) joinProject(ctx context.Context, project *models.Project, userID string, role models.TeamRole) (*models.TeamMember, error) {
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	member, err := s.teamMemberRepo.GetByProjectAndUser(ctx, project.ID, userID)
+	if err != nil && !stderrors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if member != nil && member.IsDirect() {
+		return nil, errors.ErrAlreadyInTeam
+	}
+
+	// The member record and the project's access list change together
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if member != nil {
+			member.Source = models.TeamMemberSourceDirect
+			member.Role = role
+			member.Status = models.TeamMemberStatusActive
+			member.Override = false
+			if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+				return fmt.Errorf("failed to update team member: %w", err)
+			}
+		} else {
+			member = &models.TeamMember{
+				OrgID:     project.OrgID,
+				ProjectID: project.ID,
+				UserID:    userID,
+				Role:      role,
+				Status:    models.TeamMemberStatusActive,
+				Source:    models.TeamMemberSourceDirect,
+			}
+			if err := s.teamMemberRepo.Create(ctx, member); err != nil {
+				if stderrors.Is(err, errors.ErrAlreadyInTeam) {
+					return errors.ErrAlreadyInTeam
+				}
+				return fmt.Errorf("failed to create team member: %w", err)
+			}
+		}
+
+		if err := joinOrg(ctx, s.orgRepo, project.OrgID, userID); err != nil {
+			return err
+		}
+
+		if !containsString(project.Team, userID) {
+			if err := s.projectRepo.AddToTeam(ctx, project.ID, userID); err != nil && !stderrors.Is(err, errors.ErrAlreadyInTeam) {
+				return err
+			}
+			project.Team = append(project.Team, userID)
+		}
+		if role != models.TeamRoleOwner || project.IsOwner(userID) {
+			return nil
 		}
-		return nil, fmt.Errorf("failed to create team member: %w", err)
+		previousOwners := project.Owners
+		project.AddOwner(userID)
+		return s.projectRepo.SetOwners(ctx, project.ID, previousOwners, project.Owners)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return member, nil
@@ -199,21 +590,21 @@ func (s *teamService) AddTeamMember(ctx context.Context, projectID string, input
 
 func (s *teamService) UpdateTeamMember(ctx context.Context, projectID, memberID string, input models.UpdateTeamMemberInput, updaterID string) (*models.TeamMember, error) {
 	// Get project to check permissions
-	project, err := s.projectRepo.GetByID(ctx, projectID)
+	project, err := s.getProject(ctx, projectID)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return nil, errors.ErrProjectNotFound
-		}
 		return nil, err
 	}
 
-	// Only project owner can update team members
-	if project.CreatedBy != updaterID {
+	// Only project owners can update team members
+	if !project.IsOwner(updaterID) {
 		return nil, errors.ErrUnauthorized
 	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
 
 	// Get team member and verify they belong to this project
-	member, err := s.teamRepo.GetByID(ctx, memberID)
+	member, err := s.teamMemberRepo.GetByID(ctx, memberID)
 	if err != nil {
 		if stderrors.Is(err, errors.ErrNotFound) {
 			return nil, errors.ErrNotFound
@@ -226,43 +617,84 @@ func (s *teamService) UpdateTeamMember(ctx context.Context, projectID, memberID
 		return nil, errors.ErrNotFound
 	}
 
-	// Cannot modify project owner's role
-	if member.UserID == project.CreatedBy {
-		return nil, errors.ErrCannotRemoveOwner
+	// Ownership follows direct membership only, see AssignTeamInput
+	if input.Role != nil && *input.Role == models.TeamRoleOwner && !member.IsDirect() {
+		return nil, fmt.Errorf("%w: add the user directly to make them an owner", errors.ErrInvalidInput)
 	}
+	previous := *member
 
-	// Update fields if provided
+	// Update fields if provided. On team-derived members this is an override
+	// that survives syncing, until the member is set to inherit again.
 	if input.Role != nil {
 		member.Role = *input.Role
 	}
 	if input.Status != nil {
 		member.Status = *input.Status
 	}
+	if !member.IsDirect() {
+		member.Override = !input.Inherit
+	}
+
+	// Active members with the owner role are the project's owners
+	previousOwners := project.Owners
+	ownersChanged := false
+	if member.Role == models.TeamRoleOwner && member.Status == models.TeamMemberStatusActive {
+		ownersChanged = !project.IsOwner(member.UserID)
+		project.AddOwner(member.UserID)
+	} else if input.Role != nil || input.Status != nil {
+		if ownersChanged, err = project.RemoveOwner(member.UserID); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.teamRepo.Update(ctx, member); err != nil {
-		return nil, fmt.Errorf("failed to update team member: %w", err)
+	var added []string
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+			return fmt.Errorf("failed to update team member: %w", err)
+		}
+		if ownersChanged {
+			if err := s.projectRepo.SetOwners(ctx, project.ID, previousOwners, project.Owners); err != nil {
+				return err
+			}
+		}
+		if !member.IsDirect() {
+			var err error
+			added, err = s.syncProjectMembers(ctx, project)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	if !member.IsDirect() {
+		if member, err = s.teamMemberRepo.GetByID(ctx, member.ID); err != nil {
+			return nil, err
+		}
+	}
+	s.bus.Publish(ctx, updaterID, events.MemberUpdated{Member: *member, Previous: previous})
+	s.notifyAdded(ctx, []projectAdditions{{project: project, userIDs: added}}, updaterID)
 	return member, nil
 }
 
 func (s *teamService) RemoveTeamMember(ctx context.Context, projectID, memberID string, removerID string) error {
 	// Check project exists and verify permissions
-	project, err := s.projectRepo.GetByID(ctx, projectID)
+	project, err := s.getProject(ctx, projectID)
 	if err != nil {
-		if stderrors.Is(err, errors.ErrNotFound) {
-			return errors.ErrProjectNotFound
-		}
 		return err
 	}
 
-	// Only project owner can remove team members
-	if project.CreatedBy != removerID {
+	// Only project owners can remove team members
+	if !project.IsOwner(removerID) {
 		return errors.ErrUnauthorized
 	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
 
 	// Get team member and verify they belong to this project
-	member, err := s.teamRepo.GetByID(ctx, memberID)
+	member, err := s.teamMemberRepo.GetByID(ctx, memberID)
 	if err != nil {
 		if stderrors.Is(err, errors.ErrNotFound) {
 			return errors.ErrNotFound
@@ -275,17 +707,56 @@ func (s *teamService) RemoveTeamMember(ctx context.Context, projectID, memberID
 		return errors.ErrNotFound
 	}
 
-	// Cannot remove project owner
-	if member.UserID == project.CreatedBy {
-		return errors.ErrCannotRemoveOwner
+	// Owners can leave as long as another owner remains
+	previousOwners := project.Owners
+	ownersChanged, err := project.RemoveOwner(member.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// Members through an assigned team would come back on the next sync,
+		// so they are excluded with an override instead
+		if len(member.TeamIDs) > 0 {
+			member.Source = models.TeamMemberSourceTeam
+			member.Status = models.TeamMemberStatusInactive
+			member.Override = true
+			if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+				return err
+			}
+		} else if err := s.teamMemberRepo.Delete(ctx, memberID); err != nil {
+			return err
+		}
+
+		if ownersChanged {
+			if err := s.projectRepo.SetOwners(ctx, project.ID, previousOwners, project.Owners); err != nil {
+				return err
+			}
+		}
+		if !containsString(project.Team, member.UserID) {
+			return nil
+		}
+		return s.projectRepo.UpdateTeamList(ctx, project.ID, nil, []string{member.UserID})
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.teamRepo.Delete(ctx, memberID)
+	s.bus.Publish(ctx, removerID, events.MemberRemoved{ProjectID: project.ID, UserID: member.UserID})
+	return nil
 }
 
-func (s *teamService) GetTeamMember(ctx context.Context, projectID, memberID string) (*models.TeamMember, error) {
+func (s *teamService) GetTeamMember(ctx context.Context, projectID, memberID string, userID string) (*models.TeamMember, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
 	// Get team member
-	member, err := s.teamRepo.GetByID(ctx, memberID)
+	member, err := s.teamMemberRepo.GetByID(ctx, memberID)
 	if err != nil {
 		if stderrors.Is(err, errors.ErrNotFound) {
 			return nil, errors.ErrNotFound
@@ -301,8 +772,16 @@ func (s *teamService) GetTeamMember(ctx context.Context, projectID, memberID str
 	return member, nil
 }
 
-func (s *teamService) GetProjectTeam(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
-	members, err := s.teamRepo.GetProjectMembers(ctx, projectID)
+func (s *teamService) GetProjectTeam(ctx context.Context, projectID string, userID string) ([]*models.TeamMember, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	members, err := s.teamMemberRepo.GetProjectMembers(ctx, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project team members: %w", err)
 	}
@@ -0,0 +1,361 @@
+// Package services internal/services/team_assignment.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"sort"
+	"time"
+)
+
+func (s *teamService) GetProjectTeams(ctx context.Context, projectID string, userID string) ([]models.ProjectTeamAssignment, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return project.Teams, nil
+}
+
+func (s *teamService) AssignTeam(ctx context.Context, projectID string, input models.AssignTeamInput, assignerID string) (*models.ProjectTeamAssignment, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only project owners can assign teams
+	if !project.IsOwner(assignerID) {
+		return nil, errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if project.TeamAssignment(input.TeamID) != nil {
+		return nil, errors.ErrTeamAssigned
+	}
+
+	if _, err := s.getTeam(ctx, input.TeamID); err != nil {
+		return nil, err
+	}
+
+	assignment := models.ProjectTeamAssignment{
+		TeamID:     input.TeamID,
+		Role:       input.Role,
+		AssignedBy: assignerID,
+		AssignedAt: time.Now(),
+	}
+	project.Teams = append(project.Teams, assignment)
+
+	var added []string
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.AssignTeam(ctx, project.ID, assignment); err != nil {
+			return err
+		}
+		var err error
+		added, err = s.syncProjectMembers(ctx, project)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, assignerID, events.TeamAssigned{ProjectID: project.ID, Assignment: assignment})
+	s.notifyAdded(ctx, []projectAdditions{{project: project, userIDs: added}}, assignerID)
+
+	return &assignment, nil
+}
+
+func (s *teamService) UpdateTeamAssignment(ctx context.Context, projectID, teamID string, input models.UpdateTeamAssignmentInput, updaterID string) (*models.ProjectTeamAssignment, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.IsOwner(updaterID) {
+		return nil, errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	assignment := project.TeamAssignment(teamID)
+	if assignment == nil {
+		return nil, errors.ErrTeamNotAssigned
+	}
+	previous := *assignment
+	assignment.Role = input.Role
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.UpdateTeamRole(ctx, project.ID, teamID, input.Role); err != nil {
+			return err
+		}
+		_, err := s.syncProjectMembers(ctx, project)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, updaterID, events.TeamAssignmentUpdated{ProjectID: project.ID, Assignment: *assignment, Previous: previous})
+
+	return assignment, nil
+}
+
+func (s *teamService) UnassignTeam(ctx context.Context, projectID, teamID string, removerID string) error {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if !project.IsOwner(removerID) {
+		return errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	assignment := project.TeamAssignment(teamID)
+	if assignment == nil {
+		return errors.ErrTeamNotAssigned
+	}
+	removed := *assignment
+	project.Teams = withoutTeam(project.Teams, teamID)
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.UnassignTeam(ctx, project.ID, teamID); err != nil {
+			return err
+		}
+		_, err := s.syncProjectMembers(ctx, project)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, removerID, events.TeamUnassigned{ProjectID: project.ID, Assignment: removed})
+	return nil
+}
+
+// projectAdditions lists the users who gained access to a project
+type projectAdditions struct {
+	project *models.Project
+	userIDs []string
+}
+
+// notifyAdded tells users who gained access to projects through their teams
+// that they were added. It runs once the change is committed.
+func (s *teamService) notifyAdded(ctx context.Context, additions []projectAdditions, actorID string) {
+	for _, addition := range additions {
+		for _, userID := range addition.userIDs {
+			notifyProjectAdded(ctx, s.notifications, addition.project, userID, actorID)
+		}
+	}
+}
+
+// syncTeamProjects re-derives the membership of every unarchived project a
+// team is assigned to, after people joined or left the team, and returns the
+// users each project gained
+func (s *teamService) syncTeamProjects(ctx context.Context, teamID string) ([]projectAdditions, error) {
+	projects, err := s.projectRepo.GetByAssignedTeam(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team projects: %w", err)
+	}
+
+	var additions []projectAdditions
+	for _, project := range projects {
+		// Archived projects keep the members they had
+		if project.IsArchived() {
+			continue
+		}
+		added, err := s.syncProjectMembers(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		if len(added) > 0 {
+			additions = append(additions, projectAdditions{project: project, userIDs: added})
+		}
+	}
+
+	return additions, nil
+}
+
+// unassignTeamEverywhere detaches a deleted team from its unarchived projects.
+// Archived ones keep it; a missing team adds no members when they are synced.
+func (s *teamService) unassignTeamEverywhere(ctx context.Context, teamID string) error {
+	projects, err := s.projectRepo.GetByAssignedTeam(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch team projects: %w", err)
+	}
+
+	for _, project := range projects {
+		if project.IsArchived() {
+			continue
+		}
+		if err := s.projectRepo.UnassignTeam(ctx, project.ID, teamID); err != nil {
+			return err
+		}
+		project.Teams = withoutTeam(project.Teams, teamID)
+		if _, err := s.syncProjectMembers(ctx, project); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncProjectMembers brings the team-derived members of a project in line
+// with its assigned teams, and adds and removes users on its access list
+// alone, leaving the rest of the project as it is.
+// Direct members only have their team links refreshed, and members with an
+// override keep the role and status set on them. It returns the users who
+// were not on the access list before.
+func (s *teamService) syncProjectMembers(ctx context.Context, project *models.Project) ([]string, error) {
+	teams := make(map[string]*models.Team, len(project.Teams))
+	for _, assignment := range project.Teams {
+		team, err := s.teamRepo.GetByID(ctx, assignment.TeamID)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch team: %w", err)
+		}
+		teams[team.ID] = team
+	}
+	derived := models.DeriveProjectMembers(project.Teams, teams)
+
+	existing, err := s.teamMemberRepo.GetAllByProject(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool)
+	revoked := make(map[string]bool)
+	for _, member := range existing {
+		want, ok := derived[member.UserID]
+		delete(derived, member.UserID)
+
+		if !ok {
+			if member.IsDirect() {
+				if len(member.TeamIDs) > 0 {
+					member.TeamIDs = nil
+					if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+			if err := s.teamMemberRepo.Delete(ctx, member.ID); err != nil {
+				return nil, err
+			}
+			revoked[member.UserID] = true
+			continue
+		}
+
+		member.TeamIDs = want.TeamIDs
+		if !member.IsDirect() && !member.Override {
+			member.Role = want.Role
+			member.Status = want.Status
+		}
+		if err := s.teamMemberRepo.Update(ctx, member); err != nil {
+			return nil, err
+		}
+
+		if member.IsDirect() {
+			continue
+		}
+		if member.Status == models.TeamMemberStatusActive {
+			granted[member.UserID] = true
+		} else {
+			revoked[member.UserID] = true
+		}
+	}
+
+	// Create the remaining members in a stable order
+	newUsers := make([]string, 0, len(derived))
+	for userID := range derived {
+		newUsers = append(newUsers, userID)
+	}
+	sort.Strings(newUsers)
+	for _, userID := range newUsers {
+		member := derived[userID]
+		member.OrgID = project.OrgID
+		member.ProjectID = project.ID
+		if err := s.teamMemberRepo.Create(ctx, member); err != nil {
+			return nil, fmt.Errorf("failed to create team member: %w", err)
+		}
+		granted[userID] = true
+	}
+
+	team := make([]string, 0, len(project.Team)+len(granted))
+	var removed []string
+	for _, userID := range project.Team {
+		if !revoked[userID] || project.IsOwner(userID) {
+			team = append(team, userID)
+		} else {
+			removed = append(removed, userID)
+		}
+	}
+	var added []string
+	for _, userID := range sortedKeys(granted) {
+		if !containsString(team, userID) {
+			team = append(team, userID)
+			added = append(added, userID)
+		}
+	}
+	project.Team = team
+
+	if len(added) == 0 && len(removed) == 0 {
+		return nil, nil
+	}
+	if err := s.projectRepo.UpdateTeamList(ctx, project.ID, added, removed); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (s *teamService) getProject(ctx context.Context, projectID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) || stderrors.Is(err, primitive.ErrInvalidHex) {
+			return nil, errors.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+func withoutTeam(assignments []models.ProjectTeamAssignment, teamID string) []models.ProjectTeamAssignment {
+	kept := make([]models.ProjectTeamAssignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if assignment.TeamID != teamID {
+			kept = append(kept, assignment)
+		}
+	}
+	return kept
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
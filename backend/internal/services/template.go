@@ -0,0 +1,304 @@
+// Package services internal/services/template.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/tenant"
+)
+
+type TemplateService interface {
+	ListTemplates(ctx context.Context) ([]*models.ProjectTemplate, error)
+	GetTemplate(ctx context.Context, id string) (*models.ProjectTemplate, error)
+	CreateTemplate(ctx context.Context, input models.TemplateInput, userID string) (*models.ProjectTemplate, error)
+	// SaveAsTemplate captures a project's documents, and optionally its
+	// members and teams, in a new template
+	SaveAsTemplate(ctx context.Context, projectID string, input models.SaveTemplateInput, userID string) (*models.ProjectTemplate, error)
+	// UpdateTemplate replaces the content of a template; only its creator
+	// and organization admins can change or delete it
+	UpdateTemplate(ctx context.Context, id string, input models.TemplateInput, userID string) (*models.ProjectTemplate, error)
+	DeleteTemplate(ctx context.Context, id string, userID string) error
+	// UseTemplate creates a project with the template's documents, members
+	// and teams. The user creating it becomes its owner.
+	UseTemplate(ctx context.Context, id string, input models.UseTemplateInput, userID string) (*models.Project, error)
+	// CloneProject creates a project with copies of another project's
+	// documents, mockups and membership
+	CloneProject(ctx context.Context, projectID string, input models.CloneProjectInput, userID string) (*models.Project, error)
+}
+
+type templateService struct {
+	templateRepo   repository.TemplateRepository
+	projectRepo    repository.ProjectRepository
+	documentRepo   repository.DocumentRepository
+	mockupRepo     repository.MockupRepository
+	teamMemberRepo repository.TeamMemberRepository
+	linkRepo       repository.LinkRepository
+	userRepo       repository.UserRepository
+	orgRepo        repository.OrganizationRepository
+	projects       ProjectService
+	teams          TeamService
+	linkService    LinkService
+	progress       ProgressService
+	notifications  NotificationService
+	bus            *events.Bus
+	txManager      repository.TxManager
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepository, projectRepo repository.ProjectRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, teamMemberRepo repository.TeamMemberRepository, linkRepo repository.LinkRepository, userRepo repository.UserRepository, orgRepo repository.OrganizationRepository, projects ProjectService, teams TeamService, linkService LinkService, progress ProgressService, notifications NotificationService, bus *events.Bus, txManager repository.TxManager) TemplateService {
+	return &templateService{
+		templateRepo:   templateRepo,
+		projectRepo:    projectRepo,
+		documentRepo:   documentRepo,
+		mockupRepo:     mockupRepo,
+		teamMemberRepo: teamMemberRepo,
+		linkRepo:       linkRepo,
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		projects:       projects,
+		teams:          teams,
+		linkService:    linkService,
+		progress:       progress,
+		notifications:  notifications,
+		bus:            bus,
+		txManager:      txManager,
+	}
+}
+
+func (s *templateService) ListTemplates(ctx context.Context) ([]*models.ProjectTemplate, error) {
+	// Guests work only in projects they were added to
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	templates, err := s.templateRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *templateService) GetTemplate(ctx context.Context, id string) (*models.ProjectTemplate, error) {
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, input models.TemplateInput, userID string) (*models.ProjectTemplate, error) {
+	// Guests work only in projects they were added to
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	template := &models.ProjectTemplate{CreatedBy: userID}
+	if err := s.applyInput(ctx, template, input, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TemplateCreated{Template: *template})
+	return template, nil
+}
+
+func (s *templateService) SaveAsTemplate(ctx context.Context, projectID string, input models.SaveTemplateInput, userID string) (*models.ProjectTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	project, err := s.projectForMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.selectDocuments(ctx, projectID, input.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Documents keep their IDs as keys, so links between them still resolve
+	template := &models.ProjectTemplate{
+		Name:        input.Name,
+		Description: input.Description,
+		Status:      models.ProjectStatusPlanning,
+		Documents:   []models.TemplateDocument{},
+		Members:     []models.TemplateMember{},
+		Teams:       []models.TemplateTeam{},
+		ProjectKey:  project.ID,
+		CreatedBy:   userID,
+	}
+	for _, doc := range docs {
+		template.Documents = append(template.Documents, models.TemplateDocument{
+			Key:     doc.ID,
+			Title:   doc.Title,
+			Type:    doc.Type,
+			Content: doc.Content,
+			Status:  doc.Status,
+			Tags:    doc.Tags,
+		})
+	}
+
+	if input.IncludeMembers {
+		template.Members, template.Teams, err = s.membership(ctx, project, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TemplateCreated{Template: *template})
+	return template, nil
+}
+
+func (s *templateService) UpdateTemplate(ctx context.Context, id string, input models.TemplateInput, userID string) (*models.ProjectTemplate, error) {
+	template, err := s.templateForEditor(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	previous := *template
+
+	if err := s.applyInput(ctx, template, input, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.TemplateUpdated{Template: *template, Previous: previous})
+	return template, nil
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, id string, userID string) error {
+	template, err := s.templateForEditor(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.bus.Publish(ctx, userID, events.TemplateDeleted{Template: *template})
+	return nil
+}
+
+func (s *templateService) UseTemplate(ctx context.Context, id string, input models.UseTemplateInput, userID string) (*models.Project, error) {
+	if tenant.IsGuest(ctx) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &projectCopy{
+		sourceID:  template.ProjectKey,
+		documents: make([]*models.Document, 0, len(template.Documents)),
+		members:   template.Members,
+		teams:     template.Teams,
+	}
+	for _, doc := range template.Documents {
+		content.documents = append(content.documents, &models.Document{
+			ID:      doc.Key,
+			Title:   doc.Title,
+			Type:    doc.Type,
+			Content: doc.Content,
+			Status:  doc.Status,
+			Tags:    doc.Tags,
+		})
+	}
+
+	return s.createProject(ctx, input.ProjectInput(template), content, userID)
+}
+
+// templateForEditor loads a template the user may change
+func (s *templateService) templateForEditor(ctx context.Context, id string, userID string) (*models.ProjectTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, _ := tenant.FromContext(ctx)
+	if template.CreatedBy != userID && scope.Role != models.OrgRoleAdmin {
+		return nil, errors.ErrUnauthorized
+	}
+	return template, nil
+}
+
+// applyInput validates template input and copies it onto the template.
+// Documents without a key get a fresh one.
+func (s *templateService) applyInput(ctx context.Context, template *models.ProjectTemplate, input models.TemplateInput, userID string) error {
+	if err := input.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	for n := range input.Documents {
+		if input.Documents[n].Key == "" {
+			input.Documents[n].Key = primitive.NewObjectID().Hex()
+		}
+		tags, err := models.NormalizeTags(input.Documents[n].Tags)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+		}
+		input.Documents[n].Tags = tags
+	}
+
+	// Members are added to projects without being asked, so they must
+	// already belong to the organization
+	if len(input.Members) > 0 {
+		org, err := s.orgRepo.GetByID(ctx, tenant.OrgID(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to get organization: %w", err)
+		}
+		for _, m := range input.Members {
+			if org.Member(m.UserID) == nil {
+				return errors.ErrUserNotFound
+			}
+		}
+	}
+	for _, t := range input.Teams {
+		if _, err := s.teams.GetTeamByID(ctx, t.TeamID, userID); err != nil {
+			return err
+		}
+	}
+
+	template.Name = input.Name
+	template.Description = input.Description
+	template.Status = input.Status
+	template.Documents = input.Documents
+	template.Members = input.Members
+	template.Teams = input.Teams
+	if template.Documents == nil {
+		template.Documents = []models.TemplateDocument{}
+	}
+	if template.Members == nil {
+		template.Members = []models.TemplateMember{}
+	}
+	if template.Teams == nil {
+		template.Teams = []models.TemplateTeam{}
+	}
+	return nil
+}
+
+// projectForMember loads a project and verifies the user is a member of it
+func (s *templateService) projectForMember(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
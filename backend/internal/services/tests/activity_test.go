@@ -0,0 +1,107 @@
+// internal/services/activity_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+type MockActivityRepository struct {
+	mock.Mock
+	repository.ActivityRepository
+}
+
+func (m *MockActivityRepository) Record(ctx context.Context, activity *models.Activity, since time.Time) error {
+	return m.Called(ctx, activity, since).Error(0)
+}
+
+func (m *MockActivityRepository) FindPage(ctx context.Context, projectIDs []string, q pagination.Query) (*pagination.Page[*models.Activity], error) {
+	args := m.Called(ctx, projectIDs, q)
+	return args.Get(0).(*pagination.Page[*models.Activity]), args.Error(1)
+}
+
+func activityQuery(t *testing.T, values url.Values) pagination.Query {
+	q, err := pagination.Parse(values, models.ActivityListSpec)
+	require.NoError(t, err)
+	return q
+}
+
+func TestActivityService_EditsJoinTheirBurst(t *testing.T) {
+	activityRepo := new(MockActivityRepository)
+	var recorded []*models.Activity
+	var since []time.Time
+	activityRepo.On("Record", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(*models.Activity))
+		since = append(since, args.Get(2).(time.Time))
+	}).Return(nil)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, Name: "Apollo"}, nil)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, "editor").Return(&models.User{ID: "editor", Name: "Alice"}, nil)
+
+	bus := events.NewBus()
+	services.NewActivityService(activityRepo, projectRepo, userRepo, nil).Subscribe(bus)
+
+	doc := models.Document{ID: testDocID, ProjectID: testProjectID, Title: "LLD", Status: models.DocumentStatusDraft}
+	for i := 0; i < 2; i++ {
+		bus.Publish(memberContext(), "editor", events.DocumentUpdated{Document: doc, Previous: doc})
+	}
+	// An update that changed the status is told by the status change alone
+	inReview := doc
+	inReview.Status = models.DocumentStatusInReview
+	bus.Publish(memberContext(), "editor", events.DocumentUpdated{Document: inReview, Previous: doc})
+
+	require.Len(t, recorded, 2)
+	for i, activity := range recorded {
+		assert.Equal(t, models.ActivityDocumentEdited, activity.Verb)
+		assert.Equal(t, testDocID, activity.ResourceID)
+		assert.Equal(t, "Alice", activity.ActorName)
+		assert.Equal(t, "Apollo", activity.ProjectName)
+		assert.Equal(t, 1, activity.Count)
+		// Each edit joins the entry of an edit made within the burst window
+		assert.Equal(t, activity.OccurredAt.Add(-models.ActivityBurstWindow), since[i])
+	}
+	assert.NotEqual(t, recorded[0].EventIDs, recorded[1].EventIDs)
+}
+
+func TestActivityService_ListShowsOnlyAccessibleProjects(t *testing.T) {
+	activityRepo := new(MockActivityRepository)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("GetByUser", mock.Anything, "member").Return([]*models.Project{{ID: "mine"}, {ID: "shared"}}, nil)
+	activityRepo.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.Page[*models.Activity]{Items: []*models.Activity{
+		{Verb: models.ActivityDocumentEdited, ActorName: "Alice", Subject: "LLD", Count: 3},
+	}}, nil)
+	service := services.NewActivityService(activityRepo, projectRepo, nil, nil)
+
+	page, err := service.ListActivity(memberContext(), "member", activityQuery(t, url.Values{}))
+	require.NoError(t, err)
+	activityRepo.AssertCalled(t, "FindPage", mock.Anything, []string{"mine", "shared"}, mock.Anything)
+	assert.Equal(t, "Alice edited LLD (3 times)", page.Items[0].Message)
+
+	// Asking for a project the user lost access to finds nothing
+	_, err = service.ListActivity(memberContext(), "member", activityQuery(t, url.Values{"projectId": {"gone"}}))
+	require.NoError(t, err)
+	activityRepo.AssertCalled(t, "FindPage", mock.Anything, []string{}, mock.Anything)
+}
+
+func TestActivityService_ProjectFeedIsForMembers(t *testing.T) {
+	activityRepo := new(MockActivityRepository)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"member"}}, nil)
+	service := services.NewActivityService(activityRepo, projectRepo, nil, nil)
+
+	_, err := service.ListProjectActivity(memberContext(), testProjectID, "outsider", activityQuery(t, url.Values{}))
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	activityRepo.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+}
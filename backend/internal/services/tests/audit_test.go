@@ -0,0 +1,139 @@
+// internal/services/audit_test.go
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/requestmeta"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"testing"
+	"time"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+	repository.AuditRepository
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+func (m *MockAuditRepository) FindPage(ctx context.Context, q pagination.Query) (*pagination.Page[*models.AuditEntry], error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).(*pagination.Page[*models.AuditEntry]), args.Error(1)
+}
+
+func adminContext() context.Context {
+	return tenant.WithOrg(context.Background(), "org", models.OrgRoleAdmin)
+}
+
+func auditQuery(t *testing.T) pagination.Query {
+	q, err := pagination.Parse(url.Values{}, models.AuditListSpec)
+	require.NoError(t, err)
+	return q
+}
+
+func TestAuditService_RecordsRegistration(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	var recorded []*models.AuditEntry
+	auditRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(*models.AuditEntry))
+	}).Return(nil)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, "new-user").Return(&models.User{ID: "new-user", Email: "new@example.com"}, nil)
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	bus := events.NewBus()
+	services.NewAuditService(auditRepo, nil, userRepo).Subscribe(bus)
+	organizations := services.NewOrganizationService(orgRepo, userRepo, bus, "")
+
+	ctx := requestmeta.With(context.Background(), requestmeta.Meta{IP: "203.0.113.7", UserAgent: "browser"})
+	user := &models.User{ID: "new-user", Email: "new@example.com", Name: "New"}
+	require.NoError(t, organizations.UserRegistered(ctx, user, models.RegisterInput{}))
+
+	require.Len(t, recorded, 2)
+	entry := recorded[1]
+	assert.Equal(t, "user.registered", entry.Action)
+	// The registration shows up in the workspace the user got
+	assert.Equal(t, "personal", entry.OrgID)
+	assert.Equal(t, "new@example.com", entry.ActorEmail)
+	assert.Equal(t, "203.0.113.7", entry.IP)
+	assert.Equal(t, events.ResourceUser, entry.ResourceType)
+}
+
+func TestAuditService_ExportCSVDefusesFormulas(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("FindPage", mock.Anything, mock.Anything).Return(&pagination.Page[*models.AuditEntry]{Items: []*models.AuditEntry{{
+		Action:     "document.updated",
+		ActorEmail: "@evil",
+		ResourceID: "+1",
+		UserAgent:  "=HYPERLINK(\"http://evil\")",
+		IP:         "-2",
+		Changes:    []models.AuditChange{},
+		OccurredAt: time.Now(),
+	}}}, nil)
+	service := services.NewAuditService(auditRepo, nil, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, service.ExportEntries(adminContext(), auditQuery(t), models.AuditExportCSV, &out))
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	row := rows[1]
+	assert.Equal(t, "document.updated", row[1])
+	assert.Equal(t, "'@evil", row[3])
+	assert.Equal(t, "'+1", row[5])
+	assert.Equal(t, "'-2", row[7])
+	assert.Equal(t, "'=HYPERLINK(\"http://evil\")", row[8])
+	assert.Equal(t, "[]", row[9])
+}
+
+func TestAuditService_ExportWritesEveryPage(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	q := auditQuery(t)
+	page := func(from int, next string) *pagination.Page[*models.AuditEntry] {
+		items := make([]*models.AuditEntry, pagination.MaxLimit)
+		for i := range items {
+			items[i] = &models.AuditEntry{ID: fmt.Sprint(from + i), Action: "task.updated", OccurredAt: time.Now()}
+		}
+		return &pagination.Page[*models.AuditEntry]{Items: items, NextCursor: next}
+	}
+	auditRepo.On("FindPage", mock.Anything, mock.Anything).Return(page(0, q.NextCursor(time.Now(), "a")), nil).Once()
+	auditRepo.On("FindPage", mock.Anything, mock.Anything).Return(page(pagination.MaxLimit, q.NextCursor(time.Now(), "b")), nil).Once()
+	auditRepo.On("FindPage", mock.Anything, mock.Anything).Return(page(2*pagination.MaxLimit, ""), nil).Once()
+	service := services.NewAuditService(auditRepo, nil, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, service.ExportEntries(adminContext(), q, models.AuditExportCSV, &out))
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 1+3*pagination.MaxLimit)
+	auditRepo.AssertNumberOfCalls(t, "FindPage", 3)
+}
+
+func TestAuditService_OnlyAdminsExport(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	service := services.NewAuditService(auditRepo, nil, nil)
+
+	var out bytes.Buffer
+	err := service.ExportEntries(memberContext(), auditQuery(t), models.AuditExportCSV, &out)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	assert.Zero(t, out.Len())
+	auditRepo.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+}
@@ -2,10 +2,11 @@
 
 import (
 	"context"
+	"projectnexus/internal/errors"
 	"projectnexus/internal/models"
-	"projectnexus/internal/repository"
 	"projectnexus/internal/services"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -29,6 +30,14 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*models.Us
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*models.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -42,9 +51,34 @@ func (m *MockUserRepository) Update(ctx context.Context, user *models.User) erro
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateNotificationPreferences(ctx context.Context, userID string, input models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockUserRepository) FindDigestRecipients(ctx context.Context, frequency models.DigestFrequency, sentBefore time.Time, afterID string, limit int) ([]*models.User, error) {
+	args := m.Called(ctx, frequency, sentBefore, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimDigest(ctx context.Context, userID string, sentBefore time.Time, at time.Time) (bool, error) {
+	args := m.Called(ctx, userID, sentBefore, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ReleaseDigest(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	return m.Called(ctx, userID, claimedAt, previous).Error(0)
+}
+
 func TestAuthService_Register(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	authService := services.NewAuthService(mockRepo, "test-secret")
+	authService := services.NewAuthService(mockRepo, "test-secret", nil)
 
 	ctx := context.Background()
 	input := models.RegisterInput{
@@ -56,7 +90,7 @@ func TestAuthService_Register(t *testing.T) {
 	t.Run("successful registration", func(t *testing.T) {
 		// Clear previous mock calls
 		mockRepo = new(MockUserRepository)
-		authService = services.NewAuthService(mockRepo, "test-secret")
+		authService = services.NewAuthService(mockRepo, "test-secret", nil)
 
 		mockRepo.On("GetByEmail", ctx, input.Email).Return(nil, nil)
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*models.User")).Return(nil)
@@ -74,7 +108,7 @@ func TestAuthService_Register(t *testing.T) {
 	t.Run("user already exists", func(t *testing.T) {
 		// Clear previous mock calls
 		mockRepo = new(MockUserRepository)
-		authService = services.NewAuthService(mockRepo, "test-secret")
+		authService = services.NewAuthService(mockRepo, "test-secret", nil)
 
 		existingUser := &models.User{Email: input.Email}
 		// This is what changed - we're returning nil for error since we found the user
@@ -83,7 +117,7 @@ func TestAuthService_Register(t *testing.T) {
 		response, err := authService.Register(ctx, input)
 
 		assert.Error(t, err)
-		assert.Equal(t, repository.ErrUserExists, err)
+		assert.Equal(t, errors.ErrUserExists, err)
 		assert.Nil(t, response)
 		mockRepo.AssertExpectations(t)
 	})
@@ -0,0 +1,72 @@
+// internal/services/digest_test.go
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/mail"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+	repository.NotificationRepository
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// failingMailer refuses every message
+type failingMailer struct{}
+
+func (failingMailer) Send(context.Context, mail.Message) error {
+	return fmt.Errorf("mail server unavailable")
+}
+
+func TestDigestService_FailedSendReleasesClaim(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	projectRepo := new(MockProjectRepository)
+	notificationRepo := new(MockNotificationRepository)
+	service := services.NewDigestService(userRepo, projectRepo, nil, notificationRepo, failingMailer{}, "secret", "http://app", 7)
+
+	now := time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC)
+	lastSent := now.AddDate(0, 0, -2)
+	user := &models.User{ID: testUserID, Email: "user@example.com", DigestSentAt: &lastSent}
+	userRepo.On("FindDigestRecipients", mock.Anything, models.DigestDaily, mock.Anything, "", mock.Anything).Return([]*models.User{user}, nil)
+	userRepo.On("FindDigestRecipients", mock.Anything, models.DigestWeekly, mock.Anything, "", mock.Anything).Return([]*models.User{}, nil)
+	userRepo.On("ClaimDigest", mock.Anything, testUserID, mock.Anything, now).Return(true, nil)
+	userRepo.On("ReleaseDigest", mock.Anything, testUserID, now, &lastSent).Return(nil)
+	projectRepo.On("GetByUser", mock.Anything, testUserID).Return([]*models.Project{}, nil)
+	notificationRepo.On("CountUnread", mock.Anything, testUserID).Return(int64(2), nil)
+
+	require.NoError(t, service.SendDigests(context.Background(), now))
+	// The next run owes the user the digest again
+	userRepo.AssertCalled(t, "ReleaseDigest", mock.Anything, testUserID, now, &lastSent)
+}
+
+func TestDigestService_LoadsRecipientsInBatches(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := services.NewDigestService(userRepo, nil, nil, nil, failingMailer{}, "secret", "http://app", 7)
+
+	batch := make([]*models.User, 100)
+	for i := range batch {
+		batch[i] = &models.User{ID: fmt.Sprintf("64b7f0c2a1b2c3d4e5f6%04d", i)}
+	}
+	userRepo.On("FindDigestRecipients", mock.Anything, models.DigestDaily, mock.Anything, "", 100).Return(batch, nil)
+	userRepo.On("FindDigestRecipients", mock.Anything, models.DigestDaily, mock.Anything, batch[99].ID, 100).Return([]*models.User{}, nil)
+	userRepo.On("FindDigestRecipients", mock.Anything, models.DigestWeekly, mock.Anything, "", 100).Return([]*models.User{}, nil)
+	// Another instance sent all of them already
+	userRepo.On("ClaimDigest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	require.NoError(t, service.SendDigests(context.Background(), time.Now()))
+	userRepo.AssertNumberOfCalls(t, "FindDigestRecipients", 3)
+	userRepo.AssertNumberOfCalls(t, "ClaimDigest", 100)
+}
@@ -4,11 +4,21 @@ import (
 	"context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
 	"projectnexus/internal/errors"
 	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
 	"projectnexus/internal/repository"
 	"projectnexus/internal/services"
+	"strings"
 	"testing"
+	"time"
+)
+
+// IDs are valid ObjectIDs, which the services check before any lookup
+const (
+	testDocID     = "64b7f0c2a1b2c3d4e5f60001"
+	testProjectID = "64b7f0c2a1b2c3d4e5f6a001"
 )
 
 // Mock repositories
@@ -42,6 +52,27 @@ func (m *MockDocumentRepository) GetByProject(ctx context.Context, projectID str
 	return args.Get(0).([]*models.Document), args.Error(1)
 }
 
+func (m *MockDocumentRepository) FindByProject(ctx context.Context, projectID string, filter models.DocumentFilter) ([]*models.Document, error) {
+	args := m.Called(ctx, projectID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) UpdateOrganization(ctx context.Context, id string, folderID string, tags []string) error {
+	args := m.Called(ctx, id, folderID, tags)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) GetProjectTags(ctx context.Context, projectID string) ([]string, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockDocumentRepository) Update(ctx context.Context, doc *models.Document) error {
 	args := m.Called(ctx, doc)
 	return args.Error(0)
@@ -52,6 +83,63 @@ func (m *MockDocumentRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockDocumentRepository) FindPage(ctx context.Context, projectIDs []string, filter models.DocumentFilter, q pagination.Query) (*pagination.Page[*models.Document], error) {
+	args := m.Called(ctx, projectIDs, filter, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Page[*models.Document]), args.Error(1)
+}
+
+func (m *MockDocumentRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error {
+	args := m.Called(ctx, projectID, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) GetDeletedByID(ctx context.Context, id string) (*models.Document, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) GetDeletedByProject(ctx context.Context, projectID string) ([]*models.Document, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Document, error) {
+	args := m.Called(ctx, cutoff)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
 func (m *MockDocumentRepository) GetVersions(ctx context.Context, documentID string) ([]*models.DocumentVersion, error) {
 	args := m.Called(ctx, documentID)
 	if args.Get(0) == nil {
@@ -60,6 +148,19 @@ func (m *MockDocumentRepository) GetVersions(ctx context.Context, documentID str
 	return args.Get(0).([]*models.DocumentVersion), args.Error(1)
 }
 
+func (m *MockDocumentRepository) BackfillHistory(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockTxManager runs the unit of work without a transaction, undoing it on
+// failure like the standalone fallback of the Mongo TxManager
+type MockTxManager struct{}
+
+func (MockTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return repository.RunCompensated(ctx, fn)
+}
+
 type MockProjectRepository struct {
 	mock.Mock
 }
@@ -85,8 +186,63 @@ func (m *MockProjectRepository) GetByUser(ctx context.Context, userID string) ([
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) Update(ctx context.Context, project *models.Project) error {
-	args := m.Called(ctx, project)
+func (m *MockProjectRepository) UpdateFields(ctx context.Context, project *models.Project, fields ...string) error {
+	args := m.Called(ctx, project, fields)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) SetOwners(ctx context.Context, id string, previous, owners []string) error {
+	args := m.Called(ctx, id, previous, owners)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) RequestTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error {
+	args := m.Called(ctx, id, transfer)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) ClearTransfer(ctx context.Context, id string, transfer models.OwnershipTransfer) error {
+	args := m.Called(ctx, id, transfer)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) SetProgress(ctx context.Context, id string, progress int, breakdown *models.ProgressBreakdown) error {
+	args := m.Called(ctx, id, progress, breakdown)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) AddToTeam(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateTeamList(ctx context.Context, id string, added, removed []string) error {
+	args := m.Called(ctx, id, added, removed)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) AssignTeam(ctx context.Context, id string, assignment models.ProjectTeamAssignment) error {
+	args := m.Called(ctx, id, assignment)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateTeamRole(ctx context.Context, id string, teamID string, role models.TeamRole) error {
+	args := m.Called(ctx, id, teamID, role)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UnassignTeam(ctx context.Context, id string, teamID string) error {
+	args := m.Called(ctx, id, teamID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Archive(ctx context.Context, id string, archivedBy string, at time.Time) error {
+	args := m.Called(ctx, id, archivedBy, at)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Unarchive(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -95,6 +251,48 @@ func (m *MockProjectRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockProjectRepository) FindPageByUser(ctx context.Context, userID string, q pagination.Query) (*pagination.Page[*models.Project], error) {
+	args := m.Called(ctx, userID, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Page[*models.Project]), args.Error(1)
+}
+
+func (m *MockProjectRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) GetDeletedByID(ctx context.Context, id string) (*models.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetDeletedByUser(ctx context.Context, userID string) ([]*models.Project, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]*models.Project, error) {
+	args := m.Called(ctx, cutoff)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Project), args.Error(1)
+}
+
 func (m *MockProjectRepository) List(ctx context.Context, filter interface{}) ([]*models.Project, error) {
 	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
@@ -103,48 +301,56 @@ func (m *MockProjectRepository) List(ctx context.Context, filter interface{}) ([
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
+func (m *MockProjectRepository) GetByAssignedTeam(ctx context.Context, teamID string) ([]*models.Project, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Project), args.Error(1)
+}
+
 func TestDocumentService_GetDocument(t *testing.T) {
 	ctx := context.Background()
 	mockDocRepo := new(MockDocumentRepository)
 	mockProjRepo := new(MockProjectRepository)
-	service := services.NewDocumentService(mockDocRepo, mockProjRepo)
+	service := services.NewDocumentService(mockDocRepo, mockProjRepo, nil, nil, nil, nil, nil, MockTxManager{})
 
 	testDoc := &models.Document{
-		ID:        "doc1",
-		ProjectID: "proj1",
+		ID:        testDocID,
+		ProjectID: testProjectID,
 		Title:     "Test Document",
 		CreatedBy: "user1",
 	}
 
 	testProject := &models.Project{
-		ID:        "proj1",
+		ID:        testProjectID,
 		CreatedBy: "user1",
 		Team:      []string{"user1", "user2"},
 	}
 
 	t.Run("successful get", func(t *testing.T) {
-		mockDocRepo.On("GetByID", ctx, "doc1").Return(testDoc, nil)
-		mockProjRepo.On("GetByID", ctx, "proj1").Return(testProject, nil)
+		mockDocRepo.On("GetByID", ctx, testDocID).Return(testDoc, nil)
+		mockProjRepo.On("GetByID", ctx, testProjectID).Return(testProject, nil)
 
-		doc, err := service.GetDocument(ctx, "doc1", "user1")
+		doc, err := service.GetDocument(ctx, testDocID, "user1")
 		assert.NoError(t, err)
 		assert.Equal(t, testDoc, doc)
 	})
 
 	t.Run("document not found", func(t *testing.T) {
-		mockDocRepo.On("GetByID", ctx, "nonexistent").Return(nil, repository.ErrNotFound)
+		mockDocRepo.On("GetByID", ctx, "64b7f0c2a1b2c3d4e5f6ffff").Return(nil, mongo.ErrNoDocuments)
 
-		doc, err := service.GetDocument(ctx, "nonexistent", "user1")
+		doc, err := service.GetDocument(ctx, "64b7f0c2a1b2c3d4e5f6ffff", "user1")
 		assert.Error(t, err)
 		assert.Equal(t, errors.ErrDocumentNotFound, err)
 		assert.Nil(t, doc)
 	})
 
 	t.Run("unauthorized access", func(t *testing.T) {
-		mockDocRepo.On("GetByID", ctx, "doc1").Return(testDoc, nil)
-		mockProjRepo.On("GetByID", ctx, "proj1").Return(testProject, nil)
+		mockDocRepo.On("GetByID", ctx, testDocID).Return(testDoc, nil)
+		mockProjRepo.On("GetByID", ctx, testProjectID).Return(testProject, nil)
 
-		doc, err := service.GetDocument(ctx, "doc1", "unauthorized")
+		doc, err := service.GetDocument(ctx, testDocID, "unauthorized")
 		assert.Error(t, err)
 		assert.Equal(t, errors.ErrUnauthorized, err)
 		assert.Nil(t, doc)
@@ -155,63 +361,113 @@ func TestDocumentService_ListDocuments(t *testing.T) {
 	ctx := context.Background()
 	mockDocRepo := new(MockDocumentRepository)
 	mockProjRepo := new(MockProjectRepository)
-	service := services.NewDocumentService(mockDocRepo, mockProjRepo)
+	service := services.NewDocumentService(mockDocRepo, mockProjRepo, nil, nil, nil, nil, nil, MockTxManager{})
 
 	testProjects := []*models.Project{
-		{ID: "proj1", CreatedBy: "user1"},
-		{ID: "proj2", CreatedBy: "user1"},
+		{ID: testProjectID, CreatedBy: "user1"},
+		{ID: "64b7f0c2a1b2c3d4e5f6a002", CreatedBy: "user1"},
 	}
 
 	testDocs1 := []*models.Document{
-		{ID: "doc1", ProjectID: "proj1", Title: "Doc 1"},
-		{ID: "doc2", ProjectID: "proj1", Title: "Doc 2"},
+		{ID: testDocID, ProjectID: testProjectID, Title: "Doc 1"},
+		{ID: "64b7f0c2a1b2c3d4e5f60002", ProjectID: testProjectID, Title: "Doc 2"},
 	}
 
 	testDocs2 := []*models.Document{
-		{ID: "doc3", ProjectID: "proj2", Title: "Doc 3"},
+		{ID: "64b7f0c2a1b2c3d4e5f60003", ProjectID: "64b7f0c2a1b2c3d4e5f6a002", Title: "Doc 3"},
 	}
 
+	query := pagination.Query{Limit: pagination.DefaultLimit}
+	testPage := &pagination.Page[*models.Document]{Items: append(testDocs1, testDocs2...), Total: 3}
 	mockProjRepo.On("GetByUser", ctx, "user1").Return(testProjects, nil)
-	mockDocRepo.On("GetByProject", ctx, "proj1").Return(testDocs1, nil)
-	mockDocRepo.On("GetByProject", ctx, "proj2").Return(testDocs2, nil)
+	mockDocRepo.On("FindPage", ctx, []string{testProjectID, "64b7f0c2a1b2c3d4e5f6a002"}, models.DocumentFilter{}, query).Return(testPage, nil)
 
-	docs, err := service.ListDocuments(ctx, "user1")
+	page, err := service.ListDocuments(ctx, "user1", query)
 	assert.NoError(t, err)
-	assert.Len(t, docs, 3)
+	assert.Len(t, page.Items, 3)
 }
 
 func TestDocumentService_GetProjectDocuments(t *testing.T) {
 	ctx := context.Background()
 	mockDocRepo := new(MockDocumentRepository)
 	mockProjRepo := new(MockProjectRepository)
-	service := services.NewDocumentService(mockDocRepo, mockProjRepo)
+	service := services.NewDocumentService(mockDocRepo, mockProjRepo, nil, nil, nil, nil, nil, MockTxManager{})
 
 	testProject := &models.Project{
-		ID:        "proj1",
+		ID:        testProjectID,
 		CreatedBy: "user1",
 		Team:      []string{"user1", "user2"},
 	}
 
+	query := pagination.Query{Limit: pagination.DefaultLimit}
 	testDocs := []*models.Document{
-		{ID: "doc1", ProjectID: "proj1", Title: "Doc 1"},
-		{ID: "doc2", ProjectID: "proj1", Title: "Doc 2"},
+		{ID: testDocID, ProjectID: testProjectID, Title: "Doc 1"},
+		{ID: "64b7f0c2a1b2c3d4e5f60002", ProjectID: testProjectID, Title: "Doc 2"},
 	}
 
 	t.Run("successful get", func(t *testing.T) {
-		mockProjRepo.On("GetByID", ctx, "proj1").Return(testProject, nil)
-		mockDocRepo.On("GetByProject", ctx, "proj1").Return(testDocs, nil)
+		mockProjRepo.On("GetByID", ctx, testProjectID).Return(testProject, nil)
+		testPage := &pagination.Page[*models.Document]{Items: testDocs, Total: 2}
+		mockDocRepo.On("FindPage", ctx, []string{testProjectID}, models.DocumentFilter{}, query).Return(testPage, nil)
 
-		docs, err := service.GetProjectDocuments(ctx, "proj1", "user1")
+		page, err := service.GetProjectDocuments(ctx, testProjectID, models.DocumentFilter{}, query, "user1")
 		assert.NoError(t, err)
-		assert.Equal(t, testDocs, docs)
+		assert.Equal(t, testDocs, page.Items)
 	})
 
 	t.Run("unauthorized access", func(t *testing.T) {
-		mockProjRepo.On("GetByID", ctx, "proj1").Return(testProject, nil)
+		mockProjRepo.On("GetByID", ctx, testProjectID).Return(testProject, nil)
 
-		docs, err := service.GetProjectDocuments(ctx, "proj1", "unauthorized")
+		page, err := service.GetProjectDocuments(ctx, testProjectID, models.DocumentFilter{}, query, "unauthorized")
 		assert.Error(t, err)
 		assert.Equal(t, errors.ErrUnauthorized, err)
-		assert.Nil(t, docs)
+		assert.Nil(t, page)
 	})
 }
+
+func TestDocumentService_CreateDocumentRejectsLongTags(t *testing.T) {
+	mockDocRepo := new(MockDocumentRepository)
+	mockProjRepo := new(MockProjectRepository)
+	service := services.NewDocumentService(mockDocRepo, mockProjRepo, nil, nil, nil, nil, nil, MockTxManager{})
+
+	mockProjRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "user123"}, nil)
+
+	_, err := service.CreateDocument(context.Background(), models.CreateDocumentInput{
+		ProjectID: testProjectID,
+		Title:     "Design",
+		Type:      models.DocumentTypeHLD,
+		Content:   "Content",
+		Status:    models.DocumentStatusDraft,
+		Tags:      []string{strings.Repeat("x", models.MaxTagLength+1)},
+	}, "user123")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	mockDocRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDocumentService_UpdateDocumentKeepsAuthor(t *testing.T) {
+	ctx := context.Background()
+	mockDocRepo := new(MockDocumentRepository)
+	mockProjRepo := new(MockProjectRepository)
+	service := services.NewDocumentService(mockDocRepo, mockProjRepo, nil, nil, nil, nil, nil, MockTxManager{})
+
+	testDoc := &models.Document{
+		ID:        testDocID,
+		ProjectID: testProjectID,
+		Title:     "Test Document",
+		CreatedBy: "user1",
+		UpdatedBy: "user1",
+	}
+	mockDocRepo.On("GetByID", ctx, testDocID).Return(testDoc, nil)
+	mockProjRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{
+		ID:        testProjectID,
+		CreatedBy: "user1",
+		Team:      []string{"user1", "user2"},
+	}, nil)
+	mockDocRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	title := "Renamed"
+	doc, err := service.UpdateDocument(ctx, testDocID, models.UpdateDocumentInput{Title: &title}, "user2")
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", doc.CreatedBy)
+	assert.Equal(t, "user2", doc.UpdatedBy)
+}
@@ -0,0 +1,60 @@
+// internal/services/folder_test.go
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+)
+
+type MockFolderRepository struct {
+	mock.Mock
+	repository.FolderRepository
+}
+
+func (m *MockFolderRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Folder, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*models.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	return m.Called(ctx, *folder).Error(0)
+}
+
+func (m *MockFolderRepository) SetPositions(ctx context.Context, folderIDs []string) error {
+	return m.Called(ctx, folderIDs).Error(0)
+}
+
+func TestFolderService_MoveFolderUndoesFailedMove(t *testing.T) {
+	folderRepo := new(MockFolderRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewFolderService(folderRepo, nil, projectRepo, events.NewBus(), MockTxManager{})
+
+	// a and b share the top level, c is a folder of its own to move a into
+	a := &models.Folder{ID: "a", ProjectID: testProjectID, Position: 0}
+	b := &models.Folder{ID: "b", ProjectID: testProjectID, Position: 1}
+	c := &models.Folder{ID: "c", ProjectID: testProjectID, ParentID: "b", Position: 0}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner"}, nil)
+	folderRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Folder{a, b, c}, nil)
+
+	moved := *a
+	moved.ParentID = "b"
+	moved.Position = 1
+	folderRepo.On("Update", mock.Anything, moved).Return(nil).Once()
+	folderRepo.On("SetPositions", mock.Anything, []string{"c", "a"}).Return(nil).Once()
+	folderRepo.On("SetPositions", mock.Anything, []string{"b"}).Return(errors.New("connection reset")).Once()
+
+	// The folder and the new parent's order are put back
+	folderRepo.On("SetPositions", mock.Anything, []string{"c"}).Return(nil).Once()
+	folderRepo.On("Update", mock.Anything, *a).Return(nil).Once()
+
+	_, err := service.MoveFolder(context.Background(), testProjectID, "a", models.MoveFolderInput{ParentID: "b"}, "owner")
+	assert.Error(t, err)
+	folderRepo.AssertExpectations(t)
+}
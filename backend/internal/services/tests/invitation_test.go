@@ -0,0 +1,121 @@
+// internal/services/invitation_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"projectnexus/internal/signedtoken"
+	"testing"
+	"time"
+)
+
+const (
+	testInvitationID     = "64b7f0c2a1b2c3d4e5f6c001"
+	testInvitationSecret = "invitation-secret"
+)
+
+type MockInvitationRepository struct {
+	mock.Mock
+	repository.InvitationRepository
+}
+
+func (m *MockInvitationRepository) GetByID(ctx context.Context, id string) (*models.Invitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) Respond(ctx context.Context, id string, status models.InvitationStatus, userID string) error {
+	return m.Called(ctx, id, status, userID).Error(0)
+}
+
+type MockTeamService struct {
+	mock.Mock
+	services.TeamService
+}
+
+func (m *MockTeamService) JoinProject(ctx context.Context, projectID, userID string, role models.TeamRole) (*models.TeamMember, error) {
+	args := m.Called(ctx, projectID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamMember), args.Error(1)
+}
+
+func TestInvitationService_UserRegisteredNeedsToken(t *testing.T) {
+	invitationRepo := new(MockInvitationRepository)
+	teamService := new(MockTeamService)
+	service := services.NewInvitationService(invitationRepo, nil, nil, nil, teamService, nil, events.NewBus(), MockTxManager{}, testInvitationSecret, time.Hour, "")
+	invitationRepo.On("GetByID", mock.Anything, testInvitationID).Return(&models.Invitation{
+		ID:        testInvitationID,
+		Email:     "invited@example.com",
+		Role:      models.TeamRoleMember,
+		ProjectID: testProjectID,
+		Status:    models.InvitationStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	user := &models.User{ID: "new-user", Email: "invited@example.com"}
+
+	// Signing up with the invited address is not proof of owning it
+	err := service.UserRegistered(context.Background(), user, models.RegisterInput{Email: user.Email})
+	require.NoError(t, err)
+
+	invitationRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	teamService.AssertNotCalled(t, "JoinProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_UserRegisteredAcceptsToken(t *testing.T) {
+	invitationRepo := new(MockInvitationRepository)
+	teamService := new(MockTeamService)
+	service := services.NewInvitationService(invitationRepo, nil, nil, nil, teamService, nil, events.NewBus(), MockTxManager{}, testInvitationSecret, time.Hour, "")
+	expiresAt := time.Now().Add(time.Hour)
+	invitationRepo.On("GetByID", mock.Anything, testInvitationID).Return(&models.Invitation{
+		ID:        testInvitationID,
+		Email:     "invited@example.com",
+		Role:      models.TeamRoleMember,
+		ProjectID: testProjectID,
+		Status:    models.InvitationStatusPending,
+		ExpiresAt: expiresAt,
+	}, nil)
+	token := signedtoken.Sign([]byte(testInvitationSecret), "invitation", testInvitationID, expiresAt)
+	user := &models.User{ID: "new-user", Email: "Invited@Example.com"}
+	teamService.On("JoinProject", mock.Anything, testProjectID, "new-user", models.TeamRoleMember).Return(&models.TeamMember{}, nil).Once()
+	invitationRepo.On("Respond", mock.Anything, testInvitationID, models.InvitationStatusAccepted, "new-user").Return(nil).Once()
+
+	err := service.UserRegistered(context.Background(), user, models.RegisterInput{Email: user.Email, InvitationToken: token})
+	require.NoError(t, err)
+
+	teamService.AssertExpectations(t)
+	invitationRepo.AssertExpectations(t)
+}
+
+func TestInvitationService_UserRegisteredRejectsOtherEmail(t *testing.T) {
+	invitationRepo := new(MockInvitationRepository)
+	teamService := new(MockTeamService)
+	service := services.NewInvitationService(invitationRepo, nil, nil, nil, teamService, nil, events.NewBus(), MockTxManager{}, testInvitationSecret, time.Hour, "")
+	expiresAt := time.Now().Add(time.Hour)
+	invitationRepo.On("GetByID", mock.Anything, testInvitationID).Return(&models.Invitation{
+		ID:        testInvitationID,
+		Email:     "invited@example.com",
+		Role:      models.TeamRoleMember,
+		ProjectID: testProjectID,
+		Status:    models.InvitationStatusPending,
+		ExpiresAt: expiresAt,
+	}, nil)
+	token := signedtoken.Sign([]byte(testInvitationSecret), "invitation", testInvitationID, expiresAt)
+	user := &models.User{ID: "new-user", Email: "someone-else@example.com"}
+
+	err := service.UserRegistered(context.Background(), user, models.RegisterInput{Email: user.Email, InvitationToken: token})
+	assert.ErrorIs(t, err, errors.ErrInvitationEmailMismatch)
+
+	teamService.AssertNotCalled(t, "JoinProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
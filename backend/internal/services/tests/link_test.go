@@ -0,0 +1,120 @@
+// internal/services/link_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+)
+
+const (
+	otherDocID     = "64b7f0c2a1b2c3d4e5f60002"
+	otherProjectID = "64b7f0c2a1b2c3d4e5f6a002"
+)
+
+type MockLinkRepository struct {
+	mock.Mock
+	repository.LinkRepository
+}
+
+func (m *MockLinkRepository) GetByTarget(ctx context.Context, targetType models.LinkItemType, targetID string) ([]*models.Link, error) {
+	args := m.Called(ctx, targetType, targetID)
+	return args.Get(0).([]*models.Link), args.Error(1)
+}
+
+func (m *MockLinkRepository) ReplaceExtracted(ctx context.Context, sourceType models.LinkItemType, sourceID string, links []*models.Link) error {
+	return m.Called(ctx, sourceType, sourceID, links).Error(0)
+}
+
+func (m *MockLinkRepository) MarkTargetBroken(ctx context.Context, targetType models.LinkItemType, targetIDs ...string) error {
+	return m.Called(ctx, targetType, targetIDs).Error(0)
+}
+
+func (m *MockLinkRepository) DeleteByProject(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func TestLinkService_GetBacklinksHidesInaccessibleSources(t *testing.T) {
+	linkRepo := new(MockLinkRepository)
+	documentRepo := new(MockDocumentRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewLinkService(linkRepo, documentRepo, new(MockMockupRepository), projectRepo, events.NewBus())
+
+	// The reader belongs to one project and not the other
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"reader"}}, nil)
+	projectRepo.On("GetByID", mock.Anything, otherProjectID).Return(&models.Project{ID: otherProjectID, CreatedBy: "stranger"}, nil)
+	documentRepo.On("GetByID", mock.Anything, testDocID).Return(&models.Document{ID: testDocID, ProjectID: testProjectID}, nil)
+	own := &models.Link{ID: "own", ProjectID: testProjectID, TargetType: models.LinkItemDocument, TargetID: testDocID}
+	foreign := &models.Link{ID: "foreign", ProjectID: otherProjectID, TargetType: models.LinkItemDocument, TargetID: testDocID}
+	linkRepo.On("GetByTarget", mock.Anything, models.LinkItemDocument, testDocID).Return([]*models.Link{own, foreign}, nil)
+
+	links, err := service.GetBacklinks(context.Background(), models.LinkItemDocument, testDocID, "reader")
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Link{own}, links)
+
+	links, err = service.GetBacklinks(context.Background(), models.LinkItemDocument, testDocID, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Link{own}, links)
+}
+
+func TestLinkService_SyncDocumentLinksResolvesAsAuthor(t *testing.T) {
+	linkRepo := new(MockLinkRepository)
+	documentRepo := new(MockDocumentRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewLinkService(linkRepo, documentRepo, new(MockMockupRepository), projectRepo, events.NewBus())
+
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"reader"}}, nil)
+	projectRepo.On("GetByID", mock.Anything, otherProjectID).Return(&models.Project{ID: otherProjectID, CreatedBy: "stranger"}, nil)
+	documentRepo.On("GetByID", mock.Anything, testDocID).Return(&models.Document{ID: testDocID, ProjectID: testProjectID}, nil)
+	documentRepo.On("GetByID", mock.Anything, otherDocID).Return(&models.Document{ID: otherDocID, ProjectID: otherProjectID}, nil)
+
+	doc := &models.Document{
+		ID:        "64b7f0c2a1b2c3d4e5f60003",
+		ProjectID: testProjectID,
+		Content:   "See [own](/documents/" + testDocID + ") and [foreign](/documents/" + otherDocID + ")",
+	}
+	var stored []*models.Link
+	linkRepo.On("ReplaceExtracted", mock.Anything, models.LinkItemDocument, doc.ID, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(3).([]*models.Link) }).
+		Return(nil)
+
+	require.NoError(t, service.SyncDocumentLinks(context.Background(), doc, "reader"))
+	require.Len(t, stored, 2)
+	assert.False(t, stored[0].Broken)
+	assert.Equal(t, "reader", stored[0].CreatedBy)
+	// The reader cannot see the other project, so the reference stays broken
+	assert.True(t, stored[1].Broken)
+}
+
+func TestLinkService_MarkProjectDeletedBreaksItemLinks(t *testing.T) {
+	linkRepo := new(MockLinkRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	service := services.NewLinkService(linkRepo, documentRepo, mockupRepo, new(MockProjectRepository), events.NewBus())
+
+	documentRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Document{{ID: testDocID}}, nil)
+	mockupRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Mockup{}, nil)
+	linkRepo.On("MarkTargetBroken", mock.Anything, models.LinkItemProject, []string{testProjectID}).Return(nil).Once()
+	linkRepo.On("MarkTargetBroken", mock.Anything, models.LinkItemDocument, []string{testDocID}).Return(nil).Once()
+	linkRepo.On("MarkTargetBroken", mock.Anything, models.LinkItemMockup, []string{}).Return(nil).Once()
+
+	require.NoError(t, service.MarkProjectDeleted(context.Background(), testProjectID))
+	linkRepo.AssertExpectations(t)
+}
+
+func TestLinkService_PurgeProjectDropsItsLinks(t *testing.T) {
+	linkRepo := new(MockLinkRepository)
+	service := services.NewLinkService(linkRepo, new(MockDocumentRepository), new(MockMockupRepository), new(MockProjectRepository), events.NewBus())
+
+	linkRepo.On("MarkTargetBroken", mock.Anything, models.LinkItemProject, []string{testProjectID}).Return(nil).Once()
+	linkRepo.On("DeleteByProject", mock.Anything, testProjectID).Return(nil).Once()
+
+	require.NoError(t, service.PurgeItem(context.Background(), models.LinkItemProject, testProjectID))
+	linkRepo.AssertExpectations(t)
+}
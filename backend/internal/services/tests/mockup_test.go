@@ -0,0 +1,62 @@
+// internal/services/mockup_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"testing"
+)
+
+const testMockupID = "64b7f0c2a1b2c3d4e5f6d001"
+
+func (m *MockMockupRepository) GetByID(ctx context.Context, id string) (*models.Mockup, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Mockup), args.Error(1)
+}
+
+func (m *MockMockupRepository) Create(ctx context.Context, mockup *models.Mockup) error {
+	return m.Called(ctx, mockup).Error(0)
+}
+
+func TestMockupService_ReadsNeedProjectAccess(t *testing.T) {
+	mockupRepo := new(MockMockupRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewMockupService(mockupRepo, projectRepo, nil, nil, events.NewBus())
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"member"}}, nil)
+	mockupRepo.On("GetByID", mock.Anything, testMockupID).Return(&models.Mockup{ID: testMockupID, ProjectID: testProjectID}, nil)
+	mockupRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Mockup{{ID: testMockupID}}, nil)
+
+	mockup, err := service.GetMockupByID(context.Background(), testMockupID, "member")
+	require.NoError(t, err)
+	assert.Equal(t, testMockupID, mockup.ID)
+
+	_, err = service.GetMockupByID(context.Background(), testMockupID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	mockups, err := service.GetProjectMockups(context.Background(), testProjectID, "owner")
+	require.NoError(t, err)
+	assert.Len(t, mockups, 1)
+
+	_, err = service.GetProjectMockups(context.Background(), testProjectID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+}
+
+func TestMockupService_CreateMockupNeedsProjectAccess(t *testing.T) {
+	mockupRepo := new(MockMockupRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewMockupService(mockupRepo, projectRepo, nil, nil, events.NewBus())
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"member"}}, nil)
+
+	err := service.CreateMockup(context.Background(), &models.Mockup{ProjectID: testProjectID}, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	mockupRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
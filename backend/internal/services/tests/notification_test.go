@@ -0,0 +1,45 @@
+// internal/services/notification_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"projectnexus/internal/signedtoken"
+	"testing"
+	"time"
+)
+
+const testUserID = "64b7f0c2a1b2c3d4e5f6c001"
+
+func TestNotificationService_UpdatePreferencesChangesOnlyListedChoices(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := services.NewNotificationService(nil, userRepo, nil, nil, nil, "secret", "http://app")
+
+	userRepo.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+	input := models.UpdateNotificationPreferencesInput{Types: map[models.NotificationType]bool{models.NotificationMention: false}}
+	userRepo.On("UpdateNotificationPreferences", mock.Anything, testUserID, input).Return(&models.NotificationPreferences{Types: input.Types}, nil)
+
+	preferences, err := service.UpdatePreferences(context.Background(), testUserID, input)
+	require.NoError(t, err)
+	assert.False(t, preferences.Wants(models.NotificationMention))
+	assert.True(t, preferences.Wants(models.NotificationProjectAdded))
+	// The rest of the user is never written back
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_UnsubscribeTurnsOffEmail(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := services.NewNotificationService(nil, userRepo, nil, nil, nil, "secret", "http://app")
+
+	userRepo.On("GetByID", mock.Anything, testUserID).Return(&models.User{ID: testUserID}, nil)
+	userRepo.On("UpdateNotificationPreferences", mock.Anything, testUserID, models.EmailOptOut()).Return(&models.NotificationPreferences{}, nil)
+
+	token := signedtoken.Sign([]byte("secret"), "unsubscribe", testUserID, time.Now().Add(time.Hour))
+	require.NoError(t, service.Unsubscribe(context.Background(), token))
+	userRepo.AssertExpectations(t)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,90 @@
+// internal/services/organization_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+	repository.OrganizationRepository
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	args := m.Called(ctx, org)
+	org.ID = "personal"
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetDefault(ctx context.Context) (*models.Organization, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetByMember(ctx context.Context, userID string) ([]*models.Organization, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, orgID string, member models.OrgMembership) error {
+	return m.Called(ctx, orgID, member.UserID, member.Role).Error(0)
+}
+
+func TestOrganizationService_LegacyUsersJoinDefaultAsMembers(t *testing.T) {
+	orgRepo := new(MockOrganizationRepository)
+	service := services.NewOrganizationService(orgRepo, nil, events.NewBus(), "ops@example.com")
+	// The default organization was created a day ago, before organizations
+	// existed for everyone else
+	orgRepo.On("GetByMember", mock.Anything, mock.Anything).Return([]*models.Organization{}, nil)
+	orgRepo.On("GetDefault", mock.Anything).Return(&models.Organization{
+		ID:        "default",
+		Default:   true,
+		CreatedAt: time.Now().Add(-24 * time.Hour),
+	}, nil)
+	orgRepo.On("AddMember", mock.Anything, "default", mock.Anything, mock.Anything).Return(nil)
+
+	// Signing in first no longer makes anyone an admin
+	legacy := &models.User{ID: "legacy", Email: "first@example.com", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	org, role, err := service.Resolve(context.Background(), legacy, "")
+	require.NoError(t, err)
+	assert.Equal(t, "default", org.ID)
+	assert.Equal(t, models.OrgRoleMember, role)
+
+	operator := &models.User{ID: "operator", Email: "Ops@Example.com", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	_, role, err = service.Resolve(context.Background(), operator, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleAdmin, role)
+}
+
+func TestOrganizationService_NewUsersNeverJoinDefault(t *testing.T) {
+	orgRepo := new(MockOrganizationRepository)
+	service := services.NewOrganizationService(orgRepo, nil, events.NewBus(), "")
+	orgRepo.On("GetByMember", mock.Anything, mock.Anything).Return([]*models.Organization{}, nil)
+	orgRepo.On("GetDefault", mock.Anything).Return(&models.Organization{
+		ID:        "default",
+		Default:   true,
+		CreatedAt: time.Now().Add(-24 * time.Hour),
+	}, nil)
+	orgRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	// A user whose workspace failed to be created at registration gets one
+	// now instead of landing among the legacy data
+	user := &models.User{ID: "new-user", Email: "new@example.com", CreatedAt: time.Now()}
+	org, role, err := service.Resolve(context.Background(), user, "")
+	require.NoError(t, err)
+	assert.Equal(t, "personal", org.ID)
+	assert.Equal(t, models.OrgRoleAdmin, role)
+	orgRepo.AssertNotCalled(t, "AddMember", mock.Anything, "default", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,99 @@
+// internal/services/presentation_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+type MockMilestoneRepository struct {
+	mock.Mock
+	repository.MilestoneRepository
+}
+
+func (m *MockMilestoneRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Milestone, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*models.Milestone), args.Error(1)
+}
+
+func (m *MockTeamMemberRepository) GetProjectMembers(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*models.TeamMember), args.Error(1)
+}
+
+func TestPresentationService_LooksUpUsersOnce(t *testing.T) {
+	ctx := context.Background()
+	projectRepo := new(MockProjectRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	userRepo := new(MockUserRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	milestoneRepo := new(MockMilestoneRepository)
+	service := services.NewPresentationService(projectRepo, teamMemberRepo, userRepo, documentRepo, mockupRepo, milestoneRepo)
+
+	approvedAt := time.Now().Add(-time.Hour)
+	projectRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{
+		ID:        testProjectID,
+		CreatedBy: "owner",
+		Team:      []string{"owner", "legacy"},
+	}, nil)
+	teamMemberRepo.On("GetProjectMembers", ctx, testProjectID).Return([]*models.TeamMember{
+		{UserID: "viewer", Role: models.TeamRoleViewer},
+	}, nil)
+	// Only approved documents are asked for, with the start of their content
+	documentRepo.On("FindByProject", ctx, testProjectID, models.DocumentFilter{
+		Status:        models.DocumentStatusApproved,
+		ContentLength: 4 * models.DocumentPreviewLength,
+	}).Return([]*models.Document{
+		{ID: "spec", Title: "Spec", Status: models.DocumentStatusApproved, Content: "# Spec\nThe plan", CreatedBy: "author", UpdatedBy: "editor", ApprovedAt: &approvedAt},
+	}, nil)
+	mockupRepo.On("GetByProject", ctx, testProjectID).Return([]*models.Mockup{}, nil)
+	milestoneRepo.On("GetByProject", ctx, testProjectID).Return([]*models.Milestone{
+		{ID: "m1", Title: "Beta", DueDate: time.Now().Add(24 * time.Hour), OwnerID: "planner"},
+	}, nil)
+	var lookedUp []string
+	userRepo.On("GetByIDs", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		lookedUp = args.Get(1).([]string)
+	}).Return([]*models.User{
+		{ID: "owner", Name: "Olive Owner", Email: "olive@example.com"},
+		{ID: "author", Name: "Ada Author", Email: "ada@example.com"},
+		{ID: "viewer", Email: "vic@example.com"},
+	}, nil)
+
+	presentation, err := service.GetPresentation(ctx, testProjectID, "owner")
+	require.NoError(t, err)
+	userRepo.AssertNumberOfCalls(t, "GetByIDs", 1)
+	assert.ElementsMatch(t, []string{"owner", "legacy", "viewer", "author", "planner"}, lookedUp)
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+
+	require.Len(t, presentation.Documents, 1)
+	doc := presentation.Documents[0]
+	assert.Equal(t, "author", doc.AuthorID)
+	assert.Equal(t, "Ada Author", doc.AuthorName)
+	assert.Equal(t, "Spec The plan", doc.Preview)
+
+	require.NotEmpty(t, presentation.Team)
+	assert.Equal(t, "owner", presentation.Team[0].UserID)
+	assert.Equal(t, "OO", presentation.Team[0].Initials)
+}
+
+func TestPresentationService_IsForMembers(t *testing.T) {
+	ctx := context.Background()
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	service := services.NewPresentationService(projectRepo, nil, nil, documentRepo, nil, nil)
+
+	projectRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner"}, nil)
+
+	_, err := service.GetPresentation(ctx, testProjectID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	documentRepo.AssertNotCalled(t, "FindByProject", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,36 @@
+// internal/services/progress_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"testing"
+)
+
+func TestProgressService_RecomputeWritesProgressAlone(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	service := services.NewProgressService(projectRepo, documentRepo, mockupRepo, events.NewBus())
+
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{
+		ID:               testProjectID,
+		Name:             "Launch",
+		ProgressSettings: &models.ProgressSettings{Mode: models.ProgressModeAuto},
+	}, nil)
+	documentRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Document{
+		{ID: testDocID, ProjectID: testProjectID, Status: models.DocumentStatusApproved},
+	}, nil)
+	mockupRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Mockup{}, nil)
+	projectRepo.On("SetProgress", mock.Anything, testProjectID, 100, mock.AnythingOfType("*models.ProgressBreakdown")).Return(nil).Once()
+
+	assert.NoError(t, service.Recompute(context.Background(), testProjectID))
+
+	// A whole-project write would undo edits made while progress was computed
+	projectRepo.AssertExpectations(t)
+	projectRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything, mock.Anything)
+}
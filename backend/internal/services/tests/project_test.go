@@ -0,0 +1,200 @@
+// internal/services/project_test.go
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+// The mocks below embed their interface and implement only the methods the
+// tests use; calling any other method panics
+
+type MockMockupRepository struct {
+	mock.Mock
+	repository.MockupRepository
+}
+
+func (m *MockMockupRepository) SoftDeleteByProject(ctx context.Context, projectID string, deletedBy string) error {
+	return m.Called(ctx, projectID, deletedBy).Error(0)
+}
+
+func (m *MockMockupRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func (m *MockMockupRepository) GetByProject(ctx context.Context, projectID string) ([]*models.Mockup, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Mockup), args.Error(1)
+}
+
+type MockTeamMemberRepository struct {
+	mock.Mock
+	repository.TeamMemberRepository
+}
+
+func (m *MockTeamMemberRepository) SoftDeleteByProject(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func (m *MockTeamMemberRepository) RestoreByProject(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+type MockLinkService struct {
+	mock.Mock
+	services.LinkService
+}
+
+func (m *MockLinkService) MarkProjectDeleted(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func (m *MockLinkService) MarkProjectRestored(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func TestProjectService_DeleteProjectUndoesFailedCascade(t *testing.T) {
+	ctx := context.Background()
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	linkService := new(MockLinkService)
+	service := services.NewProjectService(projectRepo, nil, documentRepo, mockupRepo, teamMemberRepo, nil, linkService, nil, events.NewBus(), MockTxManager{})
+
+	project := &models.Project{ID: testProjectID, CreatedBy: "owner"}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(project, nil)
+	projectRepo.On("SoftDelete", mock.Anything, testProjectID, "owner").Return(nil)
+	documentRepo.On("SoftDeleteByProject", mock.Anything, testProjectID, "owner").Return(nil)
+	mockupRepo.On("SoftDeleteByProject", mock.Anything, testProjectID, "owner").Return(nil)
+	teamMemberRepo.On("SoftDeleteByProject", mock.Anything, testProjectID).Return(errors.New("connection reset"))
+	linkService.On("MarkProjectDeleted", mock.Anything, testProjectID).Return(nil)
+
+	// The steps that went through are undone
+	projectRepo.On("Restore", mock.Anything, testProjectID).Return(nil).Once()
+	documentRepo.On("RestoreByProject", mock.Anything, testProjectID).Return(nil).Once()
+	mockupRepo.On("RestoreByProject", mock.Anything, testProjectID).Return(nil).Once()
+	linkService.On("MarkProjectRestored", mock.Anything, testProjectID).Return(nil).Once()
+
+	err := service.DeleteProject(ctx, testProjectID, "owner")
+	assert.Error(t, err)
+
+	projectRepo.AssertExpectations(t)
+	documentRepo.AssertExpectations(t)
+	mockupRepo.AssertExpectations(t)
+	teamMemberRepo.AssertNotCalled(t, "RestoreByProject", mock.Anything, mock.Anything)
+	linkService.AssertExpectations(t)
+}
+
+func TestProjectService_DeleteProject(t *testing.T) {
+	ctx := context.Background()
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	linkService := new(MockLinkService)
+	service := services.NewProjectService(projectRepo, nil, documentRepo, mockupRepo, teamMemberRepo, nil, linkService, nil, events.NewBus(), MockTxManager{})
+
+	project := &models.Project{ID: testProjectID, CreatedBy: "owner"}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(project, nil)
+	projectRepo.On("SoftDelete", mock.Anything, testProjectID, "owner").Return(nil)
+	documentRepo.On("SoftDeleteByProject", mock.Anything, testProjectID, "owner").Return(nil)
+	mockupRepo.On("SoftDeleteByProject", mock.Anything, testProjectID, "owner").Return(nil)
+	teamMemberRepo.On("SoftDeleteByProject", mock.Anything, testProjectID).Return(nil)
+	linkService.On("MarkProjectDeleted", mock.Anything, testProjectID).Return(nil)
+
+	assert.NoError(t, service.DeleteProject(ctx, testProjectID, "owner"))
+	projectRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	linkService.AssertNotCalled(t, "MarkProjectRestored", mock.Anything, mock.Anything)
+
+	t.Run("only owners delete", func(t *testing.T) {
+		err := service.DeleteProject(ctx, testProjectID, "someone")
+		assert.Error(t, err)
+	})
+}
+
+func TestProjectService_UpdateProjectClearsDates(t *testing.T) {
+	ctx := context.Background()
+	projectRepo := new(MockProjectRepository)
+	service := services.NewProjectService(projectRepo, nil, nil, nil, nil, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	start := time.Now()
+	end := start.Add(24 * time.Hour)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{
+		ID: testProjectID, Name: "Launch", Status: models.ProjectStatusPlanning, CreatedBy: "owner", StartDate: &start, EndDate: &end,
+	}, nil)
+	// Only the fields in the input are written
+	projectRepo.On("UpdateFields", mock.Anything, mock.Anything, []string{"end_date"}).Return(nil)
+
+	// A missing date stays as it is; a cleared one goes away
+	project, err := service.UpdateProject(ctx, testProjectID, models.UpdateProjectInput{ClearEndDate: true}, "owner")
+	assert.NoError(t, err)
+	assert.Equal(t, &start, project.StartDate)
+	assert.Nil(t, project.EndDate)
+
+	_, err = service.UpdateProject(ctx, testProjectID, models.UpdateProjectInput{EndDate: &end, ClearEndDate: true}, "owner")
+	assert.ErrorIs(t, err, errs.ErrInvalidInput)
+}
+
+func TestProjectService_UpdateProjectArchivedMeanwhile(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	service := services.NewProjectService(projectRepo, nil, nil, nil, nil, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{
+		ID: testProjectID, Name: "Launch", Status: models.ProjectStatusPlanning, CreatedBy: "owner",
+	}, nil)
+	// The project was archived after it was read
+	projectRepo.On("UpdateFields", mock.Anything, mock.Anything, []string{"name"}).Return(errs.ErrProjectArchived)
+
+	name := "Relaunch"
+	_, err := service.UpdateProject(context.Background(), testProjectID, models.UpdateProjectInput{Name: &name}, "owner")
+	assert.ErrorIs(t, err, errs.ErrProjectArchived)
+	projectRepo.AssertNumberOfCalls(t, "UpdateFields", 1)
+}
+
+func TestProjectService_AcceptOwnershipTransfer(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	service := services.NewProjectService(projectRepo, nil, nil, nil, teamMemberRepo, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	transfer := models.OwnershipTransfer{From: "owner", To: "heir", RequestedAt: time.Now()}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{
+		ID: testProjectID, CreatedBy: "owner", Team: []string{"owner"}, PendingTransfer: &transfer,
+	}, nil)
+	teamMemberRepo.On("GetAllByProject", mock.Anything, testProjectID).Return([]*models.TeamMember{}, nil)
+	teamMemberRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	// The transfer, the team and the owners are each written alone
+	projectRepo.On("ClearTransfer", mock.Anything, testProjectID, transfer).Return(nil)
+	projectRepo.On("AddToTeam", mock.Anything, testProjectID, "heir").Return(nil)
+	projectRepo.On("SetOwners", mock.Anything, testProjectID, []string(nil), []string{"heir"}).Return(nil)
+
+	project, err := service.AcceptOwnershipTransfer(context.Background(), testProjectID, "heir")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"heir"}, project.OwnerIDs())
+	assert.Nil(t, project.PendingTransfer)
+	projectRepo.AssertExpectations(t)
+
+	// A transfer accepted, declined or cancelled meanwhile is not accepted again
+	staleRepo := new(MockProjectRepository)
+	service = services.NewProjectService(staleRepo, nil, nil, nil, teamMemberRepo, nil, nil, nil, events.NewBus(), MockTxManager{})
+	staleRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{
+		ID: testProjectID, CreatedBy: "owner", Team: []string{"owner"}, PendingTransfer: &transfer,
+	}, nil)
+	staleRepo.On("ClearTransfer", mock.Anything, testProjectID, transfer).Return(errs.ErrNoTransfer)
+
+	_, err = service.AcceptOwnershipTransfer(context.Background(), testProjectID, "heir")
+	assert.ErrorIs(t, err, errs.ErrNoTransfer)
+	staleRepo.AssertNotCalled(t, "SetOwners", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+// internal/services/realtime_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/realtime"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"testing"
+	"time"
+)
+
+// receive returns the next message of a stream, or fails after a second
+func receive(t *testing.T, messages <-chan realtime.Message) realtime.Message {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message on the stream")
+		return realtime.Message{}
+	}
+}
+
+// assertQuiet fails if a stream has a message waiting
+func assertQuiet(t *testing.T, messages <-chan realtime.Message) {
+	t.Helper()
+	select {
+	case msg, open := <-messages:
+		if open {
+			t.Fatalf("unexpected %s message on the stream", msg.Event)
+		}
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRealtimeService_ProjectChangesRecheckAccessOnce(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	service := services.NewRealtimeService(realtime.NewFeed(), nil, projectRepo, nil, nil)
+	bus := events.NewBus()
+	service.Subscribe(bus)
+	ctx, cancel := context.WithCancel(tenant.WithOrg(context.Background(), "org", models.OrgRoleMember))
+	defer cancel()
+
+	projectRepo.On("GetByUser", mock.Anything, mock.Anything).Return([]*models.Project{}, nil)
+	added, err := service.Stream(ctx, "member", "", models.ChangeStreamInput{})
+	require.NoError(t, err)
+	bystander, err := service.Stream(ctx, "bystander", "", models.ChangeStreamInput{})
+	require.NoError(t, err)
+
+	// The member is added to a project they could not see before
+	project := models.Project{ID: testProjectID, OrgID: "org", CreatedBy: "owner", Team: []string{"member"}}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&project, nil)
+	bus.Publish(ctx, "owner", events.ProjectUpdated{Project: project, Previous: models.Project{ID: testProjectID, CreatedBy: "owner"}})
+
+	msg := receive(t, added)
+	assert.Equal(t, services.EventChange, msg.Event)
+	assertQuiet(t, bystander)
+	// Both streams follow every project, yet the project was loaded once
+	projectRepo.AssertNumberOfCalls(t, "GetByID", 1)
+
+	// Changes in the project reach the new member without further lookups
+	bus.Publish(ctx, "owner", events.DocumentCreated{Document: models.Document{ID: testDocID, ProjectID: testProjectID}})
+	msg = receive(t, added)
+	assert.Equal(t, testDocID, msg.Data.(realtime.Change).ResourceID)
+	assertQuiet(t, bystander)
+	projectRepo.AssertNumberOfCalls(t, "GetByID", 1)
+	projectRepo.AssertNumberOfCalls(t, "GetByUser", 2)
+}
+
+func TestRealtimeService_RemovedMemberIsRevoked(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	service := services.NewRealtimeService(realtime.NewFeed(), nil, projectRepo, nil, nil)
+	bus := events.NewBus()
+	service.Subscribe(bus)
+	ctx, cancel := context.WithCancel(tenant.WithOrg(context.Background(), "org", models.OrgRoleMember))
+	defer cancel()
+
+	project := models.Project{ID: testProjectID, OrgID: "org", CreatedBy: "owner", Team: []string{"member"}}
+	projectRepo.On("GetByUser", mock.Anything, "member").Return([]*models.Project{&project}, nil)
+	messages, err := service.Stream(ctx, "member", "", models.ChangeStreamInput{ProjectIDs: []string{testProjectID}})
+	require.NoError(t, err)
+
+	// The member is taken off the project they follow
+	removed := project
+	removed.Team = nil
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&removed, nil)
+	bus.Publish(ctx, "owner", events.MemberRemoved{ProjectID: testProjectID, UserID: "member"})
+
+	// They hear of their removal, then the stream ends
+	assert.Equal(t, services.EventChange, receive(t, messages).Event)
+	assert.Equal(t, services.EventRevoked, receive(t, messages).Event)
+	_, open := <-messages
+	assert.False(t, open)
+}
@@ -0,0 +1,263 @@
+// internal/services/share_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/requestmeta"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testShareToken = "share-token"
+	testShareIP    = "203.0.113.9"
+)
+
+type MockShareLinkRepository struct {
+	mock.Mock
+	repository.ShareLinkRepository
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	return m.Called(ctx, link).Error(0)
+}
+
+func (m *MockShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) RecordView(ctx context.Context, id string, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *MockTeamMemberRepository) GetByProjectAndUser(ctx context.Context, projectID, userID string) (*models.TeamMember, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamMember), args.Error(1)
+}
+
+// memoryAttemptStore counts attempts in memory, without windows
+type memoryAttemptStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{counts: make(map[string]int64)}
+}
+
+func (s *memoryAttemptStore) Attempts(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[key], nil
+}
+
+func (s *memoryAttemptStore) AddAttempt(ctx context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[key]++
+	return s.counts[key], nil
+}
+
+func viewerContext() context.Context {
+	return requestmeta.With(context.Background(), requestmeta.Meta{IP: testShareIP})
+}
+
+func protectedLink(t *testing.T) *models.ShareLink {
+	link := &models.ShareLink{ID: "link", OrgID: "org-b", ProjectID: testProjectID, Kind: models.ShareLinkPresentation}
+	require.NoError(t, link.SetPassword("secret-password"))
+	return link
+}
+
+func TestShareService_ViewersCannotShare(t *testing.T) {
+	ctx := context.Background()
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	service := services.NewShareService(shareRepo, projectRepo, teamMemberRepo,
+		new(MockDocumentRepository), new(MockMockupRepository), newMemoryAttemptStore(), nil, "https://nexus.example.com")
+	projectRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{
+		ID:        testProjectID,
+		CreatedBy: "owner",
+		Team:      []string{"owner", "member", "viewer", "legacy"},
+	}, nil)
+	teamMemberRepo.On("GetByProjectAndUser", ctx, testProjectID, "viewer").Return(&models.TeamMember{UserID: "viewer", Role: models.TeamRoleViewer}, nil)
+	teamMemberRepo.On("GetByProjectAndUser", ctx, testProjectID, "member").Return(&models.TeamMember{UserID: "member", Role: models.TeamRoleMember}, nil)
+	teamMemberRepo.On("GetByProjectAndUser", ctx, testProjectID, "legacy").Return(nil, errors.ErrNotFound)
+	shareRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	_, err := service.CreateProjectLink(ctx, testProjectID, models.CreateShareLinkInput{}, "viewer")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	shareRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	for _, userID := range []string{"owner", "member", "legacy"} {
+		created, err := service.CreateProjectLink(ctx, testProjectID, models.CreateShareLinkInput{}, userID)
+		require.NoError(t, err, userID)
+		assert.Equal(t, userID, created.CreatedBy)
+	}
+}
+
+func TestShareService_ViewRejectsRevokedAndExpiredLinks(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		link *models.ShareLink
+	}{
+		{name: "revoked", link: &models.ShareLink{ID: "link", ProjectID: testProjectID, RevokedAt: &past}},
+		{name: "expired", link: &models.ShareLink{ID: "link", ProjectID: testProjectID, ExpiresAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := new(MockShareLinkRepository)
+			projectRepo := new(MockProjectRepository)
+			service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+				new(MockDocumentRepository), new(MockMockupRepository), newMemoryAttemptStore(), nil, "https://nexus.example.com")
+			shareRepo.On("GetByTokenHash", mock.Anything, models.HashShareToken(testShareToken)).Return(tt.link, nil)
+
+			view, err := service.View(viewerContext(), testShareToken, "")
+			assert.ErrorIs(t, err, errors.ErrShareLinkInactive)
+			assert.Nil(t, view)
+			projectRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestShareService_ViewLooksUpTokensInEveryOrganization(t *testing.T) {
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+		new(MockDocumentRepository), new(MockMockupRepository), newMemoryAttemptStore(), nil, "https://nexus.example.com")
+	link := &models.ShareLink{ID: "link", OrgID: "org-b", ProjectID: testProjectID, Kind: models.ShareLinkPresentation}
+	shareRepo.On("GetByTokenHash", mock.MatchedBy(tenant.IsUnscoped), models.HashShareToken(testShareToken)).Return(link, nil)
+	// Everything past the token is read in the link's organization
+	inLinkOrg := mock.MatchedBy(func(ctx context.Context) bool { return tenant.OrgID(ctx) == "org-b" })
+	projectRepo.On("GetByID", inLinkOrg, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org-b", Name: "Shared"}, nil)
+	shareRepo.On("RecordView", inLinkOrg, "link", mock.Anything).Return(nil)
+
+	view, err := service.View(tenant.WithOrg(viewerContext(), "org-a", models.OrgRoleAdmin), testShareToken, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Shared", view.Project.Name)
+	shareRepo.AssertExpectations(t)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestShareService_ViewWrongPasswordsLockOutTheirAddress(t *testing.T) {
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+		new(MockDocumentRepository), new(MockMockupRepository), newMemoryAttemptStore(), nil, "https://nexus.example.com")
+	shareRepo.On("GetByTokenHash", mock.Anything, models.HashShareToken(testShareToken)).Return(protectedLink(t), nil)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org-b", Name: "Shared"}, nil)
+	shareRepo.On("RecordView", mock.Anything, "link", mock.Anything).Return(nil)
+
+	_, err := service.View(viewerContext(), testShareToken, "")
+	assert.ErrorIs(t, err, errors.ErrSharePasswordRequired)
+
+	for i := 0; i < 10; i++ {
+		_, err := service.View(viewerContext(), testShareToken, "wrong-password")
+		assert.ErrorIs(t, err, errors.ErrSharePasswordInvalid)
+	}
+
+	_, err = service.View(viewerContext(), testShareToken, "secret-password")
+	assert.ErrorIs(t, err, errors.ErrShareTooManyAttempts)
+	projectRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+
+	// Viewers elsewhere still get in
+	other := requestmeta.With(context.Background(), requestmeta.Meta{IP: "198.51.100.1"})
+	view, err := service.View(other, testShareToken, "secret-password")
+	require.NoError(t, err)
+	assert.Equal(t, "Shared", view.Project.Name)
+}
+
+func TestShareService_ViewLimitsPasswordsPerAddress(t *testing.T) {
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	attempts := newMemoryAttemptStore()
+	service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+		new(MockDocumentRepository), new(MockMockupRepository), attempts, nil, "https://nexus.example.com")
+	shareRepo.On("GetByTokenHash", mock.Anything, models.HashShareToken(testShareToken)).Return(protectedLink(t), nil)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org-b", Name: "Shared"}, nil)
+	shareRepo.On("RecordView", mock.Anything, "link", mock.Anything).Return(nil)
+
+	view, err := service.View(viewerContext(), testShareToken, "secret-password")
+	require.NoError(t, err)
+	assert.Equal(t, "Shared", view.Project.Name)
+
+	// The address has used up its checks across every link
+	attempts.counts["share-address:"+testShareIP] = 30
+	_, err = service.View(viewerContext(), testShareToken, "secret-password")
+	assert.ErrorIs(t, err, errors.ErrShareTooManyAttempts)
+
+	other := requestmeta.With(context.Background(), requestmeta.Meta{IP: "198.51.100.1"})
+	_, err = service.View(other, testShareToken, "secret-password")
+	assert.NoError(t, err)
+}
+
+func TestShareService_ViewLeavesOutItemsMovedOrDeleted(t *testing.T) {
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	mockupRepo := new(MockMockupRepository)
+	service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+		documentRepo, mockupRepo, newMemoryAttemptStore(), nil, "https://nexus.example.com")
+	shareRepo.On("GetByTokenHash", mock.Anything, models.HashShareToken(testShareToken)).Return(&models.ShareLink{
+		ID:          "link",
+		OrgID:       "org-b",
+		ProjectID:   testProjectID,
+		Kind:        models.ShareLinkPresentation,
+		DocumentIDs: []string{"kept", "moved", "deleted"},
+		MockupIDs:   []string{"kept-mockup", "moved-mockup"},
+	}, nil)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org-b", Name: "Shared"}, nil)
+	shareRepo.On("RecordView", mock.Anything, "link", mock.Anything).Return(nil)
+	documentRepo.On("GetByID", mock.Anything, "kept").Return(&models.Document{ID: "kept", ProjectID: testProjectID, Title: "Kept"}, nil)
+	documentRepo.On("GetByID", mock.Anything, "moved").Return(&models.Document{ID: "moved", ProjectID: "other-project"}, nil)
+	documentRepo.On("GetByID", mock.Anything, "deleted").Return(nil, errors.ErrDocumentNotFound)
+	mockupRepo.On("GetByID", mock.Anything, "kept-mockup").Return(&models.Mockup{ID: "kept-mockup", ProjectID: testProjectID}, nil)
+	mockupRepo.On("GetByID", mock.Anything, "moved-mockup").Return(&models.Mockup{ID: "moved-mockup", ProjectID: "other-project"}, nil)
+
+	view, err := service.View(viewerContext(), testShareToken, "")
+	require.NoError(t, err)
+	require.Len(t, view.Documents, 1)
+	assert.Equal(t, "kept", view.Documents[0].ID)
+	require.Len(t, view.Mockups, 1)
+	assert.Equal(t, "kept-mockup", view.Mockups[0].ID)
+}
+
+func TestShareService_DocumentLinkStopsWhenItsDocumentLeaves(t *testing.T) {
+	shareRepo := new(MockShareLinkRepository)
+	projectRepo := new(MockProjectRepository)
+	documentRepo := new(MockDocumentRepository)
+	service := services.NewShareService(shareRepo, projectRepo, new(MockTeamMemberRepository),
+		documentRepo, new(MockMockupRepository), newMemoryAttemptStore(), nil, "https://nexus.example.com")
+	shareRepo.On("GetByTokenHash", mock.Anything, models.HashShareToken(testShareToken)).Return(&models.ShareLink{
+		ID:          "link",
+		OrgID:       "org-b",
+		ProjectID:   testProjectID,
+		Kind:        models.ShareLinkDocument,
+		DocumentIDs: []string{"moved"},
+	}, nil)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org-b", Name: "Shared"}, nil)
+	shareRepo.On("RecordView", mock.Anything, "link", mock.Anything).Return(nil)
+	documentRepo.On("GetByID", mock.Anything, "moved").Return(&models.Document{ID: "moved", ProjectID: "other-project"}, nil)
+
+	_, err := service.View(viewerContext(), testShareToken, "")
+	assert.ErrorIs(t, err, errors.ErrShareLinkInactive)
+	shareRepo.AssertNotCalled(t, "RecordView", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,82 @@
+// internal/services/stats_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+type MockStatsRepository struct {
+	mock.Mock
+	repository.StatsRepository
+}
+
+func (m *MockStatsRepository) DocumentStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.DocumentStats, error) {
+	args := m.Called(ctx, projectIDs, since, bucket)
+	return args.Get(0).(*models.DocumentStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) EditStats(ctx context.Context, projectIDs []string, since time.Time, bucket models.StatsBucket) (*models.EditStats, error) {
+	args := m.Called(ctx, projectIDs, since, bucket)
+	return args.Get(0).(*models.EditStats), args.Error(1)
+}
+
+func TestStatsService_ProjectMetricsJoinContributions(t *testing.T) {
+	ctx := context.Background()
+	statsRepo := new(MockStatsRepository)
+	projectRepo := new(MockProjectRepository)
+	userRepo := new(MockUserRepository)
+	service := services.NewStatsService(statsRepo, projectRepo, userRepo)
+
+	projectRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{
+		ID:        testProjectID,
+		CreatedBy: "author",
+		Team:      []string{"author", "editor"},
+	}, nil)
+	statsRepo.On("DocumentStats", ctx, []string{testProjectID}, mock.Anything, models.StatsBucketDay).Return(&models.DocumentStats{
+		ByStatus: []models.KeyCount{{Key: string(models.DocumentStatusInReview), Count: 2}, {Key: string(models.DocumentStatusDraft), Count: 1}},
+		Creators: []models.KeyCount{{Key: "author", Count: 3}},
+	}, nil)
+	statsRepo.On("EditStats", ctx, []string{testProjectID}, mock.Anything, models.StatsBucketDay).Return(&models.EditStats{
+		Editors: []models.KeyCount{{Key: "editor", Count: 5}, {Key: "author", Count: 1}},
+	}, nil)
+	userRepo.On("GetByID", ctx, "author").Return(&models.User{ID: "author", Name: "Ada"}, nil)
+	userRepo.On("GetByID", ctx, "editor").Return(&models.User{ID: "editor", Email: "ed@example.com"}, nil)
+
+	stats, err := service.GetProjectMetrics(ctx, testProjectID, "editor", models.StatsWindow("7d"))
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatsWindow("7d"), stats.Window)
+	assert.Equal(t, testProjectID, stats.ProjectID)
+	assert.Equal(t, int64(3), stats.Summary.Documents)
+	assert.Equal(t, int64(2), stats.Summary.PendingReviews)
+	assert.Equal(t, 2, stats.Summary.TeamMembers)
+	assert.Equal(t, []models.MemberContribution{
+		{UserID: "editor", Name: "ed@example.com", Edits: 5},
+		{UserID: "author", Name: "Ada", DocumentsCreated: 3, Edits: 1},
+	}, stats.Contributions)
+}
+
+func TestStatsService_ProjectMetricsAreForMembers(t *testing.T) {
+	ctx := context.Background()
+	statsRepo := new(MockStatsRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewStatsService(statsRepo, projectRepo, nil)
+
+	projectRepo.On("GetByID", ctx, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "author"}, nil)
+
+	_, err := service.GetProjectMetrics(ctx, testProjectID, "outsider", "")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	statsRepo.AssertNotCalled(t, "DocumentStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = service.GetProjectMetrics(ctx, testProjectID, "author", models.StatsWindow("2d"))
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
@@ -0,0 +1,85 @@
+// internal/services/task_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"testing"
+)
+
+const testTaskID = "64b7f0c2a1b2c3d4e5f6e001"
+
+type MockTaskRepository struct {
+	mock.Mock
+	repository.TaskRepository
+}
+
+func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	task := *args.Get(0).(*models.Task)
+	return &task, args.Error(1)
+}
+
+func (m *MockTaskRepository) FindByProject(ctx context.Context, projectID string, filter models.TaskFilter) ([]*models.Task, error) {
+	args := m.Called(ctx, projectID, filter)
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(ctx context.Context, task *models.Task) error {
+	return m.Called(ctx, task).Error(0)
+}
+
+type MockBoardRepository struct {
+	mock.Mock
+	repository.BoardRepository
+}
+
+func (m *MockBoardRepository) GetByProject(ctx context.Context, projectID string) (*models.Board, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(*models.Board), args.Error(1)
+}
+
+func TestTaskService_MoveTaskNeedsCurrentVersion(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	boardRepo := new(MockBoardRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewTaskService(taskRepo, boardRepo, projectRepo, nil, nil, events.NewBus(), MockTxManager{})
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner"}, nil)
+	boardRepo.On("GetByProject", mock.Anything, testProjectID).Return(&models.Board{ProjectID: testProjectID, Columns: models.DefaultBoardColumns()}, nil)
+	taskRepo.On("GetByID", mock.Anything, testTaskID).Return(&models.Task{ID: testTaskID, ProjectID: testProjectID, ColumnID: "todo", Rank: "m", Version: 3}, nil)
+	taskRepo.On("FindByProject", mock.Anything, testProjectID, mock.Anything).Return([]*models.Task{}, nil)
+
+	_, err := service.MoveTask(context.Background(), testProjectID, testTaskID, models.MoveTaskInput{ColumnID: "done"}, "owner")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	_, err = service.MoveTask(context.Background(), testProjectID, testTaskID, models.MoveTaskInput{ColumnID: "done", Version: 2}, "owner")
+	assert.ErrorIs(t, err, errors.ErrVersionConflict)
+	taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_MoveTaskLosingARaceIsAConflict(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	boardRepo := new(MockBoardRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewTaskService(taskRepo, boardRepo, projectRepo, nil, nil, events.NewBus(), MockTxManager{})
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner"}, nil)
+	boardRepo.On("GetByProject", mock.Anything, testProjectID).Return(&models.Board{ProjectID: testProjectID, Columns: models.DefaultBoardColumns()}, nil)
+	taskRepo.On("GetByID", mock.Anything, testTaskID).Return(&models.Task{ID: testTaskID, ProjectID: testProjectID, ColumnID: "todo", Rank: "m", Version: 3}, nil)
+	taskRepo.On("FindByProject", mock.Anything, testProjectID, mock.Anything).Return([]*models.Task{}, nil)
+	// Another move saved the task between this one's read and its write
+	taskRepo.On("Update", mock.Anything, mock.Anything).Return(errors.ErrVersionConflict).Once()
+
+	_, err := service.MoveTask(context.Background(), testProjectID, testTaskID, models.MoveTaskInput{ColumnID: "done", Version: 3}, "owner")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrVersionConflict)
+}
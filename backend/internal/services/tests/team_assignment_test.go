@@ -0,0 +1,128 @@
+// internal/services/team_assignment_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/services"
+	"testing"
+	"time"
+)
+
+func (m *MockTeamMemberRepository) Create(ctx context.Context, member *models.TeamMember) error {
+	return m.Called(ctx, member).Error(0)
+}
+
+func (m *MockTeamMemberRepository) GetAllByProject(ctx context.Context, projectID string) ([]*models.TeamMember, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*models.TeamMember), args.Error(1)
+}
+
+func (m *MockTeamMemberRepository) Update(ctx context.Context, member *models.TeamMember) error {
+	return m.Called(ctx, member).Error(0)
+}
+
+func TestTeamService_GetProjectTeams(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	service := services.NewTeamService(nil, nil, projectRepo, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	assignments := []models.ProjectTeamAssignment{{TeamID: testTeamID, Role: models.TeamRoleMember}}
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"member"}, Teams: assignments}, nil)
+	projectRepo.On("GetByID", mock.Anything, "not-an-id").Return(nil, primitive.ErrInvalidHex)
+
+	teams, err := service.GetProjectTeams(context.Background(), testProjectID, "member")
+	require.NoError(t, err)
+	assert.Equal(t, assignments, teams)
+
+	_, err = service.GetProjectTeams(context.Background(), testProjectID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	_, err = service.GetProjectTeams(context.Background(), "not-an-id", "member")
+	assert.ErrorIs(t, err, errors.ErrProjectNotFound)
+}
+
+func TestTeamService_GetProjectTeamIsForMembers(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	service := services.NewTeamService(nil, teamMemberRepo, projectRepo, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"member"}}, nil)
+	teamMemberRepo.On("GetProjectMembers", mock.Anything, testProjectID).Return([]*models.TeamMember{{ProjectID: testProjectID, UserID: "member"}}, nil)
+
+	members, err := service.GetProjectTeam(context.Background(), testProjectID, "member")
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	_, err = service.GetProjectTeam(context.Background(), testProjectID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.GetTeamMember(context.Background(), testProjectID, "member-id", "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	teamMemberRepo.AssertNumberOfCalls(t, "GetProjectMembers", 1)
+}
+
+func TestTeamService_AssignTeamNotifiesNewMembers(t *testing.T) {
+	teamRepo := new(MockTeamRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	projectRepo := new(MockProjectRepository)
+	notifications := new(MockNotificationService)
+	service := services.NewTeamService(teamRepo, teamMemberRepo, projectRepo, nil, nil, notifications, events.NewBus(), MockTxManager{})
+
+	teamRepo.On("GetByID", mock.Anything, testTeamID).Return(&models.Team{ID: testTeamID, Members: []models.TeamMembership{
+		{UserID: "member", Role: models.TeamRoleMember},
+		{UserID: "newcomer", Role: models.TeamRoleMember},
+	}}, nil)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, CreatedBy: "owner", Team: []string{"owner", "member"}}, nil)
+	projectRepo.On("AssignTeam", mock.Anything, testProjectID, mock.Anything).Return(nil)
+	projectRepo.On("UpdateTeamList", mock.Anything, testProjectID, []string{"newcomer"}, []string(nil)).Return(nil)
+	// The member already works on the project directly
+	teamMemberRepo.On("GetAllByProject", mock.Anything, testProjectID).Return([]*models.TeamMember{
+		{UserID: "member", Role: models.TeamRoleMember, Status: models.TeamMemberStatusActive, Source: models.TeamMemberSourceDirect},
+	}, nil)
+	teamMemberRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	teamMemberRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	notifications.On("Notify", mock.Anything, models.NotificationProjectAdded, []string{"newcomer"}).Return(nil)
+
+	_, err := service.AssignTeam(context.Background(), testProjectID, models.AssignTeamInput{TeamID: testTeamID, Role: models.TeamRoleMember}, "owner")
+	require.NoError(t, err)
+
+	// Only the user who gained access hears of it
+	notifications.AssertNumberOfCalls(t, "Notify", 1)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestTeamService_JoinTeamLeavesArchivedProjects(t *testing.T) {
+	teamRepo := new(MockTeamRepository)
+	teamMemberRepo := new(MockTeamMemberRepository)
+	projectRepo := new(MockProjectRepository)
+	notifications := new(MockNotificationService)
+	service := services.NewTeamService(teamRepo, teamMemberRepo, projectRepo, nil, nil, notifications, events.NewBus(), MockTxManager{})
+
+	assignments := []models.ProjectTeamAssignment{{TeamID: testTeamID, Role: models.TeamRoleMember}}
+	archivedAt := time.Now()
+	teamRepo.On("GetByID", mock.Anything, testTeamID).Return(&models.Team{ID: testTeamID, Members: []models.TeamMembership{
+		{UserID: "newcomer", Role: models.TeamRoleMember},
+	}}, nil)
+	teamRepo.On("AddMember", mock.Anything, testTeamID, mock.Anything).Return(nil)
+	projectRepo.On("GetByAssignedTeam", mock.Anything, testTeamID).Return([]*models.Project{
+		{ID: testProjectID, CreatedBy: "owner", Team: []string{"owner"}, Teams: assignments},
+		{ID: "archived", CreatedBy: "owner", Team: []string{"owner"}, Teams: assignments, ArchivedAt: &archivedAt},
+	}, nil)
+	teamMemberRepo.On("GetAllByProject", mock.Anything, testProjectID).Return([]*models.TeamMember{}, nil)
+	teamMemberRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	// Only the newcomer is added, so edits made to the project meanwhile stay
+	projectRepo.On("UpdateTeamList", mock.Anything, testProjectID, []string{"newcomer"}, []string(nil)).Return(nil)
+	notifications.On("Notify", mock.Anything, models.NotificationProjectAdded, []string{"newcomer"}).Return(nil)
+
+	_, err := service.JoinTeam(context.Background(), testTeamID, "newcomer", models.TeamRoleMember)
+	require.NoError(t, err)
+
+	projectRepo.AssertExpectations(t)
+	teamMemberRepo.AssertNotCalled(t, "GetAllByProject", mock.Anything, "archived")
+	projectRepo.AssertNotCalled(t, "UpdateTeamList", mock.Anything, "archived", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,114 @@
+// internal/services/team_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+const testTeamID = "64b7f0c2a1b2c3d4e5f6b001"
+
+type MockTeamRepository struct {
+	mock.Mock
+	repository.TeamRepository
+}
+
+func (m *MockTeamRepository) GetByID(ctx context.Context, id string) (*models.Team, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	// Every call gets a copy, as a fresh read from the database would
+	team := *args.Get(0).(*models.Team)
+	team.Members = append([]models.TeamMembership(nil), team.Members...)
+	return &team, args.Error(1)
+}
+
+func (m *MockTeamRepository) AddMember(ctx context.Context, teamID string, member models.TeamMembership) error {
+	return m.Called(ctx, teamID, member).Error(0)
+}
+
+func TestTeamService_GetTeamByIDFillsInMembers(t *testing.T) {
+	teamRepo := new(MockTeamRepository)
+	userRepo := new(MockUserRepository)
+	service := services.NewTeamService(teamRepo, nil, nil, userRepo, nil, nil, events.NewBus(), MockTxManager{})
+	teamRepo.On("GetByID", mock.Anything, testTeamID).Return(&models.Team{
+		ID:   testTeamID,
+		Lead: "lead",
+		Members: []models.TeamMembership{
+			{UserID: "lead", Role: models.TeamRoleOwner},
+			{UserID: "guest-member", Role: models.TeamRoleMember},
+		},
+	}, nil)
+	userRepo.On("GetByIDs", mock.Anything, []string{"lead", "guest-member"}).Return([]*models.User{
+		{ID: "lead", Name: "Lee Lead", Email: "lee@example.com"},
+		{ID: "guest-member", Email: "guest@example.com"},
+	}, nil).Once()
+
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+	team, err := service.GetTeamByID(ctx, testTeamID, "someone")
+	require.NoError(t, err)
+	assert.Equal(t, "Lee Lead", team.Members[0].Name)
+	assert.Equal(t, "lee@example.com", team.Members[0].Email)
+	assert.Equal(t, "guest@example.com", team.Members[1].Email)
+	userRepo.AssertExpectations(t)
+}
+
+func TestTeamService_GuestsSeeOnlyTheirTeams(t *testing.T) {
+	teamRepo := new(MockTeamRepository)
+	userRepo := new(MockUserRepository)
+	service := services.NewTeamService(teamRepo, nil, nil, userRepo, nil, nil, events.NewBus(), MockTxManager{})
+	teamRepo.On("GetByID", mock.Anything, testTeamID).Return(&models.Team{
+		ID:   testTeamID,
+		Lead: "lead",
+		Members: []models.TeamMembership{
+			{UserID: "lead", Role: models.TeamRoleOwner},
+			{UserID: "guest-member", Role: models.TeamRoleMember},
+		},
+	}, nil)
+	userRepo.On("GetByIDs", mock.Anything, mock.Anything).Return([]*models.User{}, nil)
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleGuest)
+
+	_, err := service.GetTeamByID(ctx, testTeamID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.GetTeamMembers(ctx, testTeamID, "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.GetMember(ctx, testTeamID, "lead", "outsider")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	members, err := service.GetTeamMembers(ctx, testTeamID, "guest-member")
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+}
+
+func TestTeamService_GetMember(t *testing.T) {
+	teamRepo := new(MockTeamRepository)
+	userRepo := new(MockUserRepository)
+	service := services.NewTeamService(teamRepo, nil, nil, userRepo, nil, nil, events.NewBus(), MockTxManager{})
+	teamRepo.On("GetByID", mock.Anything, testTeamID).Return(&models.Team{
+		ID:   testTeamID,
+		Lead: "lead",
+		Members: []models.TeamMembership{
+			{UserID: "lead", Role: models.TeamRoleOwner},
+			{UserID: "guest-member", Role: models.TeamRoleMember},
+		},
+	}, nil)
+	userRepo.On("GetByIDs", mock.Anything, mock.Anything).Return([]*models.User{}, nil)
+	ctx := tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+
+	member, err := service.GetMember(ctx, testTeamID, "guest-member", "someone")
+	require.NoError(t, err)
+	assert.Equal(t, models.TeamRoleMember, member.Role)
+
+	_, err = service.GetMember(ctx, testTeamID, "nobody", "someone")
+	assert.ErrorIs(t, err, errors.ErrNotInTeam)
+}
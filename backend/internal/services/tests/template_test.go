@@ -0,0 +1,185 @@
+// internal/services/template_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+const testTemplateID = "64b7f0c2a1b2c3d4e5f6f001"
+
+type MockTemplateRepository struct {
+	mock.Mock
+	repository.TemplateRepository
+}
+
+func (m *MockTemplateRepository) Create(ctx context.Context, template *models.ProjectTemplate) error {
+	return m.Called(ctx, template).Error(0)
+}
+
+func (m *MockTemplateRepository) GetByID(ctx context.Context, id string) (*models.ProjectTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) List(ctx context.Context) ([]*models.ProjectTemplate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.ProjectTemplate), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organization), args.Error(1)
+}
+
+type MockProjectService struct {
+	mock.Mock
+	services.ProjectService
+}
+
+func (m *MockProjectService) CreateProject(ctx context.Context, input models.CreateProjectInput, userID string) (*models.Project, error) {
+	args := m.Called(ctx, input, userID)
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+type MockProgressService struct {
+	mock.Mock
+	services.ProgressService
+}
+
+func (m *MockProgressService) Recompute(ctx context.Context, projectID string) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+	services.NotificationService
+}
+
+func (m *MockNotificationService) Notify(ctx context.Context, notification models.Notification, recipients []string) error {
+	return m.Called(ctx, notification.Type, recipients).Error(0)
+}
+
+func memberContext() context.Context {
+	return tenant.WithOrg(context.Background(), "org", models.OrgRoleMember)
+}
+
+func TestTemplateService_GuestsCannotUseTemplates(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	projectRepo := new(MockProjectRepository)
+	service := services.NewTemplateService(templateRepo, projectRepo, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, events.NewBus(), MockTxManager{})
+	guest := tenant.WithOrg(context.Background(), "org", models.OrgRoleGuest)
+
+	_, err := service.ListTemplates(guest)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.GetTemplate(guest, testTemplateID)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.UseTemplate(guest, testTemplateID, models.UseTemplateInput{Name: "New"}, "owner")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = service.SaveAsTemplate(guest, testProjectID, models.SaveTemplateInput{Name: "Saved"}, "owner")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	templateRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestTemplateService_MembersMustBelongToOrganization(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("GetByID", mock.Anything, "org").Return(&models.Organization{ID: "org", Members: []models.OrgMembership{
+		{UserID: "owner", Role: models.OrgRoleAdmin},
+		{UserID: "colleague", Role: models.OrgRoleMember},
+	}}, nil)
+	service := services.NewTemplateService(templateRepo, nil, nil, nil, nil, nil, nil, orgRepo,
+		nil, nil, nil, nil, nil, events.NewBus(), MockTxManager{})
+
+	input := models.TemplateInput{Name: "Kickoff", Members: []models.TemplateMember{{UserID: "outsider", Role: models.TeamRoleMember}}}
+	_, err := service.CreateTemplate(memberContext(), input, "owner")
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+	templateRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTemplateService_UseTemplateAddsOnlyOrganizationMembers(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("GetByID", mock.Anything, "org").Return(&models.Organization{ID: "org", Members: []models.OrgMembership{
+		{UserID: "owner", Role: models.OrgRoleAdmin},
+		{UserID: "colleague", Role: models.OrgRoleMember},
+	}}, nil)
+	projects := new(MockProjectService)
+	projects.On("CreateProject", mock.Anything, mock.Anything, "owner").Return(&models.Project{ID: testProjectID, OrgID: "org", CreatedBy: "owner"}, nil)
+	teams := new(MockTeamService)
+	progress := new(MockProgressService)
+	progress.On("Recompute", mock.Anything, mock.Anything).Return(nil)
+	notifications := new(MockNotificationService)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org", CreatedBy: "owner"}, nil)
+	service := services.NewTemplateService(templateRepo, projectRepo, new(MockDocumentRepository), nil, nil, nil, userRepo, orgRepo,
+		projects, teams, nil, progress, notifications, events.NewBus(), MockTxManager{})
+	// The outsider was stored before members were checked
+	templateRepo.On("GetByID", mock.Anything, testTemplateID).Return(&models.ProjectTemplate{
+		ID:   testTemplateID,
+		Name: "Kickoff",
+		Members: []models.TemplateMember{
+			{UserID: "colleague", Role: models.TeamRoleMember},
+			{UserID: "outsider", Role: models.TeamRoleOwner},
+		},
+	}, nil)
+	teams.On("JoinProject", mock.Anything, testProjectID, "colleague", models.TeamRoleMember).Return(&models.TeamMember{}, nil)
+	notifications.On("Notify", mock.Anything, models.NotificationProjectAdded, []string{"colleague"}).Return(nil)
+
+	project, err := service.UseTemplate(memberContext(), testTemplateID, models.UseTemplateInput{Name: "New"}, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, testProjectID, project.ID)
+	teams.AssertNotCalled(t, "JoinProject", mock.Anything, mock.Anything, "outsider", mock.Anything)
+	notifications.AssertNumberOfCalls(t, "Notify", 1)
+}
+
+func TestTemplateService_SaveApprovedTakesLastApprovedVersion(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	documentRepo := new(MockDocumentRepository)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("GetByID", mock.Anything, testProjectID).Return(&models.Project{ID: testProjectID, OrgID: "org", CreatedBy: "owner"}, nil)
+	service := services.NewTemplateService(templateRepo, projectRepo, documentRepo, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, events.NewBus(), MockTxManager{})
+	documentRepo.On("GetByProject", mock.Anything, testProjectID).Return([]*models.Document{
+		{ID: "approved", Title: "Approved", Content: "current", Status: models.DocumentStatusApproved},
+		{ID: "reopened", Title: "Reopened", Content: "unreviewed edit", Status: models.DocumentStatusInReview},
+		{ID: "draft", Title: "Draft", Content: "never approved", Status: models.DocumentStatusDraft},
+	}, nil)
+	documentRepo.On("GetVersions", mock.Anything, "reopened").Return([]*models.DocumentVersion{
+		{Version: 3, Content: "unreviewed edit", Status: models.DocumentStatusInReview},
+		{Version: 2, Content: "signed off", Status: models.DocumentStatusApproved},
+		{Version: 1, Content: "first draft", Status: models.DocumentStatusDraft},
+	}, nil)
+	documentRepo.On("GetVersions", mock.Anything, "draft").Return([]*models.DocumentVersion{
+		{Version: 1, Content: "never approved", Status: models.DocumentStatusDraft},
+	}, nil)
+	templateRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	input := models.SaveTemplateInput{Name: "Approved only", Documents: models.DocumentSelectionApproved}
+	template, err := service.SaveAsTemplate(memberContext(), testProjectID, input, "owner")
+	require.NoError(t, err)
+
+	require.Len(t, template.Documents, 2)
+	assert.Equal(t, "current", template.Documents[0].Content)
+	assert.Equal(t, "reopened", template.Documents[1].Key)
+	assert.Equal(t, "signed off", template.Documents[1].Content)
+	assert.Equal(t, models.DocumentStatusApproved, template.Documents[1].Status)
+}
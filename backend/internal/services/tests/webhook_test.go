@@ -0,0 +1,220 @@
+// internal/services/webhook_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/services"
+	"projectnexus/internal/webhooksig"
+	"sync"
+	"testing"
+	"time"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+	repository.WebhookRepository
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscribed(ctx context.Context, projectID string, event models.WebhookEvent) ([]*models.Webhook, error) {
+	args := m.Called(ctx, projectID, event)
+	return args.Get(0).([]*models.Webhook), args.Error(1)
+}
+
+// fakeDeliveryQueue hands out its deliveries one at a time and keeps the
+// recorded outcomes, as the delivery collection would
+type fakeDeliveryQueue struct {
+	repository.WebhookDeliveryRepository
+	mu       sync.Mutex
+	due      []*models.WebhookDelivery
+	recorded map[string]models.WebhookDelivery
+	// done receives the ID of each recorded delivery
+	done chan string
+	// created holds the queued deliveries
+	created []*models.WebhookDelivery
+}
+
+func newFakeDeliveryQueue(deliveries ...*models.WebhookDelivery) *fakeDeliveryQueue {
+	return &fakeDeliveryQueue{
+		due:      deliveries,
+		recorded: make(map[string]models.WebhookDelivery),
+		done:     make(chan string, len(deliveries)),
+	}
+}
+
+func (q *fakeDeliveryQueue) Create(_ context.Context, delivery *models.WebhookDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.created = append(q.created, delivery)
+	return nil
+}
+
+func (q *fakeDeliveryQueue) ClaimDue(context.Context, time.Time, time.Duration) (*models.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.due) == 0 {
+		return nil, nil
+	}
+	delivery := q.due[0]
+	q.due = q.due[1:]
+	return delivery, nil
+}
+
+func (q *fakeDeliveryQueue) RecordAttempt(_ context.Context, delivery *models.WebhookDelivery) error {
+	q.mu.Lock()
+	q.recorded[delivery.ID] = *delivery
+	q.mu.Unlock()
+	q.done <- delivery.ID
+	return nil
+}
+
+func (q *fakeDeliveryQueue) get(id string) models.WebhookDelivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.recorded[id]
+}
+
+func newDelivery(id, webhookID string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        id,
+		WebhookID: webhookID,
+		Event:     models.WebhookDocumentApproved,
+		Payload:   `{"event":"document.approved"}`,
+		Status:    models.WebhookDeliveryPending,
+	}
+}
+
+func TestWebhookService_DeliverDueSendsSignedPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("internal details"))
+	}))
+	defer server.Close()
+
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("GetByID", mock.Anything, "hook").Return(&models.Webhook{ID: "hook", URL: server.URL, Secret: "whsec_test", Active: true}, nil)
+	queue := newFakeDeliveryQueue(newDelivery("delivery", "hook"))
+	service := services.NewWebhookService(webhookRepo, queue, nil, events.NewBus(), server.Client())
+
+	attempted, err := service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+
+	require.NotNil(t, received)
+	assert.Equal(t, `{"event":"document.approved"}`, string(body))
+	assert.Equal(t, "delivery", received.Header.Get("X-ProjectNexus-Delivery"))
+	assert.NoError(t, webhooksig.Verify([]byte("whsec_test"), received.Header.Get(webhooksig.Header), body, time.Now(), time.Minute))
+
+	delivery := queue.get("delivery")
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusOK, delivery.Attempts[0].ResponseCode)
+}
+
+func TestWebhookService_FailedDeliveryIsRetried(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("GetByID", mock.Anything, "hook").Return(&models.Webhook{ID: "hook", URL: server.URL, Active: true}, nil)
+	queue := newFakeDeliveryQueue(newDelivery("delivery", "hook"))
+	service := services.NewWebhookService(webhookRepo, queue, nil, events.NewBus(), server.Client())
+
+	_, err := service.DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	delivery := queue.get("delivery")
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	require.NotNil(t, delivery.NextAttemptAt)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.Attempts[0].ResponseCode)
+}
+
+func TestWebhookService_InactiveOrDeletedWebhooksFailAtOnce(t *testing.T) {
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("GetByID", mock.Anything, "inactive").Return(&models.Webhook{ID: "inactive", URL: "https://example.com/hook", Active: false}, nil)
+	webhookRepo.On("GetByID", mock.Anything, "deleted").Return(nil, errors.ErrWebhookNotFound)
+	queue := newFakeDeliveryQueue(newDelivery("to-inactive", "inactive"), newDelivery("to-deleted", "deleted"))
+	service := services.NewWebhookService(webhookRepo, queue, nil, events.NewBus(), http.DefaultClient)
+
+	attempted, err := service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempted)
+
+	for _, id := range []string{"to-inactive", "to-deleted"} {
+		delivery := queue.get(id)
+		assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status, id)
+		assert.Nil(t, delivery.NextAttemptAt, id)
+		assert.Len(t, delivery.Attempts, 1, id)
+	}
+}
+
+func TestWebhookService_SlowEndpointDoesNotHoldUpOthers(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("GetByID", mock.Anything, "slow").Return(&models.Webhook{ID: "slow", URL: slow.URL, Active: true}, nil)
+	webhookRepo.On("GetByID", mock.Anything, "fast").Return(&models.Webhook{ID: "fast", URL: fast.URL, Active: true}, nil)
+	// The slow endpoint's delivery is first in line
+	queue := newFakeDeliveryQueue(newDelivery("to-slow", "slow"), newDelivery("to-fast", "fast"))
+	service := services.NewWebhookService(webhookRepo, queue, nil, events.NewBus(), http.DefaultClient)
+
+	go func() { _, _ = service.DeliverDue(context.Background()) }()
+
+	select {
+	case id := <-queue.done:
+		assert.Equal(t, "to-fast", id)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the fast delivery waited for the slow one")
+	}
+	assert.Equal(t, models.WebhookDeliverySucceeded, queue.get("to-fast").Status)
+}
+
+func TestWebhookService_DispatchKeepsEventIDAcrossRedeliveries(t *testing.T) {
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("GetSubscribed", mock.Anything, testProjectID, mock.Anything).Return([]*models.Webhook{{ID: "hook", Active: true}}, nil)
+	queue := newFakeDeliveryQueue()
+	service := services.NewWebhookService(webhookRepo, queue, nil, events.NewBus(), http.DefaultClient)
+
+	project := &models.Project{ID: testProjectID}
+	env := events.Envelope{ID: "event", ActorID: "owner", OccurredAt: time.Now()}
+	// The transport hands the same event over twice
+	require.NoError(t, service.Dispatch(context.Background(), env, project, models.WebhookDocumentApproved, nil))
+	require.NoError(t, service.Dispatch(context.Background(), env, project, models.WebhookDocumentApproved, nil))
+	require.NoError(t, service.Dispatch(context.Background(), env, project, models.WebhookDocumentStatusChanged, nil))
+
+	require.Len(t, queue.created, 3)
+	// Both tries carry the same event, which the queue stores once
+	assert.Equal(t, queue.created[0].EventID, queue.created[1].EventID)
+	assert.Equal(t, queue.created[0].Payload, queue.created[1].Payload)
+	// Another webhook event of the same change is told apart
+	assert.NotEqual(t, queue.created[0].EventID, queue.created[2].EventID)
+}
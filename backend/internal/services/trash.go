@@ -0,0 +1,347 @@
+// Package services internal/services/trash.go
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/repository"
+	"time"
+)
+
+type TrashService interface {
+	ListProjectTrash(ctx context.Context, projectID string, userID string) (*models.ProjectTrash, error)
+	ListDeletedProjects(ctx context.Context, userID string) ([]*models.Project, error)
+	RestoreProject(ctx context.Context, id string, userID string) (*models.Project, error)
+	RestoreDocument(ctx context.Context, id string, userID string) (*models.Document, error)
+	RestoreMockup(ctx context.Context, id string, userID string) (*models.Mockup, error)
+	PurgeExpired(ctx context.Context) error
+	RunPurger(ctx context.Context, interval time.Duration)
+}
+
+type trashService struct {
+	projectRepo    repository.ProjectRepository
+	documentRepo   repository.DocumentRepository
+	mockupRepo     repository.MockupRepository
+	teamMemberRepo repository.TeamMemberRepository
+	folderRepo     repository.FolderRepository
+	milestoneRepo  repository.MilestoneRepository
+	taskRepo       repository.TaskRepository
+	boardRepo      repository.BoardRepository
+	webhookRepo    repository.WebhookRepository
+	deliveryRepo   repository.WebhookDeliveryRepository
+	linkService    LinkService
+	progress       ProgressService
+	bus            *events.Bus
+	txManager      repository.TxManager
+	retention      time.Duration
+	retentionDays  int
+}
+
+func NewTrashService(projectRepo repository.ProjectRepository, documentRepo repository.DocumentRepository, mockupRepo repository.MockupRepository, teamMemberRepo repository.TeamMemberRepository, folderRepo repository.FolderRepository, milestoneRepo repository.MilestoneRepository, taskRepo repository.TaskRepository, boardRepo repository.BoardRepository, webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository, linkService LinkService, progress ProgressService, bus *events.Bus, txManager repository.TxManager, retentionDays int) TrashService {
+	return &trashService{
+		projectRepo:    projectRepo,
+		documentRepo:   documentRepo,
+		mockupRepo:     mockupRepo,
+		teamMemberRepo: teamMemberRepo,
+		folderRepo:     folderRepo,
+		milestoneRepo:  milestoneRepo,
+		taskRepo:       taskRepo,
+		boardRepo:      boardRepo,
+		webhookRepo:    webhookRepo,
+		deliveryRepo:   deliveryRepo,
+		linkService:    linkService,
+		progress:       progress,
+		bus:            bus,
+		txManager:      txManager,
+		retention:      time.Duration(retentionDays) * 24 * time.Hour,
+		retentionDays:  retentionDays,
+	}
+}
+
+// activeProject loads a project that is not in the trash and verifies the
+// user is a member of it
+func (s *trashService) activeProject(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if _, trashErr := s.projectRepo.GetDeletedByID(ctx, projectID); trashErr == nil {
+			return nil, errors.ErrProjectInTrash
+		}
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.HasAccess(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+func (s *trashService) ListProjectTrash(ctx context.Context, projectID string, userID string) (*models.ProjectTrash, error) {
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if _, err := s.activeProject(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.GetDeletedByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	mockups, err := s.mockupRepo.GetDeletedByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectTrash{
+		Documents:     documents,
+		Mockups:       mockups,
+		RetentionDays: s.retentionDays,
+	}, nil
+}
+
+func (s *trashService) ListDeletedProjects(ctx context.Context, userID string) ([]*models.Project, error) {
+	return s.projectRepo.GetDeletedByUser(ctx, userID)
+}
+
+func (s *trashService) RestoreProject(ctx context.Context, id string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only project owners can restore the project
+	if !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		// Bring back only the children that were trashed with the project;
+		// items deleted individually beforehand stay in the trash
+		if err := s.documentRepo.RestoreByProject(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore project documents: %w", err)
+		}
+		if err := s.mockupRepo.RestoreByProject(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore project mockups: %w", err)
+		}
+		if err := s.teamMemberRepo.RestoreByProject(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore project members: %w", err)
+		}
+
+		return s.linkService.MarkProjectRestored(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.ProjectRestored{Project: *restored})
+	return restored, nil
+}
+
+func (s *trashService) RestoreDocument(ctx context.Context, id string, userID string) (*models.Document, error) {
+	doc, err := s.documentRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.activeProject(ctx, doc.ProjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same rule as deletion: document creator or project owner
+	if doc.CreatedBy != userID && !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if err := s.documentRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// The folder may have been deleted while the document was in the trash
+	if doc.FolderID != "" {
+		if _, err := s.folderRepo.GetByID(ctx, doc.FolderID); stderrors.Is(err, errors.ErrFolderNotFound) {
+			if err := s.documentRepo.UpdateOrganization(ctx, id, "", doc.Tags); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.linkService.MarkItemRestored(ctx, models.LinkItemDocument, id); err != nil {
+		log.Printf("Failed to restore links to document %s: %v", id, err)
+	}
+	refreshProgress(ctx, s.progress, doc.ProjectID)
+
+	restored, err := s.documentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.DocumentRestored{Document: *restored})
+	return restored, nil
+}
+
+func (s *trashService) RestoreMockup(ctx context.Context, id string, userID string) (*models.Mockup, error) {
+	mockup, err := s.mockupRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	project, err := s.activeProject(ctx, mockup.ProjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	if err := s.mockupRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkService.MarkItemRestored(ctx, models.LinkItemMockup, id); err != nil {
+		log.Printf("Failed to restore links to mockup %s: %v", id, err)
+	}
+	refreshProgress(ctx, s.progress, mockup.ProjectID)
+
+	restored, err := s.mockupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.MockupRestored{Mockup: *restored})
+	return restored, nil
+}
+
+// PurgeExpired permanently deletes everything that has been in the trash
+// longer than the retention period
+func (s *trashService) PurgeExpired(ctx context.Context) error {
+	cutoff := time.Now().Add(-s.retention)
+
+	// Documents and mockups go first, including the ones trashed together
+	// with an expired project, so their outgoing links are cleaned up before
+	// the project's collections are cleared in bulk
+	documents, err := s.documentRepo.GetDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to list expired documents: %w", err)
+	}
+	for _, doc := range documents {
+		if err := s.documentRepo.Delete(ctx, doc.ID); err != nil {
+			log.Printf("Failed to purge document %s: %v", doc.ID, err)
+			continue
+		}
+		if err := s.linkService.PurgeItem(ctx, models.LinkItemDocument, doc.ID); err != nil {
+			log.Printf("Failed to purge links of document %s: %v", doc.ID, err)
+		}
+	}
+
+	mockups, err := s.mockupRepo.GetDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to list expired mockups: %w", err)
+	}
+	for _, mockup := range mockups {
+		if err := s.mockupRepo.Delete(ctx, mockup.ID); err != nil {
+			log.Printf("Failed to purge mockup %s: %v", mockup.ID, err)
+			continue
+		}
+		if err := s.linkService.PurgeItem(ctx, models.LinkItemMockup, mockup.ID); err != nil {
+			log.Printf("Failed to purge links of mockup %s: %v", mockup.ID, err)
+		}
+	}
+
+	projects, err := s.projectRepo.GetDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to list expired projects: %w", err)
+	}
+	for _, project := range projects {
+		if err := s.purgeProject(ctx, project.ID); err != nil {
+			log.Printf("Failed to purge project %s: %v", project.ID, err)
+		}
+	}
+
+	if len(projects)+len(documents)+len(mockups) > 0 {
+		log.Printf("Purged %d projects, %d documents and %d mockups from the trash", len(projects), len(documents), len(mockups))
+	}
+
+	return nil
+}
+
+// purgeProject hard-deletes a project together with all of its children
+func (s *trashService) purgeProject(ctx context.Context, projectID string) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.purgeProjectData(ctx, projectID)
+	})
+}
+
+func (s *trashService) purgeProjectData(ctx context.Context, projectID string) error {
+	if err := s.documentRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.mockupRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.teamMemberRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.folderRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.milestoneRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.taskRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.boardRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.deliveryRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.DeleteByProject(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.linkService.PurgeItem(ctx, models.LinkItemProject, projectID); err != nil {
+		return err
+	}
+
+	return s.projectRepo.Delete(ctx, projectID)
+}
+
+// RunPurger runs PurgeExpired on the given interval until ctx is cancelled
+func (s *trashService) RunPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PurgeExpired(ctx); err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
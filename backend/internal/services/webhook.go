@@ -0,0 +1,483 @@
+// Package services internal/services/webhook.go
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"projectnexus/internal/errors"
+	"projectnexus/internal/events"
+	"projectnexus/internal/models"
+	"projectnexus/internal/pagination"
+	"projectnexus/internal/repository"
+	"projectnexus/internal/webhooksig"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// webhookLease is how long a worker may hold a delivery before another
+	// one takes it over
+	webhookLease = time.Minute
+	// webhookWorkers is the number of deliveries sent at once, so one slow
+	// endpoint does not hold up the others
+	webhookWorkers = 8
+	// webhookResponseLimit caps the response body read, and thrown away, to
+	// reuse the connection
+	webhookResponseLimit = 1024
+)
+
+type WebhookService interface {
+	// Webhooks are managed by project owners, as they see the secrets
+	ListWebhooks(ctx context.Context, projectID string, userID string) ([]*models.Webhook, error)
+	GetWebhook(ctx context.Context, projectID, webhookID string, userID string) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, projectID string, input models.CreateWebhookInput, userID string) (*models.CreatedWebhook, error)
+	UpdateWebhook(ctx context.Context, projectID, webhookID string, input models.UpdateWebhookInput, userID string) (*models.Webhook, error)
+	// DeleteWebhook removes a webhook along with its deliveries
+	DeleteWebhook(ctx context.Context, projectID, webhookID string, userID string) error
+
+	ListDeliveries(ctx context.Context, projectID, webhookID string, userID string, q pagination.Query) (*pagination.Page[*models.WebhookDelivery], error)
+	GetDelivery(ctx context.Context, projectID, webhookID, deliveryID string, userID string) (*models.WebhookDelivery, error)
+	// Redeliver queues the payload of an earlier delivery again
+	Redeliver(ctx context.Context, projectID, webhookID, deliveryID string, userID string) (*models.WebhookDelivery, error)
+
+	// Dispatch queues a delivery of the webhook event caused by the domain
+	// event to each active webhook of the project subscribed to it. The
+	// payload ID derives from the domain event, so dispatching a redelivered
+	// event again queues nothing new.
+	Dispatch(ctx context.Context, env events.Envelope, project *models.Project, event models.WebhookEvent, data interface{}) error
+
+	// DeliverDue sends the queued deliveries whose time has come, several at
+	// a time, and returns how many it attempted. Deliveries to inactive or
+	// deleted webhooks fail without retries.
+	DeliverDue(ctx context.Context) (int, error)
+
+	// Subscribe has domain events on the bus dispatched to webhooks
+	Subscribe(bus *events.Bus)
+
+	// RunDispatcher delivers queued webhooks as they are dispatched, and at
+	// least every interval for retries, until the context is done
+	RunDispatcher(ctx context.Context, interval time.Duration)
+}
+
+type webhookService struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	projectRepo  repository.ProjectRepository
+	bus          *events.Bus
+	// client sends the requests; see safehttp.NewClient
+	client *http.Client
+	// wake tells the dispatcher new deliveries are queued
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository, projectRepo repository.ProjectRepository, bus *events.Bus, client *http.Client) WebhookService {
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		projectRepo:  projectRepo,
+		bus:          bus,
+		client:       client,
+		wake:         make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// projectForOwner loads a project and verifies the user owns it
+func (s *webhookService) projectForOwner(ctx context.Context, projectID string, userID string) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.ErrProjectNotFound
+	}
+
+	if !project.IsOwner(userID) {
+		return nil, errors.ErrUnauthorized
+	}
+	return project, nil
+}
+
+// getProjectWebhook loads a webhook and verifies it belongs to the project
+func (s *webhookService) getProjectWebhook(ctx context.Context, projectID, webhookID string) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.ProjectID != projectID {
+		return nil, errors.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, projectID string, userID string) ([]*models.Webhook, error) {
+	if _, err := s.projectForOwner(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetByProject(ctx, projectID)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, projectID, webhookID string, userID string) (*models.Webhook, error) {
+	if _, err := s.projectForOwner(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.getProjectWebhook(ctx, projectID, webhookID)
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, projectID string, input models.CreateWebhookInput, userID string) (*models.CreatedWebhook, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForOwner(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		OrgID:     project.OrgID,
+		ProjectID: projectID,
+		URL:       input.URL,
+		Events:    input.Events,
+		Secret:    secret,
+		Active:    input.Active == nil || *input.Active,
+		CreatedBy: userID,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.WebhookCreated{Webhook: *webhook})
+
+	return &models.CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, projectID, webhookID string, input models.UpdateWebhookInput, userID string) (*models.Webhook, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidInput, err)
+	}
+
+	project, err := s.projectForOwner(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.getProjectWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	previous := *webhook
+
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+	if input.Events != nil {
+		webhook.Events = *input.Events
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, userID, events.WebhookUpdated{Webhook: *webhook, Previous: previous})
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, projectID, webhookID string, userID string) error {
+	project, err := s.projectForOwner(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if err := project.CheckWritable(); err != nil {
+		return err
+	}
+
+	webhook, err := s.getProjectWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	s.bus.Publish(ctx, userID, events.WebhookDeleted{Webhook: *webhook})
+	if err := s.deliveryRepo.DeleteByWebhook(ctx, webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, projectID, webhookID string, userID string, q pagination.Query) (*pagination.Page[*models.WebhookDelivery], error) {
+	if q.Filter.Status != "" && !models.WebhookDeliveryStatus(q.Filter.Status).IsValid() {
+		return nil, fmt.Errorf("%w: invalid delivery status: %s", errors.ErrInvalidInput, q.Filter.Status)
+	}
+	if _, err := s.GetWebhook(ctx, projectID, webhookID, userID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.FindPageByWebhook(ctx, webhookID, q)
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, projectID, webhookID, deliveryID string, userID string) (*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(ctx, projectID, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, errors.ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, projectID, webhookID, deliveryID string, userID string) (*models.WebhookDelivery, error) {
+	original, err := s.GetDelivery(ctx, projectID, webhookID, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	delivery := &models.WebhookDelivery{
+		OrgID:         original.OrgID,
+		WebhookID:     original.WebhookID,
+		ProjectID:     original.ProjectID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		RedeliveryOf:  original.ID,
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	s.signal()
+	return delivery, nil
+}
+
+func (s *webhookService) Dispatch(ctx context.Context, env events.Envelope, project *models.Project, event models.WebhookEvent, data interface{}) error {
+	webhooks, err := s.webhookRepo.GetSubscribed(ctx, project.ID, event)
+	if err != nil {
+		return fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	payload := models.WebhookPayload{
+		ID:         webhookEventID(env, event),
+		Event:      event,
+		OccurredAt: env.OccurredAt,
+		OrgID:      project.OrgID,
+		ProjectID:  project.ID,
+		ActorID:    env.ActorID,
+		Data:       data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		delivery := &models.WebhookDelivery{
+			OrgID:         project.OrgID,
+			WebhookID:     webhook.ID,
+			ProjectID:     project.ID,
+			EventID:       payload.ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.signal()
+	return stderrors.Join(errs...)
+}
+
+func (s *webhookService) Subscribe(bus *events.Bus) {
+	events.On(bus, "webhooks", func(ctx context.Context, env events.Envelope, event events.DocumentStatusChanged) error {
+		project, err := s.projectRepo.GetByID(ctx, event.Document.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		change := models.DocumentStatusChange{
+			ID:             event.Document.ID,
+			Title:          event.Document.Title,
+			Status:         event.Document.Status,
+			PreviousStatus: event.PreviousStatus,
+		}
+		err = s.Dispatch(ctx, env, project, models.WebhookDocumentStatusChanged, change)
+		if event.Document.Status == models.DocumentStatusApproved {
+			err = stderrors.Join(err, s.Dispatch(ctx, env, project, models.WebhookDocumentApproved, change))
+		}
+		return err
+	})
+
+	events.On(bus, "webhooks", func(ctx context.Context, env events.Envelope, event events.ProjectStatusChanged) error {
+		return s.Dispatch(ctx, env, &event.Project, models.WebhookProjectStatusChanged, models.ProjectStatusChange{
+			ID:             event.Project.ID,
+			Name:           event.Project.Name,
+			Status:         event.Project.Status,
+			PreviousStatus: event.PreviousStatus,
+		})
+	})
+}
+
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	var (
+		attempted atomic.Int64
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      []error
+	)
+	for i := 0; i < webhookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				delivery, err := s.deliveryRepo.ClaimDue(ctx, s.now(), webhookLease)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("failed to claim webhook delivery: %w", err))
+					mu.Unlock()
+					return
+				}
+				if delivery == nil {
+					return
+				}
+
+				s.deliver(ctx, delivery)
+				attempted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(attempted.Load()), stderrors.Join(errs...)
+}
+
+// deliver makes one attempt at a delivery and records its outcome
+func (s *webhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	attempt, retry := s.attempt(ctx, delivery)
+	if retry {
+		delivery.Record(attempt)
+	} else {
+		delivery.Abandon(attempt)
+	}
+	if err := s.deliveryRepo.RecordAttempt(ctx, delivery); err != nil {
+		log.Printf("Failed to record attempt of webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (s *webhookService) RunDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverDue(ctx); err != nil {
+			log.Printf("Webhook delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// signal wakes the dispatcher without waiting for it
+func (s *webhookService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// attempt sends a delivery once and describes the outcome. It reports
+// whether a failed attempt is worth retrying.
+func (s *webhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) (attempt models.WebhookAttempt, retry bool) {
+	started := s.now()
+	attempt.At = started
+	defer func() {
+		attempt.DurationMs = s.now().Sub(started).Milliseconds()
+	}()
+
+	webhook, err := s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, !stderrors.Is(err, errors.ErrWebhookNotFound)
+	}
+	if !webhook.Active {
+		attempt.Error = "webhook is inactive"
+		return attempt, false
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ProjectNexus-Webhooks/1.0")
+	req.Header.Set("X-ProjectNexus-Event", string(delivery.Event))
+	req.Header.Set("X-ProjectNexus-Delivery", delivery.ID)
+	req.Header.Set(webhooksig.Header, webhooksig.Sign([]byte(webhook.Secret), started, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, true
+	}
+	defer resp.Body.Close()
+
+	attempt.ResponseCode = resp.StatusCode
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseLimit))
+	return attempt, true
+}
+
+// webhookEventID identifies a webhook event by the domain event that caused
+// it. One domain event can cause several webhook events, e.g. an approval
+// causes both document.status_changed and document.approved.
+func webhookEventID(env events.Envelope, event models.WebhookEvent) string {
+	return env.ID + "-" + string(event)
+}
+
+func newWebhookSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b[:]), nil
+}
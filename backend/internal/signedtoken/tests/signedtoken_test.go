@@ -0,0 +1,44 @@
+// internal/signedtoken/signedtoken_test.go
+package tests
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/signedtoken"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := signedtoken.Sign(secret, "invite", "65f1c0ffee0000000000beef", now.Add(time.Hour))
+
+	subject, err := signedtoken.Verify(secret, "invite", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "65f1c0ffee0000000000beef", subject)
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		purpose string
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "expired", secret: secret, purpose: "invite", token: token, now: now.Add(2 * time.Hour), wantErr: errs.ErrTokenExpired},
+		{name: "other secret", secret: []byte("other"), purpose: "invite", token: token, now: now, wantErr: errs.ErrInvalidToken},
+		{name: "other purpose", secret: secret, purpose: "share", token: token, now: now, wantErr: errs.ErrInvalidToken},
+		{name: "tampered", secret: secret, purpose: "invite", token: "x" + token, now: now, wantErr: errs.ErrInvalidToken},
+		{name: "garbage", secret: secret, purpose: "invite", token: "garbage", now: now, wantErr: errs.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signedtoken.Verify(tt.secret, tt.purpose, tt.token, tt.now)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package tenant internal/tenant/tenant.go
+//
+// A tenant is the organization a request acts in. The tenant middleware puts
+// it on the request context and repositories scope every query to it.
+// Contexts without a tenant see nothing; background jobs and other work that
+// spans organizations opt in with Unscoped.
+package tenant
+
+import (
+	"context"
+	"projectnexus/internal/models"
+)
+
+type contextKey struct{}
+
+// Scope is the organization a request acts in and the user's role there
+type Scope struct {
+	OrgID string
+	Role  models.OrgRole
+
+	// all is set by Unscoped
+	all bool
+}
+
+// WithOrg returns a context scoped to the organization
+func WithOrg(ctx context.Context, orgID string, role models.OrgRole) context.Context {
+	return context.WithValue(ctx, contextKey{}, Scope{OrgID: orgID, Role: role})
+}
+
+// FromContext returns the scope of a context, if it has one
+func FromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(contextKey{}).(Scope)
+	return scope, ok && scope.OrgID != ""
+}
+
+// OrgID returns the organization of a scoped context, or "" if it has none
+func OrgID(ctx context.Context) string {
+	scope, _ := FromContext(ctx)
+	return scope.OrgID
+}
+
+// IsGuest reports whether the context acts as a guest of its organization.
+// Guests only work in the projects and teams they were added to.
+func IsGuest(ctx context.Context) bool {
+	scope, ok := FromContext(ctx)
+	return ok && scope.Role == models.OrgRoleGuest
+}
+
+// Unscoped returns a context that sees every organization, for background
+// jobs and for work that is authorized by other means, such as a signed
+// invitation token
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, Scope{all: true})
+}
+
+// IsUnscoped reports whether the context was opted in to every organization
+func IsUnscoped(ctx context.Context) bool {
+	scope, _ := ctx.Value(contextKey{}).(Scope)
+	return scope.all
+}
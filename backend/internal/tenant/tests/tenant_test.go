@@ -0,0 +1,34 @@
+// internal/tenant/tenant_test.go
+package tests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"projectnexus/internal/models"
+	"projectnexus/internal/tenant"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	ctx := context.Background()
+	_, ok := tenant.FromContext(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, "", tenant.OrgID(ctx))
+	assert.False(t, tenant.IsGuest(ctx))
+
+	guest := tenant.WithOrg(ctx, "org1", models.OrgRoleGuest)
+	scope, ok := tenant.FromContext(guest)
+	assert.True(t, ok)
+	assert.Equal(t, tenant.Scope{OrgID: "org1", Role: models.OrgRoleGuest}, scope)
+	assert.True(t, tenant.IsGuest(guest))
+	assert.False(t, tenant.IsGuest(tenant.WithOrg(ctx, "org1", models.OrgRoleMember)))
+
+	// Only contexts opted in to it see every organization
+	assert.False(t, tenant.IsUnscoped(ctx))
+	assert.False(t, tenant.IsUnscoped(guest))
+	unscoped := tenant.Unscoped(guest)
+	assert.Equal(t, "", tenant.OrgID(unscoped))
+	assert.False(t, tenant.IsGuest(unscoped))
+	assert.True(t, tenant.IsUnscoped(unscoped))
+	assert.False(t, tenant.IsUnscoped(tenant.WithOrg(unscoped, "org1", models.OrgRoleMember)))
+}
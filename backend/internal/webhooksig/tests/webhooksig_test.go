@@ -0,0 +1,44 @@
+// internal/webhooksig/webhooksig_test.go
+package tests
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	errs "projectnexus/internal/errors"
+	"projectnexus/internal/webhooksig"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := []byte(`{"event":"document.approved"}`)
+	sentAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	header := webhooksig.Sign(secret, sentAt, body)
+
+	assert.Regexp(t, `^t=1709294400,v1=[0-9a-f]{64}$`, header)
+	require.NoError(t, webhooksig.Verify(secret, header, body, sentAt.Add(time.Minute), 5*time.Minute))
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr error
+	}{
+		{name: "too old", secret: secret, header: header, body: body, now: sentAt.Add(time.Hour), wantErr: errs.ErrTokenExpired},
+		{name: "other secret", secret: []byte("other"), header: header, body: body, now: sentAt, wantErr: errs.ErrInvalidToken},
+		{name: "tampered body", secret: secret, header: header, body: []byte(`{"event":"project.status_changed"}`), now: sentAt, wantErr: errs.ErrInvalidToken},
+		{name: "replayed with new time", secret: secret, header: "t=1709298000," + header[len("t=1709294400,"):], body: body, now: sentAt, wantErr: errs.ErrInvalidToken},
+		{name: "garbage", secret: secret, header: "garbage", body: body, now: sentAt, wantErr: errs.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhooksig.Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
@@ -7,6 +7,10 @@ const nextConfig: NextConfig = {
         ignoreDuringBuilds: true,
     },
     output: 'standalone',
+    images: {
+        // Team avatars on the presentation page
+        remotePatterns: [{ protocol: 'https', hostname: 'www.gravatar.com', pathname: '/avatar/**' }],
+    },
     // Removed experimental features temporarily
     webpack: (config, { isServer }) => {
         // Add polling for development
@@ -2,7 +2,7 @@
 
 'use client';
 import { useState } from 'react';
-import { useRouter } from 'next/navigation';
+import { useRouter, useSearchParams } from 'next/navigation';
 import Link from 'next/link';
 import { useAuth } from '@/lib/context/auth';
 
@@ -14,6 +14,7 @@ export default function RegisterPage() {
     const [error, setError] = useState('');
     const [isLoading, setIsLoading] = useState(false);
     const router = useRouter();
+    const invitationToken = useSearchParams().get('invitation') ?? undefined;
     const { register } = useAuth();
 
     const validateForm = () => {
@@ -54,7 +55,7 @@ export default function RegisterPage() {
 
         setIsLoading(true);
         try {
-            await register(email, password, name);
+            await register(email, password, name, invitationToken);
             router.push('/dashboard');
         } catch (err) {
             setError(err instanceof Error ? err.message : 'Failed to register');
@@ -7,70 +7,54 @@ import { ActivityFeed } from '@/components/dashboard/ActivityFeed';
 import { QuickActions } from '@/components/dashboard/QuickActions';
 import { ProjectOverview } from '@/components/dashboard/ProjectOverview';
 import { useRouter } from 'next/navigation';
-import { StatsCard, Activity, QuickAction, ProjectSummary } from '@/types/dashboard';
+import { Stats, StatsCard, QuickAction, ProjectSummary } from '@/types/dashboard';
+import { useActivity } from '@/lib/hooks/use-activity';
+import { useStats } from '@/lib/hooks/use-stats';
+import { describeWindow } from '@/lib/api/stats';
 import { FileText, Users, Clock, Award, Plus, UserPlus, FileText as FileTextIcon } from 'lucide-react';
 
-const statsCards: StatsCard[] = [
-    {
-        title: 'Active Projects',
-        value: '12',
-        description: 'Projects in progress',
-        icon: FileText,
-        trend: '+2 this week',
-        trendDirection: 'up'
-    },
-    {
-        title: 'Team Members',
-        value: '24',
-        description: 'Across all projects',
-        icon: Users,
-        trend: '+3 this month',
-        trendDirection: 'up'
-    },
-    {
-        title: 'Recent Updates',
-        value: '48',
-        description: 'In the last 7 days',
-        icon: Clock,
-        trend: '15% increase',
-        trendDirection: 'up'
-    },
-    {
-        title: 'Completed Projects',
-        value: '32',
-        description: 'Successfully delivered',
-        icon: Award,
-        trend: '+5 this quarter',
-        trendDirection: 'up'
-    }
-];
+// buildStatsCards turns the statistics of the window asked for into the cards
+function buildStatsCards(stats: Stats | null): StatsCard[] {
+    const projects = stats?.projectsByStatus ?? [];
+    const completed = projects.find((p) => p.key === 'Completed')?.count ?? 0;
+    const active = projects.reduce((total, p) => total + p.count, 0) - completed;
+    const summary = stats?.summary;
 
-const recentActivity: Activity[] = [
-    {
-        id: 1,
-        action: "Updated high-level design document",
-        project: "E-commerce Platform",
-        time: "2 hours ago",
-        user: "Alex Morrison",
-        type: 'document'
-    },
-    {
-        id: 2,
-        action: "Created new project",
-        project: "Mobile App Redesign",
-        time: "5 hours ago",
-        user: "Sarah Chen",
-        type: 'project'
-    },
-    {
-        id: 3,
-        action: "Added team member",
-        project: "API Integration",
-        time: "1 day ago",
-        user: "Michael Scott",
-        type: 'team'
-    }
-];
+    return [
+        {
+            title: 'Active Projects',
+            value: String(active),
+            description: 'Projects in progress',
+            icon: FileText,
+            trend: `${summary?.documentsCreated ?? 0} new documents`,
+            trendDirection: 'up'
+        },
+        {
+            title: 'Team Members',
+            value: String(summary?.teamMembers ?? 0),
+            description: 'Across all projects',
+            icon: Users,
+            trend: `${stats?.contributions.length ?? 0} contributing`,
+            trendDirection: 'up'
+        },
+        {
+            title: 'Recent Updates',
+            value: String(summary?.edits ?? 0),
+            description: stats ? describeWindow(stats.window) : '',
+            icon: Clock,
+            trend: `${summary?.documents ?? 0} documents in total`,
+            trendDirection: 'up'
+        },
+        {
+            title: 'Completed Projects',
+            value: String(completed),
+            description: 'Successfully delivered',
+            icon: Award,
+            trend: `${summary?.approvals ?? 0} documents approved`,
+            trendDirection: 'up'
+        }
+    ];
+}
 
 const projectSummaries: ProjectSummary[] = [
     {
@@ -79,12 +63,6 @@ const projectSummaries: ProjectSummary[] = [
         trendValue: "+2",
         trendLabel: "from last month"
     },
-    {
-        label: "Pending Reviews",
-        value: "5 documents",
-        trendValue: "-1",
-        trendLabel: "from last week"
-    },
     {
         label: "Upcoming Deadlines",
         value: "3 this week",
@@ -96,6 +74,18 @@ const projectSummaries: ProjectSummary[] = [
 export default function DashboardPage() {
     const router = useRouter();
     const { user } = useAuth();
+    const { activities, isLoading: isActivityLoading } = useActivity();
+    const { stats } = useStats();
+    const statsCards = buildStatsCards(stats);
+    const summaries: ProjectSummary[] = [
+        projectSummaries[0],
+        {
+            label: "Pending Reviews",
+            value: `${stats?.summary.pendingReviews ?? 0} documents`,
+            trendLabel: "awaiting approval"
+        },
+        ...projectSummaries.slice(1),
+    ];
 
     const quickActions: QuickAction[] = [
         {
@@ -135,11 +125,11 @@ export default function DashboardPage() {
 
             <div className="grid grid-cols-1 lg:grid-cols-2 gap-6">
                 {/* Activity Feed */}
-                <ActivityFeed activities={recentActivity} />
+                <ActivityFeed activities={activities} isLoading={isActivityLoading} />
 
                 <div className="space-y-6">
                     {/* Project Overview */}
-                    <ProjectOverview summaries={projectSummaries} />
+                    <ProjectOverview summaries={summaries} />
 
                     {/* Quick Actions */}
                     <QuickActions actions={quickActions} />
@@ -11,35 +11,53 @@ import MockupsGrid from '@/components/present/MockupsGrid';
 import TeamGrid from '@/components/present/TeamGrid';
 import ShareDialog from '@/components/present/ShareDialog';
 import DocumentViewer from '@/components/present/DocumentViewer';
-import { useProjects } from '@/lib/hooks/use-projects';
-import { useDocuments } from '@/lib/hooks/use-documents';
-import { useTeams } from '@/lib/hooks/use-teams';
+import { usePresentation } from '@/lib/hooks/use-presentation';
+import { useActivity } from '@/lib/hooks/use-activity';
+import { useStats } from '@/lib/hooks/use-stats';
 import { useToast } from '@/lib/hooks/use-toast';
 import {
-    APIDocument,
+    Presentation,
     PresentDocument,
     PresentTeamMember,
     ViewableItem,
-    Mockup
+    Mockup,
+    TimelineEvent
 } from '@/types/types';
 // Type guards
 const isDocument = (item: ViewableItem): item is PresentDocument => 'lastModified' in item;
 const isMockup = (item: ViewableItem): item is Mockup => 'tool' in item;
 
 // Mapping functions
-const mapAPIDocumentToPresent = (doc: APIDocument): PresentDocument => ({
-    id: Number(doc.id),
+const mapDocumentToPresent = (doc: Presentation['documents'][number]): PresentDocument => ({
+    id: doc.id,
     type: doc.type || 'Document',
     title: doc.title,
     lastModified: doc.updatedAt,
-    preview: '/api/placeholder/400/200'
+    preview: '/api/placeholder/400/200',
+    excerpt: doc.preview
 });
 
-const mapTeamMemberToPresent = (member: { id: string; name: string; role: string }): PresentTeamMember => ({
-    id: Number(member.id),
+const mapMockupToPresent = (mockup: Presentation['mockups'][number]): Mockup => ({
+    id: mockup.id,
+    title: mockup.name,
+    type: mockup.type,
+    tool: mockup.tool,
+    preview: mockup.thumbnail || '/api/placeholder/400/200'
+});
+
+const mapTeamMemberToPresent = (member: Presentation['team'][number]): PresentTeamMember => ({
+    id: member.userId,
     name: member.name,
     role: member.role,
-    avatar: `/api/placeholder/32/32`
+    initials: member.initials
+});
+
+const mapTimelineEventToPresent = (event: Presentation['timeline'][number]): TimelineEvent => ({
+    id: event.id,
+    title: event.title,
+    date: event.date,
+    status: event.status,
+    assignee: event.assigneeName
 });
 
 interface ProjectPresentationProps {
@@ -53,22 +71,19 @@ export default function ProjectPresentation({ id }: ProjectPresentationProps) {
     const [showShareDialog, setShowShareDialog] = useState<boolean>(false);
     const [selectedItem, setSelectedItem] = useState<ViewableItem | null>(null);
 
-    // Data fetching hooks
-    const { projects, isLoading: projectLoading } = useProjects();
-    const { documents, isLoading: documentsLoading } = useDocuments();
-    const { teams, isLoading: teamLoading } = useTeams();
+    // The project, its team, approved documents, mockups and timeline
+    const { presentation, isLoading } = usePresentation(id);
+    const { activities, isLoading: activityLoading } = useActivity(id);
+    const { stats } = useStats(id);
 
-    // Find current project and related data
-    const project = projects.find((p) => p.id === id);
-    const projectDocuments = documents
-        .filter((d) => d.projectId === id)
-        .map(mapAPIDocumentToPresent);
-    const teamMembers = teams
-        .flatMap((team) => team.members)
-        .map(mapTeamMemberToPresent);
+    const project = presentation?.project;
+    const projectDocuments = presentation?.documents.map(mapDocumentToPresent) ?? [];
+    const projectMockups = presentation?.mockups.map(mapMockupToPresent) ?? [];
+    const teamMembers = presentation?.team.map(mapTeamMemberToPresent) ?? [];
+    const timeline = presentation?.timeline.map(mapTimelineEventToPresent) ?? [];
 
     // Loading state
-    if (projectLoading || documentsLoading || teamLoading) {
+    if (isLoading) {
         return (
             <div className="flex items-center justify-center h-96">
                 <div className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600"></div>
@@ -136,8 +151,8 @@ export default function ProjectPresentation({ id }: ProjectPresentationProps) {
                 thumbnail={`/api/placeholder/800/400`}
                 name={project.name}
                 status={project.status}
-                startDate={project.startDate}
-                endDate={project.endDate}
+                startDate={project.startDate ?? ''}
+                endDate={project.endDate ?? ''}
                 onShare={handleShare}
                 onDownload={handleDownload}
             />
@@ -153,7 +168,11 @@ export default function ProjectPresentation({ id }: ProjectPresentationProps) {
                         description={project.description}
                         progress={project.progress}
                         documents={projectDocuments}
-                        mockups={[]}
+                        mockups={projectMockups}
+                        timeline={timeline}
+                        stats={stats}
+                        activity={activities}
+                        activityLoading={activityLoading}
                         onDocumentClick={handleDocumentClick}
                         onMockupClick={handleMockupClick}
                     />
@@ -168,7 +187,7 @@ export default function ProjectPresentation({ id }: ProjectPresentationProps) {
 
                 {activeSection === 'mockups' && (
                     <MockupsGrid
-                        mockups={[]}
+                        mockups={projectMockups}
                         onMockupClick={handleMockupClick}
                     />
                 )}
@@ -182,7 +201,10 @@ export default function ProjectPresentation({ id }: ProjectPresentationProps) {
             </div>
 
             <ShareDialog
+                projectId={project.id}
                 projectName={project.name}
+                documents={projectDocuments}
+                mockups={projectMockups}
                 isOpen={showShareDialog}
                 onClose={() => setShowShareDialog(false)}
             />
@@ -1,21 +1,14 @@
 ﻿// src/components/dashboard/ActivityFeed.tsx
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
-
-interface Activity {
-    id: number;
-    action: string;
-    project: string;
-    time: string;
-    user: string;
-    type?: 'document' | 'project' | 'team';
-}
+import type { Activity } from '@/types/dashboard';
 
 interface ActivityFeedProps {
     activities: Activity[];
     title?: string;
+    isLoading?: boolean;
 }
 
-export const ActivityFeed = ({ activities, title = "Recent Activity" }: ActivityFeedProps) => {
+export const ActivityFeed = ({ activities, title = "Recent Activity", isLoading = false }: ActivityFeedProps) => {
     return (
         <Card>
             <CardHeader>
@@ -23,6 +16,9 @@ export const ActivityFeed = ({ activities, title = "Recent Activity" }: Activity
             </CardHeader>
             <CardContent>
                 <div className="space-y-4">
+                    {!isLoading && activities.length === 0 && (
+                        <p className="text-sm text-gray-500">No activity yet</p>
+                    )}
                     {activities.map((activity) => (
                         <div key={activity.id}
                              className="flex items-center justify-between py-3 hover:bg-gray-50 rounded-lg px-3 transition-colors">
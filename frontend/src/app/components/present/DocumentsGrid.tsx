@@ -42,6 +42,9 @@ export default function DocumentsGrid({ documents, onDocumentClick }: DocumentsG
                     <CardContent className="p-4">
                         <h3 className="font-medium mb-1">{doc.title}</h3>
                         <p className="text-sm text-gray-500">{doc.type}</p>
+                        {doc.excerpt && (
+                            <p className="text-sm text-gray-600 mt-2 line-clamp-3">{doc.excerpt}</p>
+                        )}
                         <p className="text-sm text-gray-500 mt-2">
                             Last modified: {new Date(doc.lastModified).toLocaleDateString()}
                         </p>
@@ -21,6 +21,8 @@ export default function EnhancedTimeline({ events, onEventClick }: EnhancedTimel
                 return <CheckCircle className="h-4 w-4 text-green-500" />;
             case 'current':
                 return <Clock className="h-4 w-4 text-blue-500" />;
+            case 'overdue':
+                return <AlertCircle className="h-4 w-4 text-red-500" />;
             case 'upcoming':
                 return <AlertCircle className="h-4 w-4 text-gray-400" />;
         }
@@ -32,6 +34,8 @@ export default function EnhancedTimeline({ events, onEventClick }: EnhancedTimel
                 return 'border-green-200 hover:bg-green-50';
             case 'current':
                 return 'border-blue-200 hover:bg-blue-50';
+            case 'overdue':
+                return 'border-red-200 hover:bg-red-50';
             case 'upcoming':
                 return 'border-gray-200 hover:bg-gray-50';
         }
@@ -1,154 +1,96 @@
 ﻿import React from 'react';
 import {
     BookOpen, Layout, FileText, ImageIcon, ChevronRight,
-    TrendingUp, Users, Clock, Target, Calendar, MessageSquare
+    TrendingUp, Users, Clock, Target
 } from 'lucide-react';
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
 import EnhancedTimeline from './EnhancedTimeline';
-import { Document, Mockup, TimelineEvent, ProjectMetric, ActivityItem } from '@/types/present';
+import { Document, Mockup, TimelineEvent, ProjectMetric } from '@/types/present';
+import type { Activity, Stats } from '@/types/dashboard';
+import { describeWindow } from '@/lib/api/stats';
 
 interface ProjectOverviewProps {
     description: string;
     progress: number;
     documents: Document[];
     mockups: Mockup[];
+    timeline?: TimelineEvent[];
+    // stats are the project's metrics; the cards show zeros until they load
+    stats?: Stats | null;
+    // activity is the project's activity feed, latest first
+    activity?: Activity[];
+    activityLoading?: boolean;
     onDocumentClick?: (doc: Document) => void;
     onMockupClick?: (mockup: Mockup) => void;
 }
 
-// Example metrics data
-const metrics: ProjectMetric[] = [
-    {
-        category: 'Progress',
-        value: '65%',
-        trend: '+10% this month',
-        trendValue: 10,
-        icon: TrendingUp,
-        chartData: [
-            { date: 'Jan', value: 20 },
-            { date: 'Feb', value: 35 },
-            { date: 'Mar', value: 45 },
-            { date: 'Apr', value: 65 },
-        ]
-    },
-    {
-        category: 'Team Members',
-        value: '12',
-        trend: '+2 this week',
-        trendValue: 2,
-        icon: Users,
-    },
-    {
-        category: 'Time Spent',
-        value: '320h',
-        trend: '40h this week',
-        trendValue: 40,
-        icon: Clock,
-    },
-    {
-        category: 'Milestones',
-        value: '8/12',
-        trend: '2 upcoming',
-        trendValue: 0,
-        icon: Target,
-    }
-];
+// buildMetrics turns the project's progress and metrics into the cards
+function buildMetrics(progress: number, stats?: Stats | null): ProjectMetric[] {
+    const summary = stats?.summary;
+    const leadTime = stats?.approvalLeadTime;
+    const period = stats ? describeWindow(stats.window).toLowerCase() : '';
 
-// Example timeline data
-const timelineEvents: TimelineEvent[] = [
-    {
-        id: 1,
-        title: 'Project Kickoff',
-        date: '2024-01-01',
-        status: 'completed',
-        assignee: 'Sarah Chen',
-        comments: 5,
-        subEvents: [
-            { id: 101, title: 'Team Assembly', status: 'completed' },
-            { id: 102, title: 'Initial Planning', status: 'completed' },
-        ]
-    },
-    {
-        id: 2,
-        title: 'Design Phase',
-        date: '2024-02-01',
-        status: 'completed',
-        assignee: 'Alex Morrison',
-        comments: 8,
-        subEvents: [
-            { id: 201, title: 'UI/UX Design', status: 'completed' },
-            { id: 202, title: 'Design Review', status: 'completed' },
-        ]
-    },
-    {
-        id: 3,
-        title: 'Development Sprint 1',
-        date: '2024-03-01',
-        status: 'current',
-        assignee: 'Michael Scott',
-        comments: 3,
-        subEvents: [
-            { id: 301, title: 'Frontend Setup', status: 'completed' },
-            { id: 302, title: 'Backend APIs', status: 'pending' },
-        ]
-    },
-    {
-        id: 4,
-        title: 'User Testing',
-        date: '2024-04-01',
-        status: 'upcoming',
-        assignee: 'Sarah Chen',
-        subEvents: [
-            { id: 401, title: 'Beta Testing', status: 'pending' },
-            { id: 402, title: 'Feedback Collection', status: 'pending' },
-        ]
-    },
-];
-
-// Example activity data
-const recentActivity: ActivityItem[] = [
-    {
-        id: 1,
-        type: 'update',
-        message: 'Sarah Chen updated the design mockups',
-        timestamp: '2h ago',
-        user: 'Sarah Chen'
-    },
-    {
-        id: 2,
-        type: 'comment',
-        message: 'New comments on API documentation',
-        timestamp: '5h ago'
-    },
-    {
-        id: 3,
-        type: 'meeting',
-        message: 'Team meeting scheduled for tomorrow',
-        timestamp: '1d ago'
-    }
-];
+    return [
+        {
+            category: 'Progress',
+            value: `${progress}%`,
+            trend: `${summary?.approvals ?? 0} approved ${period}`,
+            trendValue: summary?.approvals ?? 0,
+            icon: TrendingUp,
+            chartData: stats?.trends.approvals,
+        },
+        {
+            category: 'Team Members',
+            value: String(summary?.teamMembers ?? 0),
+            trend: `${stats?.contributions.length ?? 0} contributing`,
+            trendValue: stats?.contributions.length ?? 0,
+            icon: Users,
+        },
+        {
+            category: 'Edits',
+            value: String(summary?.edits ?? 0),
+            trend: `${summary?.pendingReviews ?? 0} awaiting review`,
+            trendValue: summary?.edits ?? 0,
+            icon: Clock,
+            chartData: stats?.editsPerWeek,
+        },
+        {
+            category: 'Approval Time',
+            value: leadTime?.approvals ? `${Math.round(leadTime.averageHours)}h` : '-',
+            trend: `${leadTime?.approvals ?? 0} approvals ${period}`,
+            trendValue: leadTime?.approvals ?? 0,
+            icon: Target,
+        }
+    ];
+}
 
 export default function ProjectOverview({
                                             description,
                                             progress,
                                             documents,
                                             mockups,
+                                            timeline = [],
+                                            stats,
+                                            activity = [],
+                                            activityLoading = false,
                                             onDocumentClick,
                                             onMockupClick,
                                         }: ProjectOverviewProps) {
+    const metrics = buildMetrics(progress, stats);
+
     const handleTimelineEventClick = (event: TimelineEvent) => {
         console.log('Timeline event clicked:', event);
     };
 
-    const getActivityIcon = (type: ActivityItem['type']) => {
+    const getActivityIcon = (type: Activity['type']) => {
         switch (type) {
-            case 'update':
-                return <Users className="h-4 w-4 mr-2 text-blue-500" />;
-            case 'comment':
-                return <MessageSquare className="h-4 w-4 mr-2 text-green-500" />;
-            case 'meeting':
-                return <Calendar className="h-4 w-4 mr-2 text-purple-500" />;
-            case 'milestone':
+            case 'document':
+                return <FileText className="h-4 w-4 mr-2 text-blue-500" />;
+            case 'mockup':
+                return <ImageIcon className="h-4 w-4 mr-2 text-green-500" />;
+            case 'team':
+                return <Users className="h-4 w-4 mr-2 text-purple-500" />;
+            default:
                 return <Target className="h-4 w-4 mr-2 text-yellow-500" />;
         }
     };
@@ -214,7 +156,7 @@ export default function ProjectOverview({
                 {/* Right Column - Timeline */}
                 <div className="col-span-4">
                     <EnhancedTimeline
-                        events={timelineEvents}
+                        events={timeline}
                         onEventClick={handleTimelineEventClick}
                     />
                 </div>
@@ -294,12 +236,15 @@ export default function ProjectOverview({
                         </CardHeader>
                         <CardContent className="pt-0">
                             <div className="space-y-2">
-                                {recentActivity.map((activity) => (
-                                    <div key={activity.id} className="flex items-center text-sm">
-                                        {getActivityIcon(activity.type)}
+                                {!activityLoading && activity.length === 0 && (
+                                    <p className="text-sm text-gray-500">No activity yet</p>
+                                )}
+                                {activity.slice(0, 5).map((item) => (
+                                    <div key={item.id} className="flex items-center text-sm">
+                                        {getActivityIcon(item.type)}
                                         <div className="flex-1 min-w-0">
-                                            <p className="text-sm truncate">{activity.message}</p>
-                                            <p className="text-xs text-gray-500">{activity.timestamp}</p>
+                                            <p className="text-sm truncate">{item.action}</p>
+                                            <p className="text-xs text-gray-500">{item.time}</p>
                                         </div>
                                     </div>
                                 ))}
@@ -1,22 +1,65 @@
 ﻿// components/present/ShareDialog.tsx
 import React, { useState } from 'react';
-import { X, Copy, Mail, Link } from 'lucide-react';
+import { X, Copy, Mail, Link, Lock } from 'lucide-react';
 import Button from '@/components/ui/Button';
 import Input from '@/components/ui/Input';
+import Checkbox from '@/components/ui/Checkbox';
+import { sharesApi } from '@/lib/api/shares';
+import { SHARE_LINK_MAX_ITEMS } from '@/types/share';
+
+interface ShareableItem {
+    id: string;
+    title: string;
+}
 
 interface ShareDialogProps {
+    projectId: string;
     projectName: string;
+    // documents and mockups are what can be picked for the link to show
+    // besides the overview
+    documents: ShareableItem[];
+    mockups?: ShareableItem[];
     onClose: () => void;
     isOpen: boolean;
 }
 
-export default function ShareDialog({ projectName, onClose, isOpen }: ShareDialogProps) {
+function toggle(ids: string[], id: string): string[] {
+    return ids.includes(id) ? ids.filter((other) => other !== id) : [...ids, id];
+}
+
+export default function ShareDialog({ projectId, projectName, documents, mockups = [], onClose, isOpen }: ShareDialogProps) {
     const [copied, setCopied] = useState(false);
+    const [documentIds, setDocumentIds] = useState<string[]>([]);
+    const [mockupIds, setMockupIds] = useState<string[]>([]);
     const [email, setEmail] = useState('');
+    const [password, setPassword] = useState('');
+    const [expiresOn, setExpiresOn] = useState('');
+    const [shareLink, setShareLink] = useState('');
+    const [creating, setCreating] = useState(false);
+    const [error, setError] = useState('');
 
     if (!isOpen) return null;
 
-    const shareLink = `https://projectnexus.com/projects/${projectName.toLowerCase().replace(/\s+/g, '-')}`;
+    const selected = documentIds.length + mockupIds.length;
+    const tooMany = selected > SHARE_LINK_MAX_ITEMS;
+
+    const handleCreateLink = async () => {
+        setCreating(true);
+        setError('');
+        try {
+            const link = await sharesApi.createForProject(projectId, {
+                documentIds,
+                mockupIds,
+                password: password || undefined,
+                expiresAt: expiresOn ? new Date(`${expiresOn}T23:59:59`).toISOString() : undefined,
+            });
+            setShareLink(link.url);
+        } catch (err) {
+            setError(err instanceof Error ? err.message : 'Failed to create share link');
+        } finally {
+            setCreating(false);
+        }
+    };
 
     const handleCopyLink = async () => {
         try {
@@ -48,6 +91,80 @@ export default function ShareDialog({ projectName, onClose, isOpen }: ShareDialo
                 </div>
 
                 <div className="p-4 space-y-4">
+                    {!shareLink ? (
+                        <div className="space-y-3">
+                            <p className="text-sm text-gray-600 dark:text-gray-400">
+                                Anyone with the link can view the presentation without signing in.
+                            </p>
+                            <div className="space-y-2">
+                                <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
+                                    Include
+                                </label>
+                                <div className="max-h-48 overflow-y-auto space-y-2 rounded-md border border-gray-200 dark:border-gray-700 p-2">
+                                    {documents.length === 0 && mockups.length === 0 && (
+                                        <p className="text-sm text-gray-500 dark:text-gray-400">
+                                            No documents or mockups to share yet.
+                                        </p>
+                                    )}
+                                    {documents.map((doc) => (
+                                        <Checkbox
+                                            key={doc.id}
+                                            id={`share-document-${doc.id}`}
+                                            size="sm"
+                                            label={doc.title}
+                                            description="Document"
+                                            checked={documentIds.includes(doc.id)}
+                                            onChange={() => setDocumentIds((ids) => toggle(ids, doc.id))}
+                                        />
+                                    ))}
+                                    {mockups.map((mockup) => (
+                                        <Checkbox
+                                            key={mockup.id}
+                                            id={`share-mockup-${mockup.id}`}
+                                            size="sm"
+                                            label={mockup.title}
+                                            description="Mockup"
+                                            checked={mockupIds.includes(mockup.id)}
+                                            onChange={() => setMockupIds((ids) => toggle(ids, mockup.id))}
+                                        />
+                                    ))}
+                                </div>
+                                <p className={`text-xs ${tooMany ? 'text-red-600' : 'text-gray-500 dark:text-gray-400'}`}>
+                                    {selected} of at most {SHARE_LINK_MAX_ITEMS} items selected
+                                </p>
+                            </div>
+                            <div className="space-y-2">
+                                <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
+                                    Password (optional)
+                                </label>
+                                <Input
+                                    type="password"
+                                    placeholder="At least 6 characters"
+                                    value={password}
+                                    onChange={(e) => setPassword(e.target.value)}
+                                />
+                            </div>
+                            <div className="space-y-2">
+                                <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
+                                    Expires on (optional)
+                                </label>
+                                <Input
+                                    type="date"
+                                    value={expiresOn}
+                                    onChange={(e) => setExpiresOn(e.target.value)}
+                                />
+                            </div>
+                            {error && <p className="text-sm text-red-600">{error}</p>}
+                            <Button
+                                fullWidth
+                                onClick={handleCreateLink}
+                                disabled={creating || tooMany || (password !== '' && password.length < 6)}
+                                leftIcon={<Lock className="h-4 w-4" />}
+                            >
+                                {creating ? 'Creating...' : 'Create Share Link'}
+                            </Button>
+                        </div>
+                    ) : (
                     <div className="space-y-2">
                         <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
                             Project Link
@@ -66,8 +183,14 @@ export default function ShareDialog({ projectName, onClose, isOpen }: ShareDialo
                                 {copied ? 'Copied!' : 'Copy'}
                             </Button>
                         </div>
+                        <p className="text-xs text-gray-500 dark:text-gray-400">
+                            Copy the link now; it is not shown again.
+                        </p>
                     </div>
+                    )}
 
+                    {shareLink && (
+                    <>
                     <div className="space-y-2">
                         <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
                             Share via Email
@@ -108,6 +231,8 @@ export default function ShareDialog({ projectName, onClose, isOpen }: ShareDialo
                             Share via...
                         </Button>
                     </div>
+                    </>
+                    )}
                 </div>
             </div>
         </div>
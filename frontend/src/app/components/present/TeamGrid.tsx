@@ -1,6 +1,5 @@
 ﻿// components/present/TeamGrid.tsx
 import React from 'react';
-import Image from 'next/image';
 import { Card, CardContent } from '@/components/ui/card';
 import { TeamMember }  from '@/types/present';
 
@@ -20,13 +19,12 @@ export default function TeamGrid({ team, onTeamMemberClick }: TeamGridProps) {
                 >
                     <CardContent className="p-4">
                         <div className="flex items-center space-x-4">
-                            <Image
-                                src={member.avatar}
-                                alt={`${member.name}'s avatar`}
-                                width={48}
-                                height={48}
-                                className="h-12 w-12 rounded-full object-cover"
-                            />
+                            <div
+                                aria-hidden="true"
+                                className="h-12 w-12 rounded-full bg-blue-100 text-blue-700 dark:bg-blue-900 dark:text-blue-200 flex items-center justify-center font-medium"
+                            >
+                                {member.initials}
+                            </div>
                             <div>
                                 <h3 className="font-medium">{member.name}</h3>
                                 <p className="text-sm text-gray-500">{member.role}</p>
@@ -0,0 +1,95 @@
+// app/lib/api/activity.ts
+import type { Activity } from '@/types/dashboard';
+import type { Page } from '@/types/types';
+
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
+
+interface ActivityResponse {
+    id: string;
+    projectId: string;
+    projectName: string;
+    actorId: string;
+    actorName: string;
+    type: Activity['type'];
+    verb: string;
+    message: string;
+    count: number;
+    occurredAt: string;
+}
+
+export interface ActivityPage {
+    activities: Activity[];
+    nextCursor?: string;
+}
+
+class ApiError extends Error {
+    constructor(message: string, public statusCode?: number) {
+        super(message);
+        this.name = 'ApiError';
+    }
+}
+
+// timeAgo describes how long ago something happened, e.g. "2 hours ago"
+function timeAgo(iso: string): string {
+    const seconds = Math.max(0, Math.floor((Date.now() - new Date(iso).getTime()) / 1000));
+    const units: [number, string][] = [
+        [86400, 'day'],
+        [3600, 'hour'],
+        [60, 'minute'],
+    ];
+    for (const [size, unit] of units) {
+        const count = Math.floor(seconds / size);
+        if (count > 0) {
+            return `${count} ${unit}${count === 1 ? '' : 's'} ago`;
+        }
+    }
+    return 'just now';
+}
+
+function mapActivityResponse(activity: ActivityResponse): Activity {
+    return {
+        id: activity.id,
+        action: activity.message,
+        project: activity.projectName,
+        time: timeAgo(activity.occurredAt),
+        user: activity.actorName,
+        type: activity.type,
+    };
+}
+
+async function fetchActivity(path: string, cursor?: string): Promise<ActivityPage> {
+    const token = localStorage.getItem('token');
+    if (!token) {
+        throw new ApiError('No authentication token found');
+    }
+
+    const params = new URLSearchParams({ limit: '20' });
+    if (cursor) {
+        params.set('cursor', cursor);
+    }
+    const response = await fetch(`${API_URL}${path}?${params}`, {
+        headers: {
+            'Authorization': `Bearer ${token}`,
+            'Content-Type': 'application/json'
+        }
+    });
+
+    if (!response.ok) {
+        const error = await response.json();
+        throw new ApiError(error.error || 'Failed to fetch activity', response.status);
+    }
+
+    const data: Page<ActivityResponse> = await response.json();
+    return { activities: data.items.map(mapActivityResponse), nextCursor: data.nextCursor };
+}
+
+export const activityApi = {
+    // list returns the activity in every project the user can access
+    list(cursor?: string): Promise<ActivityPage> {
+        return fetchActivity('/activity', cursor);
+    },
+
+    listForProject(projectId: string, cursor?: string): Promise<ActivityPage> {
+        return fetchActivity(`/projects/${projectId}/activity`, cursor);
+    },
+};
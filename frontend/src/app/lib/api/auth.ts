@@ -6,6 +6,8 @@ interface LoginCredentials {
 
 interface RegisterData extends LoginCredentials {
     name: string;
+    // From an invitation link; joins the new account to what it invites to
+    invitationToken?: string;
 }
 
 interface AuthResponse {
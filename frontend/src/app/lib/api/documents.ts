@@ -1,6 +1,8 @@
 // app/lib/api/documents.ts
 
 import {DocumentVersion} from "@/types/documents";
+import type {Page} from "@/types/types";
+import {fetchAllPages, pageUrl} from "./pages";
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
 
@@ -39,19 +41,22 @@ export const documentsApi = {
 
     async getAll(): Promise<Document[]> {
         const token = localStorage.getItem('token');
-        const response = await fetch(`${API_URL}/documents`, {
-            headers: {
-                'Authorization': `Bearer ${token}`,
-                'Content-Type': 'application/json',
-            },
+        return fetchAllPages(async (cursor) => {
+            const response = await fetch(pageUrl(`${API_URL}/documents`, cursor), {
+                headers: {
+                    'Authorization': `Bearer ${token}`,
+                    'Content-Type': 'application/json',
+                },
+            });
+
+            if (!response.ok) {
+                const error = await response.json();
+                throw new Error(error.message || 'Failed to fetch documents');
+            }
+
+            const data: Page<Document> = await response.json();
+            return data;
         });
-
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.message || 'Failed to fetch documents');
-        }
-
-        return response.json();
     },
 
     async getById(id: string): Promise<Document> {
@@ -108,19 +113,22 @@ export const documentsApi = {
 
     async getByProject(projectId: string): Promise<Document[]> {
         const token = localStorage.getItem('token');
-        const response = await fetch(`${API_URL}/documents/project/${projectId}`, {
-            headers: {
-                'Authorization': `Bearer ${token}`,
-                'Content-Type': 'application/json',
-            },
+        return fetchAllPages(async (cursor) => {
+            const response = await fetch(pageUrl(`${API_URL}/documents/project/${projectId}`, cursor), {
+                headers: {
+                    'Authorization': `Bearer ${token}`,
+                    'Content-Type': 'application/json',
+                },
+            });
+
+            if (!response.ok) {
+                const error = await response.json();
+                throw new Error(error.message || 'Failed to fetch project documents');
+            }
+
+            const data: Page<Document> = await response.json();
+            return data;
         });
-
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.message || 'Failed to fetch project documents');
-        }
-
-        return response.json();
     },
 
     async getVersions(id: string): Promise<DocumentVersion[]> {
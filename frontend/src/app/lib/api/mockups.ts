@@ -1,5 +1,7 @@
 // lib/api/mockups.ts
 import type { Mockup, MockupType, MockupStatus } from '@/types/mockup';
+import type { Page } from '@/types/types';
+import { fetchAllPages, pageUrl } from './pages';
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
 
@@ -97,15 +99,16 @@ export const mockupApi = {
             }];
         }
 
-        const response = await fetch(`${API_URL}/mockups`, {
-            headers: {
-                'Authorization': `Bearer ${getAuthToken()}`,
-                'Content-Type': 'application/json',
-            },
-        });
-
         try {
-            return handleResponse<Mockup[]>(response);
+            return await fetchAllPages(async (cursor) => {
+                const response = await fetch(pageUrl(`${API_URL}/mockups`, cursor), {
+                    headers: {
+                        'Authorization': `Bearer ${getAuthToken()}`,
+                        'Content-Type': 'application/json',
+                    },
+                });
+                return handleResponse<Page<Mockup>>(response);
+            });
         } catch (error) {
             console.error('Error fetching mockups:', error);
             // Return empty array instead of throwing error for better UX
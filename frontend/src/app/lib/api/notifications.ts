@@ -0,0 +1,18 @@
+// app/lib/api/notifications.ts
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
+
+export const notificationsApi = {
+    // unsubscribe turns off every email of the user named by the token of an
+    // unsubscribe link; it works without signing in
+    async unsubscribe(token: string): Promise<void> {
+        const response = await fetch(`${API_URL}/notifications/unsubscribe`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ token }),
+        });
+        if (!response.ok) {
+            const error = await response.json().catch(() => ({}));
+            throw new Error(error.error || 'Failed to unsubscribe');
+        }
+    },
+};
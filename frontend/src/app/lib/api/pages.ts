@@ -0,0 +1,27 @@
+// app/lib/api/pages.ts
+import type { Page } from '@/types/types';
+
+// PAGE_LIMIT is the largest page the API hands out
+const PAGE_LIMIT = 100;
+
+// pageUrl adds the page size and the cursor of the page to fetch to a list URL
+export function pageUrl(url: string, cursor?: string): string {
+    const params = new URLSearchParams({ limit: String(PAGE_LIMIT) });
+    if (cursor) {
+        params.set('cursor', cursor);
+    }
+    return `${url}${url.includes('?') ? '&' : '?'}${params}`;
+}
+
+// fetchAllPages follows nextCursor from the first page to the last and
+// returns the items of every page
+export async function fetchAllPages<T>(fetchPage: (cursor?: string) => Promise<Page<T>>): Promise<T[]> {
+    const items: T[] = [];
+    let cursor: string | undefined;
+    do {
+        const page = await fetchPage(cursor);
+        items.push(...page.items);
+        cursor = page.nextCursor;
+    } while (cursor);
+    return items;
+}
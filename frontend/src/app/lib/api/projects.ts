@@ -1,5 +1,7 @@
 // app/lib/api/projects.ts
 import { Project } from '@/types/project';
+import type { Page, Presentation } from '@/types/types';
+import { fetchAllPages, pageUrl } from './pages';
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
 
@@ -61,7 +63,32 @@ export const projectApi = {
             throw new ApiError('No authentication token found');
         }
 
-        const response = await fetch(`${API_URL}/projects`, {
+        const projects = await fetchAllPages(async (cursor) => {
+            const response = await fetch(pageUrl(`${API_URL}/projects`, cursor), {
+                headers: {
+                    'Authorization': `Bearer ${token}`,
+                    'Content-Type': 'application/json'
+                }
+            });
+
+            if (!response.ok) {
+                const error = await response.json();
+                throw new ApiError(error.message || 'Failed to fetch projects', response.status);
+            }
+
+            const data: Page<ProjectResponse> = await response.json();
+            return data;
+        });
+        return projects.map(mapProjectResponse);
+    },
+
+    async get(id: string): Promise<Project> {
+        const token = localStorage.getItem('token');
+        if (!token) {
+            throw new ApiError('No authentication token found');
+        }
+
+        const response = await fetch(`${API_URL}/projects/${id}`, {
             headers: {
                 'Authorization': `Bearer ${token}`,
                 'Content-Type': 'application/json'
@@ -70,20 +97,21 @@ export const projectApi = {
 
         if (!response.ok) {
             const error = await response.json();
-            throw new ApiError(error.message || 'Failed to fetch projects', response.status);
+            throw new ApiError(error.message || 'Failed to fetch project', response.status);
         }
 
-        const data: ProjectResponse[] = await response.json();
-        return data.map(mapProjectResponse);
+        const data: ProjectResponse = await response.json();
+        return mapProjectResponse(data);
     },
 
-    async get(id: string): Promise<Project> {
+    // getPresentation loads everything the presentation page shows at once
+    async getPresentation(id: string): Promise<Presentation> {
         const token = localStorage.getItem('token');
         if (!token) {
             throw new ApiError('No authentication token found');
         }
 
-        const response = await fetch(`${API_URL}/projects/${id}`, {
+        const response = await fetch(`${API_URL}/projects/${id}/presentation`, {
             headers: {
                 'Authorization': `Bearer ${token}`,
                 'Content-Type': 'application/json'
@@ -92,11 +120,10 @@ export const projectApi = {
 
         if (!response.ok) {
             const error = await response.json();
-            throw new ApiError(error.message || 'Failed to fetch project', response.status);
+            throw new ApiError(error.error || 'Failed to fetch presentation', response.status);
         }
 
-        const data: ProjectResponse = await response.json();
-        return mapProjectResponse(data);
+        return response.json();
     },
 
     async create(input: CreateProjectInput): Promise<Project> {
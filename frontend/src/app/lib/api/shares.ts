@@ -0,0 +1,60 @@
+// app/lib/api/shares.ts
+import type { CreatedShareLink, CreateShareLinkInput, ShareLink, SharedView } from '@/types/share';
+
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
+
+export class ShareApiError extends Error {
+    constructor(message: string, public statusCode?: number, public passwordRequired?: boolean) {
+        super(message);
+        this.name = 'ShareApiError';
+    }
+}
+
+async function request<T>(path: string, init: RequestInit = {}, authenticated = true): Promise<T> {
+    const headers: Record<string, string> = {
+        'Content-Type': 'application/json',
+        ...(init.headers as Record<string, string>),
+    };
+    if (authenticated) {
+        const token = localStorage.getItem('token');
+        if (!token) {
+            throw new ShareApiError('No authentication token found');
+        }
+        headers['Authorization'] = `Bearer ${token}`;
+    }
+
+    const response = await fetch(`${API_URL}${path}`, { ...init, headers });
+    if (!response.ok) {
+        const error = await response.json().catch(() => ({}));
+        throw new ShareApiError(error.error || 'Share link request failed', response.status, error.passwordRequired);
+    }
+
+    return response.json();
+}
+
+export const sharesApi = {
+    list(projectId: string): Promise<ShareLink[]> {
+        return request(`/projects/${projectId}/shares`);
+    },
+
+    createForProject(projectId: string, input: CreateShareLinkInput): Promise<CreatedShareLink> {
+        return request(`/projects/${projectId}/shares`, { method: 'POST', body: JSON.stringify(input) });
+    },
+
+    createForDocument(documentId: string, input: CreateShareLinkInput): Promise<CreatedShareLink> {
+        return request(`/documents/${documentId}/shares`, { method: 'POST', body: JSON.stringify(input) });
+    },
+
+    revoke(projectId: string, shareId: string): Promise<ShareLink> {
+        return request(`/projects/${projectId}/shares/${shareId}`, { method: 'DELETE' });
+    },
+
+    // view opens a share link without signing in
+    view(token: string, password?: string): Promise<SharedView> {
+        return request(
+            `/public/shares/${encodeURIComponent(token)}`,
+            { headers: password ? { 'X-Share-Password': password } : {} },
+            false
+        );
+    },
+};
@@ -0,0 +1,57 @@
+// app/lib/api/stats.ts
+import type { Stats, StatsWindow } from '@/types/dashboard';
+
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
+
+class ApiError extends Error {
+    constructor(message: string, public statusCode?: number) {
+        super(message);
+        this.name = 'ApiError';
+    }
+}
+
+async function fetchStats(path: string, window: StatsWindow): Promise<Stats> {
+    const token = localStorage.getItem('token');
+    if (!token) {
+        throw new ApiError('No authentication token found');
+    }
+
+    const response = await fetch(`${API_URL}${path}?window=${window}`, {
+        headers: {
+            'Authorization': `Bearer ${token}`,
+            'Content-Type': 'application/json'
+        }
+    });
+
+    if (!response.ok) {
+        const error = await response.json();
+        throw new ApiError(error.error || 'Failed to fetch statistics', response.status);
+    }
+
+    return response.json();
+}
+
+// describeWindow names the period statistics cover, e.g. "In the last 30 days"
+export function describeWindow(window: StatsWindow): string {
+    switch (window) {
+        case '7d':
+            return 'In the last 7 days';
+        case '90d':
+            return 'In the last 90 days';
+        case '365d':
+            return 'In the last year';
+        default:
+            return 'In the last 30 days';
+    }
+}
+
+export const statsApi = {
+    // dashboard returns the statistics of every project the user can access
+    dashboard(window: StatsWindow = '30d'): Promise<Stats> {
+        return fetchStats('/stats/dashboard', window);
+    },
+
+    projectMetrics(projectId: string, window: StatsWindow = '30d'): Promise<Stats> {
+        return fetchStats(`/projects/${projectId}/metrics`, window);
+    },
+};
@@ -0,0 +1,122 @@
+// app/lib/api/streams.ts
+const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
+
+// reconnectDelay is how long a dropped stream waits before reconnecting
+const reconnectDelay = 5000;
+
+// Change is a change to a resource the user can access, from /changes/stream
+export interface Change {
+    id: string;
+    event: string;
+    resourceType: string;
+    resourceId: string;
+    projectId?: string;
+    actorId?: string;
+    occurredAt: string;
+    data?: unknown;
+}
+
+export type StreamListeners = Record<string, (data: unknown) => void>;
+
+// fetchTicket asks for a one-time ticket to open a stream with, as
+// EventSource cannot send the Authorization header
+async function fetchTicket(): Promise<string> {
+    const token = localStorage.getItem('token');
+    if (!token) {
+        throw new Error('No authentication token found');
+    }
+
+    const response = await fetch(`${API_URL}/streams/ticket`, {
+        method: 'POST',
+        headers: {
+            'Authorization': `Bearer ${token}`,
+            'Content-Type': 'application/json'
+        }
+    });
+    if (!response.ok) {
+        throw new Error('Failed to get a stream ticket');
+    }
+    const data: { ticket: string } = await response.json();
+    return data.ticket;
+}
+
+// openStream follows a server-sent event stream, handing the data of each
+// named event to its listener. A ticket opens one connection only, so after
+// an error the stream reconnects with a new one, resuming after the last
+// event it saw. It returns a function that closes the stream.
+export function openStream(path: string, params: Record<string, string>, listeners: StreamListeners): () => void {
+    let source: EventSource | null = null;
+    let lastEventId = '';
+    let closed = false;
+    let retry: ReturnType<typeof setTimeout> | undefined;
+
+    const reconnect = () => {
+        if (!closed) {
+            retry = setTimeout(connect, reconnectDelay);
+        }
+    };
+
+    const connect = async () => {
+        try {
+            const ticket = await fetchTicket();
+            if (closed) {
+                return;
+            }
+            const query = new URLSearchParams({ ...params, ticket });
+            if (lastEventId) {
+                query.set('lastEventId', lastEventId);
+            }
+
+            source = new EventSource(`${API_URL}${path}?${query}`);
+            for (const [event, listener] of Object.entries(listeners)) {
+                source.addEventListener(event, (e) => {
+                    const message = e as MessageEvent<string>;
+                    if (message.lastEventId) {
+                        lastEventId = message.lastEventId;
+                    }
+                    listener(JSON.parse(message.data));
+                });
+            }
+            // The browser would retry with the used ticket, so reconnect here
+            source.onerror = () => {
+                source?.close();
+                reconnect();
+            };
+        } catch {
+            reconnect();
+        }
+    };
+
+    void connect();
+    return () => {
+        closed = true;
+        clearTimeout(retry);
+        source?.close();
+    };
+}
+
+export const streamsApi = {
+    // changes follows the changes to the projects the user can access, or
+    // to the given ones
+    changes(onChange: (change: Change) => void, projectIds: string[] = []): () => void {
+        const params: Record<string, string> = {};
+        if (projectIds.length > 0) {
+            params.projects = projectIds.join(',');
+        }
+        const close: () => void = openStream('/changes/stream', params, {
+            change: (data) => onChange(data as Change),
+            // The user lost access to what the stream follows
+            revoked: () => close(),
+        });
+        return close;
+    },
+
+    // notifications follows the user's new notifications and their unread
+    // count, which comes first
+    notifications(onNotification: (notification: unknown) => void, onUnread: (count: number) => void): () => void {
+        return openStream('/notifications/stream', {}, {
+            notification: (data) => onNotification(data),
+            unread: (data) => onUnread((data as { count: number }).count),
+        });
+    },
+};
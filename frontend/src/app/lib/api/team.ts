@@ -1,14 +1,25 @@
 // lib/api/team.ts
-import { TeamMember } from '@/types/team';
+import { TeamMember, TeamMemberRole } from '@/types/team';
+import type { Page } from '@/types/types';
+import { fetchAllPages, pageUrl } from './pages';
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8085/api/v1';
 
+interface TeamMembership {
+    userId: string;
+    name: string;
+    email: string;
+    role: TeamMemberRole;
+    joinedAt: string;
+}
+
 interface Team {
     id: string;
     name: string;
     description: string;
-    members: TeamMember[];
+    members: TeamMembership[];
     lead: string;
+    createdBy: string;
     createdAt: string;
     updatedAt: string;
     projectCount?: number;
@@ -17,6 +28,7 @@ interface Team {
 interface CreateTeamInput {
     name: string;
     description: string;
+    lead?: string;
 }
 
 interface UpdateTeamInput {
@@ -29,19 +41,22 @@ export const teamApi = {
     // Get all teams
     getAllTeams: async (): Promise<Team[]> => {
         const token = localStorage.getItem('token');
-        const response = await fetch(`${API_URL}/teams`, {
-            headers: {
-                'Authorization': `Bearer ${token}`,
-                'Content-Type': 'application/json',
-            },
-        });
+        return fetchAllPages(async (cursor) => {
+            const response = await fetch(pageUrl(`${API_URL}/teams`, cursor), {
+                headers: {
+                    'Authorization': `Bearer ${token}`,
+                    'Content-Type': 'application/json',
+                },
+            });
 
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.message || 'Failed to fetch teams');
-        }
+            if (!response.ok) {
+                const error = await response.json();
+                throw new Error(error.message || 'Failed to fetch teams');
+            }
 
-        return response.json();
+            const data: Page<Team> = await response.json();
+            return data;
+        });
     },
 
     // Get team by ID
@@ -193,4 +208,79 @@ export const teamApi = {
             throw new Error(error.message || 'Failed to remove team member');
         }
     },
+
+    // Add a user to a team
+    addMember: async (teamId: string, userId: string, role: TeamMemberRole): Promise<TeamMembership> => {
+        const token = localStorage.getItem('token');
+        const response = await fetch(`${API_URL}/teams/${teamId}/members`, {
+            method: 'POST',
+            headers: {
+                'Authorization': `Bearer ${token}`,
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ userId, role }),
+        });
+
+        if (!response.ok) {
+            const error = await response.json();
+            throw new Error(error.message || 'Failed to add team member');
+        }
+
+        return response.json();
+    },
+
+    // Get one member of a team
+    getMember: async (teamId: string, userId: string): Promise<TeamMembership> => {
+        const token = localStorage.getItem('token');
+        const response = await fetch(`${API_URL}/teams/${teamId}/members/${userId}`, {
+            headers: {
+                'Authorization': `Bearer ${token}`,
+                'Content-Type': 'application/json',
+            },
+        });
+
+        if (!response.ok) {
+            const error = await response.json();
+            throw new Error(error.message || 'Failed to fetch team member');
+        }
+
+        return response.json();
+    },
+
+    // Change a team member's role
+    updateMember: async (teamId: string, userId: string, role: TeamMemberRole): Promise<TeamMembership> => {
+        const token = localStorage.getItem('token');
+        const response = await fetch(`${API_URL}/teams/${teamId}/members/${userId}`, {
+            method: 'PUT',
+            headers: {
+                'Authorization': `Bearer ${token}`,
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ role }),
+        });
+
+        if (!response.ok) {
+            const error = await response.json();
+            throw new Error(error.message || 'Failed to update team member');
+        }
+
+        return response.json();
+    },
+
+    // Remove a user from a team
+    removeMember: async (teamId: string, userId: string): Promise<void> => {
+        const token = localStorage.getItem('token');
+        const response = await fetch(`${API_URL}/teams/${teamId}/members/${userId}`, {
+            method: 'DELETE',
+            headers: {
+                'Authorization': `Bearer ${token}`,
+                'Content-Type': 'application/json',
+            },
+        });
+
+        if (!response.ok) {
+            const error = await response.json();
+            throw new Error(error.message || 'Failed to remove team member');
+        }
+    },
 };
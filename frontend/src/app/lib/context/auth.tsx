@@ -17,7 +17,7 @@ interface AuthContextType {
     user: User | null;
     loading: boolean;
     login: (email: string, password: string) => Promise<void>;
-    register: (email: string, password: string, name: string) => Promise<void>;
+    register: (email: string, password: string, name: string, invitationToken?: string) => Promise<void>;
     logout: () => Promise<void>;
     refreshUser: () => Promise<User | void>;
 }
@@ -65,10 +65,10 @@ export function AuthProvider({ children }: { children: React.ReactNode }) {
         }
     };
 
-    const register = async (email: string, password: string, name: string) => {
+    const register = async (email: string, password: string, name: string, invitationToken?: string) => {
         try {
             setLoading(true);
-            const response = await authApi.register({ email, password, name });
+            const response = await authApi.register({ email, password, name, invitationToken });
             setUser(response.user);
             toast({
                 title: 'Success',
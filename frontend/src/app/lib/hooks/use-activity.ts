@@ -0,0 +1,38 @@
+// app/hooks/use-activity.ts
+import { useState, useEffect, useCallback } from 'react';
+import { activityApi } from '@/lib/api/activity';
+import type { Activity } from '@/types/dashboard';
+import { useToast } from '@/lib/hooks/use-toast';
+
+// useActivity loads the activity feed of the user, or of one project
+export function useActivity(projectId?: string) {
+    const [activities, setActivities] = useState<Activity[]>([]);
+    const [isLoading, setIsLoading] = useState(false);
+    const { toast } = useToast();
+
+    const fetchActivity = useCallback(async () => {
+        try {
+            setIsLoading(true);
+            const page = projectId ? await activityApi.listForProject(projectId) : await activityApi.list();
+            setActivities(page.activities);
+        } catch (error) {
+            toast({
+                title: 'Error',
+                description: error instanceof Error ? error.message : 'Failed to fetch activity',
+                variant: 'destructive',
+            });
+        } finally {
+            setIsLoading(false);
+        }
+    }, [projectId, toast]);
+
+    useEffect(() => {
+        void fetchActivity();
+    }, [fetchActivity]);
+
+    return {
+        activities,
+        isLoading,
+        refreshActivity: fetchActivity
+    };
+}
@@ -0,0 +1,17 @@
+// app/hooks/use-changes.ts
+import { useEffect, useRef } from 'react';
+import { streamsApi, type Change } from '@/lib/api/streams';
+
+// useChanges calls onChange for every change made to the projects the user
+// can access, or to one project, by anyone, as it happens
+export function useChanges(onChange: (change: Change) => void, projectId?: string) {
+    // The stream stays open when only the callback changes
+    const handler = useRef(onChange);
+    useEffect(() => {
+        handler.current = onChange;
+    }, [onChange]);
+
+    useEffect(() => {
+        return streamsApi.changes((change) => handler.current(change), projectId ? [projectId] : []);
+    }, [projectId]);
+}
@@ -8,6 +8,7 @@ import type {
     UpdateDocumentInput
 } from '@/types/documents';
 import { useToast } from '@/lib/hooks/use-toast';
+import { useChanges } from '@/lib/hooks/use-changes';
 
 export function useDocuments() {
     const [documents, setDocuments] = useState<ProjectDocument[]>([]);
@@ -121,6 +122,22 @@ export function useDocuments() {
         void fetchDocuments();
     }, [fetchDocuments]);
 
+    // Documents changed elsewhere are reloaded quietly as they change
+    useChanges(useCallback((change) => {
+        if (change.resourceType !== 'document') {
+            return;
+        }
+        documentsApi.getAll()
+            .then((data) => {
+                if (Array.isArray(data)) {
+                    setDocuments(data as unknown as ProjectDocument[]);
+                }
+            })
+            .catch(() => {
+                // The next change or a manual refresh tries again
+            });
+    }, []));
+
     return {
         documents,
         isLoading,
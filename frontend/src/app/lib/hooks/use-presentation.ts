@@ -0,0 +1,37 @@
+// app/hooks/use-presentation.ts
+import { useState, useEffect, useCallback } from 'react';
+import { projectApi } from '@/lib/api/projects';
+import type { Presentation } from '@/types/types';
+import { useToast } from '@/lib/hooks/use-toast';
+
+// usePresentation loads a project's presentation in one request
+export function usePresentation(projectId: string) {
+    const [presentation, setPresentation] = useState<Presentation | null>(null);
+    const [isLoading, setIsLoading] = useState(true);
+    const { toast } = useToast();
+
+    const fetchPresentation = useCallback(async () => {
+        try {
+            setIsLoading(true);
+            setPresentation(await projectApi.getPresentation(projectId));
+        } catch (error) {
+            toast({
+                title: 'Error',
+                description: error instanceof Error ? error.message : 'Failed to fetch presentation',
+                variant: 'destructive',
+            });
+        } finally {
+            setIsLoading(false);
+        }
+    }, [projectId, toast]);
+
+    useEffect(() => {
+        void fetchPresentation();
+    }, [fetchPresentation]);
+
+    return {
+        presentation,
+        isLoading,
+        refreshPresentation: fetchPresentation
+    };
+}
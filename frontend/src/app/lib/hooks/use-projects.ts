@@ -3,6 +3,7 @@ import { useState, useEffect, useCallback } from 'react';
 import { projectApi } from '@/lib/api/projects';
 import type { Project } from '@/types/project';
 import { useToast } from '@/lib/hooks/use-toast';
+import { useChanges } from '@/lib/hooks/use-changes';
 
 export function useProjects() {
     const [projects, setProjects] = useState<Project[]>([]);
@@ -29,6 +30,18 @@ export function useProjects() {
         void fetchProjects();
     }, [fetchProjects]);
 
+    // Projects changed elsewhere are reloaded quietly as they change
+    useChanges(useCallback((change) => {
+        if (change.resourceType !== 'project') {
+            return;
+        }
+        projectApi.list()
+            .then(setProjects)
+            .catch(() => {
+                // The next change or a manual refresh tries again
+            });
+    }, []));
+
     return {
         projects,
         isLoading,
@@ -0,0 +1,37 @@
+// app/hooks/use-stats.ts
+import { useState, useEffect, useCallback } from 'react';
+import { statsApi } from '@/lib/api/stats';
+import type { Stats, StatsWindow } from '@/types/dashboard';
+import { useToast } from '@/lib/hooks/use-toast';
+
+// useStats loads the dashboard statistics, or the metrics of one project
+export function useStats(projectId?: string, window: StatsWindow = '30d') {
+    const [stats, setStats] = useState<Stats | null>(null);
+    const [isLoading, setIsLoading] = useState(false);
+    const { toast } = useToast();
+
+    const fetchStats = useCallback(async () => {
+        try {
+            setIsLoading(true);
+            setStats(projectId ? await statsApi.projectMetrics(projectId, window) : await statsApi.dashboard(window));
+        } catch (error) {
+            toast({
+                title: 'Error',
+                description: error instanceof Error ? error.message : 'Failed to fetch statistics',
+                variant: 'destructive',
+            });
+        } finally {
+            setIsLoading(false);
+        }
+    }, [projectId, window, toast]);
+
+    useEffect(() => {
+        void fetchStats();
+    }, [fetchStats]);
+
+    return {
+        stats,
+        isLoading,
+        refreshStats: fetchStats
+    };
+}
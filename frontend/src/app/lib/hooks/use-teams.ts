@@ -18,7 +18,16 @@ export function useTeams() {
                 id: team.id,
                 name: team.name,
                 description: team.description,
-                members: team.members,
+                members: team.members.map(member => ({
+                    id: member.userId,
+                    userId: member.userId,
+                    name: member.name || member.email,
+                    email: member.email,
+                    role: member.role,
+                    status: 'Active' as const,
+                    createdAt: member.joinedAt,
+                    updatedAt: member.joinedAt,
+                })),
                 lead: team.lead,
                 createdAt: team.createdAt,
                 updatedAt: team.updatedAt,
@@ -32,7 +32,9 @@ export const config = {
          * 2. /_next (Next.js internals)
          * 3. /static (static files)
          * 4. favicon.ico, etc.
+         * 5. /share (share links are viewed without signing in)
+         * 6. /unsubscribe (links in emails work without signing in)
          */
-        '/((?!api|_next/static|_next/image|favicon.ico).*)',
+        '/((?!api|_next/static|_next/image|favicon.ico|share/|unsubscribe).*)',
     ],
 }
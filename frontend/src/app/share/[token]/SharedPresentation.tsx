@@ -0,0 +1,153 @@
+// app/share/[token]/SharedPresentation.tsx
+"use client";
+
+import React, { useCallback, useEffect, useState } from 'react';
+import { FileText, Layout, Lock } from 'lucide-react';
+import Button from '@/components/ui/Button';
+import Input from '@/components/ui/Input';
+import { Card } from '@/components/ui/card';
+import { sharesApi, ShareApiError } from '@/lib/api/shares';
+import type { SharedView } from '@/types/share';
+
+interface SharedPresentationProps {
+    token: string;
+}
+
+export default function SharedPresentation({ token }: SharedPresentationProps) {
+    const [view, setView] = useState<SharedView | null>(null);
+    const [isLoading, setIsLoading] = useState(true);
+    const [error, setError] = useState('');
+    const [needsPassword, setNeedsPassword] = useState(false);
+    const [password, setPassword] = useState('');
+    const [openDocument, setOpenDocument] = useState<string | null>(null);
+
+    const load = useCallback(async (withPassword?: string) => {
+        setIsLoading(true);
+        setError('');
+        try {
+            setView(await sharesApi.view(token, withPassword));
+            setNeedsPassword(false);
+        } catch (err) {
+            if (err instanceof ShareApiError && err.passwordRequired) {
+                setNeedsPassword(true);
+                if (withPassword) setError(err.message);
+            } else {
+                setError(err instanceof Error ? err.message : 'Failed to open share link');
+            }
+        } finally {
+            setIsLoading(false);
+        }
+    }, [token]);
+
+    useEffect(() => {
+        load();
+    }, [load]);
+
+    if (isLoading && !needsPassword) {
+        return (
+            <div className="flex items-center justify-center h-96">
+                <div className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600"></div>
+            </div>
+        );
+    }
+
+    if (needsPassword) {
+        return (
+            <div className="max-w-sm mx-auto py-24 space-y-4">
+                <div className="flex items-center space-x-2 text-gray-900 dark:text-white">
+                    <Lock className="h-5 w-5" />
+                    <h2 className="text-lg font-semibold">This link is password protected</h2>
+                </div>
+                <form
+                    className="space-y-3"
+                    onSubmit={(e) => {
+                        e.preventDefault();
+                        load(password);
+                    }}
+                >
+                    <Input
+                        type="password"
+                        placeholder="Password"
+                        value={password}
+                        onChange={(e) => setPassword(e.target.value)}
+                    />
+                    {error && <p className="text-sm text-red-600">{error}</p>}
+                    <Button type="submit" fullWidth disabled={!password || isLoading}>
+                        {isLoading ? 'Opening...' : 'Open'}
+                    </Button>
+                </form>
+            </div>
+        );
+    }
+
+    if (!view) {
+        return (
+            <div className="text-center py-24">
+                <h2 className="text-2xl font-semibold text-gray-900 dark:text-white">Link unavailable</h2>
+                <p className="mt-2 text-gray-600 dark:text-gray-400">{error}</p>
+            </div>
+        );
+    }
+
+    return (
+        <div className="max-w-5xl mx-auto px-4 py-8 space-y-8">
+            <div>
+                <h1 className="text-3xl font-bold text-gray-900 dark:text-white">{view.project.name}</h1>
+                <p className="mt-2 text-gray-600 dark:text-gray-400">{view.project.description}</p>
+                <div className="mt-4 flex items-center space-x-4 text-sm text-gray-500 dark:text-gray-400">
+                    <span className="capitalize">{view.project.status}</span>
+                    <span>{view.project.progress}% complete</span>
+                    {view.expiresAt && (
+                        <span>Link expires {new Date(view.expiresAt).toLocaleDateString()}</span>
+                    )}
+                </div>
+            </div>
+
+            {view.documents.length > 0 && (
+                <section className="space-y-3">
+                    <h2 className="text-xl font-semibold text-gray-900 dark:text-white">Documents</h2>
+                    {view.documents.map((doc) => (
+                        <Card key={doc.id} className="p-4">
+                            <button
+                                className="w-full flex items-center justify-between text-left"
+                                onClick={() => setOpenDocument(openDocument === doc.id ? null : doc.id)}
+                            >
+                                <div className="flex items-center space-x-3">
+                                    <FileText className="h-5 w-5 text-blue-600" />
+                                    <div>
+                                        <p className="font-medium text-gray-900 dark:text-white">{doc.title}</p>
+                                        <p className="text-xs text-gray-500 dark:text-gray-400">
+                                            {doc.type} · v{doc.version} · {new Date(doc.updatedAt).toLocaleDateString()}
+                                        </p>
+                                    </div>
+                                </div>
+                            </button>
+                            {openDocument === doc.id && (
+                                <pre className="mt-4 whitespace-pre-wrap text-sm text-gray-700 dark:text-gray-300">
+                                    {doc.content}
+                                </pre>
+                            )}
+                        </Card>
+                    ))}
+                </section>
+            )}
+
+            {view.mockups.length > 0 && (
+                <section className="space-y-3">
+                    <h2 className="text-xl font-semibold text-gray-900 dark:text-white">Mockups</h2>
+                    <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+                        {view.mockups.map((mockup) => (
+                            <Card key={mockup.id} className="p-4 flex items-center space-x-3">
+                                <Layout className="h-5 w-5 text-purple-600" />
+                                <div>
+                                    <p className="font-medium text-gray-900 dark:text-white">{mockup.name}</p>
+                                    <p className="text-xs text-gray-500 dark:text-gray-400">{mockup.tool}</p>
+                                </div>
+                            </Card>
+                        ))}
+                    </div>
+                </section>
+            )}
+        </div>
+    );
+}
@@ -0,0 +1,22 @@
+// app/share/[token]/page.tsx
+import SharedPresentation from "./SharedPresentation";
+import { Metadata } from "next";
+
+type PageProps = {
+    params: Promise<{ token: string }>;
+}
+
+export const metadata: Metadata = {
+    title: 'Shared Project | ProjectNexus',
+    robots: { index: false, follow: false },
+};
+
+export default async function Page({ params }: PageProps) {
+    const resolvedParams = await params;
+
+    return (
+        <div className="min-h-screen bg-gray-50 dark:bg-gray-900">
+            <SharedPresentation token={resolvedParams.token} />
+        </div>
+    );
+}
@@ -9,13 +9,63 @@ export interface StatsCard {
     trendDirection?: 'up' | 'down';
 }
 
+export type StatsWindow = '7d' | '30d' | '90d' | '365d';
+
+export interface KeyCount {
+    key: string;
+    count: number;
+}
+
+export interface TrendPoint {
+    date: string;
+    value: number;
+}
+
+// Stats are the dashboard statistics, or the metrics of one project
+export interface Stats {
+    projectId?: string;
+    window: StatsWindow;
+    bucket: 'day' | 'week';
+    from: string;
+    to: string;
+    summary: {
+        documents: number;
+        pendingReviews: number;
+        teamMembers: number;
+        documentsCreated: number;
+        edits: number;
+        approvals: number;
+    };
+    projectsByStatus?: KeyCount[];
+    documentsByStatus: KeyCount[];
+    documentsByType: KeyCount[];
+    approvalLeadTime: {
+        approvals: number;
+        averageHours: number;
+        minHours: number;
+        maxHours: number;
+    };
+    editsPerWeek: TrendPoint[];
+    contributions: {
+        userId: string;
+        name: string;
+        documentsCreated: number;
+        edits: number;
+    }[];
+    trends: {
+        documentsCreated: TrendPoint[];
+        edits: TrendPoint[];
+        approvals: TrendPoint[];
+    };
+}
+
 export interface Activity {
-    id: number;
+    id: string;
     action: string;
     project: string;
     time: string;
     user: string;
-    type?: 'document' | 'project' | 'team';
+    type?: 'document' | 'project' | 'team' | 'mockup';
 }
 
 export interface QuickAction {
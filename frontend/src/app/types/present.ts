@@ -1,21 +1,23 @@
 ﻿// types/present/index.ts
 export interface TeamMember {
-    id: number;
+    id: string;
     name: string;
     role: string;
-    avatar: string;
+    initials: string;
 }
 
 export interface Document {
-    id: number;
+    id: string;
     type: string;
     title: string;
     lastModified: string;
     preview: string;
+    // excerpt is the start of the document's text
+    excerpt?: string;
 }
 
 export interface Mockup {
-    id: number;
+    id: string;
     title: string;
     type: string;
     preview: string;
@@ -23,10 +25,10 @@ export interface Mockup {
 }
 
 export interface TimelineEvent {
-    id: number;
+    id: string;
     title: string;
     date: string;
-    status: 'completed' | 'current' | 'upcoming';
+    status: 'completed' | 'overdue' | 'current' | 'upcoming';
     assignee?: string;
     comments?: number;
     subEvents?: Array<{
@@ -80,3 +82,53 @@ export interface Project {
 
 
 export type ViewableItem = Document | Mockup;
+
+// Presentation is the response of GET /projects/:id/presentation
+export interface Presentation {
+    project: {
+        id: string;
+        name: string;
+        description: string;
+        status: string;
+        progress: number;
+        startDate?: string;
+        endDate?: string;
+    };
+    team: Array<{
+        userId: string;
+        name: string;
+        email: string;
+        role: string;
+        initials: string;
+    }>;
+    documents: Array<{
+        id: string;
+        title: string;
+        type: string;
+        version: number;
+        preview: string;
+        tags: string[];
+        authorId: string;
+        authorName: string;
+        approvedAt?: string;
+        updatedAt: string;
+    }>;
+    mockups: Array<{
+        id: string;
+        name: string;
+        type: string;
+        tool: string;
+        thumbnail: string;
+        status: string;
+        updatedAt: string;
+    }>;
+    timeline: Array<{
+        id: string;
+        kind: 'start' | 'milestone' | 'end';
+        title: string;
+        date: string;
+        status: 'completed' | 'overdue' | 'current' | 'upcoming';
+        assigneeId?: string;
+        assigneeName?: string;
+    }>;
+}
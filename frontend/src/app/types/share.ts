@@ -0,0 +1,66 @@
+// types/share.ts
+export type ShareLinkKind = 'presentation' | 'document';
+
+// SHARE_LINK_MAX_ITEMS is how many documents and mockups one link can show
+export const SHARE_LINK_MAX_ITEMS = 100;
+
+export interface ShareLink {
+    id: string;
+    projectId: string;
+    kind: ShareLinkKind;
+    documentIds: string[];
+    mockupIds: string[];
+    passwordProtected: boolean;
+    expiresAt?: string;
+    views: number;
+    lastViewedAt?: string;
+    revokedAt?: string;
+    revokedBy?: string;
+    createdBy: string;
+    createdAt: string;
+    updatedAt: string;
+}
+
+// CreatedShareLink carries the link's token, which is only shown once
+export interface CreatedShareLink extends ShareLink {
+    token: string;
+    url: string;
+}
+
+export interface CreateShareLinkInput {
+    documentIds?: string[];
+    mockupIds?: string[];
+    password?: string;
+    expiresAt?: string;
+}
+
+export interface SharedView {
+    kind: ShareLinkKind;
+    project: {
+        name: string;
+        description: string;
+        status: string;
+        progress: number;
+        startDate?: string;
+        endDate?: string;
+    };
+    documents: {
+        id: string;
+        title: string;
+        type: string;
+        status: string;
+        version: number;
+        content: string;
+        updatedAt: string;
+    }[];
+    mockups: {
+        id: string;
+        name: string;
+        type: string;
+        tool: string;
+        thumbnail: string;
+        status: string;
+        updatedAt: string;
+    }[];
+    expiresAt?: string;
+}
@@ -1,4 +1,4 @@
-﻿import { ProjectData, Document, TeamMember, Mockup, TimelineEvent, ProjectMetric, ActivityItem, ViewableItem, Project } from '@/types/present';
+﻿import { ProjectData, Document, TeamMember, Mockup, TimelineEvent, ProjectMetric, ActivityItem, ViewableItem, Project, Presentation } from '@/types/present';
 
 export interface PresentPageProps {
     params: Promise<{ id: string }>;
@@ -11,10 +11,17 @@ export interface APIDocument {
     projectId: string;
 }
 
+// Page is the envelope returned by paginated list endpoints
+export interface Page<T> {
+    items: T[];
+    nextCursor?: string;
+    total: number;
+}
+
 export type PresentDocument = Document;
 export type PresentTeamMember = TeamMember;
 
-export type { ProjectData, Mockup, TimelineEvent, ProjectMetric, ActivityItem, ViewableItem, Project };
+export type { ProjectData, Mockup, TimelineEvent, ProjectMetric, ActivityItem, ViewableItem, Project, Presentation };
 
 
 
@@ -0,0 +1,56 @@
+// app/unsubscribe/Unsubscribe.tsx
+"use client";
+
+import React, { useState } from 'react';
+import { MailX } from 'lucide-react';
+import Button from '@/components/ui/Button';
+import { notificationsApi } from '@/lib/api/notifications';
+
+interface UnsubscribeProps {
+    token: string;
+}
+
+// Unsubscribe asks before turning emails off, as mail scanners open the
+// links in emails on their own
+export default function Unsubscribe({ token }: UnsubscribeProps) {
+    const [isSubmitting, setIsSubmitting] = useState(false);
+    const [done, setDone] = useState(false);
+    const [error, setError] = useState(token ? '' : 'This unsubscribe link is incomplete.');
+
+    const unsubscribe = async () => {
+        setIsSubmitting(true);
+        setError('');
+        try {
+            await notificationsApi.unsubscribe(token);
+            setDone(true);
+        } catch (err) {
+            setError(err instanceof Error ? err.message : 'Failed to unsubscribe');
+        } finally {
+            setIsSubmitting(false);
+        }
+    };
+
+    return (
+        <div className="max-w-sm mx-auto py-24 space-y-4">
+            <div className="flex items-center space-x-2 text-gray-900 dark:text-white">
+                <MailX className="h-5 w-5" />
+                <h2 className="text-lg font-semibold">Unsubscribe from ProjectNexus emails</h2>
+            </div>
+            {done ? (
+                <p className="text-sm text-gray-600 dark:text-gray-300">
+                    You will no longer receive notification emails or summaries. You can turn them back on in your settings.
+                </p>
+            ) : (
+                <>
+                    <p className="text-sm text-gray-600 dark:text-gray-300">
+                        This turns off every notification email and summary. Notifications in the app stay as they are.
+                    </p>
+                    {error && <p className="text-sm text-red-600">{error}</p>}
+                    <Button fullWidth onClick={unsubscribe} disabled={!token || isSubmitting}>
+                        {isSubmitting ? 'Unsubscribing...' : 'Unsubscribe'}
+                    </Button>
+                </>
+            )}
+        </div>
+    );
+}
@@ -0,0 +1,22 @@
+// app/unsubscribe/page.tsx
+import Unsubscribe from "./Unsubscribe";
+import { Metadata } from "next";
+
+type PageProps = {
+    searchParams: Promise<{ token?: string }>;
+}
+
+export const metadata: Metadata = {
+    title: 'Unsubscribe | ProjectNexus',
+    robots: { index: false, follow: false },
+};
+
+export default async function Page({ searchParams }: PageProps) {
+    const resolvedParams = await searchParams;
+
+    return (
+        <div className="min-h-screen bg-gray-50 dark:bg-gray-900">
+            <Unsubscribe token={resolvedParams.token ?? ''} />
+        </div>
+    );
+}